@@ -0,0 +1,340 @@
+package archive
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Archive kinds, as returned by Kind().
+const (
+	KindTar   = "tar"
+	KindTarGz = "tgz"
+	KindZip   = "zip"
+)
+
+// The creator written in the CreationInfo of scanned documents.
+var Creator = "Tool: spdx-go"
+
+// Archive extensions and their kind. The longer extensions must be checked
+// before the shorter ones (".tar.gz" before ".gz").
+var extensions = []pair{
+	{".tar.gz", KindTarGz},
+	{".tgz", KindTarGz},
+	{".tar", KindTar},
+	{".zip", KindZip},
+}
+
+// File extensions considered to be source code.
+var sourceExtensions = map[string]interface{}{
+	".c": nil, ".h": nil, ".cc": nil, ".cpp": nil, ".cxx": nil, ".hpp": nil,
+	".go": nil, ".java": nil, ".py": nil, ".rb": nil, ".pl": nil, ".php": nil,
+	".js": nil, ".ts": nil, ".rs": nil, ".cs": nil, ".m": nil, ".s": nil,
+	".sh": nil, ".scala": nil, ".swift": nil, ".kt": nil, ".lua": nil,
+}
+
+// File extensions considered to be binaries.
+var binaryExtensions = map[string]interface{}{
+	".o": nil, ".a": nil, ".so": nil, ".dll": nil, ".exe": nil, ".dylib": nil,
+	".class": nil, ".pyc": nil, ".bin": nil,
+}
+
+// Useful helper pair struct.
+type pair struct {
+	ext, kind string
+}
+
+// Returns the archive kind (one of the Kind* constants) of the file `name`,
+// judging by its extension. Returns an empty string if `name` is not an
+// archive that can be scanned.
+func Kind(name string) string {
+	lower := strings.ToLower(name)
+	for _, e := range extensions {
+		if strings.HasSuffix(lower, e.ext) {
+			return e.kind
+		}
+	}
+	return ""
+}
+
+// Returns the archive kind (one of the Kind* constants) of the content
+// starting with `start`, judging by its magic bytes. Gzipped content is taken
+// to be a gzipped tar. Returns an empty string if the content is not an
+// archive that can be scanned.
+func Detect(start []byte) string {
+	switch {
+	case bytes.HasPrefix(start, []byte{0x1f, 0x8b}):
+		return KindTarGz
+	case bytes.HasPrefix(start, []byte("PK\x03\x04")), bytes.HasPrefix(start, []byte("PK\x05\x06")):
+		return KindZip
+	case len(start) >= 262 && string(start[257:262]) == "ustar":
+		return KindTar
+	}
+	return ""
+}
+
+// Returns the SPDX file type (spdx.FT_* constants) for the file `name`,
+// judging by its extension.
+func FileType(name string) string {
+	if Kind(name) != "" {
+		return spdx.FT_ARCHIVE
+	}
+	ext := strings.ToLower(path.Ext(name))
+	if _, ok := sourceExtensions[ext]; ok {
+		return spdx.FT_SOURCE
+	}
+	if _, ok := binaryExtensions[ext]; ok {
+		return spdx.FT_BINARY
+	}
+	return spdx.FT_OTHER
+}
+
+// Scanner reads tar, gzipped tar and zip archives and describes them as a
+// SPDX Document without unpacking them to disk.
+//
+// The resulting document has one package for the archive itself, with the
+// archive checksum and file name, and one file per regular file found in the
+// archive. File names are the paths inside the archive.
+//
+// If Recursive is set to true (default false), archives found inside the
+// archive are scanned as well and their files are added with the path of the
+// nested archive as prefix (e.g. "./lib/dep.zip/README"). Nested archives are
+// always recorded as ARCHIVE files. A nested archive which cannot be read does
+// not stop the scan: it is recorded with the error in its file comment, with
+// the files read before the error.
+//
+// Always use NewScanner() to create a new Scanner.
+type Scanner struct {
+	Recursive bool
+	files     []*spdx.File
+}
+
+// Create a new *Scanner.
+func NewScanner() *Scanner {
+	return &Scanner{}
+}
+
+// Scan an archive file given by its path. See Scanner.Scan().
+func (s *Scanner) ScanFile(name string) (*spdx.Document, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return s.Scan(f, filepath.Base(name))
+}
+
+// Scan the archive read from `r`. The archive format is given by the
+// extension of `name` (see Kind()), which is also used as the package file
+// name, or by the content if `name` has no archive extension (see Detect()),
+// e.g. for an archive read from the standard input.
+func (s *Scanner) Scan(r io.Reader, name string) (*spdx.Document, error) {
+	kind := Kind(name)
+	if kind == "" {
+		buf := bufio.NewReader(r)
+		r = buf
+		start, _ := buf.Peek(512)
+		kind = Detect(start)
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("%s: unknown archive format. Supported extensions: .tar, .tar.gz, .tgz and .zip.", name)
+	}
+
+	s.files = nil
+	h := sha1.New()
+	if err := s.archive(io.TeeReader(r, h), kind, "./"); err != nil {
+		return nil, err
+	}
+	// hash whatever the archive readers did not consume (padding, etc.)
+	if _, err := io.Copy(ioutil.Discard, io.TeeReader(r, h)); err != nil {
+		return nil, err
+	}
+
+	pkg := &spdx.Package{
+		Name:             spdx.Str(packageName(name), nil),
+		FileName:         spdx.Str(name, nil),
+		DownloadLocation: spdx.Str(spdx.NOASSERTION, nil),
+		Checksum:         checksum(h.Sum(nil)),
+		VerificationCode: verificationCode(s.files),
+		LicenceConcluded: spdx.NewLicence(spdx.NOASSERTION, nil),
+		LicenceDeclared:  spdx.NewLicence(spdx.NOASSERTION, nil),
+		LicenceInfoFromFiles: []spdx.AnyLicence{
+			spdx.NewLicence(spdx.NOASSERTION, nil),
+		},
+		CopyrightText: spdx.Str(spdx.NOASSERTION, nil),
+		Files:         s.files,
+	}
+
+	doc := &spdx.Document{
+		SpecVersion: spdx.Str("SPDX-1.2", nil),
+		DataLicence: spdx.Str(spdx.DATA_LICENCE_TAG, nil),
+		CreationInfo: &spdx.CreationInfo{
+			Creator: []spdx.ValueCreator{spdx.NewValueCreator(Creator, nil)},
+			Created: spdx.NewValueDate(time.Now().UTC().Format(time.RFC3339), nil),
+		},
+		Packages: []*spdx.Package{pkg},
+		Files:    s.files,
+	}
+	return doc, nil
+}
+
+// Scan an archive of the given kind, prefixing all file names with `prefix`.
+func (s *Scanner) archive(r io.Reader, kind, prefix string) error {
+	switch kind {
+	case KindTarGz:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		return s.tar(gz, prefix)
+	case KindTar:
+		return s.tar(r, prefix)
+	case KindZip:
+		// zip needs random access, read the whole archive
+		buf, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return s.zip(bytes.NewReader(buf), int64(len(buf)), prefix)
+	}
+	return fmt.Errorf("Unknown archive kind %s.", kind)
+}
+
+// Scan all the regular files in a tar archive.
+func (s *Scanner) tar(r io.Reader, prefix string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !hdr.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		if err = s.entry(tr, prefix+cleanName(hdr.Name)); err != nil {
+			return err
+		}
+	}
+}
+
+// Scan all the regular files in a zip archive.
+func (s *Scanner) zip(r io.ReaderAt, size int64, prefix string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, zf := range zr.File {
+		if !zf.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = s.entry(rc, prefix+cleanName(zf.Name))
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Add the file `name` read from `r` and, if it is an archive and the scanner
+// is recursive, all the files inside it. A nested archive which cannot be
+// read is recorded with the error in its comment.
+func (s *Scanner) entry(r io.Reader, name string) error {
+	h := sha1.New()
+	tee := io.TeeReader(r, h)
+
+	if kind := Kind(name); kind != "" && s.Recursive {
+		// add the archive before its contents
+		file := newFile(name)
+		s.files = append(s.files, file)
+		if err := s.archive(tee, kind, name+"/"); err != nil {
+			file.Comment = spdx.Str("The archive could not be scanned: "+err.Error(), nil)
+		}
+		if _, err := io.Copy(ioutil.Discard, tee); err != nil {
+			return err
+		}
+		file.Checksum = checksum(h.Sum(nil))
+		return nil
+	}
+
+	if _, err := io.Copy(ioutil.Discard, tee); err != nil {
+		return err
+	}
+	file := newFile(name)
+	file.Checksum = checksum(h.Sum(nil))
+	s.files = append(s.files, file)
+	return nil
+}
+
+// Create a new *spdx.File with the given name and NOASSERTION licensing info.
+func newFile(name string) *spdx.File {
+	return &spdx.File{
+		Name:              spdx.Str(name, nil),
+		Type:              spdx.Str(FileType(name), nil),
+		LicenceConcluded:  spdx.NewLicence(spdx.NOASSERTION, nil),
+		LicenceInfoInFile: []spdx.AnyLicence{spdx.NewLicence(spdx.NOASSERTION, nil)},
+		CopyrightText:     spdx.Str(spdx.NOASSERTION, nil),
+	}
+}
+
+// Create a SHA1 *spdx.Checksum from a hash sum.
+func checksum(sum []byte) *spdx.Checksum {
+	return &spdx.Checksum{
+		Algo:  spdx.Str("SHA1", nil),
+		Value: spdx.Str(hex.EncodeToString(sum), nil),
+	}
+}
+
+// Compute the package verification code as described in the SPDX 1.2
+// specification: the SHA1 of the sorted and concatenated SHA1 checksums of all
+// files in the package.
+func verificationCode(files []*spdx.File) *spdx.VerificationCode {
+	sums := make([]string, 0, len(files))
+	for _, f := range files {
+		sums = append(sums, f.Checksum.Value.Val)
+	}
+	sort.Strings(sums)
+	sum := sha1.Sum([]byte(strings.Join(sums, "")))
+	return &spdx.VerificationCode{Value: spdx.Str(hex.EncodeToString(sum[:]), nil)}
+}
+
+// Clean an archive entry name so that it is relative to the archive root.
+func cleanName(name string) string {
+	name = path.Clean("/" + name)
+	return strings.TrimPrefix(name, "/")
+}
+
+// Returns the archive name without directories and the archive extension.
+func packageName(name string) string {
+	base := filepath.Base(name)
+	lower := strings.ToLower(base)
+	for _, e := range extensions {
+		if strings.HasSuffix(lower, e.ext) {
+			return base[:len(base)-len(e.ext)]
+		}
+	}
+	return base
+}
@@ -0,0 +1,233 @@
+package archive
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha1"
+	"encoding/hex"
+	"sort"
+	"testing"
+)
+
+// Returns the hex SHA1 of `data`.
+func sha1Hex(data string) string {
+	sum := sha1.Sum([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create a zip archive with the given name => content files.
+func makeZip(t *testing.T, files [][2]string) []byte {
+	buf := new(bytes.Buffer)
+	w := zip.NewWriter(buf)
+	for _, f := range files {
+		fw, err := w.Create(f[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write([]byte(f[1]))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// Create a gzipped tar archive with the given name => content files.
+func makeTarGz(t *testing.T, files [][2]string) []byte {
+	buf := new(bytes.Buffer)
+	gz := gzip.NewWriter(buf)
+	w := tar.NewWriter(gz)
+	w.WriteHeader(&tar.Header{Name: "dir/", Typeflag: tar.TypeDir, Mode: 0755})
+	for _, f := range files {
+		w.WriteHeader(&tar.Header{Name: f[0], Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(f[1]))})
+		w.Write([]byte(f[1]))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	gz.Close()
+	return buf.Bytes()
+}
+
+func TestKind(t *testing.T) {
+	tests := map[string]string{
+		"a.tar":        KindTar,
+		"a.tar.gz":     KindTarGz,
+		"a.TGZ":        KindTarGz,
+		"dir/a.zip":    KindZip,
+		"a.gz":         "",
+		"archive.txt":  "",
+		"tar":          "",
+		"release.tar.": "",
+	}
+	for name, kind := range tests {
+		if k := Kind(name); k != kind {
+			t.Errorf("Kind(%#v) = %#v, expected %#v", name, k, kind)
+		}
+	}
+}
+
+func TestFileType(t *testing.T) {
+	tests := map[string]string{
+		"main.go":    spdx.FT_SOURCE,
+		"lib.so":     spdx.FT_BINARY,
+		"inner.zip":  spdx.FT_ARCHIVE,
+		"README":     spdx.FT_OTHER,
+		"notes.TXT":  spdx.FT_OTHER,
+		"x/y/Main.C": spdx.FT_SOURCE,
+	}
+	for name, ft := range tests {
+		if f := FileType(name); f != ft {
+			t.Errorf("FileType(%#v) = %#v, expected %#v", name, f, ft)
+		}
+	}
+}
+
+func TestScanTarGz(t *testing.T) {
+	inner := makeZip(t, [][2]string{{"a.c", "int a;"}})
+	data := makeTarGz(t, [][2]string{
+		{"dir/README", "readme"},
+		{"./dir/inner.zip", string(inner)},
+	})
+
+	doc, err := NewScanner().Scan(bytes.NewReader(data), "release-1.0.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(doc.Packages) != 1 {
+		t.Fatalf("Expected one package, found %d.", len(doc.Packages))
+	}
+	pkg := doc.Packages[0]
+	if pkg.Name.Val != "release-1.0" || pkg.FileName.Val != "release-1.0.tar.gz" {
+		t.Errorf("Wrong package name or file name: %#v %#v", pkg.Name.Val, pkg.FileName.Val)
+	}
+	if pkg.Checksum.Value.Val != sha1Hex(string(data)) {
+		t.Errorf("Wrong package checksum %s.", pkg.Checksum.Value.Val)
+	}
+
+	if len(doc.Files) != 2 {
+		t.Fatalf("Expected 2 files, found %d.", len(doc.Files))
+	}
+	if f := doc.Files[0]; f.Name.Val != "./dir/README" || f.Checksum.Value.Val != sha1Hex("readme") || f.Type.Val != spdx.FT_OTHER {
+		t.Errorf("Wrong first file: %s %s %s", f.Name.Val, f.Checksum.Value.Val, f.Type.Val)
+	}
+	if f := doc.Files[1]; f.Name.Val != "./dir/inner.zip" || f.Checksum.Value.Val != sha1Hex(string(inner)) || f.Type.Val != spdx.FT_ARCHIVE {
+		t.Errorf("Wrong second file: %s %s %s", f.Name.Val, f.Checksum.Value.Val, f.Type.Val)
+	}
+
+	sums := []string{sha1Hex("readme"), sha1Hex(string(inner))}
+	sort.Strings(sums)
+	if code := sha1Hex(sums[0] + sums[1]); pkg.VerificationCode.Value.Val != code {
+		t.Errorf("Wrong verification code %s.", pkg.VerificationCode.Value.Val)
+	}
+}
+
+func TestScanRecursive(t *testing.T) {
+	inner := makeZip(t, [][2]string{{"a.c", "int a;"}, {"lib/b.so", "elf"}})
+	data := makeTarGz(t, [][2]string{{"dir/inner.zip", string(inner)}})
+
+	scanner := NewScanner()
+	scanner.Recursive = true
+	doc, err := scanner.Scan(bytes.NewReader(data), "release.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][2]string{
+		{"./dir/inner.zip", sha1Hex(string(inner))},
+		{"./dir/inner.zip/a.c", sha1Hex("int a;")},
+		{"./dir/inner.zip/lib/b.so", sha1Hex("elf")},
+	}
+	if len(doc.Files) != len(expected) {
+		t.Fatalf("Expected %d files, found %d.", len(expected), len(doc.Files))
+	}
+	for i, e := range expected {
+		f := doc.Files[i]
+		if f.Name.Val != e[0] || f.Checksum.Value.Val != e[1] {
+			t.Errorf("Expected file %s (%s) but found %s (%s).", e[0], e[1], f.Name.Val, f.Checksum.Value.Val)
+		}
+	}
+}
+
+func TestScanZip(t *testing.T) {
+	data := makeZip(t, [][2]string{{"../../etc/x.go", "package x"}})
+	doc, err := NewScanner().Scan(bytes.NewReader(data), "x.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Files) != 1 || doc.Files[0].Name.Val != "./etc/x.go" || doc.Files[0].Type.Val != spdx.FT_SOURCE {
+		t.Errorf("Wrong files: %+v", doc.Files)
+	}
+}
+
+func TestScanUnknownFormat(t *testing.T) {
+	if _, err := NewScanner().Scan(bytes.NewReader(nil), "x.rar"); err == nil {
+		t.Error("No error for unknown archive format.")
+	}
+}
+
+func TestScanValid(t *testing.T) {
+	data := makeTarGz(t, [][2]string{{"dir/a.c", "int a;"}})
+	doc, err := NewScanner().Scan(bytes.NewReader(data), "release.tar.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := spdx.NewValidator()
+	v.Document(doc)
+	if v.HasErrors() {
+		t.Errorf("Scanned document is not valid: %v", v.Errors())
+	}
+}
+
+func TestScanCorruptNested(t *testing.T) {
+	data := makeTarGz(t, [][2]string{{"bad.zip", "not a zip"}, {"dir/inner.tgz", "\x1f\x8bbad"}, {"a.c", "int a;"}})
+	scanner := NewScanner()
+	scanner.Recursive = true
+	doc, err := scanner.Scan(bytes.NewReader(data), "release.tgz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Files) != 3 {
+		t.Fatalf("Expected 3 files, found %d.", len(doc.Files))
+	}
+	for _, f := range doc.Files[:2] {
+		if f.Comment.Val == "" || f.Type.Val != spdx.FT_ARCHIVE {
+			t.Errorf("Corrupt archive %s not recorded: %+v", f.Name.Val, f)
+		}
+	}
+	if f := doc.Files[2]; f.Name.Val != "./a.c" || f.Comment.Val != "" {
+		t.Errorf("Wrong file after the corrupt archives: %+v", f)
+	}
+	if f := doc.Files[0]; f.Checksum.Value.Val != sha1Hex("not a zip") {
+		t.Errorf("Wrong checksum of the corrupt archive %s", f.Checksum.Value.Val)
+	}
+}
+
+func TestScanDetect(t *testing.T) {
+	tests := map[string][]byte{
+		KindZip:   makeZip(t, [][2]string{{"a.c", "int a;"}}),
+		KindTarGz: makeTarGz(t, [][2]string{{"a.c", "int a;"}}),
+	}
+	for kind, data := range tests {
+		if k := Detect(data); k != kind {
+			t.Errorf("Detected %#v, expected %#v", k, kind)
+		}
+		doc, err := NewScanner().Scan(bytes.NewReader(data), "stdin")
+		if err != nil {
+			t.Errorf("%s: %s", kind, err)
+			continue
+		}
+		pkg := doc.Packages[0]
+		if len(doc.Files) != 1 || pkg.Checksum.Value.Val != sha1Hex(string(data)) {
+			t.Errorf("%s: wrong document %+v", kind, pkg)
+		}
+	}
+	if k := Detect([]byte("plain text")); k != "" {
+		t.Errorf("Detected %#v for text", k)
+	}
+}
@@ -0,0 +1,270 @@
+// Package changelog summarises the changes between two releases of a SPDX
+// document for humans: packages added and removed, version changes, licence
+// changes (flagging the licences which newly bring copyleft terms, also in
+// added packages and files), new
+// extracted licences and changes of copyright holders.
+//
+// It is built on the element by element comparison of package diff, and is
+// written as Markdown or JSON.
+package changelog
+
+import (
+	"github.com/spdx/tools-go/diff"
+	"github.com/spdx/tools-go/spdx"
+)
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The prefixes of the IDs of the SPDX licences with (strong or weak) copyleft
+// terms.
+var CopyleftPrefixes = []string{
+	"GPL-", "LGPL-", "AGPL-", "MPL-", "EPL-", "CDDL-", "EUPL-", "OSL-",
+	"CC-BY-SA-", "CECILL-", "CPL-", "Sleepycat",
+}
+
+// A package of a document. Copyleft lists the copyleft licences of an added
+// package.
+type Package struct {
+	Name             string   `json:"name"`
+	Version          string   `json:"version,omitempty"`
+	LicenceConcluded string   `json:"licenceConcluded,omitempty"`
+	LicenceDeclared  string   `json:"licenceDeclared,omitempty"`
+	Copyleft         []string `json:"copyleft,omitempty"`
+}
+
+// A change of the version of a package.
+type VersionChange struct {
+	Package string `json:"package"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// A change of a licence property of a package or a file. Copyleft lists the
+// copyleft licences of the new value which are not in the old one.
+type LicenceChange struct {
+	Element  string   `json:"element"` // diff.ElemPackage or diff.ElemFile
+	Id       string   `json:"id"`      // Package or file name
+	Field    string   `json:"field"`   // Tag property name
+	Old      string   `json:"old,omitempty"`
+	New      string   `json:"new,omitempty"`
+	Copyleft []string `json:"copyleft,omitempty"`
+}
+
+// An extracted licence added to the document.
+type Licence struct {
+	Id   string   `json:"id"`
+	Name []string `json:"name,omitempty"`
+}
+
+// A change of the copyright holders of a package or a file.
+type CopyrightChange struct {
+	Element string   `json:"element"` // diff.ElemPackage or diff.ElemFile
+	Id      string   `json:"id"`      // Package or file name
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// The changelog from an old to a new release of a document.
+type Changelog struct {
+	PackagesAdded    []Package         `json:"packagesAdded"`
+	PackagesRemoved  []Package         `json:"packagesRemoved"`
+	VersionChanges   []VersionChange   `json:"versionChanges"`
+	LicenceChanges   []LicenceChange   `json:"licenceChanges"`
+	LicencesAdded    []Licence         `json:"licencesAdded"`
+	CopyrightChanges []CopyrightChange `json:"copyrightChanges"`
+}
+
+// Checks whether the changelog has no changes.
+func (c *Changelog) Empty() bool {
+	return len(c.PackagesAdded) == 0 && len(c.PackagesRemoved) == 0 &&
+		len(c.VersionChanges) == 0 && len(c.LicenceChanges) == 0 &&
+		len(c.LicencesAdded) == 0 && len(c.CopyrightChanges) == 0
+}
+
+// The licence properties of packages and files.
+var licenceFields = map[string]bool{
+	"PackageLicenseConcluded":     true,
+	"PackageLicenseDeclared":      true,
+	"PackageLicenseInfoFromFiles": true,
+	"LicenseConcluded":            true,
+	"LicenseInfoInFile":           true,
+}
+
+// Returns the changelog from `old` to `doc`.
+func Build(old, doc *spdx.Document) *Changelog {
+	c := new(Changelog)
+	for _, ch := range diff.Documents(old, doc) {
+		switch {
+		case ch.Element == diff.ElemPackage && ch.Kind == diff.Added:
+			pkg := findPackage(doc, ch.Id, ch.NewMeta)
+			pkg.Copyleft = newCopyleft("", pkg.LicenceConcluded+" "+pkg.LicenceDeclared)
+			c.PackagesAdded = append(c.PackagesAdded, pkg)
+		case ch.Element == diff.ElemFile && ch.Kind == diff.Added:
+			c.LicenceChanges = append(c.LicenceChanges, fileCopyleft(doc, ch.Id, ch.NewMeta)...)
+		case ch.Element == diff.ElemPackage && ch.Kind == diff.Removed:
+			c.PackagesRemoved = append(c.PackagesRemoved, findPackage(old, ch.Id, ch.OldMeta))
+		case ch.Element == diff.ElemLicence && ch.Kind == diff.Added:
+			c.LicencesAdded = append(c.LicencesAdded, findLicence(doc, ch.Id))
+		case ch.Kind != diff.Changed:
+		case ch.Field == "PackageVersion":
+			c.VersionChanges = append(c.VersionChanges, VersionChange{ch.Id, ch.Old, ch.New})
+		case licenceFields[ch.Field]:
+			c.LicenceChanges = append(c.LicenceChanges, LicenceChange{
+				Element:  ch.Element,
+				Id:       ch.Id,
+				Field:    ch.Field,
+				Old:      ch.Old,
+				New:      ch.New,
+				Copyleft: newCopyleft(ch.Old, ch.New),
+			})
+		case ch.Field == "PackageCopyrightText" || ch.Field == "FileCopyrightText":
+			added, removed := diffStrings(Holders(ch.Old), Holders(ch.New))
+			if len(added) > 0 || len(removed) > 0 {
+				c.CopyrightChanges = append(c.CopyrightChanges, CopyrightChange{ch.Element, ch.Id, added, removed})
+			}
+		}
+	}
+	return c
+}
+
+// Returns the package with the given name and metadata in `doc`.
+func findPackage(doc *spdx.Document, name string, meta *spdx.Meta) Package {
+	for _, pkg := range doc.Packages {
+		if pkg != nil && pkg.Name.Val == name && pkg.Meta == meta {
+			return Package{
+				Name:             name,
+				Version:          pkg.Version.Val,
+				LicenceConcluded: spdx.CanonicalLicence(pkg.LicenceConcluded),
+				LicenceDeclared:  spdx.CanonicalLicence(pkg.LicenceDeclared),
+			}
+		}
+	}
+	return Package{Name: name}
+}
+
+// Returns the licence changes of the file with the given name and metadata,
+// added to `doc`, which bring copyleft licences: one per licence property
+// value.
+func fileCopyleft(doc *spdx.Document, name string, meta *spdx.Meta) (res []LicenceChange) {
+	add := func(field string, lic spdx.AnyLicence) {
+		val := spdx.CanonicalLicence(lic)
+		if copyleft := newCopyleft("", val); len(copyleft) > 0 {
+			res = append(res, LicenceChange{diff.ElemFile, name, field, "", val, copyleft})
+		}
+	}
+	for _, file := range doc.Files {
+		if file != nil && file.Name.Val == name && file.Meta == meta {
+			add("LicenseConcluded", file.LicenceConcluded)
+			for _, lic := range file.LicenceInfoInFile {
+				add("LicenseInfoInFile", lic)
+			}
+			break
+		}
+	}
+	return
+}
+
+// Returns the extracted licence with the given ID in `doc`.
+func findLicence(doc *spdx.Document, id string) Licence {
+	for _, lic := range doc.ExtractedLicences {
+		if lic != nil && lic.Id.Val == id {
+			res := Licence{Id: id}
+			for _, name := range lic.Name {
+				res.Name = append(res.Name, name.Val)
+			}
+			return res
+		}
+	}
+	return Licence{Id: id}
+}
+
+// Checks whether `id` is a licence with copyleft terms (see
+// CopyleftPrefixes).
+func Copyleft(id string) bool {
+	for _, prefix := range CopyleftPrefixes {
+		if strings.HasPrefix(id, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the licence IDs of a licence expression.
+func licenceIds(expr string) (ids []string) {
+	for _, word := range strings.FieldsFunc(expr, func(r rune) bool { return r == '(' || r == ')' || r == ' ' }) {
+		if lower := strings.ToLower(word); lower != "and" && lower != "or" {
+			ids = append(ids, word)
+		}
+	}
+	return
+}
+
+// Returns the copyleft licences of the expression `new` which are not in
+// `old`.
+func newCopyleft(old, new string) (res []string) {
+	known := make(map[string]bool)
+	for _, id := range licenceIds(old) {
+		known[id] = true
+	}
+	for _, id := range licenceIds(new) {
+		if Copyleft(id) && !known[id] {
+			known[id] = true
+			res = append(res, id)
+		}
+	}
+	sort.Strings(res)
+	return
+}
+
+// Matches the lines with a copyright statement.
+var copyrightLine = regexp.MustCompile(`(?i)^\s*(copyright|\(c\)|©)`)
+
+// Matches the parts of a copyright statement which are not the holder.
+var copyrightNoise = regexp.MustCompile(`(?i)copyright|\(c\)|©|all rights reserved|\b[0-9]{4}\b`)
+
+// Returns the copyright holders of a copyright text, sorted: the lines with a
+// copyright statement, without the statement, the years and "All rights
+// reserved".
+func Holders(text string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for _, line := range strings.Split(text, "\n") {
+		if !copyrightLine.MatchString(line) {
+			continue
+		}
+		holder := strings.Join(strings.Fields(copyrightNoise.ReplaceAllString(line, " ")), " ")
+		holder = strings.Trim(holder, " ,.:;-")
+		if holder != "" && !seen[holder] {
+			seen[holder] = true
+			res = append(res, holder)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Returns the strings of `new` not in `old` and the strings of `old` not in
+// `new`.
+func diffStrings(old, new []string) (added, removed []string) {
+	inOld := make(map[string]bool)
+	for _, s := range old {
+		inOld[s] = true
+	}
+	inNew := make(map[string]bool)
+	for _, s := range new {
+		inNew[s] = true
+		if !inOld[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range old {
+		if !inNew[s] {
+			removed = append(removed, s)
+		}
+	}
+	return
+}
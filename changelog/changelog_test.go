@@ -0,0 +1,175 @@
+package changelog
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const oldInput = `SPDXVersion: SPDX-1.2
+
+PackageName: lib
+PackageVersion: 1.0
+PackageLicenseDeclared: MIT
+PackageCopyrightText: <text>Copyright (c) 2014 Foo Inc.
+Copyright 2013-2014 Bar</text>
+
+PackageName: old
+PackageVersion: 0.1
+
+FileName: a.c
+LicenseConcluded: (MIT or GPL-2.0)
+FileCopyrightText: Copyright 2014 Foo Inc.
+`
+
+const newInput = `SPDXVersion: SPDX-1.2
+
+PackageName: lib
+PackageVersion: 2.0
+PackageLicenseDeclared: (MIT and GPL-3.0)
+PackageCopyrightText: <text>Copyright (c) 2014-2015 Foo Inc. All rights reserved.
+Copyright 2015 Baz</text>
+
+PackageName: new
+PackageVersion: 3.1
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseDeclared: (LGPL-2.1 or MIT)
+
+FileName: a.c
+LicenseConcluded: (GPL-2.0 or MIT)
+FileCopyrightText: Copyright 2015 Foo Inc.
+
+FileName: b.c
+LicenseConcluded: MIT
+LicenseInfoInFile: MIT
+LicenseInfoInFile: GPL-3.0
+
+LicenseID: LicenseRef-1
+LicenseName: Custom
+ExtractedText: text
+`
+
+func build(t *testing.T, input string) *spdx.Document {
+	doc, err := tag.Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return doc
+}
+
+func TestBuild(t *testing.T) {
+	c := Build(build(t, oldInput), build(t, newInput))
+	expected := &Changelog{
+		PackagesAdded: []Package{{
+			Name:             "new",
+			Version:          "3.1",
+			LicenceConcluded: "NOASSERTION",
+			LicenceDeclared:  "(LGPL-2.1 or MIT)",
+			Copyleft:         []string{"LGPL-2.1"},
+		}},
+		PackagesRemoved: []Package{{Name: "old", Version: "0.1"}},
+		VersionChanges:  []VersionChange{{Package: "lib", Old: "1.0", New: "2.0"}},
+		LicenceChanges: []LicenceChange{{
+			Element:  "package",
+			Id:       "lib",
+			Field:    "PackageLicenseDeclared",
+			Old:      "MIT",
+			New:      "(GPL-3.0 and MIT)",
+			Copyleft: []string{"GPL-3.0"},
+		}, {
+			Element:  "file",
+			Id:       "b.c",
+			Field:    "LicenseInfoInFile",
+			New:      "GPL-3.0",
+			Copyleft: []string{"GPL-3.0"},
+		}},
+		LicencesAdded: []Licence{{Id: "LicenseRef-1", Name: []string{"Custom"}}},
+		CopyrightChanges: []CopyrightChange{{
+			Element: "package",
+			Id:      "lib",
+			Added:   []string{"Baz"},
+			Removed: []string{"Bar"},
+		}},
+	}
+	if !reflect.DeepEqual(c, expected) {
+		t.Errorf("Expected %+v but found %+v", expected, c)
+	}
+}
+
+func TestBuildSame(t *testing.T) {
+	if c := Build(build(t, oldInput), build(t, oldInput)); !c.Empty() {
+		t.Errorf("Found changes %+v", c)
+	}
+}
+
+func TestHolders(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []string
+	}{
+		{"NONE", nil},
+		{"Copyright 2014 Foo", []string{"Foo"}},
+		{"(c) 2010, 2012-2014 Foo, Inc.\nsome text\n© Bar. All rights reserved.", []string{"Bar", "Foo, Inc"}},
+		{"COPYRIGHT Foo\nCopyright  Foo", []string{"Foo"}},
+	}
+	for i, test := range tests {
+		if res := Holders(test.text); !reflect.DeepEqual(res, test.expected) {
+			t.Errorf("%d: Expected %#v but found %#v", i, test.expected, res)
+		}
+	}
+}
+
+func TestNewCopyleft(t *testing.T) {
+	if res := newCopyleft("(MIT or GPL-2.0)", "(GPL-2.0 and LGPL-2.1 and Apache-2.0)"); !reflect.DeepEqual(res, []string{"LGPL-2.1"}) {
+		t.Errorf("Wrong copyleft licences %#v", res)
+	}
+	if res := newCopyleft("GPL-2.0", "MIT"); res != nil {
+		t.Errorf("Wrong copyleft licences %#v", res)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteMarkdown(buf, Build(build(t, oldInput), build(t, newInput))); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := "# SBOM changelog\n" +
+		"\n## Packages added\n\n- **new 3.1: `(LGPL-2.1 or MIT)` (new copyleft: LGPL-2.1)**\n" +
+		"\n## Packages removed\n\n- old 0.1\n" +
+		"\n## Version changes\n\n- lib: `1.0` → `2.0`\n" +
+		"\n## Licence changes\n\n- **package lib, PackageLicenseDeclared: `MIT` → `(GPL-3.0 and MIT)` (new copyleft: GPL-3.0)**\n" +
+		"- **file b.c, LicenseInfoInFile: none → `GPL-3.0` (new copyleft: GPL-3.0)**\n" +
+		"\n## New extracted licences\n\n- LicenseRef-1 (Custom)\n" +
+		"\n## Copyright holder changes\n\n- package lib: added `Baz`; removed `Bar`\n"
+	if res := buf.String(); res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+
+	buf.Reset()
+	if err := WriteMarkdown(buf, new(Changelog)); err != nil || buf.String() != "# SBOM changelog\n\nNo changes.\n" {
+		t.Errorf("Printed %#v (error %v)", buf.String(), err)
+	}
+}
+
+func TestWriteJson(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteJson(buf, new(Changelog)); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	var res map[string][]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	for _, key := range []string{"packagesAdded", "packagesRemoved", "versionChanges", "licenceChanges", "licencesAdded", "copyrightChanges"} {
+		if list, ok := res[key]; !ok || list == nil {
+			t.Errorf("Missing list %s in %s", key, buf.String())
+		}
+	}
+}
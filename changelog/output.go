@@ -0,0 +1,162 @@
+package changelog
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Output formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatJson     = "json"
+)
+
+// Write the changelog in the given format (FormatMarkdown or FormatJson).
+func Write(w io.Writer, c *Changelog, format string) error {
+	switch format {
+	case FormatMarkdown:
+		return WriteMarkdown(w, c)
+	case FormatJson:
+		return WriteJson(w, c)
+	}
+	return fmt.Errorf("Unsupported changelog format %s.", format)
+}
+
+// Write the changelog as JSON. Empty lists are written as [].
+func WriteJson(w io.Writer, c *Changelog) error {
+	res := *c
+	if res.PackagesAdded == nil {
+		res.PackagesAdded = []Package{}
+	}
+	if res.PackagesRemoved == nil {
+		res.PackagesRemoved = []Package{}
+	}
+	if res.VersionChanges == nil {
+		res.VersionChanges = []VersionChange{}
+	}
+	if res.LicenceChanges == nil {
+		res.LicenceChanges = []LicenceChange{}
+	}
+	if res.LicencesAdded == nil {
+		res.LicencesAdded = []Licence{}
+	}
+	if res.CopyrightChanges == nil {
+		res.CopyrightChanges = []CopyrightChange{}
+	}
+	data, err := json.MarshalIndent(&res, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Returns the name and the version of a package.
+func (p Package) String() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	return p.Name + " " + p.Version
+}
+
+// Returns the licence of a package: the concluded licence, or the declared
+// one if the concluded licence says nothing.
+func (p Package) licence() string {
+	if spdx.IsEmpty(p.LicenceConcluded) {
+		return p.LicenceDeclared
+	}
+	return p.LicenceConcluded
+}
+
+// Returns a value in Markdown code, or "none" if it is empty.
+func code(val string) string {
+	if val == "" {
+		return "none"
+	}
+	return "`" + strings.Replace(val, "\n", " ", -1) + "`"
+}
+
+// Returns a list of strings in Markdown code.
+func codes(vals []string) string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = code(v)
+	}
+	return strings.Join(res, ", ")
+}
+
+// Write the changelog as Markdown, with one section per kind of change.
+// Added packages and licence changes which bring new copyleft licences are in
+// bold.
+func WriteMarkdown(w io.Writer, c *Changelog) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintln(out, "# SBOM changelog")
+	if c.Empty() {
+		fmt.Fprintln(out, "\nNo changes.")
+		return out.Flush()
+	}
+
+	section := func(title string, n int) {
+		if n > 0 {
+			fmt.Fprintf(out, "\n## %s\n\n", title)
+		}
+	}
+
+	section("Packages added", len(c.PackagesAdded))
+	for _, p := range c.PackagesAdded {
+		line := p.String()
+		if lic := p.licence(); lic != "" {
+			line += ": " + code(lic)
+		}
+		if len(p.Copyleft) > 0 {
+			line = "**" + line + " (new copyleft: " + strings.Join(p.Copyleft, ", ") + ")**"
+		}
+		fmt.Fprintf(out, "- %s\n", line)
+	}
+
+	section("Packages removed", len(c.PackagesRemoved))
+	for _, p := range c.PackagesRemoved {
+		fmt.Fprintf(out, "- %s\n", p)
+	}
+
+	section("Version changes", len(c.VersionChanges))
+	for _, v := range c.VersionChanges {
+		fmt.Fprintf(out, "- %s: %s → %s\n", v.Package, code(v.Old), code(v.New))
+	}
+
+	section("Licence changes", len(c.LicenceChanges))
+	for _, l := range c.LicenceChanges {
+		line := fmt.Sprintf("%s %s, %s: %s → %s", l.Element, l.Id, l.Field, code(l.Old), code(l.New))
+		if len(l.Copyleft) > 0 {
+			line = "**" + line + " (new copyleft: " + strings.Join(l.Copyleft, ", ") + ")**"
+		}
+		fmt.Fprintf(out, "- %s\n", line)
+	}
+
+	section("New extracted licences", len(c.LicencesAdded))
+	for _, l := range c.LicencesAdded {
+		if len(l.Name) > 0 {
+			fmt.Fprintf(out, "- %s (%s)\n", l.Id, strings.Join(l.Name, ", "))
+		} else {
+			fmt.Fprintf(out, "- %s\n", l.Id)
+		}
+	}
+
+	section("Copyright holder changes", len(c.CopyrightChanges))
+	for _, ch := range c.CopyrightChanges {
+		var parts []string
+		if len(ch.Added) > 0 {
+			parts = append(parts, "added "+codes(ch.Added))
+		}
+		if len(ch.Removed) > 0 {
+			parts = append(parts, "removed "+codes(ch.Removed))
+		}
+		fmt.Fprintf(out, "- %s %s: %s\n", ch.Element, ch.Id, strings.Join(parts, "; "))
+	}
+	return out.Flush()
+}
@@ -0,0 +1,120 @@
+// Package cyclonedx converts SPDX documents to CycloneDX bills of materials
+// and back, and reads and writes the CycloneDX JSON and XML formats (spec
+// version 1.6 is written).
+//
+// Packages become "library" components, with their files as nested "file"
+// components; files which are in no package are top level components.
+// Checksums become hashes and file dependencies become the BOM dependencies.
+// The tools in the document creators become the metadata tools and the people
+// and organisations its authors.
+//
+// CycloneDX has no equivalent for a number of SPDX properties (e.g. package
+// verification codes, file types or reviews). FromSpdx leaves them out and
+// lists them as a []*Loss. NOASSERTION and NONE values are always left out,
+// CycloneDX expresses them by not having the property. As that reads back as
+// NOASSERTION, NONE values are listed as losses too.
+//
+// ToSpdx converts a Bom, read with ReadJson or ReadXml, to a SPDX-1.2
+// document the other way round.
+package cyclonedx
+
+import "github.com/spdx/tools-go/spdx"
+
+// The CycloneDX specification version written.
+const SpecVersion = "1.6"
+
+// The XML namespace of CycloneDX documents.
+const xmlNs = "http://cyclonedx.org/schema/bom/" + SpecVersion
+
+// Component types.
+const (
+	TypeApplication = "application"
+	TypeLibrary     = "library"
+	TypeFile        = "file"
+)
+
+// Licence acknowledgements. A licence without acknowledgement is both
+// concluded and declared.
+const (
+	AckConcluded = "concluded"
+	AckDeclared  = "declared"
+)
+
+// External reference types.
+const (
+	RefDistribution = "distribution"
+	RefWebsite      = "website"
+)
+
+// Bom is a CycloneDX bill of materials, with the parts of the CycloneDX model
+// SPDX documents are converted to and from. Every element has the
+// *spdx.Meta with the lines it spans in the input, if it was read.
+type Bom struct {
+	Version      int
+	Metadata     *Metadata
+	Components   []*Component
+	Dependencies []*Dependency
+	*spdx.Meta
+}
+
+// Metadata of a Bom.
+type Metadata struct {
+	Timestamp string       // Creation date, as in the SPDX document.
+	Tools     []*Component // Tools which created the BOM, as application components.
+	Authors   []*Contact   // People and organisations which created the BOM.
+	Component *Component   // The component the BOM describes, if any.
+	*spdx.Meta
+}
+
+// A person or organisation.
+type Contact struct {
+	Name, Email string
+	*spdx.Meta
+}
+
+// Component of a Bom.
+type Component struct {
+	Type        string
+	BomRef      string     // Unique ID of the component in the Bom.
+	Supplier    *Contact   // Supplier organisation.
+	Author      string     // Person or organisation which created the component.
+	Name        string     // Component name; the file name for files.
+	Version     string     // Component version.
+	Description string     // Component description.
+	Hashes      []*Hash    // Hashes of the component.
+	Licences    []*Licence // Licences, or one licence with an Expression.
+	Copyright   string     // Copyright text.
+	References  []*Reference
+	Components  []*Component // Nested components, e.g. the files of a package.
+	*spdx.Meta
+}
+
+// Hash of a component. Alg is the CycloneDX algorithm name (e.g. SHA-1).
+type Hash struct {
+	Alg, Content string
+	*spdx.Meta
+}
+
+// Licence of a component. Exactly one of Id, Name and Expression is set:
+// Id for licences in the SPDX Licence List, Name for other licences (e.g.
+// LicenseRef-) and Expression for SPDX licence expressions.
+type Licence struct {
+	Id, Name, Expression string
+	Text                 string // Licence text, for Name licences.
+	Url                  string // Licence URL, for Name licences.
+	Acknowledgement      string // AckConcluded, AckDeclared or empty for both.
+	*spdx.Meta
+}
+
+// External reference of a component.
+type Reference struct {
+	Type, Url string
+	*spdx.Meta
+}
+
+// The components the component with BomRef Ref depends on.
+type Dependency struct {
+	Ref       string
+	DependsOn []string
+	*spdx.Meta
+}
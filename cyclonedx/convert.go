@@ -0,0 +1,419 @@
+package cyclonedx
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Loss is a SPDX value left out of a converted Bom because CycloneDX has no
+// equivalent for it.
+type Loss struct {
+	Element    string // The SPDX element, e.g. `package "foo"`.
+	Property   string // The Tag format name of the property.
+	Value      string // The value left out.
+	*spdx.Meta        // The metadata of the value, if any.
+}
+
+// Returns the element and property of the Loss.
+func (l *Loss) String() string {
+	return l.Element + ": " + l.Property
+}
+
+// CycloneDX names of the SPDX checksum algorithms.
+var hashAlgs = map[string]string{
+	"MD5":     "MD5",
+	"SHA1":    "SHA-1",
+	"SHA256":  "SHA-256",
+	"SHA-256": "SHA-256",
+	"SHA384":  "SHA-384",
+	"SHA-384": "SHA-384",
+	"SHA512":  "SHA-512",
+	"SHA-512": "SHA-512",
+}
+
+// Converts an SPDX document to a Bom.
+type converter struct {
+	bom       *Bom
+	losses    []*Loss
+	refs      map[*spdx.File]string             // BomRef of the files converted
+	extracted map[string]*spdx.ExtractedLicence // by licence ID
+	written   map[string]bool                   // extracted licences with their text in the Bom
+}
+
+// Converts `doc` to a Bom. Returns the Bom and the values which could not be
+// converted.
+//
+// The SPDX version and data licence of the document are not converted, nor
+// reported as lost, as they only apply to SPDX documents.
+func FromSpdx(doc *spdx.Document) (*Bom, []*Loss) {
+	c := &converter{
+		bom:       &Bom{Version: 1},
+		refs:      make(map[*spdx.File]string),
+		extracted: make(map[string]*spdx.ExtractedLicence),
+		written:   make(map[string]bool),
+	}
+	for _, lic := range doc.ExtractedLicences {
+		c.extracted[lic.Id.Val] = lic
+	}
+
+	c.lose("document", "DocumentComment", doc.Comment)
+	c.metadata(doc.CreationInfo)
+
+	for i, pkg := range doc.Packages {
+		c.bom.Components = append(c.bom.Components, c.pkg(pkg, i+1))
+	}
+	for _, file := range doc.Files {
+		if _, ok := c.refs[file]; !ok {
+			c.bom.Components = append(c.bom.Components, c.file(file))
+		}
+	}
+
+	c.dependencies(doc)
+	c.extractedLicences(doc.ExtractedLicences)
+	for _, rev := range doc.Reviews {
+		el := "review by " + rev.Reviewer.V()
+		c.lose(el, "Reviewer", rev.Reviewer)
+		c.lose(el, "ReviewDate", rev.Date)
+		c.lose(el, "ReviewComment", rev.Comment)
+	}
+	return c.bom, c.losses
+}
+
+// Returns `val` or "" if it is NOASSERTION or NONE.
+func known(val string) string {
+	if val == "NOASSERTION" || val == "NONE" {
+		return ""
+	}
+	return val
+}
+
+// Record a Loss of `val`, if it isn't empty or NOASSERTION.
+func (c *converter) lose(element, property string, val spdx.Value) {
+	if val == nil || val.V() == "" || val.V() == spdx.NOASSERTION {
+		return
+	}
+	c.losses = append(c.losses, &Loss{element, property, val.V(), val.M()})
+}
+
+// Record a Loss of `val`, if it is NONE. CycloneDX has no value for NONE, so
+// it would be read back as NOASSERTION.
+func (c *converter) loseNone(element, property string, val spdx.Value) {
+	if val != nil && val.V() == spdx.NONE {
+		c.lose(element, property, val)
+	}
+}
+
+// Returns the name of a creator, or its value if it doesn't have the
+// `what: name (email)` format.
+func creatorName(cr spdx.ValueCreator) string {
+	if cr.Name() != "" {
+		return cr.Name()
+	}
+	return cr.V()
+}
+
+// Returns the Contact of a creator, or nil if it is NOASSERTION or NONE.
+func contact(cr spdx.ValueCreator) *Contact {
+	if known(cr.V()) == "" {
+		return nil
+	}
+	return &Contact{Name: creatorName(cr), Email: cr.Email()}
+}
+
+// Returns the application Component of a tool name, with the version
+// split from the name if it is of the form name-1.0.
+func tool(name string) *Component {
+	res := &Component{Type: TypeApplication, Name: name}
+	if i := strings.LastIndex(name, "-"); i > 0 && i+1 < len(name) && name[i+1] >= '0' && name[i+1] <= '9' {
+		res.Name, res.Version = name[:i], name[i+1:]
+	}
+	return res
+}
+
+// Convert the creation info to the Bom metadata.
+func (c *converter) metadata(ci *spdx.CreationInfo) {
+	if ci == nil {
+		return
+	}
+	meta := &Metadata{Timestamp: ci.Created.V()}
+	for _, cr := range ci.Creator {
+		if cr.What() == "Tool" {
+			meta.Tools = append(meta.Tools, tool(cr.Name()))
+		} else if ct := contact(cr); ct != nil {
+			meta.Authors = append(meta.Authors, ct)
+		}
+	}
+	c.bom.Metadata = meta
+	c.lose("creation info", "CreatorComment", ci.Comment)
+	c.lose("creation info", "LicenseListVersion", ci.LicenceListVersion)
+}
+
+// Returns the CycloneDX hashes of `cksum`.
+func (c *converter) hashes(cksum *spdx.Checksum, element, property string) []*Hash {
+	if cksum == nil || cksum.Value.Val == "" {
+		return nil
+	}
+	alg, ok := hashAlgs[strings.ToUpper(cksum.Algo.Val)]
+	if !ok {
+		c.losses = append(c.losses, &Loss{element, property, cksum.Algo.Val + ": " + cksum.Value.Val, cksum.Meta})
+		return nil
+	}
+	return []*Hash{{Alg: alg, Content: cksum.Value.Val}}
+}
+
+// Returns the licence expression of `lic`, with AND and OR operators.
+func expression(lic spdx.AnyLicence) string {
+	var members []spdx.AnyLicence
+	op := ""
+	switch l := lic.(type) {
+	case spdx.ConjunctiveLicenceSet:
+		members, op = l.Members, " AND "
+	case spdx.DisjunctiveLicenceSet:
+		members, op = l.Members, " OR "
+	default:
+		return lic.LicenceId()
+	}
+	strs := make([]string, len(members))
+	for i, m := range members {
+		strs[i] = expression(m)
+		if isSet(m) {
+			strs[i] = "(" + strs[i] + ")"
+		}
+	}
+	return strings.Join(strs, op)
+}
+
+// Checks if `lic` is a licence set.
+func isSet(lic spdx.AnyLicence) bool {
+	switch lic.(type) {
+	case spdx.ConjunctiveLicenceSet, spdx.DisjunctiveLicenceSet:
+		return true
+	}
+	return false
+}
+
+// Returns the Licence of `lic`, which must not be a licence set. Licence
+// references have the text and first cross reference of their extracted
+// licence.
+func (c *converter) licence(lic spdx.AnyLicence, ack string) *Licence {
+	id := lic.LicenceId()
+	if l, ok := lic.(spdx.Licence); ok && !l.IsReference() {
+		return &Licence{Id: id, Acknowledgement: ack}
+	}
+	res := &Licence{Name: id, Acknowledgement: ack}
+	if ext := c.extracted[id]; ext != nil {
+		res.Text = ext.Text.Val
+		if len(ext.CrossReference) > 0 {
+			res.Url = ext.CrossReference[0].Val
+		}
+		c.written[id] = true
+	}
+	return res
+}
+
+// Returns the licences of a component with the concluded licence `concluded`
+// and the declared licences `declared`, leaving out NOASSERTION and NONE.
+//
+// CycloneDX components have either licences or one licence expression. If
+// there is any licence set, only the expression of the concluded licence (or
+// the first declared licence if there is no concluded licence) is returned
+// and the other licences are returned as lost.
+func (c *converter) licences(concluded spdx.AnyLicence, declared []spdx.AnyLicence) (res []*Licence, lost []spdx.AnyLicence) {
+	if concluded != nil && known(concluded.LicenceId()) == "" {
+		concluded = nil
+	}
+	decl := make([]spdx.AnyLicence, 0, len(declared))
+	for _, lic := range declared {
+		if lic != nil && known(lic.LicenceId()) != "" {
+			decl = append(decl, lic)
+		}
+	}
+
+	if concluded != nil && len(decl) == 1 && spdx.SameLicence(concluded, decl[0]) {
+		if isSet(concluded) {
+			return []*Licence{{Expression: expression(concluded)}}, nil
+		}
+		return []*Licence{c.licence(concluded, "")}, nil
+	}
+
+	sets := concluded != nil && isSet(concluded)
+	for _, lic := range decl {
+		sets = sets || isSet(lic)
+	}
+	if sets {
+		if concluded != nil {
+			return []*Licence{{Expression: expression(concluded), Acknowledgement: AckConcluded}}, decl
+		}
+		return []*Licence{{Expression: expression(decl[0]), Acknowledgement: AckDeclared}}, decl[1:]
+	}
+
+	if concluded != nil {
+		res = append(res, c.licence(concluded, AckConcluded))
+	}
+	for _, lic := range decl {
+		res = append(res, c.licence(lic, AckDeclared))
+	}
+	return res, nil
+}
+
+// Returns the References of the URLs, leaving out NOASSERTION and NONE.
+func references(download, homepage string) (res []*Reference) {
+	if u := known(download); u != "" {
+		res = append(res, &Reference{Type: RefDistribution, Url: u})
+	}
+	if u := known(homepage); u != "" {
+		res = append(res, &Reference{Type: RefWebsite, Url: u})
+	}
+	return res
+}
+
+// Returns the Component of the package. `n` is its position in the
+// document, used for its BomRef.
+func (c *converter) pkg(pkg *spdx.Package, n int) *Component {
+	el := fmt.Sprintf("package %q", pkg.Name.Val)
+	comp := &Component{
+		Type:        TypeLibrary,
+		BomRef:      "package-" + strconv.Itoa(n),
+		Supplier:    contact(pkg.Supplier),
+		Name:        pkg.Name.Val,
+		Version:     pkg.Version.Val,
+		Description: pkg.Description.Val,
+		Hashes:      c.hashes(pkg.Checksum, el, "PackageChecksum"),
+		Copyright:   known(pkg.CopyrightText.Val),
+		References:  references(pkg.DownloadLocation.Val, pkg.HomePage.Val),
+	}
+	if ct := contact(pkg.Originator); ct != nil {
+		comp.Author = ct.Name
+	}
+	if comp.Description == "" {
+		comp.Description = pkg.Summary.Val
+	} else {
+		c.lose(el, "PackageSummary", pkg.Summary)
+	}
+	c.loseNone(el, "PackageDownloadLocation", pkg.DownloadLocation)
+	c.loseNone(el, "PackageHomePage", pkg.HomePage)
+	c.loseNone(el, "PackageCopyrightText", pkg.CopyrightText)
+
+	var lost []spdx.AnyLicence
+	comp.Licences, lost = c.licences(pkg.LicenceConcluded, []spdx.AnyLicence{pkg.LicenceDeclared})
+	c.loseNone(el, "PackageLicenseConcluded", pkg.LicenceConcluded)
+	c.loseNone(el, "PackageLicenseDeclared", pkg.LicenceDeclared)
+	for _, lic := range lost {
+		c.lose(el, "PackageLicenseDeclared", lic)
+	}
+	for _, lic := range pkg.LicenceInfoFromFiles {
+		c.lose(el, "PackageLicenseInfoFromFiles", lic)
+	}
+
+	c.lose(el, "PackageFileName", pkg.FileName)
+	if vc := pkg.VerificationCode; vc != nil {
+		val := vc.Value.Val
+		if len(vc.ExcludedFiles) > 0 {
+			val += " (" + spdx.Join(vc.ExcludedFiles, ", ") + ")"
+		}
+		c.lose(el, "PackageVerificationCode", spdx.Str(val, vc.Meta))
+	}
+	c.lose(el, "PackageSourceInfo", pkg.SourceInfo)
+	c.lose(el, "PackageLicenseComments", pkg.LicenceComments)
+
+	for _, file := range pkg.Files {
+		if _, ok := c.refs[file]; ok {
+			c.lose(el, "FileName", file.Name)
+			continue
+		}
+		comp.Components = append(comp.Components, c.file(file))
+	}
+	return comp
+}
+
+// Returns the Component of the file. The licence info in file are its
+// declared licences.
+func (c *converter) file(file *spdx.File) *Component {
+	el := fmt.Sprintf("file %q", file.Name.Val)
+	ref := "file-" + strconv.Itoa(len(c.refs)+1)
+	c.refs[file] = ref
+	comp := &Component{
+		Type:      TypeFile,
+		BomRef:    ref,
+		Name:      file.Name.Val,
+		Hashes:    c.hashes(file.Checksum, el, "FileChecksum"),
+		Copyright: known(file.CopyrightText.Val),
+	}
+
+	c.loseNone(el, "FileCopyrightText", file.CopyrightText)
+
+	var lost []spdx.AnyLicence
+	comp.Licences, lost = c.licences(file.LicenceConcluded, file.LicenceInfoInFile)
+	c.loseNone(el, "LicenseConcluded", file.LicenceConcluded)
+	for _, lic := range file.LicenceInfoInFile {
+		c.loseNone(el, "LicenseInfoInFile", lic)
+	}
+	for _, lic := range lost {
+		c.lose(el, "LicenseInfoInFile", lic)
+	}
+
+	c.lose(el, "FileType", file.Type)
+	c.lose(el, "LicenseComments", file.LicenceComments)
+	c.lose(el, "FileNotice", file.Notice)
+	for _, contrib := range file.Contributor {
+		c.lose(el, "FileContributor", contrib)
+	}
+	for _, artif := range file.ArtifactOf {
+		c.lose(el, "ArtifactOfProjectName", artif.Name)
+	}
+	c.lose(el, "FileComment", file.Comment)
+	return comp
+}
+
+// Convert the file dependencies of the files in the Bom. Dependencies on
+// files which are not in the document are lost.
+func (c *converter) dependencies(doc *spdx.Document) {
+	files := make([]*spdx.File, 0, len(c.refs))
+	for _, pkg := range doc.Packages {
+		files = append(files, pkg.Files...)
+	}
+	files = append(files, doc.Files...)
+
+	done := make(map[*spdx.File]bool)
+	for _, file := range files {
+		if done[file] || len(file.Dependency) == 0 {
+			continue
+		}
+		done[file] = true
+		dep := &Dependency{Ref: c.refs[file]}
+		for _, d := range file.Dependency {
+			if ref, ok := c.refs[d]; ok {
+				dep.DependsOn = append(dep.DependsOn, ref)
+			} else {
+				c.lose(fmt.Sprintf("file %q", file.Name.Val), "FileDependency", d.Name)
+			}
+		}
+		if len(dep.DependsOn) > 0 {
+			c.bom.Dependencies = append(c.bom.Dependencies, dep)
+		}
+	}
+}
+
+// Record the losses of the extracted licences. Their texts and first cross
+// references are only lost if they are not in the Bom.
+func (c *converter) extractedLicences(lics []*spdx.ExtractedLicence) {
+	for _, lic := range lics {
+		el := "extracted licence " + lic.Id.Val
+		refs := lic.CrossReference
+		if !c.written[lic.Id.Val] {
+			c.lose(el, "ExtractedText", lic.Text)
+		} else if len(refs) > 0 {
+			refs = refs[1:]
+		}
+		for _, name := range lic.Name {
+			c.lose(el, "LicenseName", name)
+		}
+		for _, ref := range refs {
+			c.lose(el, "LicenseCrossReference", ref)
+		}
+		c.lose(el, "LicenseComment", lic.Comment)
+	}
+}
@@ -0,0 +1,216 @@
+package cyclonedx
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDocument = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+DocumentComment: <text>A comment.</text>
+Creator: Tool: spdx-go-1.0
+Creator: Person: Jane Doe (jane@example.com)
+Created: 2014-08-01T00:00:00Z
+
+PackageName: pkg
+PackageVersion: 1.0
+PackageSupplier: Organization: ACME (acme@example.com)
+PackageOriginator: Person: John Doe
+PackageDownloadLocation: http://example.com/pkg-1.0.tar.gz
+PackageHomePage: NOASSERTION
+PackageVerificationCode: 4e3211c67a2d28fced849ee1bb76e7391b93feba
+PackageChecksum: SHA1: 85ed0817af83a24ad8da68c2b5094de69833983c
+PackageSummary: <text>Summary.</text>
+PackageLicenseConcluded: (Apache-2.0 and MIT)
+PackageLicenseDeclared: MIT
+PackageLicenseInfoFromFiles: MIT
+PackageCopyrightText: NOASSERTION
+
+FileName: a.c
+FileType: SOURCE
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12
+LicenseConcluded: MIT
+LicenseInfoInFile: MIT
+FileCopyrightText: Copyright Jane Doe
+FileDependency: b.c
+
+FileName: b.c
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb13
+LicenseConcluded: LicenseRef-1
+LicenseInfoInFile: GPL-2.0
+FileCopyrightText: NONE
+
+LicenseID: LicenseRef-1
+ExtractedText: <text>Licence text.</text>
+LicenseName: Licence 1
+
+Reviewer: Person: Joe Reviewer
+ReviewDate: 2010-02-10T00:00:00Z
+`
+
+// Build the test document and convert it.
+func testBom(t *testing.T) (*Bom, []*Loss) {
+	doc, err := tag.Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return FromSpdx(doc)
+}
+
+func TestFromSpdx(t *testing.T) {
+	bom, _ := testBom(t)
+
+	meta := bom.Metadata
+	if meta == nil || meta.Timestamp != "2014-08-01T00:00:00Z" ||
+		len(meta.Tools) != 1 || meta.Tools[0].Name != "spdx-go" || meta.Tools[0].Version != "1.0" ||
+		len(meta.Authors) != 1 || *meta.Authors[0] != (Contact{Name: "Jane Doe", Email: "jane@example.com"}) {
+		t.Errorf("Wrong metadata: %+v", meta)
+	}
+
+	if len(bom.Components) != 3 {
+		t.Fatalf("Wrong number of components: %d", len(bom.Components))
+	}
+	pkg := bom.Components[0]
+	if pkg.Type != TypeLibrary || pkg.Name != "pkg" || pkg.Version != "1.0" || pkg.Description != "Summary." ||
+		pkg.Author != "John Doe" || *pkg.Supplier != (Contact{Name: "ACME", Email: "acme@example.com"}) || pkg.Copyright != "" {
+		t.Errorf("Wrong package component: %+v", pkg)
+	}
+	if len(pkg.Hashes) != 1 || *pkg.Hashes[0] != (Hash{Alg: "SHA-1", Content: "85ed0817af83a24ad8da68c2b5094de69833983c"}) {
+		t.Errorf("Wrong package hashes: %v", pkg.Hashes)
+	}
+	if len(pkg.References) != 1 || *pkg.References[0] != (Reference{Type: RefDistribution, Url: "http://example.com/pkg-1.0.tar.gz"}) {
+		t.Errorf("Wrong package references: %v", pkg.References)
+	}
+	if len(pkg.Licences) != 1 || *pkg.Licences[0] != (Licence{Expression: "Apache-2.0 AND MIT", Acknowledgement: AckConcluded}) {
+		t.Errorf("Wrong package licences: %+v", pkg.Licences)
+	}
+
+	a, b := bom.Components[1], bom.Components[2]
+	if a.Type != TypeFile || a.BomRef != "file-1" || a.Name != "a.c" || a.Copyright != "Copyright Jane Doe" {
+		t.Errorf("Wrong file component: %+v", a)
+	}
+	if len(a.Licences) != 1 || *a.Licences[0] != (Licence{Id: "MIT"}) {
+		t.Errorf("Wrong file licences: %+v", a.Licences)
+	}
+	expected := []Licence{
+		{Name: "LicenseRef-1", Text: "Licence text.", Acknowledgement: AckConcluded},
+		{Id: "GPL-2.0", Acknowledgement: AckDeclared},
+	}
+	if len(b.Licences) != len(expected) {
+		t.Fatalf("Wrong file licences: %+v", b.Licences)
+	}
+	for i, lic := range b.Licences {
+		if *lic != expected[i] {
+			t.Errorf("Wrong file licence: %+v (expected %+v)", lic, expected[i])
+		}
+	}
+
+	if len(bom.Dependencies) != 1 || bom.Dependencies[0].Ref != "file-1" ||
+		len(bom.Dependencies[0].DependsOn) != 1 || bom.Dependencies[0].DependsOn[0] != "file-2" {
+		t.Errorf("Wrong dependencies: %+v", bom.Dependencies)
+	}
+}
+
+func TestFromSpdxLosses(t *testing.T) {
+	_, losses := testBom(t)
+	expected := []string{
+		"document: DocumentComment",
+		`package "pkg": PackageLicenseDeclared`,
+		`package "pkg": PackageLicenseInfoFromFiles`,
+		`package "pkg": PackageVerificationCode`,
+		`file "a.c": FileType`,
+		`file "b.c": FileCopyrightText`,
+		"extracted licence LicenseRef-1: LicenseName",
+		"review by Person: Joe Reviewer: Reviewer",
+		"review by Person: Joe Reviewer: ReviewDate",
+	}
+	if len(losses) != len(expected) {
+		t.Errorf("Wrong number of losses: %d (expected %d)", len(losses), len(expected))
+	}
+	for i, loss := range losses {
+		if i < len(expected) && loss.String() != expected[i] {
+			t.Errorf("Wrong loss: %s (expected %s)", loss, expected[i])
+		}
+		if loss.Meta == nil || loss.LineStart == 0 {
+			t.Errorf("No line for loss %s", loss)
+		}
+	}
+}
+
+func TestFromSpdxNoLicenceSets(t *testing.T) {
+	pkg := &spdx.Package{
+		Name:             spdx.Str("pkg", nil),
+		LicenceConcluded: spdx.NewLicence("MIT", nil),
+		LicenceDeclared:  spdx.NewLicence("MIT", nil),
+	}
+	file := &spdx.File{
+		Name:              spdx.Str("a.c", nil),
+		Checksum:          &spdx.Checksum{Algo: spdx.Str("SHA3", nil), Value: spdx.Str("00", nil)},
+		LicenceConcluded:  spdx.NewLicence("NOASSERTION", nil),
+		LicenceInfoInFile: []spdx.AnyLicence{spdx.NewLicence("NONE", nil)},
+		Dependency:        []*spdx.File{{Name: spdx.Str("missing.c", nil)}},
+	}
+	bom, losses := FromSpdx(&spdx.Document{Packages: []*spdx.Package{pkg}, Files: []*spdx.File{file}})
+
+	if len(bom.Components) != 2 {
+		t.Fatalf("Wrong number of components: %d", len(bom.Components))
+	}
+	if lics := bom.Components[0].Licences; len(lics) != 1 || *lics[0] != (Licence{Id: "MIT"}) {
+		t.Errorf("Same concluded and declared licences not merged: %+v", lics)
+	}
+	if f := bom.Components[1]; f.Type != TypeFile || len(f.Licences) != 0 || len(f.Hashes) != 0 {
+		t.Errorf("Wrong file component: %+v", f)
+	}
+	if bom.Metadata != nil || len(bom.Dependencies) != 0 {
+		t.Errorf("Unexpected metadata or dependencies: %+v %+v", bom.Metadata, bom.Dependencies)
+	}
+	if len(losses) != 3 || losses[0].String() != `file "a.c": FileChecksum` ||
+		losses[1].String() != `file "a.c": LicenseInfoInFile` || losses[1].Value != "NONE" ||
+		losses[2].String() != `file "a.c": FileDependency` {
+		t.Errorf("Wrong losses: %v", losses)
+	}
+}
+
+func TestFromSpdxNone(t *testing.T) {
+	pkg := &spdx.Package{
+		Name:             spdx.Str("pkg", nil),
+		DownloadLocation: spdx.Str("NONE", nil),
+		HomePage:         spdx.Str("NOASSERTION", nil),
+		CopyrightText:    spdx.Str("NONE", nil),
+		LicenceConcluded: spdx.NewLicence("NONE", nil),
+		LicenceDeclared:  spdx.NewLicence("NOASSERTION", nil),
+	}
+	bom, losses := FromSpdx(&spdx.Document{Packages: []*spdx.Package{pkg}})
+
+	if c := bom.Components[0]; c.Copyright != "" || len(c.References) != 0 || len(c.Licences) != 0 {
+		t.Errorf("Wrong package component: %+v", c)
+	}
+	expected := []string{
+		`package "pkg": PackageDownloadLocation`,
+		`package "pkg": PackageCopyrightText`,
+		`package "pkg": PackageLicenseConcluded`,
+	}
+	if len(losses) != len(expected) {
+		t.Fatalf("Expected %v but found %v", expected, losses)
+	}
+	for i, loss := range losses {
+		if loss.String() != expected[i] || loss.Value != "NONE" {
+			t.Errorf("Expected %s: NONE but found %s: %s", expected[i], loss, loss.Value)
+		}
+	}
+}
+
+func TestExpression(t *testing.T) {
+	lic := spdx.NewDisjunctiveSet(nil,
+		spdx.NewLicence("MIT", nil),
+		spdx.NewConjunctiveSet(nil, spdx.NewLicence("Apache-2.0", nil), &spdx.ExtractedLicence{Id: spdx.Str("LicenseRef-1", nil)}),
+	)
+	if expr := expression(lic); expr != "MIT OR (Apache-2.0 AND LicenseRef-1)" {
+		t.Errorf("Wrong expression: %s", expr)
+	}
+}
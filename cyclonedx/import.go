@@ -0,0 +1,505 @@
+package cyclonedx
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The creator written in the CreationInfo of documents converted from BOMs
+// with no tools and authors.
+var Creator = "Tool: spdx-go"
+
+// SPDX names of the CycloneDX hash algorithms.
+var checksumAlgos = map[string]string{
+	"MD5":     "MD5",
+	"SHA-1":   "SHA1",
+	"SHA-256": "SHA256",
+	"SHA-384": "SHA384",
+	"SHA-512": "SHA512",
+}
+
+// Characters not allowed in licence reference IDs.
+var invalidRefChars = regexp.MustCompile("[^a-zA-Z0-9.+-]+")
+
+// Converts a Bom to an SPDX document.
+type importer struct {
+	doc       *spdx.Document
+	files     map[string]*spdx.File             // by BomRef
+	extracted map[string]*spdx.ExtractedLicence // by licence ID
+	generated map[string]string                 // generated licence IDs, by name and text
+}
+
+// Converts `bom` to an SPDX-1.2 document. The metadata component and the
+// components become packages, except components of type "file" which become
+// the files of the package they are nested in, or of the document.
+//
+// Values CycloneDX does not have are NOASSERTION and the package
+// verification codes are computed from the SHA-1 hashes of the files, so
+// that the document can be validated. The files without a SHA-1 hash are
+// excluded from the verification code. Packages without files have no
+// verification code.
+//
+// Licences are the concluded or declared licences depending on their
+// acknowledgement, or both without one. Multiple licences are a choice (a
+// DisjunctiveLicenceSet). Licences with a name which is not a LicenseRef- ID
+// and licences with exceptions ("WITH") become ExtractedLicences with
+// generated LicenseRef- IDs.
+//
+// If there is an invalid licence expression, the error is a
+// *spdx.ParseError.
+func ToSpdx(bom *Bom) (*spdx.Document, error) {
+	im := &importer{
+		doc: &spdx.Document{
+			SpecVersion: spdx.Str("SPDX-1.2", nil),
+			DataLicence: spdx.Str(spdx.DATA_LICENCE_TAG, nil),
+			Meta:        bom.Meta,
+		},
+		files:     make(map[string]*spdx.File),
+		extracted: make(map[string]*spdx.ExtractedLicence),
+		generated: make(map[string]string),
+	}
+	im.creationInfo(bom.Metadata)
+
+	comps := bom.Components
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		comps = append([]*Component{bom.Metadata.Component}, comps...)
+	}
+	for _, comp := range comps {
+		if err := im.component(comp, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, dep := range bom.Dependencies {
+		file := im.files[dep.Ref]
+		if file == nil {
+			continue
+		}
+		for _, ref := range dep.DependsOn {
+			if d := im.files[ref]; d != nil {
+				file.Dependency = append(file.Dependency, d)
+			}
+		}
+	}
+
+	for _, pkg := range im.doc.Packages {
+		pkg.VerificationCode = verificationCode(pkg.Files, pkg.Meta)
+	}
+	return im.doc, nil
+}
+
+// Returns a NOASSERTION licence.
+func noAssertion(m *spdx.Meta) spdx.Licence {
+	return spdx.NewLicence(spdx.NOASSERTION, m)
+}
+
+// Returns `val` or NOASSERTION if it is empty.
+func orNoAssertion(val string, m *spdx.Meta) spdx.ValueStr {
+	if val == "" {
+		val = spdx.NOASSERTION
+	}
+	return spdx.Str(val, m)
+}
+
+// Returns the creator value of a contact.
+func creator(what string, ct *Contact) string {
+	if ct.Email != "" {
+		return what + ": " + ct.Name + " (" + ct.Email + ")"
+	}
+	return what + ": " + ct.Name
+}
+
+// Convert the Bom metadata to the CreationInfo. The creation date is the
+// current time if the Bom has no valid timestamp.
+func (im *importer) creationInfo(meta *Metadata) {
+	ci := &spdx.CreationInfo{}
+	created := time.Now()
+	if meta != nil {
+		ci.Meta = meta.Meta
+		for _, tool := range meta.Tools {
+			name := tool.Name
+			if tool.Version != "" {
+				name += "-" + tool.Version
+			}
+			ci.Creator = append(ci.Creator, spdx.NewValueCreator("Tool: "+name, tool.Meta))
+		}
+		for _, a := range meta.Authors {
+			ci.Creator = append(ci.Creator, spdx.NewValueCreator(creator("Person", a), a.Meta))
+		}
+		if t, err := time.Parse(time.RFC3339, meta.Timestamp); err == nil {
+			created = t
+		}
+	}
+	if len(ci.Creator) == 0 {
+		ci.Creator = []spdx.ValueCreator{spdx.NewValueCreator(Creator, nil)}
+	}
+	ci.Created = spdx.NewValueDate(created.UTC().Format("2006-01-02T15:04:05Z"), ci.Meta)
+	im.doc.CreationInfo = ci
+}
+
+// Returns the checksum of the hashes: the SHA-1 hash if there is one,
+// otherwise the first hash.
+func checksum(hashes []*Hash) *spdx.Checksum {
+	var res *Hash
+	for _, h := range hashes {
+		if h.Alg == "SHA-1" {
+			res = h
+			break
+		}
+		if res == nil {
+			res = h
+		}
+	}
+	if res == nil {
+		return nil
+	}
+	algo, ok := checksumAlgos[res.Alg]
+	if !ok {
+		algo = res.Alg
+	}
+	return &spdx.Checksum{
+		Algo:  spdx.Str(algo, res.Meta),
+		Value: spdx.Str(strings.ToLower(res.Content), res.Meta),
+		Meta:  res.Meta,
+	}
+}
+
+// Compute the package verification code of the files: the SHA1 of the sorted
+// and concatenated SHA1 checksums of the files. Files without a SHA1 checksum
+// are excluded. Returns nil if there are no files, as there is nothing to
+// compute the code from.
+func verificationCode(files []*spdx.File, m *spdx.Meta) *spdx.VerificationCode {
+	if len(files) == 0 {
+		return nil
+	}
+	vc := &spdx.VerificationCode{Meta: m}
+	sums := make([]string, 0, len(files))
+	for _, f := range files {
+		if f.Checksum != nil && f.Checksum.Algo.Val == "SHA1" {
+			sums = append(sums, f.Checksum.Value.Val)
+		} else {
+			vc.ExcludedFiles = append(vc.ExcludedFiles, spdx.Str(f.Name.Val, f.Meta))
+		}
+	}
+	sort.Strings(sums)
+	sum := sha1.Sum([]byte(strings.Join(sums, "")))
+	vc.Value = spdx.Str(hex.EncodeToString(sum[:]), m)
+	return vc
+}
+
+// Returns the URL of the first reference of type `typ`.
+func reference(refs []*Reference, typ string) string {
+	for _, ref := range refs {
+		if ref.Type == typ {
+			return ref.Url
+		}
+	}
+	return ""
+}
+
+// Convert a component and its nested components. Files are added to `pkg`,
+// or the document if it is nil.
+func (im *importer) component(comp *Component, pkg *spdx.Package) error {
+	if comp.Type == TypeFile {
+		file, err := im.file(comp)
+		if err != nil {
+			return err
+		}
+		if pkg != nil {
+			pkg.Files = append(pkg.Files, file)
+		} else {
+			im.doc.Files = append(im.doc.Files, file)
+		}
+		if comp.BomRef != "" {
+			im.files[comp.BomRef] = file
+		}
+		pkg = nil
+	} else {
+		var err error
+		if pkg, err = im.pkg(comp); err != nil {
+			return err
+		}
+		im.doc.Packages = append(im.doc.Packages, pkg)
+	}
+
+	for _, c := range comp.Components {
+		if err := im.component(c, pkg); err != nil {
+			return err
+		}
+	}
+	if pkg != nil {
+		pkg.LicenceInfoFromFiles = licenceInfoFromFiles(pkg)
+	}
+	return nil
+}
+
+// Returns the Package of a component, without files.
+func (im *importer) pkg(comp *Component) (*spdx.Package, error) {
+	m := comp.Meta
+	pkg := &spdx.Package{
+		Name:             spdx.Str(comp.Name, m),
+		Version:          spdx.Str(comp.Version, m),
+		DownloadLocation: orNoAssertion(reference(comp.References, RefDistribution), m),
+		HomePage:         spdx.Str(reference(comp.References, RefWebsite), m),
+		Checksum:         checksum(comp.Hashes),
+		CopyrightText:    orNoAssertion(comp.Copyright, m),
+		Description:      spdx.Str(comp.Description, m),
+		Meta:             m,
+	}
+	if s := comp.Supplier; s != nil && s.Name != "" {
+		pkg.Supplier = spdx.NewValueCreator(creator("Organization", s), s.Meta)
+	}
+	if comp.Author != "" {
+		pkg.Originator = spdx.NewValueCreator("Person: "+comp.Author, m)
+	}
+
+	concluded, declared, err := im.licences(comp.Licences)
+	if err != nil {
+		return nil, err
+	}
+	pkg.LicenceConcluded = choice(concluded, m)
+	pkg.LicenceDeclared = choice(declared, m)
+	return pkg, nil
+}
+
+// Returns the File of a component.
+func (im *importer) file(comp *Component) (*spdx.File, error) {
+	m := comp.Meta
+	file := &spdx.File{
+		Name:          spdx.Str(comp.Name, m),
+		Checksum:      checksum(comp.Hashes),
+		CopyrightText: orNoAssertion(comp.Copyright, m),
+		Meta:          m,
+	}
+	concluded, declared, err := im.licences(comp.Licences)
+	if err != nil {
+		return nil, err
+	}
+	file.LicenceConcluded = choice(concluded, m)
+	file.LicenceInfoInFile = members(declared)
+	if len(file.LicenceInfoInFile) == 0 {
+		file.LicenceInfoInFile = []spdx.AnyLicence{noAssertion(m)}
+	}
+	return file, nil
+}
+
+// Returns NOASSERTION if there are no licences, the licence if there is one
+// or the DisjunctiveLicenceSet of the licences.
+func choice(lics []spdx.AnyLicence, m *spdx.Meta) spdx.AnyLicence {
+	switch len(lics) {
+	case 0:
+		return noAssertion(m)
+	case 1:
+		return lics[0]
+	}
+	return spdx.NewDisjunctiveSet(m, lics...)
+}
+
+// Returns the licences in `lics` and their sets, without sets and duplicates.
+func members(lics []spdx.AnyLicence) []spdx.AnyLicence {
+	res := make([]spdx.AnyLicence, 0, len(lics))
+	seen := make(map[string]bool)
+	var add func(lic spdx.AnyLicence)
+	add = func(lic spdx.AnyLicence) {
+		switch l := lic.(type) {
+		case spdx.ConjunctiveLicenceSet:
+			for _, m := range l.Members {
+				add(m)
+			}
+		case spdx.DisjunctiveLicenceSet:
+			for _, m := range l.Members {
+				add(m)
+			}
+		default:
+			if !seen[lic.LicenceId()] {
+				seen[lic.LicenceId()] = true
+				res = append(res, lic)
+			}
+		}
+	}
+	for _, lic := range lics {
+		add(lic)
+	}
+	return res
+}
+
+// Returns the licence info in the files of the package, or NOASSERTION if the
+// package has no files with licence info.
+func licenceInfoFromFiles(pkg *spdx.Package) []spdx.AnyLicence {
+	var lics []spdx.AnyLicence
+	for _, f := range pkg.Files {
+		for _, lic := range f.LicenceInfoInFile {
+			if l, ok := lic.(spdx.Licence); !ok || (l.V() != spdx.NOASSERTION && l.V() != spdx.NONE) {
+				lics = append(lics, lic)
+			}
+		}
+	}
+	if lics = members(lics); len(lics) == 0 {
+		return []spdx.AnyLicence{noAssertion(pkg.Meta)}
+	}
+	return lics
+}
+
+// Returns the concluded and declared licences of the licences of a
+// component.
+func (im *importer) licences(lics []*Licence) (concluded, declared []spdx.AnyLicence, err error) {
+	for _, lic := range lics {
+		var res spdx.AnyLicence
+		switch {
+		case lic.Expression != "":
+			if res, err = im.expression(lic.Expression, lic.Meta); err != nil {
+				return nil, nil, err
+			}
+		case lic.Id != "":
+			res = spdx.NewLicence(lic.Id, lic.Meta)
+		case lic.Name != "":
+			res = im.extractedLicence(lic.Name, lic.Text, lic.Url, lic.Meta)
+		default:
+			continue
+		}
+		if lic.Acknowledgement != AckDeclared {
+			concluded = append(concluded, res)
+		}
+		if lic.Acknowledgement != AckConcluded {
+			declared = append(declared, res)
+		}
+	}
+	return concluded, declared, nil
+}
+
+// Returns the ExtractedLicence of a licence name, adding it to the document
+// if it is new. Names which are LicenseRef- IDs are kept, other names get a
+// generated LicenseRef- ID.
+func (im *importer) extractedLicence(name, text, url string, m *spdx.Meta) *spdx.ExtractedLicence {
+	id := name
+	if !strings.HasPrefix(name, "LicenseRef-") {
+		key := name + "\x00" + text
+		if id = im.generated[key]; id == "" {
+			id = "LicenseRef-" + strings.Trim(invalidRefChars.ReplaceAllString(name, "-"), "-")
+			for i := 2; im.extracted[id] != nil; i++ {
+				id = fmt.Sprintf("LicenseRef-%s-%d", strings.Trim(invalidRefChars.ReplaceAllString(name, "-"), "-"), i)
+			}
+			im.generated[key] = id
+		}
+	}
+
+	lic := im.extracted[id]
+	if lic == nil {
+		lic = &spdx.ExtractedLicence{Id: spdx.Str(id, m), Name: []spdx.ValueStr{spdx.Str(name, m)}, Meta: m}
+		im.extracted[id] = lic
+		im.doc.ExtractedLicences = append(im.doc.ExtractedLicences, lic)
+	}
+	if lic.Text.Val == "" && text != "" {
+		lic.Text = spdx.Str(text, m)
+	}
+	if len(lic.CrossReference) == 0 && url != "" {
+		lic.CrossReference = []spdx.ValueStr{spdx.Str(url, m)}
+	}
+	return lic
+}
+
+// Parses a licence expression. AND has precedence over OR. Licence references
+// not defined by other licences are added as ExtractedLicences without text,
+// and licences with exceptions as ExtractedLicences named by the expression
+// ("GPL-2.0 WITH Classpath-exception-2.0").
+func (im *importer) expression(expr string, m *spdx.Meta) (spdx.AnyLicence, error) {
+	p := &exprParser{im: im, meta: m}
+	for _, f := range strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expr)) {
+		p.tokens = append(p.tokens, f)
+	}
+	lic := p.or()
+	if lic == nil || p.pos < len(p.tokens) {
+		return nil, spdx.NewParseError(fmt.Sprintf(MsgInvalidExpression, expr), m)
+	}
+	return lic, nil
+}
+
+// Recursive-descent parser of licence expressions. The parse functions
+// return nil on syntax errors.
+type exprParser struct {
+	im     *importer
+	meta   *spdx.Meta
+	tokens []string
+	pos    int
+}
+
+// Returns the next token, upper case if it is an operator, or "" at the end.
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	tok := p.tokens[p.pos]
+	if up := strings.ToUpper(tok); up == "AND" || up == "OR" || up == "WITH" {
+		return up
+	}
+	return tok
+}
+
+// or = and { "OR" and }
+func (p *exprParser) or() spdx.AnyLicence {
+	return p.set("OR", p.and)
+}
+
+// and = term { "AND" term }
+func (p *exprParser) and() spdx.AnyLicence {
+	return p.set("AND", p.term)
+}
+
+// Parses operands joined by the operator `op` into a licence set.
+func (p *exprParser) set(op string, operand func() spdx.AnyLicence) spdx.AnyLicence {
+	first := operand()
+	if first == nil || p.peek() != op {
+		return first
+	}
+	lics := []spdx.AnyLicence{first}
+	for p.peek() == op {
+		p.pos++
+		lic := operand()
+		if lic == nil {
+			return nil
+		}
+		lics = append(lics, lic)
+	}
+	if op == "AND" {
+		return spdx.NewConjunctiveSet(p.meta, lics...)
+	}
+	return spdx.NewDisjunctiveSet(p.meta, lics...)
+}
+
+// term = "(" or ")" | id [ "WITH" id ]
+func (p *exprParser) term() spdx.AnyLicence {
+	switch tok := p.peek(); tok {
+	case "", ")", "AND", "OR", "WITH":
+		return nil
+	case "(":
+		p.pos++
+		lic := p.or()
+		if lic == nil || p.peek() != ")" {
+			return nil
+		}
+		p.pos++
+		return lic
+	default:
+		p.pos++
+		if p.peek() == "WITH" {
+			p.pos++
+			exc := p.peek()
+			if exc == "" || exc == "(" || exc == ")" || exc == "AND" || exc == "OR" || exc == "WITH" {
+				return nil
+			}
+			p.pos++
+			return p.im.extractedLicence(tok+" WITH "+exc, "", "", p.meta)
+		}
+		if strings.HasPrefix(tok, "LicenseRef-") {
+			return p.im.extractedLicence(tok, "", "", p.meta)
+		}
+		return spdx.NewLicence(tok, p.meta)
+	}
+}
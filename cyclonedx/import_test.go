@@ -0,0 +1,151 @@
+package cyclonedx
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToSpdx(t *testing.T) {
+	bom, err := ReadXml(strings.NewReader(testXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := ToSpdx(bom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ci := doc.CreationInfo
+	if ci == nil || len(ci.Creator) != 1 || ci.Creator[0].V() != "Tool: scanner-2.1" || ci.Created.V() != "2024-01-02T03:04:05Z" {
+		t.Errorf("Wrong creation info: %+v", ci)
+	}
+	if len(doc.Packages) != 1 || len(doc.Files) != 0 {
+		t.Fatalf("Wrong number of packages or files: %d, %d", len(doc.Packages), len(doc.Files))
+	}
+	pkg := doc.Packages[0]
+	if pkg.Name.Val != "lib" || pkg.Supplier.V() != "Organization: ACME (acme@example.com)" ||
+		pkg.DownloadLocation.Val != spdx.NOASSERTION || pkg.CopyrightText.Val != spdx.NOASSERTION {
+		t.Errorf("Wrong package: %+v", pkg)
+	}
+	if pkg.Checksum == nil || pkg.Checksum.Algo.Val != "SHA1" || pkg.Checksum.Value.Val != "85ed0817af83a24ad8da68c2b5094de69833983c" {
+		t.Errorf("Wrong checksum: %+v", pkg.Checksum)
+	}
+	choice := spdx.NewDisjunctiveSet(nil, spdx.NewLicence("MIT", nil), spdx.NewLicence("Apache-2.0", nil))
+	if !spdx.SameLicence(pkg.LicenceConcluded, choice) || !spdx.SameLicence(pkg.LicenceDeclared, choice) {
+		t.Errorf("Wrong licences: %v, %v", pkg.LicenceConcluded, pkg.LicenceDeclared)
+	}
+	if len(pkg.LicenceInfoFromFiles) != 1 || pkg.LicenceInfoFromFiles[0].LicenceId() != spdx.NOASSERTION {
+		t.Errorf("Wrong licence info from files: %v", pkg.LicenceInfoFromFiles)
+	}
+	if len(pkg.Files) != 1 || pkg.Files[0].Name.Val != "a.c" {
+		t.Fatalf("Wrong files: %+v", pkg.Files)
+	}
+	if vc := pkg.VerificationCode; vc == nil || len(vc.ExcludedFiles) != 1 || vc.ExcludedFiles[0].Val != "a.c" {
+		t.Errorf("Files without SHA-1 not excluded from the verification code: %+v", vc)
+	}
+	if pkg.Meta == nil || pkg.LineStart != 10 {
+		t.Errorf("Wrong package meta: %+v", pkg.Meta)
+	}
+}
+
+func TestToSpdxRoundTrip(t *testing.T) {
+	bom, _ := testBom(t)
+	doc, err := ToSpdx(bom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Packages) != 1 || len(doc.Files) != 2 {
+		t.Fatalf("Wrong number of packages or files: %d, %d", len(doc.Packages), len(doc.Files))
+	}
+	pkg := doc.Packages[0]
+	concluded := spdx.NewConjunctiveSet(nil, spdx.NewLicence("Apache-2.0", nil), spdx.NewLicence("MIT", nil))
+	if !spdx.SameLicence(pkg.LicenceConcluded, concluded) || pkg.LicenceDeclared.LicenceId() != spdx.NOASSERTION {
+		t.Errorf("Wrong package licences: %v, %v", pkg.LicenceConcluded, pkg.LicenceDeclared)
+	}
+	if pkg.Originator.V() != "Person: John Doe" || pkg.DownloadLocation.Val != "http://example.com/pkg-1.0.tar.gz" {
+		t.Errorf("Wrong package: %+v", pkg)
+	}
+
+	a, b := doc.Files[0], doc.Files[1]
+	if len(a.Dependency) != 1 || a.Dependency[0] != b {
+		t.Errorf("Wrong file dependencies: %v", a.Dependency)
+	}
+	if b.LicenceConcluded.LicenceId() != "LicenseRef-1" || len(b.LicenceInfoInFile) != 1 || b.LicenceInfoInFile[0].LicenceId() != "GPL-2.0" {
+		t.Errorf("Wrong file licences: %v, %v", b.LicenceConcluded, b.LicenceInfoInFile)
+	}
+	if len(doc.ExtractedLicences) != 1 || doc.ExtractedLicences[0].Text.Val != "Licence text." {
+		t.Errorf("Wrong extracted licences: %+v", doc.ExtractedLicences)
+	}
+}
+
+func TestToSpdxLicences(t *testing.T) {
+	comp := &Component{Type: TypeLibrary, Name: "lib", Licences: []*Licence{
+		{Expression: "(MIT AND LicenseRef-x) or GPL-2.0 WITH Classpath-exception-2.0", Acknowledgement: AckConcluded},
+		{Name: "My licence", Url: "http://example.com/licence", Acknowledgement: AckDeclared},
+		{Name: "My licence", Text: "other", Acknowledgement: AckDeclared},
+	}}
+	doc, err := ToSpdx(&Bom{Components: []*Component{comp}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := doc.Packages[0]
+	if pkg.VerificationCode != nil {
+		t.Errorf("Verification code of a package without files: %+v", pkg.VerificationCode)
+	}
+	if id := pkg.LicenceConcluded.LicenceId(); id != "((MIT and LicenseRef-x) or LicenseRef-GPL-2.0-WITH-Classpath-exception-2.0)" {
+		t.Errorf("Wrong concluded licence: %s", id)
+	}
+	if id := pkg.LicenceDeclared.LicenceId(); id != "(LicenseRef-My-licence or LicenseRef-My-licence-2)" {
+		t.Errorf("Wrong declared licence: %s", id)
+	}
+	ids := make([]string, len(doc.ExtractedLicences))
+	for i, lic := range doc.ExtractedLicences {
+		ids[i] = lic.Id.Val
+	}
+	if strings.Join(ids, " ") != "LicenseRef-x LicenseRef-GPL-2.0-WITH-Classpath-exception-2.0 LicenseRef-My-licence LicenseRef-My-licence-2" {
+		t.Errorf("Wrong extracted licences: %v", ids)
+	}
+	if lic := doc.ExtractedLicences[2]; lic.Name[0].Val != "My licence" || lic.CrossReference[0].Val != "http://example.com/licence" {
+		t.Errorf("Wrong extracted licence: %+v", lic)
+	}
+
+	for _, expr := range []string{"MIT AND", "(MIT", "MIT OR OR GPL-2.0", "MIT WITH", "MIT)"} {
+		_, err := ToSpdx(&Bom{Components: []*Component{{Licences: []*Licence{{Expression: expr}}}}})
+		if _, ok := err.(*spdx.ParseError); !ok {
+			t.Errorf("Expected *spdx.ParseError for %q, got %v", expr, err)
+		}
+	}
+}
+
+func TestToSpdxValid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cyclonedx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	list := filepath.Join(dir, "licence-list.txt")
+	if err = ioutil.WriteFile(list, []byte("MIT\nApache-2.0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	spdx.LicenceListFile = list
+
+	bom, err := ReadXml(strings.NewReader(testXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bom.Components[0].Components[0].Hashes = []*Hash{{Alg: "SHA-1", Content: "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12"}}
+	doc, err := ToSpdx(bom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := spdx.NewValidator()
+	v.Document(doc)
+	if v.HasErrors() {
+		t.Errorf("Converted document is not valid: %v", v.Errors())
+	}
+}
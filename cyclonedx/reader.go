@@ -0,0 +1,245 @@
+package cyclonedx
+
+import (
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/spdx"
+)
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Error messages used by the readers.
+var (
+	MsgNotCycloneDX      = "Not a CycloneDX document."
+	MsgInvalidType       = "Invalid value type for %s. Expected %s."
+	MsgInvalidBase64     = "Invalid base64 licence text."
+	MsgInvalidExpression = "Invalid licence expression %q."
+)
+
+// Reads the Bom model from a CycloneDX JSON Node tree, keeping the first
+// error found.
+type jsonReader struct {
+	err error
+}
+
+// Record an invalid value type error, if there is no error yet.
+func (r *jsonReader) invalid(n *json.Node, key, expected string) {
+	if r.err == nil {
+		r.err = spdx.NewParseError(fmt.Sprintf(MsgInvalidType, key, expected), n.Meta)
+	}
+}
+
+// Returns the string member `key` of `n` or "" if there is none.
+func (r *jsonReader) str(n *json.Node, key string) string {
+	v := n.Get(key)
+	if v == nil || v.Kind == json.KindNull {
+		return ""
+	}
+	if v.Kind != json.KindString {
+		r.invalid(v, key, "string")
+		return ""
+	}
+	return v.Value
+}
+
+// Returns the object member `key` of `n` or nil if there is none.
+func (r *jsonReader) obj(n *json.Node, key string) *json.Node {
+	v := n.Get(key)
+	if v == nil || v.Kind == json.KindNull {
+		return nil
+	}
+	if v.Kind != json.KindObject {
+		r.invalid(v, key, "object")
+		return nil
+	}
+	return v
+}
+
+// Returns the object items of the array member `key` of `n`.
+func (r *jsonReader) arr(n *json.Node, key string) []*json.Node {
+	v := n.Get(key)
+	if v == nil || v.Kind == json.KindNull {
+		return nil
+	}
+	if v.Kind != json.KindArray {
+		r.invalid(v, key, "array")
+		return nil
+	}
+	res := make([]*json.Node, 0, len(v.Items))
+	for _, item := range v.Items {
+		if item.Kind != json.KindObject {
+			r.invalid(item, key, "array of objects")
+			continue
+		}
+		res = append(res, item)
+	}
+	return res
+}
+
+// Read a CycloneDX JSON document. If there is an error in the input, it is of
+// type *spdx.ParseError. Properties which are not in the Bom model are
+// ignored.
+func ReadJson(r io.Reader) (*Bom, error) {
+	root, err := json.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	if root.Kind != json.KindObject || root.Get("bomFormat") == nil || root.Get("bomFormat").Value != "CycloneDX" {
+		return nil, spdx.NewParseError(MsgNotCycloneDX, root.Meta)
+	}
+
+	jr := new(jsonReader)
+	bom := &Bom{Version: 1, Meta: root.Meta}
+	if v := root.Get("version"); v != nil {
+		if n, err := strconv.Atoi(v.Value); v.Kind == json.KindNumber && err == nil {
+			bom.Version = n
+		} else {
+			jr.invalid(v, "version", "integer")
+		}
+	}
+	if mn := jr.obj(root, "metadata"); mn != nil {
+		bom.Metadata = jr.metadata(mn)
+	}
+	bom.Components = jr.components(root, "components")
+	for _, dn := range jr.arr(root, "dependencies") {
+		dep := &Dependency{Ref: jr.str(dn, "ref"), Meta: dn.Meta}
+		if on := dn.Get("dependsOn"); on != nil {
+			for _, ref := range on.Items {
+				if ref.Kind != json.KindString {
+					jr.invalid(ref, "dependsOn", "array of strings")
+					continue
+				}
+				dep.DependsOn = append(dep.DependsOn, ref.Value)
+			}
+		}
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+
+	if jr.err != nil {
+		return nil, jr.err
+	}
+	return bom, nil
+}
+
+// Returns the Metadata of the "metadata" Node. The tools are either the
+// legacy array of tools or an object with the tool components.
+func (r *jsonReader) metadata(n *json.Node) *Metadata {
+	meta := &Metadata{Timestamp: r.str(n, "timestamp"), Meta: n.Meta}
+	if tools := n.Get("tools"); tools != nil && tools.Kind == json.KindArray {
+		for _, tn := range r.arr(n, "tools") {
+			meta.Tools = append(meta.Tools, &Component{
+				Type:    TypeApplication,
+				Author:  r.str(tn, "vendor"),
+				Name:    r.str(tn, "name"),
+				Version: r.str(tn, "version"),
+				Meta:    tn.Meta,
+			})
+		}
+	} else if tools := r.obj(n, "tools"); tools != nil {
+		meta.Tools = r.components(tools, "components")
+	}
+	for _, an := range r.arr(n, "authors") {
+		meta.Authors = append(meta.Authors, r.contact(an))
+	}
+	if cn := r.obj(n, "component"); cn != nil {
+		meta.Component = r.component(cn)
+	}
+	return meta
+}
+
+// Returns the Contact of a person or organisation Node.
+func (r *jsonReader) contact(n *json.Node) *Contact {
+	return &Contact{Name: r.str(n, "name"), Email: r.str(n, "email"), Meta: n.Meta}
+}
+
+// Returns the components in the array member `key` of `n`.
+func (r *jsonReader) components(n *json.Node, key string) []*Component {
+	var res []*Component
+	for _, cn := range r.arr(n, key) {
+		res = append(res, r.component(cn))
+	}
+	return res
+}
+
+// Returns the Component of a component Node. The first of the "authors" is
+// the author if the component has no "author".
+func (r *jsonReader) component(n *json.Node) *Component {
+	comp := &Component{
+		Type:        r.str(n, "type"),
+		BomRef:      r.str(n, "bom-ref"),
+		Author:      r.str(n, "author"),
+		Name:        r.str(n, "name"),
+		Version:     r.str(n, "version"),
+		Description: r.str(n, "description"),
+		Copyright:   r.str(n, "copyright"),
+		Meta:        n.Meta,
+	}
+	if sn := r.obj(n, "supplier"); sn != nil {
+		comp.Supplier = &Contact{Name: r.str(sn, "name"), Meta: sn.Meta}
+		for _, cn := range r.arr(sn, "contact") {
+			if email := r.str(cn, "email"); email != "" {
+				comp.Supplier.Email = email
+				break
+			}
+		}
+	}
+	if authors := r.arr(n, "authors"); comp.Author == "" && len(authors) > 0 {
+		comp.Author = r.str(authors[0], "name")
+	}
+	for _, hn := range r.arr(n, "hashes") {
+		comp.Hashes = append(comp.Hashes, &Hash{Alg: r.str(hn, "alg"), Content: r.str(hn, "content"), Meta: hn.Meta})
+	}
+	for _, ln := range r.arr(n, "licenses") {
+		if lic := r.licence(ln); lic != nil {
+			comp.Licences = append(comp.Licences, lic)
+		}
+	}
+	for _, rn := range r.arr(n, "externalReferences") {
+		comp.References = append(comp.References, &Reference{Type: r.str(rn, "type"), Url: r.str(rn, "url"), Meta: rn.Meta})
+	}
+	comp.Components = r.components(n, "components")
+	return comp
+}
+
+// Returns the Licence of an item of the "licenses" array, or nil if it is
+// neither a licence nor an expression.
+func (r *jsonReader) licence(n *json.Node) *Licence {
+	if expr := r.str(n, "expression"); expr != "" {
+		return &Licence{Expression: expr, Acknowledgement: r.str(n, "acknowledgement"), Meta: n.Meta}
+	}
+	ln := r.obj(n, "license")
+	if ln == nil {
+		return nil
+	}
+	lic := &Licence{
+		Id:              r.str(ln, "id"),
+		Name:            r.str(ln, "name"),
+		Url:             r.str(ln, "url"),
+		Acknowledgement: r.str(ln, "acknowledgement"),
+		Meta:            ln.Meta,
+	}
+	if tn := r.obj(ln, "text"); tn != nil {
+		text, err := licenceText(r.str(tn, "content"), r.str(tn, "encoding"), tn.Meta)
+		if err != nil && r.err == nil {
+			r.err = err
+		}
+		lic.Text = text
+	}
+	return lic
+}
+
+// Returns the licence text `content`, decoded if its encoding is base64.
+func licenceText(content, encoding string, m *spdx.Meta) (string, error) {
+	if encoding != "base64" {
+		return content, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return "", spdx.NewParseError(MsgInvalidBase64, m)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,109 @@
+package cyclonedx
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testJson = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 2,
+  "metadata": {
+    "timestamp": "2024-01-02T03:04:05+02:00",
+    "tools": [
+      {"vendor": "ACME", "name": "scanner", "version": "2.1"}
+    ],
+    "component": {"type": "application", "bom-ref": "app", "name": "app"}
+  },
+  "components": [
+    {
+      "type": "library",
+      "bom-ref": "lib",
+      "authors": [{"name": "Jane Doe"}],
+      "name": "lib",
+      "purl": "pkg:generic/lib@1.0",
+      "hashes": [{"alg": "SHA-256", "content": "AB"}],
+      "licenses": [
+        {"license": {"name": "Custom", "text": {"content": "Q3VzdG9tIHRleHQ=", "encoding": "base64"}}}
+      ]
+    }
+  ],
+  "dependencies": [{"ref": "app", "dependsOn": ["lib"]}]
+}`
+
+func TestReadJson(t *testing.T) {
+	bom, err := ReadJson(strings.NewReader(testJson))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bom.Version != 2 || bom.Metadata == nil || bom.Metadata.Timestamp != "2024-01-02T03:04:05+02:00" {
+		t.Fatalf("Wrong BOM: %+v", bom)
+	}
+	if tools := bom.Metadata.Tools; len(tools) != 1 || tools[0].Name != "scanner" || tools[0].Version != "2.1" || tools[0].Author != "ACME" {
+		t.Errorf("Legacy tools not read: %+v", tools)
+	}
+	if comp := bom.Metadata.Component; comp == nil || comp.BomRef != "app" || comp.LineStart != 10 {
+		t.Errorf("Wrong metadata component: %+v", comp)
+	}
+	if len(bom.Components) != 1 {
+		t.Fatalf("Wrong number of components: %d", len(bom.Components))
+	}
+	lib := bom.Components[0]
+	if lib.Author != "Jane Doe" || len(lib.Hashes) != 1 || lib.Hashes[0].Alg != "SHA-256" {
+		t.Errorf("Wrong component: %+v", lib)
+	}
+	if len(lib.Licences) != 1 || lib.Licences[0].Name != "Custom" || lib.Licences[0].Text != "Custom text" || lib.Licences[0].LineStart != 21 {
+		t.Errorf("Wrong licences: %+v", lib.Licences)
+	}
+	if len(bom.Dependencies) != 1 || bom.Dependencies[0].DependsOn[0] != "lib" {
+		t.Errorf("Wrong dependencies: %+v", bom.Dependencies)
+	}
+}
+
+func TestReadJsonErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{`{"spdxVersion": "SPDX-2.3"}`, 1},
+		{"{\"bomFormat\": \"CycloneDX\",\n\"components\": {}}", 2},
+		{"{\"bomFormat\": \"CycloneDX\",\n\"components\": [\n{\"name\": 1}]}", 3},
+		{"{\"bomFormat\": \"CycloneDX\",\n\"version\": \"1\"}", 2},
+		{"{\"bomFormat\": \"CycloneDX\",\n\"components\": [{\"licenses\": [{\"license\":\n{\"text\": {\"content\": \"!\", \"encoding\": \"base64\"}}}]}]}", 3},
+	}
+	for _, test := range tests {
+		_, err := ReadJson(strings.NewReader(test.input))
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("Expected *spdx.ParseError for %q, got %v", test.input, err)
+			continue
+		}
+		if perr.LineStart != test.line {
+			t.Errorf("Wrong line for %q: %d (expected %d)", test.input, perr.LineStart, test.line)
+		}
+	}
+}
+
+func TestWriteReadJson(t *testing.T) {
+	bom, _ := testBom(t)
+	var b bytes.Buffer
+	if err := WriteJson(&b, bom); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	read, err := ReadJson(&b)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	b.Reset()
+	if err := WriteJson(&b, read); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != out {
+		t.Errorf("Different output after writing and reading.\n%s\n%s", out, b.String())
+	}
+}
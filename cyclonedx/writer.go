@@ -0,0 +1,327 @@
+package cyclonedx
+
+import "github.com/spdx/tools-go/json"
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// Add a string member to an object Node if `val` is not empty.
+func addStr(n *json.Node, key, val string) {
+	if val != "" {
+		n.Add(key, json.String(val, nil))
+	}
+}
+
+// Add an array member to an object Node if `arr` has any items.
+func addArray(n *json.Node, key string, arr *json.Node) {
+	if len(arr.Items) > 0 {
+		n.Add(key, arr)
+	}
+}
+
+// Returns the Node tree of `bom` in the CycloneDX JSON structure. Empty values
+// are left out.
+func JsonNode(bom *Bom) *json.Node {
+	n := json.Object(nil)
+	addStr(n, "bomFormat", "CycloneDX")
+	addStr(n, "specVersion", SpecVersion)
+	n.Add("version", &json.Node{Kind: json.KindNumber, Value: strconv.Itoa(bom.Version)})
+
+	if meta := bom.Metadata; meta != nil {
+		mn := json.Object(nil)
+		addStr(mn, "timestamp", meta.Timestamp)
+		if len(meta.Tools) > 0 {
+			tn := json.Object(nil)
+			addArray(tn, "components", componentsNode(meta.Tools))
+			mn.Add("tools", tn)
+		}
+		authors := json.Array(nil)
+		for _, a := range meta.Authors {
+			authors.Items = append(authors.Items, contactNode(a))
+		}
+		addArray(mn, "authors", authors)
+		if meta.Component != nil {
+			mn.Add("component", componentNode(meta.Component))
+		}
+		n.Add("metadata", mn)
+	}
+
+	addArray(n, "components", componentsNode(bom.Components))
+
+	deps := json.Array(nil)
+	for _, dep := range bom.Dependencies {
+		dn := json.Object(nil)
+		addStr(dn, "ref", dep.Ref)
+		on := json.Array(nil)
+		for _, ref := range dep.DependsOn {
+			on.Items = append(on.Items, json.String(ref, nil))
+		}
+		addArray(dn, "dependsOn", on)
+		deps.Items = append(deps.Items, dn)
+	}
+	addArray(n, "dependencies", deps)
+	return n
+}
+
+// Returns the Node of a person or organisation.
+func contactNode(c *Contact) *json.Node {
+	n := json.Object(nil)
+	addStr(n, "name", c.Name)
+	addStr(n, "email", c.Email)
+	return n
+}
+
+// Returns the array Node of `comps`.
+func componentsNode(comps []*Component) *json.Node {
+	arr := json.Array(nil)
+	for _, comp := range comps {
+		arr.Items = append(arr.Items, componentNode(comp))
+	}
+	return arr
+}
+
+// Returns the Node of `comp`.
+func componentNode(comp *Component) *json.Node {
+	n := json.Object(nil)
+	addStr(n, "type", comp.Type)
+	addStr(n, "bom-ref", comp.BomRef)
+	if comp.Supplier != nil {
+		sn := json.Object(nil)
+		addStr(sn, "name", comp.Supplier.Name)
+		if comp.Supplier.Email != "" {
+			cn := json.Array(nil)
+			cn.Items = append(cn.Items, contactNode(&Contact{Email: comp.Supplier.Email}))
+			sn.Add("contact", cn)
+		}
+		n.Add("supplier", sn)
+	}
+	addStr(n, "author", comp.Author)
+	addStr(n, "name", comp.Name)
+	addStr(n, "version", comp.Version)
+	addStr(n, "description", comp.Description)
+
+	hashes := json.Array(nil)
+	for _, h := range comp.Hashes {
+		hn := json.Object(nil)
+		addStr(hn, "alg", h.Alg)
+		addStr(hn, "content", h.Content)
+		hashes.Items = append(hashes.Items, hn)
+	}
+	addArray(n, "hashes", hashes)
+
+	lics := json.Array(nil)
+	for _, lic := range comp.Licences {
+		lics.Items = append(lics.Items, licenceNode(lic))
+	}
+	addArray(n, "licenses", lics)
+	addStr(n, "copyright", comp.Copyright)
+
+	refs := json.Array(nil)
+	for _, ref := range comp.References {
+		rn := json.Object(nil)
+		addStr(rn, "url", ref.Url)
+		addStr(rn, "type", ref.Type)
+		refs.Items = append(refs.Items, rn)
+	}
+	addArray(n, "externalReferences", refs)
+	addArray(n, "components", componentsNode(comp.Components))
+	return n
+}
+
+// Returns the item Node of the "licenses" array.
+func licenceNode(lic *Licence) *json.Node {
+	n := json.Object(nil)
+	if lic.Expression != "" {
+		addStr(n, "expression", lic.Expression)
+		addStr(n, "acknowledgement", lic.Acknowledgement)
+		return n
+	}
+	ln := json.Object(nil)
+	addStr(ln, "id", lic.Id)
+	addStr(ln, "name", lic.Name)
+	addStr(ln, "acknowledgement", lic.Acknowledgement)
+	if lic.Text != "" {
+		tn := json.Object(nil)
+		addStr(tn, "contentType", "text/plain")
+		addStr(tn, "content", lic.Text)
+		ln.Add("text", tn)
+	}
+	addStr(ln, "url", lic.Url)
+	n.Add("license", ln)
+	return n
+}
+
+// Write `bom` as CycloneDX JSON.
+func WriteJson(w io.Writer, bom *Bom) error {
+	return json.Encode(w, JsonNode(bom))
+}
+
+// Writes XML elements, keeping the first error.
+type xmlWriter struct {
+	enc *xml.Encoder
+	err error
+}
+
+// Write an XML token.
+func (x *xmlWriter) token(t xml.Token) {
+	if x.err == nil {
+		x.err = x.enc.EncodeToken(t)
+	}
+}
+
+// Write a start tag with attributes given as name and value pairs. Attributes
+// with empty values are left out.
+func (x *xmlWriter) start(name string, attrs ...string) {
+	el := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		if attrs[i+1] != "" {
+			el.Attr = append(el.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+		}
+	}
+	x.token(el)
+}
+
+// Write an end tag.
+func (x *xmlWriter) end(name string) {
+	x.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+// Write an element with text content, if `val` is not empty.
+func (x *xmlWriter) text(name, val string, attrs ...string) {
+	if val == "" {
+		return
+	}
+	x.start(name, attrs...)
+	x.token(xml.CharData(val))
+	x.end(name)
+}
+
+// Write `bom` as CycloneDX XML.
+func WriteXml(w io.Writer, bom *Bom) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	x := &xmlWriter{enc: xml.NewEncoder(w)}
+	x.enc.Indent("", "  ")
+
+	x.start("bom", "xmlns", xmlNs, "version", strconv.Itoa(bom.Version))
+	if meta := bom.Metadata; meta != nil {
+		x.start("metadata")
+		x.text("timestamp", meta.Timestamp)
+		if len(meta.Tools) > 0 {
+			x.start("tools")
+			x.components(meta.Tools)
+			x.end("tools")
+		}
+		if len(meta.Authors) > 0 {
+			x.start("authors")
+			for _, a := range meta.Authors {
+				x.start("author")
+				x.text("name", a.Name)
+				x.text("email", a.Email)
+				x.end("author")
+			}
+			x.end("authors")
+		}
+		if meta.Component != nil {
+			x.component(meta.Component)
+		}
+		x.end("metadata")
+	}
+
+	x.components(bom.Components)
+
+	if len(bom.Dependencies) > 0 {
+		x.start("dependencies")
+		for _, dep := range bom.Dependencies {
+			x.start("dependency", "ref", dep.Ref)
+			for _, ref := range dep.DependsOn {
+				x.start("dependency", "ref", ref)
+				x.end("dependency")
+			}
+			x.end("dependency")
+		}
+		x.end("dependencies")
+	}
+	x.end("bom")
+
+	if x.err == nil {
+		x.err = x.enc.Flush()
+	}
+	if x.err == nil {
+		_, x.err = io.WriteString(w, "\n")
+	}
+	return x.err
+}
+
+// Write a "components" element, if there are any components.
+func (x *xmlWriter) components(comps []*Component) {
+	if len(comps) == 0 {
+		return
+	}
+	x.start("components")
+	for _, comp := range comps {
+		x.component(comp)
+	}
+	x.end("components")
+}
+
+// Write a "component" element.
+func (x *xmlWriter) component(comp *Component) {
+	x.start("component", "type", comp.Type, "bom-ref", comp.BomRef)
+	if s := comp.Supplier; s != nil {
+		x.start("supplier")
+		x.text("name", s.Name)
+		if s.Email != "" {
+			x.start("contact")
+			x.text("email", s.Email)
+			x.end("contact")
+		}
+		x.end("supplier")
+	}
+	x.text("author", comp.Author)
+	x.text("name", comp.Name)
+	x.text("version", comp.Version)
+	x.text("description", comp.Description)
+
+	if len(comp.Hashes) > 0 {
+		x.start("hashes")
+		for _, h := range comp.Hashes {
+			x.text("hash", h.Content, "alg", h.Alg)
+		}
+		x.end("hashes")
+	}
+
+	if len(comp.Licences) > 0 {
+		x.start("licenses")
+		for _, lic := range comp.Licences {
+			if lic.Expression != "" {
+				x.text("expression", lic.Expression, "acknowledgement", lic.Acknowledgement)
+				continue
+			}
+			x.start("license", "acknowledgement", lic.Acknowledgement)
+			x.text("id", lic.Id)
+			x.text("name", lic.Name)
+			x.text("text", lic.Text, "content-type", "text/plain")
+			x.text("url", lic.Url)
+			x.end("license")
+		}
+		x.end("licenses")
+	}
+	x.text("copyright", comp.Copyright)
+
+	if len(comp.References) > 0 {
+		x.start("externalReferences")
+		for _, ref := range comp.References {
+			x.start("reference", "type", ref.Type)
+			x.text("url", ref.Url)
+			x.end("reference")
+		}
+		x.end("externalReferences")
+	}
+	x.components(comp.Components)
+	x.end("component")
+}
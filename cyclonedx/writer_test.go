@@ -0,0 +1,64 @@
+package cyclonedx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestWriteJson(t *testing.T) {
+	bom, _ := testBom(t)
+	var b bytes.Buffer
+	if err := WriteJson(&b, bom); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	expected := []string{
+		`"bomFormat": "CycloneDX"`,
+		`"specVersion": "1.6"`,
+		`"version": 1`,
+		`"tools": {`,
+		`"bom-ref": "package-1"`,
+		`"expression": "Apache-2.0 AND MIT"`,
+		`"license": {`,
+		`"contentType": "text/plain"`,
+		`"alg": "SHA-1"`,
+		`"type": "distribution"`,
+		`"dependsOn": [`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(out, s) {
+			t.Errorf("%s not found in output:\n%s", s, out)
+		}
+	}
+}
+
+func TestWriteXml(t *testing.T) {
+	bom, _ := testBom(t)
+	var b bytes.Buffer
+	if err := WriteXml(&b, bom); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	expected := []string{
+		`<bom xmlns="http://cyclonedx.org/schema/bom/1.6" version="1">`,
+		`<component type="application">`,
+		`<component type="library" bom-ref="package-1">`,
+		`<expression acknowledgement="concluded">Apache-2.0 AND MIT</expression>`,
+		`<license acknowledgement="declared">`,
+		`<text content-type="text/plain">Licence text.</text>`,
+		`<hash alg="SHA-1">`,
+		`<dependency ref="file-2"></dependency>`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(out, s) {
+			t.Errorf("%s not found in output:\n%s", s, out)
+		}
+	}
+
+	var v struct{}
+	if err := xml.Unmarshal(b.Bytes(), &v); err != nil {
+		t.Errorf("Invalid XML: %s\n%s", err, out)
+	}
+}
@@ -0,0 +1,269 @@
+package cyclonedx
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// An XML element with the lines it spans in the input. Only the local names
+// of attributes are kept.
+type element struct {
+	space    string // namespace
+	name     string // local name
+	attrs    map[string]string
+	text     string
+	children []*element
+	*spdx.Meta
+}
+
+// Returns the first child element named `name`, or nil if there is none.
+func (e *element) child(name string) *element {
+	if e == nil {
+		return nil
+	}
+	for _, c := range e.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Returns the child elements named `name`.
+func (e *element) all(name string) []*element {
+	if e == nil {
+		return nil
+	}
+	var res []*element
+	for _, c := range e.children {
+		if c.name == name {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// Returns the text of the first child element named `name`.
+func (e *element) str(name string) string {
+	if c := e.child(name); c != nil {
+		return strings.TrimSpace(c.text)
+	}
+	return ""
+}
+
+// Read the XML document in `data` to an element tree.
+func decodeXml(data []byte) (*element, error) {
+	lines := make([]int, 0)
+	for i, b := range data {
+		if b == '\n' {
+			lines = append(lines, i)
+		}
+	}
+	line := func(offset int64) int {
+		return sort.SearchInts(lines, int(offset)) + 1
+	}
+
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var root *element
+	stack := make([]*element, 0)
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if serr, ok := err.(*xml.SyntaxError); ok {
+				return nil, spdx.NewParseError(serr.Msg, spdx.NewMetaL(serr.Line))
+			}
+			return nil, spdx.NewParseError(err.Error(), spdx.NewMetaL(line(dec.InputOffset())))
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			el := &element{space: t.Name.Space, name: t.Name.Local, attrs: make(map[string]string), Meta: spdx.NewMetaL(line(start))}
+			for _, a := range t.Attr {
+				el.attrs[a.Name.Local] = a.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, el)
+			} else if root == nil {
+				root = el
+			}
+			stack = append(stack, el)
+		case xml.EndElement:
+			stack[len(stack)-1].LineEnd = line(dec.InputOffset())
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, spdx.NewParseError(MsgNotCycloneDX, spdx.NewMetaL(1))
+	}
+	return root, nil
+}
+
+// Read a CycloneDX XML document. If there is an error in the input, it is of
+// type *spdx.ParseError. Elements which are not in the Bom model are
+// ignored.
+func ReadXml(r io.Reader) (*Bom, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	root, err := decodeXml(data)
+	if err != nil {
+		return nil, err
+	}
+	if root.name != "bom" || !strings.HasPrefix(root.space, "http://cyclonedx.org/schema/bom/") {
+		return nil, spdx.NewParseError(MsgNotCycloneDX, root.Meta)
+	}
+
+	bom := &Bom{Version: 1, Meta: root.Meta}
+	if v, ok := root.attrs["version"]; ok {
+		if bom.Version, err = strconv.Atoi(v); err != nil {
+			return nil, spdx.NewParseError(fmt.Sprintf(MsgInvalidType, "version", "integer"), root.Meta)
+		}
+	}
+	if me := root.child("metadata"); me != nil {
+		if bom.Metadata, err = xmlMetadata(me); err != nil {
+			return nil, err
+		}
+	}
+	if bom.Components, err = xmlComponents(root.child("components")); err != nil {
+		return nil, err
+	}
+	for _, de := range root.child("dependencies").all("dependency") {
+		dep := &Dependency{Ref: de.attrs["ref"], Meta: de.Meta}
+		for _, on := range de.all("dependency") {
+			dep.DependsOn = append(dep.DependsOn, on.attrs["ref"])
+		}
+		bom.Dependencies = append(bom.Dependencies, dep)
+	}
+	return bom, nil
+}
+
+// Returns the Metadata of the "metadata" element. The tools are either the
+// legacy tool elements or the tool components.
+func xmlMetadata(e *element) (*Metadata, error) {
+	meta := &Metadata{Timestamp: e.str("timestamp"), Meta: e.Meta}
+	tools := e.child("tools")
+	for _, te := range tools.all("tool") {
+		meta.Tools = append(meta.Tools, &Component{
+			Type:    TypeApplication,
+			Author:  te.str("vendor"),
+			Name:    te.str("name"),
+			Version: te.str("version"),
+			Meta:    te.Meta,
+		})
+	}
+	comps, err := xmlComponents(tools.child("components"))
+	if err != nil {
+		return nil, err
+	}
+	meta.Tools = append(meta.Tools, comps...)
+
+	for _, ae := range e.child("authors").all("author") {
+		meta.Authors = append(meta.Authors, &Contact{Name: ae.str("name"), Email: ae.str("email"), Meta: ae.Meta})
+	}
+	if ce := e.child("component"); ce != nil {
+		if meta.Component, err = xmlComponent(ce); err != nil {
+			return nil, err
+		}
+	}
+	return meta, nil
+}
+
+// Returns the components of a "components" element, which may be nil.
+func xmlComponents(e *element) ([]*Component, error) {
+	var res []*Component
+	for _, ce := range e.all("component") {
+		comp, err := xmlComponent(ce)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, comp)
+	}
+	return res, nil
+}
+
+// Returns the Component of a "component" element. The first of the "authors"
+// is the author if the component has no "author".
+func xmlComponent(e *element) (*Component, error) {
+	comp := &Component{
+		Type:        e.attrs["type"],
+		BomRef:      e.attrs["bom-ref"],
+		Author:      e.str("author"),
+		Name:        e.str("name"),
+		Version:     e.str("version"),
+		Description: e.str("description"),
+		Copyright:   e.str("copyright"),
+		Meta:        e.Meta,
+	}
+	if se := e.child("supplier"); se != nil {
+		comp.Supplier = &Contact{Name: se.str("name"), Meta: se.Meta}
+		for _, ce := range se.all("contact") {
+			if email := ce.str("email"); email != "" {
+				comp.Supplier.Email = email
+				break
+			}
+		}
+	}
+	if authors := e.child("authors").all("author"); comp.Author == "" && len(authors) > 0 {
+		comp.Author = authors[0].str("name")
+	}
+	for _, he := range e.child("hashes").all("hash") {
+		comp.Hashes = append(comp.Hashes, &Hash{Alg: he.attrs["alg"], Content: strings.TrimSpace(he.text), Meta: he.Meta})
+	}
+
+	var licences []*element
+	if le := e.child("licenses"); le != nil {
+		licences = le.children
+	}
+	for _, le := range licences {
+		switch le.name {
+		case "expression":
+			comp.Licences = append(comp.Licences, &Licence{
+				Expression:      strings.TrimSpace(le.text),
+				Acknowledgement: le.attrs["acknowledgement"],
+				Meta:            le.Meta,
+			})
+		case "license":
+			lic := &Licence{
+				Id:              le.str("id"),
+				Name:            le.str("name"),
+				Url:             le.str("url"),
+				Acknowledgement: le.attrs["acknowledgement"],
+				Meta:            le.Meta,
+			}
+			if te := le.child("text"); te != nil {
+				text, err := licenceText(te.text, te.attrs["encoding"], te.Meta)
+				if err != nil {
+					return nil, err
+				}
+				lic.Text = text
+			}
+			comp.Licences = append(comp.Licences, lic)
+		}
+	}
+
+	for _, re := range e.child("externalReferences").all("reference") {
+		comp.References = append(comp.References, &Reference{Type: re.attrs["type"], Url: re.str("url"), Meta: re.Meta})
+	}
+
+	var err error
+	comp.Components, err = xmlComponents(e.child("components"))
+	return comp, err
+}
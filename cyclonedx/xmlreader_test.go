@@ -0,0 +1,115 @@
+package cyclonedx
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testXml = `<?xml version="1.0"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.4" version="3">
+  <metadata>
+    <timestamp>2024-01-02T03:04:05Z</timestamp>
+    <tools>
+      <tool><vendor>ACME</vendor><name>scanner</name><version>2.1</version></tool>
+    </tools>
+  </metadata>
+  <components>
+    <component type="library" bom-ref="lib">
+      <supplier><name>ACME</name><contact><email>acme@example.com</email></contact></supplier>
+      <name>lib</name>
+      <hashes><hash alg="SHA-1">85ed0817af83a24ad8da68c2b5094de69833983c</hash></hashes>
+      <licenses>
+        <expression>MIT OR Apache-2.0</expression>
+      </licenses>
+      <purl>pkg:generic/lib@1.0</purl>
+      <components>
+        <component type="file" bom-ref="f"><name>a.c</name></component>
+      </components>
+    </component>
+  </components>
+  <dependencies>
+    <dependency ref="lib"><dependency ref="f"/></dependency>
+  </dependencies>
+</bom>
+`
+
+func TestReadXml(t *testing.T) {
+	bom, err := ReadXml(strings.NewReader(testXml))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bom.Version != 3 || bom.Metadata == nil || bom.Metadata.Timestamp != "2024-01-02T03:04:05Z" {
+		t.Fatalf("Wrong BOM: %+v", bom)
+	}
+	if tools := bom.Metadata.Tools; len(tools) != 1 || tools[0].Name != "scanner" || tools[0].Version != "2.1" {
+		t.Errorf("Legacy tools not read: %+v", tools)
+	}
+	if len(bom.Components) != 1 {
+		t.Fatalf("Wrong number of components: %d", len(bom.Components))
+	}
+	lib := bom.Components[0]
+	if lib.Type != TypeLibrary || lib.Name != "lib" || lib.Supplier == nil || lib.Supplier.Email != "acme@example.com" {
+		t.Errorf("Wrong component: %+v", lib)
+	}
+	if lib.LineStart != 10 || lib.LineEnd != 21 {
+		t.Errorf("Wrong component lines: %d to %d", lib.LineStart, lib.LineEnd)
+	}
+	if len(lib.Hashes) != 1 || lib.Hashes[0].Content != "85ed0817af83a24ad8da68c2b5094de69833983c" {
+		t.Errorf("Wrong hashes: %+v", lib.Hashes)
+	}
+	if len(lib.Licences) != 1 || lib.Licences[0].Expression != "MIT OR Apache-2.0" {
+		t.Errorf("Wrong licences: %+v", lib.Licences)
+	}
+	if len(lib.Components) != 1 || lib.Components[0].Type != TypeFile || lib.Components[0].Name != "a.c" {
+		t.Errorf("Wrong nested components: %+v", lib.Components)
+	}
+	if len(bom.Dependencies) != 1 || bom.Dependencies[0].Ref != "lib" || bom.Dependencies[0].DependsOn[0] != "f" {
+		t.Errorf("Wrong dependencies: %+v", bom.Dependencies)
+	}
+}
+
+func TestReadXmlErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"<bom>\n</bom>", 1},
+		{"<?xml version=\"1.0\"?>\n<bom xmlns=\"http://cyclonedx.org/schema/bom/1.6\">\n<components>\n</bom>", 4},
+		{"\n<bom xmlns=\"http://cyclonedx.org/schema/bom/1.6\" version=\"x\"/>", 2},
+		{"", 1},
+	}
+	for _, test := range tests {
+		_, err := ReadXml(strings.NewReader(test.input))
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("Expected *spdx.ParseError for %q, got %v", test.input, err)
+			continue
+		}
+		if perr.LineStart != test.line {
+			t.Errorf("Wrong line for %q: %d (expected %d)", test.input, perr.LineStart, test.line)
+		}
+	}
+}
+
+func TestWriteReadXml(t *testing.T) {
+	bom, _ := testBom(t)
+	var b bytes.Buffer
+	if err := WriteXml(&b, bom); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	read, err := ReadXml(&b)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	b.Reset()
+	if err := WriteXml(&b, read); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != out {
+		t.Errorf("Different output after writing and reading.\n%s\n%s", out, b.String())
+	}
+}
@@ -0,0 +1,360 @@
+// Package diff compares two SPDX documents element by element.
+//
+// Unlike spdx.Document.Equal(), the elements are not compared by position:
+// packages and files are matched by name, extracted licences by ID and reviews
+// by reviewer (elements with the same name are matched in order). The files
+// of the packages are compared with the document files.
+//
+// The result is a list of changes: the elements added and removed, and the
+// properties changed in the matched elements, with the old and new values and
+// their metadata. Licence expressions are compared in their canonical form
+// (see spdx.CanonicalLicence()) and multi-valued properties as sets, so
+// reordering is not a change.
+package diff
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kinds of changes.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Elements of a document.
+const (
+	ElemDocument = "document"
+	ElemPackage  = "package"
+	ElemFile     = "file"
+	ElemLicence  = "licence"
+	ElemReview   = "review"
+)
+
+// A difference between two documents. Added and removed elements only have
+// the meta of the element on their side. A changed single-valued property
+// has both values; a value added to or removed from a multi-valued property
+// only has the new or the old value.
+type Change struct {
+	Kind    string     `json:"kind"`              // Added, Removed or Changed
+	Element string     `json:"element"`           // ElemDocument, ElemPackage, ...
+	Id      string     `json:"id,omitempty"`      // Name or ID of the element
+	Field   string     `json:"field,omitempty"`   // Tag property name of a changed value
+	Old     string     `json:"old,omitempty"`     // Old value
+	New     string     `json:"new,omitempty"`     // New value
+	OldMeta *spdx.Meta `json:"oldMeta,omitempty"` // Metadata in the old document
+	NewMeta *spdx.Meta `json:"newMeta,omitempty"` // Metadata in the new document
+}
+
+// Collects the changes of the element being compared.
+type differ struct {
+	changes []*Change
+	element string
+	id      string
+}
+
+// Returns the changes from `from` to `to`.
+func Documents(from, to *spdx.Document) []*Change {
+	if from == nil {
+		from = new(spdx.Document)
+	}
+	if to == nil {
+		to = new(spdx.Document)
+	}
+	d := &differ{element: ElemDocument}
+	d.document(from, to)
+
+	d.elements(ElemPackage, packages(from), packages(to), func(o, n interface{}) {
+		d.pkg(o.(*spdx.Package), n.(*spdx.Package))
+	})
+	d.elements(ElemFile, files(from), files(to), func(o, n interface{}) {
+		d.file(o.(*spdx.File), n.(*spdx.File))
+	})
+	d.elements(ElemLicence, licences(from), licences(to), func(o, n interface{}) {
+		d.licence(o.(*spdx.ExtractedLicence), n.(*spdx.ExtractedLicence))
+	})
+	d.elements(ElemReview, reviews(from), reviews(to), func(o, n interface{}) {
+		d.review(o.(*spdx.Review), n.(*spdx.Review))
+	})
+	return d.changes
+}
+
+// An element with its name or ID.
+type element struct {
+	id   string
+	elem interface{}
+	meta *spdx.Meta
+}
+
+func packages(doc *spdx.Document) (res []element) {
+	for _, pkg := range doc.Packages {
+		if pkg != nil {
+			res = append(res, element{pkg.Name.Val, pkg, pkg.Meta})
+		}
+	}
+	return
+}
+
+// Returns the document files and the files of the packages.
+func files(doc *spdx.Document) (res []element) {
+	seen := make(map[*spdx.File]bool)
+	add := func(files []*spdx.File) {
+		for _, file := range files {
+			if file != nil && !seen[file] {
+				seen[file] = true
+				res = append(res, element{file.Name.Val, file, file.Meta})
+			}
+		}
+	}
+	add(doc.Files)
+	for _, pkg := range doc.Packages {
+		if pkg != nil {
+			add(pkg.Files)
+		}
+	}
+	return
+}
+
+func licences(doc *spdx.Document) (res []element) {
+	for _, lic := range doc.ExtractedLicences {
+		if lic != nil {
+			res = append(res, element{lic.Id.Val, lic, lic.Meta})
+		}
+	}
+	return
+}
+
+func reviews(doc *spdx.Document) (res []element) {
+	for _, rev := range doc.Reviews {
+		if rev != nil {
+			res = append(res, element{rev.Reviewer.V(), rev, rev.Meta})
+		}
+	}
+	return
+}
+
+// Matches the old and new elements by ID, reports the removed and added ones
+// and calls `compare` with the matched pairs.
+func (d *differ) elements(kind string, from, to []element, compare func(o, n interface{})) {
+	byId := make(map[string][]element)
+	for _, n := range to {
+		byId[n.id] = append(byId[n.id], n)
+	}
+	matched := make(map[interface{}]bool)
+	for _, o := range from {
+		d.element, d.id = kind, o.id
+		candidates := byId[o.id]
+		if len(candidates) == 0 {
+			d.add(&Change{Kind: Removed, OldMeta: o.meta})
+			continue
+		}
+		byId[o.id] = candidates[1:]
+		matched[candidates[0].elem] = true
+		compare(o.elem, candidates[0].elem)
+	}
+	for _, n := range to {
+		if !matched[n.elem] {
+			d.element, d.id = kind, n.id
+			d.add(&Change{Kind: Added, NewMeta: n.meta})
+		}
+	}
+}
+
+// Adds a change of the current element.
+func (d *differ) add(c *Change) {
+	c.Element, c.Id = d.element, d.id
+	d.changes = append(d.changes, c)
+}
+
+// Returns the value and the metadata of `v`, which may be nil.
+func valueMeta(v spdx.Value) (string, *spdx.Meta) {
+	if v == nil {
+		return "", nil
+	}
+	return v.V(), v.M()
+}
+
+// Compares a single-valued property.
+func (d *differ) value(field string, from, to spdx.Value) {
+	o, om := valueMeta(from)
+	n, nm := valueMeta(to)
+	if o != n {
+		d.add(&Change{Kind: Changed, Field: field, Old: o, New: n, OldMeta: om, NewMeta: nm})
+	}
+}
+
+// Compares a multi-valued property as a set of values.
+func (d *differ) values(field string, from, to []spdx.Value) {
+	count := make(map[string]int)
+	for _, v := range to {
+		count[v.V()]++
+	}
+	for _, v := range from {
+		if count[v.V()] > 0 {
+			count[v.V()]--
+		} else {
+			d.add(&Change{Kind: Changed, Field: field, Old: v.V(), OldMeta: v.M()})
+		}
+	}
+	count = make(map[string]int)
+	for _, v := range from {
+		count[v.V()]++
+	}
+	for _, v := range to {
+		if count[v.V()] > 0 {
+			count[v.V()]--
+		} else {
+			d.add(&Change{Kind: Changed, Field: field, New: v.V(), NewMeta: v.M()})
+		}
+	}
+}
+
+// Returns a ValueStr slice as a Value slice.
+func strs(values []spdx.ValueStr) []spdx.Value {
+	res := make([]spdx.Value, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+	return res
+}
+
+// Returns a licence of the element with the metadata `elem` as a Value with
+// its canonical expression. A resolved licence reference has the metadata of
+// the licence definition, so it has the metadata of the element instead.
+func licence(lic spdx.AnyLicence, elem *spdx.Meta) spdx.Value {
+	if lic == nil {
+		return nil
+	}
+	meta := lic.M()
+	if _, ok := lic.(*spdx.ExtractedLicence); ok {
+		meta = elem
+	}
+	return spdx.Str(spdx.CanonicalLicence(lic), meta)
+}
+
+// Returns a licence slice of the element with the metadata `elem` as a Value
+// slice of canonical expressions.
+func licenceList(lics []spdx.AnyLicence, elem *spdx.Meta) []spdx.Value {
+	var res []spdx.Value
+	for _, lic := range lics {
+		if lic != nil {
+			res = append(res, licence(lic, elem))
+		}
+	}
+	return res
+}
+
+// Returns a checksum as a Value.
+func checksum(cksum *spdx.Checksum) spdx.Value {
+	if cksum == nil {
+		return nil
+	}
+	return spdx.Str(strings.ToUpper(cksum.Algo.Val)+": "+strings.ToLower(cksum.Value.Val), cksum.Meta)
+}
+
+// Returns a verification code as a Value, with the excluded files sorted.
+func verifCode(vc *spdx.VerificationCode) spdx.Value {
+	if vc == nil {
+		return nil
+	}
+	excluded := make([]string, len(vc.ExcludedFiles))
+	for i, f := range vc.ExcludedFiles {
+		excluded[i] = f.Val
+	}
+	sort.Strings(excluded)
+	val := strings.ToLower(vc.Value.Val)
+	if len(excluded) > 0 {
+		val += " (Excludes: " + strings.Join(excluded, ", ") + ")"
+	}
+	return spdx.Str(val, vc.Meta)
+}
+
+func (d *differ) document(from, to *spdx.Document) {
+	d.value("SPDXVersion", from.SpecVersion, to.SpecVersion)
+	d.value("DataLicense", from.DataLicence, to.DataLicence)
+	d.value("DocumentComment", from.Comment, to.Comment)
+
+	oci, nci := from.CreationInfo, to.CreationInfo
+	if oci == nil {
+		oci = new(spdx.CreationInfo)
+	}
+	if nci == nil {
+		nci = new(spdx.CreationInfo)
+	}
+	creators := func(ci *spdx.CreationInfo) []spdx.Value {
+		res := make([]spdx.Value, len(ci.Creator))
+		for i, cr := range ci.Creator {
+			res[i] = cr
+		}
+		return res
+	}
+	d.values("Creator", creators(oci), creators(nci))
+	d.value("Created", oci.Created, nci.Created)
+	d.value("LicenseListVersion", oci.LicenceListVersion, nci.LicenceListVersion)
+	d.value("CreatorComment", oci.Comment, nci.Comment)
+}
+
+func (d *differ) pkg(from, to *spdx.Package) {
+	d.value("PackageVersion", from.Version, to.Version)
+	d.value("PackageFileName", from.FileName, to.FileName)
+	d.value("PackageSupplier", from.Supplier, to.Supplier)
+	d.value("PackageOriginator", from.Originator, to.Originator)
+	d.value("PackageDownloadLocation", from.DownloadLocation, to.DownloadLocation)
+	d.value("PackageVerificationCode", verifCode(from.VerificationCode), verifCode(to.VerificationCode))
+	d.value("PackageChecksum", checksum(from.Checksum), checksum(to.Checksum))
+	d.value("PackageHomePage", from.HomePage, to.HomePage)
+	d.value("PackageSourceInfo", from.SourceInfo, to.SourceInfo)
+	d.value("PackageLicenseConcluded", licence(from.LicenceConcluded, from.Meta), licence(to.LicenceConcluded, to.Meta))
+	d.values("PackageLicenseInfoFromFiles", licenceList(from.LicenceInfoFromFiles, from.Meta), licenceList(to.LicenceInfoFromFiles, to.Meta))
+	d.value("PackageLicenseDeclared", licence(from.LicenceDeclared, from.Meta), licence(to.LicenceDeclared, to.Meta))
+	d.value("PackageLicenseComments", from.LicenceComments, to.LicenceComments)
+	d.value("PackageCopyrightText", from.CopyrightText, to.CopyrightText)
+	d.value("PackageSummary", from.Summary, to.Summary)
+	d.value("PackageDescription", from.Description, to.Description)
+}
+
+func (d *differ) file(from, to *spdx.File) {
+	d.value("FileType", from.Type, to.Type)
+	d.value("FileChecksum", checksum(from.Checksum), checksum(to.Checksum))
+	d.value("LicenseConcluded", licence(from.LicenceConcluded, from.Meta), licence(to.LicenceConcluded, to.Meta))
+	d.values("LicenseInfoInFile", licenceList(from.LicenceInfoInFile, from.Meta), licenceList(to.LicenceInfoInFile, to.Meta))
+	d.value("LicenseComments", from.LicenceComments, to.LicenceComments)
+	d.value("FileCopyrightText", from.CopyrightText, to.CopyrightText)
+	d.value("FileNotice", from.Notice, to.Notice)
+	artifacts := func(file *spdx.File) (res []spdx.Value) {
+		for _, a := range file.ArtifactOf {
+			if a != nil {
+				res = append(res, spdx.Str(strings.Join([]string{a.Name.Val, a.HomePage.Val, a.ProjectUri.Val}, ", "), a.Meta))
+			}
+		}
+		return
+	}
+	d.values("ArtifactOf", artifacts(from), artifacts(to))
+	deps := func(file *spdx.File) (res []spdx.Value) {
+		for _, dep := range file.Dependency {
+			if dep != nil {
+				res = append(res, dep.Name)
+			}
+		}
+		return
+	}
+	d.values("FileDependency", deps(from), deps(to))
+	d.values("FileContributor", strs(from.Contributor), strs(to.Contributor))
+	d.value("FileComment", from.Comment, to.Comment)
+}
+
+func (d *differ) licence(from, to *spdx.ExtractedLicence) {
+	d.value("ExtractedText", from.Text, to.Text)
+	d.values("LicenseName", strs(from.Name), strs(to.Name))
+	d.values("LicenseCrossReference", strs(from.CrossReference), strs(to.CrossReference))
+	d.value("LicenseComment", from.Comment, to.Comment)
+}
+
+func (d *differ) review(from, to *spdx.Review) {
+	d.value("ReviewDate", from.Date, to.Date)
+	d.value("ReviewComment", from.Comment, to.Comment)
+}
@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const oldInput = `SPDXVersion: SPDX-1.2
+Creator: Tool: x
+Creator: Person: Me
+
+PackageName: p
+PackageVersion: 1.0
+PackageLicenseDeclared: (MIT and GPL-2.0)
+
+FileName: a.c
+FileType: SOURCE
+FileContributor: Me
+
+FileName: b.c
+
+LicenseID: LicenseRef-1
+ExtractedText: text
+`
+
+const newInput = `SPDXVersion: SPDX-1.2
+Creator: Person: Me
+Creator: Tool: x
+
+PackageName: p
+PackageVersion: 2.0
+PackageLicenseDeclared: (GPL-2.0 and MIT)
+
+FileName: c.c
+
+FileName: a.c
+FileType: BINARY
+FileContributor: You
+
+LicenseID: LicenseRef-1
+ExtractedText: text
+`
+
+func build(t *testing.T, input string) *spdx.Document {
+	doc, err := tag.Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return doc
+}
+
+func TestDocuments(t *testing.T) {
+	changes := Documents(build(t, oldInput), build(t, newInput))
+	expected := []Change{
+		{Kind: Changed, Element: ElemPackage, Id: "p", Field: "PackageVersion", Old: "1.0", New: "2.0", OldMeta: spdx.NewMetaL(6), NewMeta: spdx.NewMetaL(6)},
+		{Kind: Changed, Element: ElemFile, Id: "a.c", Field: "FileType", Old: "SOURCE", New: "BINARY", OldMeta: spdx.NewMetaL(10), NewMeta: spdx.NewMetaL(12)},
+		{Kind: Changed, Element: ElemFile, Id: "a.c", Field: "FileContributor", Old: "Me", OldMeta: spdx.NewMetaL(11)},
+		{Kind: Changed, Element: ElemFile, Id: "a.c", Field: "FileContributor", New: "You", NewMeta: spdx.NewMetaL(13)},
+		{Kind: Removed, Element: ElemFile, Id: "b.c", OldMeta: spdx.NewMetaL(13)},
+		{Kind: Added, Element: ElemFile, Id: "c.c", NewMeta: spdx.NewMetaL(9)},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes but found %d: %v", len(expected), len(changes), changes)
+	}
+	for i, e := range expected {
+		c := changes[i]
+		if c.Kind != e.Kind || c.Element != e.Element || c.Id != e.Id || c.Field != e.Field || c.Old != e.Old || c.New != e.New {
+			t.Errorf("%d: Expected %v but found %v", i, &e, c)
+		}
+		if (c.OldMeta == nil) != (e.OldMeta == nil) || (c.OldMeta != nil && c.OldMeta.LineStart != e.OldMeta.LineStart) {
+			t.Errorf("%d: Expected old meta %v but found %v", i, e.OldMeta, c.OldMeta)
+		}
+		if (c.NewMeta == nil) != (e.NewMeta == nil) || (c.NewMeta != nil && c.NewMeta.LineStart != e.NewMeta.LineStart) {
+			t.Errorf("%d: Expected new meta %v but found %v", i, e.NewMeta, c.NewMeta)
+		}
+	}
+}
+
+func TestDocumentsSame(t *testing.T) {
+	if changes := Documents(build(t, oldInput), build(t, oldInput)); len(changes) != 0 {
+		t.Errorf("Found changes %v", changes)
+	}
+}
+
+func TestDocumentsDuplicateNames(t *testing.T) {
+	old := build(t, "SPDXVersion: SPDX-1.2\nFileName: a.c\nFileType: SOURCE\nFileName: a.c\nFileType: BINARY\n")
+	doc := build(t, "SPDXVersion: SPDX-1.2\nFileName: a.c\nFileType: SOURCE\n")
+	changes := Documents(old, doc)
+	if len(changes) != 1 || changes[0].Kind != Removed || changes[0].OldMeta.LineStart != 4 {
+		t.Errorf("Wrong changes %v", changes)
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteText(buf, Documents(build(t, oldInput), build(t, newInput))); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := `~ package "p" PackageVersion: "1.0" -> "2.0" (line 6 -> 6)
+~ file "a.c" FileType: "SOURCE" -> "BINARY" (line 10 -> 12)
+~ file "a.c" FileContributor: "Me" -> "" (line 11)
+~ file "a.c" FileContributor: "" -> "You" (line 13)
+- file "b.c" (line 13)
++ file "c.c" (line 9)
+`
+	if res := buf.String(); res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+
+	buf.Reset()
+	if err := WriteText(buf, nil); err != nil || buf.String() != "No differences.\n" {
+		t.Errorf("Printed %#v (error %v)", buf.String(), err)
+	}
+}
+
+func TestWriteJson(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteJson(buf, Documents(build(t, oldInput), build(t, newInput))); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	var res []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(res) != 6 || res[0]["kind"] != Changed || res[0]["old"] != "1.0" || res[0]["new"] != "2.0" {
+		t.Errorf("Wrong JSON %s", buf.String())
+	}
+	if meta, ok := res[1]["newMeta"].(map[string]interface{}); !ok || meta["LineStart"] != 12.0 {
+		t.Errorf("Wrong JSON meta %s", buf.String())
+	}
+}
+
+func TestDocumentsLicenceReference(t *testing.T) {
+	old := build(t, "SPDXVersion: SPDX-1.2\n\nFileName: a.c\nFileType: SOURCE\nLicenseConcluded: LicenseRef-1\n\nLicenseID: LicenseRef-1\nExtractedText: text\n")
+	doc := build(t, "SPDXVersion: SPDX-1.2\n\nFileName: a.c\nFileType: SOURCE\nLicenseConcluded: MIT\n")
+	changes := Documents(old, doc)
+	if len(changes) != 2 || changes[0].Field != "LicenseConcluded" {
+		t.Fatalf("Wrong changes %v", changes)
+	}
+	if changes[0].OldMeta == nil || changes[0].OldMeta.LineStart != 3 {
+		t.Errorf("Expected old meta at line 3 but found %v", changes[0].OldMeta)
+	}
+}
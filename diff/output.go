@@ -0,0 +1,89 @@
+package diff
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Output formats.
+const (
+	FormatText = "text"
+	FormatJson = "json"
+)
+
+// Write the changes in the given format (FormatText or FormatJson).
+func Write(w io.Writer, changes []*Change, format string) error {
+	switch format {
+	case FormatText:
+		return WriteText(w, changes)
+	case FormatJson:
+		return WriteJson(w, changes)
+	}
+	return fmt.Errorf("Unsupported diff format %s.", format)
+}
+
+// Write the changes as a JSON array.
+func WriteJson(w io.Writer, changes []*Change) error {
+	if changes == nil {
+		changes = []*Change{}
+	}
+	data, err := json.MarshalIndent(changes, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Write the changes as text, one per line:
+//
+//   - file "a.c" (line 12)
+//   - licence "LicenseRef-1" (line 30)
+//     ~ package "p" PackageVersion: "1.0" -> "2.0" (line 8 -> 9)
+//     ~ file "b.c" FileContributor: "" -> "Me" (line 20)
+func WriteText(w io.Writer, changes []*Change) error {
+	if len(changes) == 0 {
+		_, err := io.WriteString(w, "No differences.\n")
+		return err
+	}
+	for _, c := range changes {
+		if _, err := io.WriteString(w, c.String()+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the text representation of the change (see WriteText()).
+func (c *Change) String() string {
+	res := c.Element
+	if c.Id != "" {
+		res += " " + strconv.Quote(c.Id)
+	}
+	switch c.Kind {
+	case Added:
+		return "+ " + res + lines(nil, c.NewMeta)
+	case Removed:
+		return "- " + res + lines(c.OldMeta, nil)
+	}
+	res += " " + c.Field + ": " + strconv.Quote(c.Old) + " -> " + strconv.Quote(c.New)
+	return "~ " + res + lines(c.OldMeta, c.NewMeta)
+}
+
+// Returns the line numbers of the old and new metadata, if any.
+func lines(o, n *spdx.Meta) string {
+	line := func(m *spdx.Meta) string { return strconv.Itoa(m.LineStart) }
+	switch {
+	case o == nil && n == nil:
+		return ""
+	case o == nil:
+		return " (line " + line(n) + ")"
+	case n == nil:
+		return " (line " + line(o) + ")"
+	}
+	return " (line " + line(o) + " -> " + line(n) + ")"
+}
@@ -0,0 +1,139 @@
+package gomod
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"crypto/sha1"
+	"debug/buildinfo"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+)
+
+// The main package path of the binaries built from files given on the
+// command line (`go build file.go`).
+const commandLinePackage = "command-line-arguments"
+
+// Returns the path of the main module of a binary: the module path, or the
+// path of the main package for binaries built outside a module (GOPATH mode).
+// Returns an empty string for binaries built from files given on the command
+// line.
+func mainPath(info *debug.BuildInfo) string {
+	if info.Main.Path != "" {
+		return info.Main.Path
+	}
+	if info.Path != commandLinePackage {
+		return info.Path
+	}
+	return ""
+}
+
+// Read the module information embedded in a Go binary: the main module and
+// the dependency modules, with their versions, hashes and replacements.
+// Whether a dependency is direct or indirect is not recorded in binaries.
+// Binaries built outside a module have no main module, but for the path of
+// their main package (see mainPath()).
+func (im *Importer) BuildInfo(info *debug.BuildInfo) {
+	if path := mainPath(info); path != "" {
+		im.main = path
+		main := im.module(path)
+		main.main = true
+		main.version = info.Main.Version
+		im.addSum(&info.Main)
+	}
+
+	for _, dep := range info.Deps {
+		mod := im.module(dep.Path)
+		mod.version = dep.Version
+		im.addSum(dep)
+		if dep.Replace != nil {
+			mod.replace = &listModule{Path: dep.Replace.Path, Version: dep.Replace.Version}
+			im.addSum(dep.Replace)
+		}
+	}
+}
+
+// Index the hash of a module from the build info, if there is one.
+func (im *Importer) addSum(mod *debug.Module) {
+	if mod.Sum != "" {
+		im.sums[mod.Path+"@"+mod.Version] = mod.Sum
+	}
+}
+
+// Describe a Go binary as a SPDX Document. The document has a package for
+// each module compiled in the binary (see Importer) and the binary itself as
+// a BINARY file of the main module package, with its SHA1 checksum. The Go
+// version and the build settings are written to the file comment. The main
+// package of a binary without a main module (see mainPath()) is named after
+// the binary.
+//
+// `name` is used as the file name and `r` must have `size` bytes.
+func Binary(r io.ReaderAt, size int64, name string) (*spdx.Document, error) {
+	info, err := buildinfo.Read(r)
+	if err != nil {
+		return nil, err
+	}
+	return binary(info, r, size, name)
+}
+
+// Describe the Go binary with the build info `info`. See Binary().
+func binary(info *debug.BuildInfo, r io.ReaderAt, size int64, name string) (*spdx.Document, error) {
+	h := sha1.New()
+	if _, err := io.Copy(h, io.NewSectionReader(r, 0, size)); err != nil {
+		return nil, err
+	}
+
+	im := NewImporter()
+	if mainPath(info) == "" {
+		im.main = name
+		im.module(name).main = true
+	}
+	im.BuildInfo(info)
+	doc := im.Document()
+
+	file := &spdx.File{
+		Name: spdx.Str(name, nil),
+		Type: spdx.Str(spdx.FT_BINARY, nil),
+		Checksum: &spdx.Checksum{
+			Algo:  spdx.Str("SHA1", nil),
+			Value: spdx.Str(hex.EncodeToString(h.Sum(nil)), nil),
+		},
+		LicenceConcluded:  spdx.NewLicence(spdx.NOASSERTION, nil),
+		LicenceInfoInFile: []spdx.AnyLicence{spdx.NewLicence(spdx.NOASSERTION, nil)},
+		CopyrightText:     spdx.Str(spdx.NOASSERTION, nil),
+		Comment:           spdx.Str(buildComment(info), nil),
+	}
+	doc.Files = []*spdx.File{file}
+	if len(doc.Packages) > 0 {
+		doc.Packages[0].Files = doc.Files
+	}
+	return doc, nil
+}
+
+// Describe the Go binary at the given path. See Binary().
+func BinaryFile(name string) (*spdx.Document, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return Binary(f, info.Size(), filepath.Base(name))
+}
+
+// The file comment for a binary: Go version and build settings.
+func buildComment(info *debug.BuildInfo) string {
+	comment := "Built with " + info.GoVersion + "."
+	if len(info.Settings) > 0 {
+		comment += "\nBuild settings:"
+		for _, s := range info.Settings {
+			comment += "\n" + s.Key + "=" + s.Value
+		}
+	}
+	return comment
+}
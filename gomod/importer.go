@@ -0,0 +1,269 @@
+package gomod
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"io"
+	"strings"
+	"time"
+)
+
+// The creator written in the CreationInfo of imported documents.
+var Creator = "Tool: spdx-go"
+
+// The Go module proxy used to build package download locations.
+var Proxy = "https://proxy.golang.org"
+
+// Code hosting sites where the first path element after the host is the
+// owner of the module (e.g. github.com/<owner>/<repo>).
+var hostingSites = []string{
+	"github.com",
+	"gitlab.com",
+	"bitbucket.org",
+	"gitee.com",
+	"codeberg.org",
+}
+
+// Kinds of dependency on the main module.
+const (
+	depUnknown  = ""
+	depDirect   = "Direct dependency"
+	depIndirect = "Indirect dependency"
+)
+
+// A Go module, as found in either go.mod or `go list -m -json` output.
+type module struct {
+	path, version string
+	main          bool
+	dependency    string
+	replace       *listModule
+}
+
+// Importer describes Go modules as a SPDX Document. It reads any combination
+// of go.mod, go.sum and the output of `go list -m -json all`, or the build
+// information embedded in a Go binary (see Importer.BuildInfo and Binary).
+//
+// The module list is taken from `go list` if available, otherwise from the
+// require directives in go.mod. go.mod also marks direct and indirect
+// dependencies and go.sum provides the h1: hashes of the modules.
+//
+// Every module becomes a spdx.Package. SPDX-1.2 cannot express relationships
+// between packages, so the dependency of a module on the main module, its
+// replacement and its h1: hashes are written to the package source info.
+//
+// Always use NewImporter() to create a new Importer.
+type Importer struct {
+	main    string
+	order   []string
+	modules map[string]*module
+	sums    map[string]string
+}
+
+// Create a new *Importer.
+func NewImporter() *Importer {
+	return &Importer{
+		modules: make(map[string]*module),
+		sums:    make(map[string]string),
+	}
+}
+
+// Import Go module metadata to a *spdx.Document. Any of the readers can be nil
+// but at least one of `list` and `mod` must be given.
+func Import(list, mod, sum io.Reader) (*spdx.Document, error) {
+	im := NewImporter()
+	if list != nil {
+		if err := im.List(list); err != nil {
+			return nil, err
+		}
+	}
+	if mod != nil {
+		if err := im.GoMod(mod); err != nil {
+			return nil, err
+		}
+	}
+	if sum != nil {
+		if err := im.GoSum(sum); err != nil {
+			return nil, err
+		}
+	}
+	return im.Document(), nil
+}
+
+// Returns the module with the given path, adding it if it is not known yet.
+func (im *Importer) module(path string) *module {
+	mod, ok := im.modules[path]
+	if !ok {
+		mod = &module{path: path}
+		im.modules[path] = mod
+		im.order = append(im.order, path)
+	}
+	return mod
+}
+
+// Read the output of `go list -m -json all`.
+func (im *Importer) List(r io.Reader) error {
+	list, err := parseList(r)
+	if err != nil {
+		return err
+	}
+	for _, lm := range list {
+		mod := im.module(lm.Path)
+		mod.version = lm.Version
+		mod.main = lm.Main
+		mod.dependency = dependency(lm.Indirect)
+		mod.replace = lm.Replace
+		if lm.Main && im.main == "" {
+			im.main = lm.Path
+		}
+	}
+	return nil
+}
+
+// Read a go.mod file. Its requirements are only added as modules if there is
+// no `go list` output read before it.
+func (im *Importer) GoMod(r io.Reader) error {
+	gm, err := parseGoMod(r)
+	if err != nil {
+		return err
+	}
+	fromList := len(im.order) > 0
+
+	if im.main == "" {
+		im.main = gm.module
+	}
+	im.module(gm.module).main = true
+
+	for _, req := range gm.requires {
+		mod, ok := im.modules[req.path]
+		if !ok {
+			if fromList {
+				continue
+			}
+			mod = im.module(req.path)
+			mod.version = req.version
+		}
+		mod.dependency = dependency(req.indirect)
+	}
+	return nil
+}
+
+// Returns the kind of dependency.
+func dependency(indirect bool) string {
+	if indirect {
+		return depIndirect
+	}
+	return depDirect
+}
+
+// Read a go.sum file.
+func (im *Importer) GoSum(r io.Reader) error {
+	sums, err := parseGoSum(r)
+	if err != nil {
+		return err
+	}
+	for k, v := range sums {
+		im.sums[k] = v
+	}
+	return nil
+}
+
+// Create the *spdx.Document describing all the modules read.
+func (im *Importer) Document() *spdx.Document {
+	doc := &spdx.Document{
+		SpecVersion: spdx.Str("SPDX-1.2", nil),
+		DataLicence: spdx.Str(spdx.DATA_LICENCE_TAG, nil),
+		CreationInfo: &spdx.CreationInfo{
+			Creator: []spdx.ValueCreator{spdx.NewValueCreator(Creator, nil)},
+			Created: spdx.NewValueDate(time.Now().UTC().Format(time.RFC3339), nil),
+		},
+	}
+	for _, path := range im.order {
+		doc.Packages = append(doc.Packages, im.pkg(im.modules[path]))
+	}
+	return doc
+}
+
+// Create the *spdx.Package for the given module.
+func (im *Importer) pkg(mod *module) *spdx.Package {
+	// the module that is actually used; replacements without a version are
+	// local directories
+	path, version := mod.path, mod.version
+	local := false
+	if mod.replace != nil {
+		if mod.replace.Version == "" {
+			local = true
+		} else {
+			path, version = mod.replace.Path, mod.replace.Version
+		}
+	}
+
+	pkg := &spdx.Package{
+		Name:             spdx.Str(mod.path, nil),
+		Version:          spdx.Str(version, nil),
+		Supplier:         spdx.NewValueCreator(Supplier(mod.path), nil),
+		DownloadLocation: spdx.Str(spdx.NOASSERTION, nil),
+		LicenceConcluded: spdx.NewLicence(spdx.NOASSERTION, nil),
+		LicenceDeclared:  spdx.NewLicence(spdx.NOASSERTION, nil),
+		CopyrightText:    spdx.Str(spdx.NOASSERTION, nil),
+	}
+
+	if !mod.main && !local && version != "" {
+		pkg.DownloadLocation.Val = DownloadLocation(path, version)
+	}
+
+	info := make([]string, 0, 4)
+	if mod.main {
+		info = append(info, "Main Go module.")
+	} else if im.main != "" {
+		dep := mod.dependency
+		if dep == depUnknown {
+			dep = "Dependency"
+		}
+		info = append(info, dep+" of "+im.main+".")
+	}
+	if mod.replace != nil {
+		info = append(info, strings.TrimSpace("Replaced by "+mod.replace.Path+" "+mod.replace.Version)+".")
+	}
+	if sum, ok := im.sums[path+"@"+version]; ok {
+		info = append(info, "Module hash: "+sum)
+	}
+	if sum, ok := im.sums[path+"@"+version+goModSuffix]; ok {
+		info = append(info, "go.mod hash: "+sum)
+	}
+	pkg.SourceInfo.Val = strings.Join(info, "\n")
+
+	return pkg
+}
+
+// Returns the supplier of a module, derived from its path. For modules on a
+// known code hosting site, the supplier is the owner of the repository,
+// otherwise the host.
+func Supplier(path string) string {
+	elems := strings.Split(path, "/")
+	for _, site := range hostingSites {
+		if elems[0] == site && len(elems) >= 2 {
+			return "Organization: " + elems[1]
+		}
+	}
+	return "Organization: " + elems[0]
+}
+
+// Returns the module proxy URL of the zip file of the given module version.
+func DownloadLocation(path, version string) string {
+	return strings.TrimSuffix(Proxy, "/") + "/" + escape(path) + "/@v/" + escape(version) + ".zip"
+}
+
+// Escape a module path or version for the module proxy protocol: upper case
+// letters are replaced by an exclamation mark followed by the lower case
+// letter.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,219 @@
+package gomod
+
+import (
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+const testGoMod = `module example.com/app
+
+go 1.21
+
+require github.com/Foo/bar v1.2.3
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+	example.com/local v0.0.0
+)
+
+replace example.com/local => ../local
+`
+
+const testGoSum = `github.com/Foo/bar v1.2.3 h1:modhash=
+github.com/Foo/bar v1.2.3/go.mod h1:gomodhash=
+golang.org/x/text v0.14.0/go.mod h1:texthash=
+`
+
+const testList = `{
+	"Path": "example.com/app",
+	"Main": true,
+	"Dir": "/src/app"
+}
+{
+	"Path": "github.com/Foo/bar",
+	"Version": "v1.2.3"
+}
+{
+	"Path": "golang.org/x/text",
+	"Version": "v0.14.0",
+	"Indirect": true
+}
+{
+	"Path": "example.com/local",
+	"Version": "v0.0.0",
+	"Replace": {"Path": "../local"}
+}
+`
+
+func TestParseGoMod(t *testing.T) {
+	mod, err := parseGoMod(strings.NewReader(testGoMod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mod.module != "example.com/app" {
+		t.Errorf("Wrong module %#v", mod.module)
+	}
+	expected := []require{
+		{"github.com/Foo/bar", "v1.2.3", false},
+		{"golang.org/x/text", "v0.14.0", true},
+		{"example.com/local", "v0.0.0", false},
+	}
+	if len(mod.requires) != len(expected) {
+		t.Fatalf("Expected %d requirements, found %d", len(expected), len(mod.requires))
+	}
+	for i, req := range expected {
+		if mod.requires[i] != req {
+			t.Errorf("Expected %+v but found %+v", req, mod.requires[i])
+		}
+	}
+}
+
+func TestParseGoModErrors(t *testing.T) {
+	if _, err := parseGoMod(strings.NewReader("go 1.21\n")); err == nil {
+		t.Error("No error for missing module directive.")
+	}
+	if _, err := parseGoMod(strings.NewReader("module a\nrequire b\n")); err == nil {
+		t.Error("No error for invalid require.")
+	}
+}
+
+func TestParseGoSum(t *testing.T) {
+	sums, err := parseGoSum(strings.NewReader(testGoSum))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sums["github.com/Foo/bar@v1.2.3"] != "h1:modhash=" || sums["github.com/Foo/bar@v1.2.3/go.mod"] != "h1:gomodhash=" {
+		t.Errorf("Wrong sums %+v", sums)
+	}
+	if _, err = parseGoSum(strings.NewReader("a v1\n")); err == nil {
+		t.Error("No error for invalid go.sum line.")
+	}
+}
+
+func TestSupplier(t *testing.T) {
+	tests := map[string]string{
+		"github.com/spdx/tools-go": "Organization: spdx",
+		"golang.org/x/text":        "Organization: golang.org",
+		"gopkg.in/yaml.v3":         "Organization: gopkg.in",
+		"example":                  "Organization: example",
+	}
+	for path, supplier := range tests {
+		if s := Supplier(path); s != supplier {
+			t.Errorf("Supplier(%#v) = %#v, expected %#v", path, s, supplier)
+		}
+	}
+}
+
+func TestDownloadLocation(t *testing.T) {
+	loc := DownloadLocation("github.com/Foo/bar", "v1.2.3")
+	if loc != "https://proxy.golang.org/github.com/!foo/bar/@v/v1.2.3.zip" {
+		t.Errorf("Wrong download location %s", loc)
+	}
+}
+
+func TestImport(t *testing.T) {
+	doc, err := Import(strings.NewReader(testList), strings.NewReader(testGoMod), strings.NewReader(testGoSum))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Packages) != 4 {
+		t.Fatalf("Expected 4 packages, found %d", len(doc.Packages))
+	}
+
+	app, bar, text, local := doc.Packages[0], doc.Packages[1], doc.Packages[2], doc.Packages[3]
+	if app.Name.Val != "example.com/app" || app.DownloadLocation.Val != "NOASSERTION" || app.SourceInfo.Val != "Main Go module." {
+		t.Errorf("Wrong main package %+v", app)
+	}
+
+	if bar.Version.Val != "v1.2.3" || bar.Supplier.V() != "Organization: Foo" {
+		t.Errorf("Wrong version or supplier %s %s", bar.Version.Val, bar.Supplier.V())
+	}
+	info := "Direct dependency of example.com/app.\nModule hash: h1:modhash=\ngo.mod hash: h1:gomodhash="
+	if bar.SourceInfo.Val != info {
+		t.Errorf("Wrong source info %#v", bar.SourceInfo.Val)
+	}
+
+	if !strings.HasPrefix(text.SourceInfo.Val, "Indirect dependency") {
+		t.Errorf("Wrong source info %#v", text.SourceInfo.Val)
+	}
+
+	if local.DownloadLocation.Val != "NOASSERTION" || !strings.Contains(local.SourceInfo.Val, "Replaced by ../local.") {
+		t.Errorf("Wrong local replacement %s %#v", local.DownloadLocation.Val, local.SourceInfo.Val)
+	}
+}
+
+func TestImportGoModOnly(t *testing.T) {
+	doc, err := Import(nil, strings.NewReader(testGoMod), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Packages) != 4 || doc.Packages[0].Name.Val != "example.com/app" {
+		t.Errorf("Wrong packages %+v", doc.Packages)
+	}
+	if doc.Packages[3].DownloadLocation.Val != DownloadLocation("example.com/local", "v0.0.0") {
+		t.Errorf("Wrong download location %s", doc.Packages[3].DownloadLocation.Val)
+	}
+}
+
+func TestIsList(t *testing.T) {
+	if !IsList([]byte(testList)) || !IsList([]byte("\n")) {
+		t.Error("go list output not detected.")
+	}
+	if IsList([]byte(testGoMod)) || IsList([]byte("// comment\nmodule a\n")) {
+		t.Error("go.mod detected as go list output.")
+	}
+}
+
+func TestBinary(t *testing.T) {
+	name, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	doc, err := BinaryFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Packages) == 0 || doc.Packages[0].SourceInfo.Val != "Main Go module." {
+		t.Fatalf("No main module package found: %+v", doc.Packages)
+	}
+	if len(doc.Files) != 1 || doc.Packages[0].Files[0] != doc.Files[0] {
+		t.Fatalf("Binary file not in the main package: %+v", doc.Files)
+	}
+	file := doc.Files[0]
+	if file.Name.Val != filepath.Base(name) || file.Type.Val != "BINARY" || len(file.Checksum.Value.Val) != 40 {
+		t.Errorf("Wrong binary file %s %s %s", file.Name.Val, file.Type.Val, file.Checksum.Value.Val)
+	}
+	if !strings.HasPrefix(file.Comment.Val, "Built with go") {
+		t.Errorf("Wrong file comment %#v", file.Comment.Val)
+	}
+}
+
+func TestBinaryNoModule(t *testing.T) {
+	tests := map[string]string{
+		"command-line-arguments": "tool",
+		"example.com/cmd/tool":   "example.com/cmd/tool",
+	}
+	for path, expected := range tests {
+		info := &debug.BuildInfo{GoVersion: "go1.22.0", Path: path}
+		r := strings.NewReader("binary")
+		doc, err := binary(info, r, r.Size(), "tool")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(doc.Packages) != 1 || doc.Packages[0].SourceInfo.Val != "Main Go module." {
+			t.Errorf("%s: No main module package found: %+v", path, doc.Packages)
+		} else if doc.Packages[0].Name.Val != expected {
+			t.Errorf("%s: Expected %v but found %v", path, expected, doc.Packages[0].Name.Val)
+		}
+	}
+}
+
+func TestBinaryNotGo(t *testing.T) {
+	r := strings.NewReader("not a binary")
+	if _, err := Binary(r, r.Size(), "x"); err == nil {
+		t.Error("No error for a file that is not a Go binary.")
+	}
+}
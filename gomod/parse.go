@@ -0,0 +1,177 @@
+package gomod
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// Error messages used when reading go.mod and go.sum files.
+var (
+	MsgInvalidRequire = "Invalid require directive. Expected: <module path> <version>."
+	MsgInvalidSum     = "Invalid go.sum line. Expected: <module path> <version>[/go.mod] <hash>."
+	MsgNoModule       = "No module directive found."
+)
+
+// The go.sum suffix of the versions that hash a go.mod file.
+const goModSuffix = "/go.mod"
+
+// A requirement found in a go.mod file.
+type require struct {
+	path, version string
+	indirect      bool
+}
+
+// The parts of a go.mod file used by the importer.
+type goModFile struct {
+	module   string
+	requires []require
+}
+
+// Parse the module path and the requirements of a go.mod file. Other directives
+// are ignored; replacements are expected to come from `go list -m -json`.
+func parseGoMod(r io.Reader) (*goModFile, error) {
+	mod := new(goModFile)
+	scanner := bufio.NewScanner(r)
+	inRequire := false
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text, comment := splitComment(scanner.Text())
+		fields := strings.Fields(text)
+
+		if inRequire {
+			if len(fields) == 1 && fields[0] == ")" {
+				inRequire = false
+				continue
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			req, err := parseRequire(fields, comment, line)
+			if err != nil {
+				return nil, err
+			}
+			mod.requires = append(mod.requires, req)
+			continue
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "module":
+			if len(fields) >= 2 {
+				mod.module = unquote(fields[1])
+			}
+		case "require":
+			if len(fields) == 2 && fields[1] == "(" {
+				inRequire = true
+				continue
+			}
+			req, err := parseRequire(fields[1:], comment, line)
+			if err != nil {
+				return nil, err
+			}
+			mod.requires = append(mod.requires, req)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if mod.module == "" {
+		return nil, spdx.NewParseError(MsgNoModule, nil)
+	}
+	return mod, nil
+}
+
+// Create a require from the fields of a require directive (without the
+// "require" keyword).
+func parseRequire(fields []string, comment string, line int) (require, error) {
+	if len(fields) != 2 {
+		return require{}, spdx.NewParseError(MsgInvalidRequire, spdx.NewMetaL(line))
+	}
+	return require{
+		path:     unquote(fields[0]),
+		version:  unquote(fields[1]),
+		indirect: strings.TrimSpace(comment) == "indirect",
+	}, nil
+}
+
+// Split a go.mod line into the text before the "//" comment and the comment.
+func splitComment(line string) (text, comment string) {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i], line[i+2:]
+	}
+	return line, ""
+}
+
+// Remove the quotes around a go.mod string, if any.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '`') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// Parse a go.sum file. The hashes are indexed by "path@version" for module
+// hashes and by "path@version/go.mod" for go.mod hashes.
+func parseGoSum(r io.Reader) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, spdx.NewParseError(MsgInvalidSum, spdx.NewMetaL(line))
+		}
+		sums[fields[0]+"@"+fields[1]] = fields[2]
+	}
+	return sums, scanner.Err()
+}
+
+// A module as written by `go list -m -json`.
+type listModule struct {
+	Path     string
+	Version  string
+	Main     bool
+	Indirect bool
+	Dir      string
+	Replace  *listModule
+}
+
+// Checks whether the input starting with `start` is the output of `go list -m
+// -json` (a stream of JSON objects) rather than a go.mod file. Empty input is
+// an empty `go list` output.
+func IsList(start []byte) bool {
+	start = bytes.TrimLeft(start, " \t\r\n")
+	return len(start) == 0 || start[0] == '{'
+}
+
+// Decode the stream of JSON objects written by `go list -m -json all`.
+func parseList(r io.Reader) ([]*listModule, error) {
+	dec := json.NewDecoder(r)
+	mods := make([]*listModule, 0)
+	for {
+		mod := new(listModule)
+		err := dec.Decode(mod)
+		if err == io.EOF {
+			return mods, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		mods = append(mods, mod)
+	}
+}
@@ -0,0 +1,74 @@
+package graph
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The Graphviz shapes of the node kinds.
+var dotShapes = map[int]string{
+	kindDocument: "note",
+	kindPackage:  "box3d",
+	kindFile:     "box",
+	kindDir:      "folder",
+	kindProject:  "component",
+	kindLicence:  "ellipse",
+}
+
+// Returns `s` as a DOT quoted string.
+func dotQuote(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\r", "", -1)
+	return `"` + strings.Replace(s, "\n", `\n`, -1) + `"`
+}
+
+// Writes the node statement of `n`.
+func dotNode(b *bytes.Buffer, indent string, n *node) {
+	fmt.Fprintf(b, "%s%s [label=%s, shape=%s];\n", indent, n.id, dotQuote(n.label), dotShapes[n.kind])
+}
+
+// Write the graph of `doc` to `w` in the Graphviz DOT format. The options may
+// be nil.
+func WriteDot(w io.Writer, doc *spdx.Document, opts *Options) error {
+	g := build(doc, opts)
+	var b bytes.Buffer
+	b.WriteString("digraph spdx {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("\tedge [fontname=\"Helvetica\", fontsize=9];\n\n")
+	dotNode(&b, "\t", g.doc)
+	for _, c := range g.clusters {
+		fmt.Fprintf(&b, "\n\tsubgraph %s {\n", c.id)
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", dotQuote(c.label))
+		b.WriteString("\t\tstyle=rounded;\n")
+		for _, n := range c.nodes {
+			dotNode(&b, "\t\t", n)
+		}
+		b.WriteString("\t}\n")
+	}
+	if len(g.edges) > 0 {
+		b.WriteString("\n")
+	}
+	for _, e := range g.edges {
+		var attrs []string
+		if e.label != "" {
+			attrs = append(attrs, "label="+dotQuote(e.label))
+		}
+		if e.dashed {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "\t%s -> %s", e.from.id, e.to.id)
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	_, err := b.WriteTo(w)
+	return err
+}
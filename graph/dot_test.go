@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	doc := testDoc(t)
+	doc.Packages[0].Name.Val = `"quoted" \ pkg`
+	var b bytes.Buffer
+	if err := WriteDot(&b, doc, nil); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	expected := []string{
+		"digraph spdx {\n",
+		"\tdoc [label=\"SPDX document\\nSPDX-1.2\", shape=note];\n",
+		"\tsubgraph cluster_pkg1 {\n\t\tlabel=\"\\\"quoted\\\" \\\\ pkg 1.0\";\n",
+		"\t\tpkg1 [label=\"\\\"quoted\\\" \\\\ pkg 1.0\", shape=box3d];\n",
+		"\t\tfile1 [label=\"./src/a.c\", shape=box];\n",
+		"\tsubgraph cluster_projects {\n",
+		"\t\tproject1 [label=\"Jena\", shape=component];\n",
+		"\t\tlicence3 [label=\"LicenseRef-1\", shape=ellipse];\n",
+		"\tdoc -> pkg1 [label=\"describes\"];\n",
+		"\tpkg1 -> licence1 [label=\"declared\", style=dashed];\n",
+		"\tfile1 -> licence1 [style=dashed];\n",
+	}
+	last := -1
+	for _, s := range expected {
+		i := strings.Index(out, s)
+		if i < 0 {
+			t.Errorf("%q not found in:\n%s", s, out)
+		} else if i < last {
+			t.Errorf("%q not in order in:\n%s", s, out)
+		}
+		last = i
+	}
+	if !strings.HasSuffix(out, "\tfile3 -> licence3 [style=dashed];\n}\n") {
+		t.Errorf("Wrong end of graph:\n%s", out)
+	}
+}
+
+func TestWriteDotCollapseDirs(t *testing.T) {
+	var b bytes.Buffer
+	if err := WriteDot(&b, testDoc(t), &Options{CollapseDirs: true}); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if !strings.Contains(out, "\t\tdir1 [label=\"src/ (2 files)\", shape=folder];\n") || strings.Contains(out, "file1") {
+		t.Errorf("Files not collapsed:\n%s", out)
+	}
+}
@@ -0,0 +1,272 @@
+// Package graph renders the structure of a SPDX document as a Graphviz DOT or
+// a Mermaid flowchart.
+//
+// The graph has a node for the document, one for each package, file,
+// ArtifactOf project and licence. The files are clustered by package (the
+// document files belong to the package when there is only one), the projects
+// and the licences have their own clusters. The edges are:
+//
+//   - document -> package (describes)
+//   - file -> file (File.Dependency)
+//   - file -> project (ArtifactOf)
+//   - file -> licence (the concluded licence or, if there is none, the licences
+//     found in the file)
+//   - package -> licence (the concluded and declared licences)
+//
+// With Options.CollapseDirs the files of a package are replaced by one node per
+// directory, which has the edges of all its files.
+package graph
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Output formats.
+const (
+	FormatDot     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+// Rendering options.
+type Options struct {
+	CollapseDirs bool // One node per directory instead of one per file
+}
+
+// Node kinds.
+const (
+	kindDocument = iota
+	kindPackage
+	kindFile
+	kindDir
+	kindProject
+	kindLicence
+)
+
+// A node of the graph.
+type node struct {
+	id    string
+	label string
+	kind  int
+	files []*spdx.File // files of a file or directory node
+}
+
+// A group of nodes drawn together.
+type cluster struct {
+	id    string
+	label string
+	nodes []*node
+}
+
+// An edge of the graph.
+type edge struct {
+	from, to *node
+	label    string
+	dashed   bool
+}
+
+// A document graph.
+type graph struct {
+	doc      *node
+	clusters []*cluster
+	edges    []*edge
+
+	ids     map[string]int  // number of nodes by id prefix
+	edgeIds map[string]bool // edges already added
+}
+
+// Returns a new node with a unique id starting with `prefix`.
+func (g *graph) node(prefix, label string, kind int) *node {
+	g.ids[prefix]++
+	return &node{id: prefix + strconv.Itoa(g.ids[prefix]), label: label, kind: kind}
+}
+
+// Adds the cluster `c` to the graph, if it has nodes.
+func (g *graph) addCluster(c *cluster) {
+	if len(c.nodes) > 0 {
+		g.clusters = append(g.clusters, c)
+	}
+}
+
+// Adds an edge, unless it is a loop or it was already added.
+func (g *graph) edge(from, to *node, label string, dashed bool) {
+	key := from.id + " " + to.id + " " + label
+	if from == to || g.edgeIds[key] {
+		return
+	}
+	g.edgeIds[key] = true
+	g.edges = append(g.edges, &edge{from, to, label, dashed})
+}
+
+// Returns the directory of the file `name`.
+func dir(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+strings.Replace(name, "\\", "/", -1)), "/")
+	if d := path.Dir(name); d != "" {
+		return d
+	}
+	return "."
+}
+
+// Returns the label of `pkg`.
+func pkgLabel(pkg *spdx.Package) string {
+	label := pkg.Name.Val
+	if pkg.Version.Val != "" {
+		label += " " + pkg.Version.Val
+	}
+	return label
+}
+
+// Build the graph of `doc`.
+func build(doc *spdx.Document, opts *Options) *graph {
+	if opts == nil {
+		opts = new(Options)
+	}
+	g := &graph{ids: make(map[string]int), edgeIds: make(map[string]bool)}
+	label := "SPDX document"
+	if doc.SpecVersion.Val != "" {
+		label += "\n" + doc.SpecVersion.Val
+	}
+	g.doc = &node{id: "doc", label: label, kind: kindDocument}
+
+	// file and directory nodes
+	files := make(map[*spdx.File]*node)
+	dirs := make(map[string]*node) // by cluster id and directory
+	addFiles := func(c *cluster, fs []*spdx.File) {
+		for _, f := range fs {
+			if f == nil || files[f] != nil {
+				continue
+			}
+			var n *node
+			key := c.id + " " + dir(f.Name.Val)
+			if !opts.CollapseDirs {
+				n = g.node("file", f.Name.Val, kindFile)
+				c.nodes = append(c.nodes, n)
+			} else if n = dirs[key]; n == nil {
+				n = g.node("dir", dir(f.Name.Val)+"/", kindDir)
+				dirs[key] = n
+				c.nodes = append(c.nodes, n)
+			}
+			n.files = append(n.files, f)
+			files[f] = n
+		}
+	}
+
+	var packages []*node
+	for i, pkg := range doc.Packages {
+		c := &cluster{id: "cluster_pkg" + strconv.Itoa(i+1), label: pkgLabel(pkg)}
+		n := g.node("pkg", pkgLabel(pkg), kindPackage)
+		packages = append(packages, n)
+		c.nodes = append(c.nodes, n)
+		addFiles(c, pkg.Files)
+		if len(doc.Packages) == 1 {
+			addFiles(c, doc.Files)
+		}
+		g.clusters = append(g.clusters, c)
+		g.edge(g.doc, n, "describes", false)
+	}
+	docFiles := &cluster{id: "cluster_files", label: "Files"}
+	addFiles(docFiles, doc.Files)
+	g.addCluster(docFiles)
+
+	// dependencies not in the document
+	other := &cluster{id: "cluster_other", label: "Other files"}
+	for _, c := range g.clusters {
+		for i := 0; i < len(c.nodes); i++ {
+			for _, f := range c.nodes[i].files {
+				addFiles(other, f.Dependency)
+			}
+		}
+	}
+	for i := 0; i < len(other.nodes); i++ {
+		for _, f := range other.nodes[i].files {
+			addFiles(other, f.Dependency)
+		}
+	}
+	g.addCluster(other)
+
+	projects := &cluster{id: "cluster_projects", label: "Projects"}
+	projectIds := make(map[string]*node)
+	licences := &cluster{id: "cluster_licences", label: "Licences"}
+	licenceIds := make(map[string]*node)
+	licence := func(from *node, lic spdx.AnyLicence, label string, dashed bool) {
+		for _, m := range spdx.LicenceMembers(lic) {
+			if spdx.IsEmpty(m.LicenceId()) {
+				continue
+			}
+			n, ok := licenceIds[m.LicenceId()]
+			if !ok {
+				n = g.node("licence", m.LicenceId(), kindLicence)
+				licenceIds[m.LicenceId()] = n
+				licences.nodes = append(licences.nodes, n)
+			}
+			g.edge(from, n, label, dashed)
+		}
+	}
+
+	for i, pkg := range doc.Packages {
+		licence(packages[i], pkg.LicenceConcluded, "concluded", false)
+		licence(packages[i], pkg.LicenceDeclared, "declared", true)
+	}
+
+	// the file edges, in the order of the nodes
+	for _, c := range g.clusters {
+		for _, n := range c.nodes {
+			for _, f := range n.files {
+				for _, dep := range f.Dependency {
+					if dep != nil {
+						g.edge(n, files[dep], "depends on", false)
+					}
+				}
+				for _, art := range f.ArtifactOf {
+					key := art.ProjectUri.Val + " " + art.Name.Val + " " + art.HomePage.Val
+					p, ok := projectIds[key]
+					if !ok {
+						label := art.Name.Val
+						if label == "" {
+							label = art.ProjectUri.Val
+						}
+						p = g.node("project", label, kindProject)
+						projectIds[key] = p
+						projects.nodes = append(projects.nodes, p)
+					}
+					g.edge(n, p, "artifact of", true)
+				}
+				lics := []spdx.AnyLicence{f.LicenceConcluded}
+				if f.LicenceConcluded == nil || spdx.IsEmpty(f.LicenceConcluded.LicenceId()) {
+					lics = f.LicenceInfoInFile
+				}
+				for _, lic := range lics {
+					licence(n, lic, "", true)
+				}
+			}
+		}
+	}
+	g.addCluster(projects)
+	g.addCluster(licences)
+
+	for _, n := range dirs {
+		if len(n.files) == 1 {
+			n.label += " (1 file)"
+		} else {
+			n.label += " (" + strconv.Itoa(len(n.files)) + " files)"
+		}
+	}
+	return g
+}
+
+// Write the graph of `doc` to `w` in the given format, FormatDot or
+// FormatMermaid. The options may be nil.
+func Write(w io.Writer, doc *spdx.Document, format string, opts *Options) error {
+	switch format {
+	case FormatDot:
+		return WriteDot(w, doc, opts)
+	case FormatMermaid:
+		return WriteMermaid(w, doc, opts)
+	}
+	return fmt.Errorf("Unknown graph format %q.", format)
+}
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testDocument = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+Creator: Person: Jane Doe
+Created: 2014-08-01T00:00:00Z
+
+PackageName: pkg
+PackageVersion: 1.0
+PackageDownloadLocation: NOASSERTION
+PackageLicenseConcluded: (Apache-2.0 and MIT)
+PackageLicenseDeclared: Apache-2.0
+PackageCopyrightText: NOASSERTION
+
+FileName: ./src/a.c
+LicenseConcluded: Apache-2.0
+ArtifactOfProjectName: Jena
+ArtifactOfProjectHomePage: http://jena.apache.org
+FileDependency: ./src/b.c
+FileDependency: ./lib/c.h
+
+FileName: ./src/b.c
+LicenseConcluded: NOASSERTION
+LicenseInfoInFile: MIT
+FileDependency: ./src/a.c
+
+FileName: ./lib/c.h
+LicenseConcluded: LicenseRef-1
+ArtifactOfProjectName: Jena
+ArtifactOfProjectHomePage: http://jena.apache.org
+
+LicenseID: LicenseRef-1
+ExtractedText: <text>Text</text>
+`
+
+// Returns testDocument.
+func testDoc(t *testing.T) *spdx.Document {
+	doc, err := tag.Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// Returns the labels of the nodes of `c`.
+func labels(c *cluster) []string {
+	res := make([]string, len(c.nodes))
+	for i, n := range c.nodes {
+		res[i] = n.label
+	}
+	return res
+}
+
+// Returns the edges of `g` as "from -label-> to" strings.
+func edges(g *graph) string {
+	var res []string
+	for _, e := range g.edges {
+		arrow := " -" + e.label + "-> "
+		if e.dashed {
+			arrow = " ." + e.label + ".> "
+		}
+		res = append(res, e.from.id+arrow+e.to.id)
+	}
+	return strings.Join(res, "\n")
+}
+
+func TestBuild(t *testing.T) {
+	g := build(testDoc(t), nil)
+	if len(g.clusters) != 3 {
+		t.Fatalf("Wrong number of clusters: %d", len(g.clusters))
+	}
+	expected := [][]string{
+		{"pkg 1.0", "./src/a.c", "./src/b.c", "./lib/c.h"},
+		{"Jena"},
+		{"Apache-2.0", "MIT", "LicenseRef-1"},
+	}
+	for i, c := range g.clusters {
+		if got := strings.Join(labels(c), ", "); got != strings.Join(expected[i], ", ") {
+			t.Errorf("Wrong cluster %s: %s", c.id, got)
+		}
+	}
+	expectedEdges := `doc -describes-> pkg1
+pkg1 -concluded-> licence1
+pkg1 -concluded-> licence2
+pkg1 .declared.> licence1
+file1 -depends on-> file2
+file1 -depends on-> file3
+file1 .artifact of.> project1
+file1 ..> licence1
+file2 -depends on-> file1
+file2 ..> licence2
+file3 .artifact of.> project1
+file3 ..> licence3`
+	if got := edges(g); got != expectedEdges {
+		t.Errorf("Wrong edges:\n%s", got)
+	}
+}
+
+func TestBuildCollapseDirs(t *testing.T) {
+	g := build(testDoc(t), &Options{CollapseDirs: true})
+	if got := strings.Join(labels(g.clusters[0]), ", "); got != "pkg 1.0, src/ (2 files), lib/ (1 file)" {
+		t.Errorf("Wrong package cluster: %s", got)
+	}
+	expectedEdges := `doc -describes-> pkg1
+pkg1 -concluded-> licence1
+pkg1 -concluded-> licence2
+pkg1 .declared.> licence1
+dir1 -depends on-> dir2
+dir1 .artifact of.> project1
+dir1 ..> licence1
+dir1 ..> licence2
+dir2 .artifact of.> project1
+dir2 ..> licence3`
+	if got := edges(g); got != expectedEdges {
+		t.Errorf("Wrong edges:\n%s", got)
+	}
+}
+
+func TestBuildFiles(t *testing.T) {
+	dep := &spdx.File{Name: spdx.Str("dep.c", nil)}
+	shared := &spdx.File{Name: spdx.Str("shared.c", nil), Dependency: []*spdx.File{dep, nil}}
+	doc := &spdx.Document{
+		Packages: []*spdx.Package{
+			{Name: spdx.Str("one", nil), Files: []*spdx.File{shared}},
+			{Name: spdx.Str("two", nil), Files: []*spdx.File{shared}},
+		},
+		Files: []*spdx.File{{Name: spdx.Str("doc.c", nil), LicenceConcluded: spdx.NewLicence(spdx.NONE, nil)}, shared},
+	}
+	g := build(doc, nil)
+	expected := []string{"one, shared.c", "two", "doc.c", "dep.c"}
+	if len(g.clusters) != len(expected) {
+		t.Fatalf("Wrong number of clusters: %d", len(g.clusters))
+	}
+	for i, c := range g.clusters {
+		if got := strings.Join(labels(c), ", "); got != expected[i] {
+			t.Errorf("Wrong cluster %s: %s", c.id, got)
+		}
+	}
+	if got := edges(g); !strings.HasSuffix(got, "file1 -depends on-> file3") || strings.Contains(got, "licence") {
+		t.Errorf("Wrong edges:\n%s", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, testDoc(t), FormatMermaid, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "flowchart LR\n") {
+		t.Errorf("Wrong output:\n%s", b.String())
+	}
+	if err := Write(&b, testDoc(t), "svg", nil); err == nil {
+		t.Error("No error for an unknown format.")
+	}
+}
@@ -0,0 +1,69 @@
+package graph
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The Mermaid shapes of the node kinds, as the opening and closing brackets.
+var mermaidShapes = map[int][2]string{
+	kindDocument: {">", "]"},
+	kindPackage:  {"[[", "]]"},
+	kindFile:     {"[", "]"},
+	kindDir:      {"[/", "/]"},
+	kindProject:  {"{{", "}}"},
+	kindLicence:  {"([", "])"},
+}
+
+// Escapes the characters of Mermaid labels as entity codes.
+var mermaidEscaper = strings.NewReplacer(
+	"#", "#35;",
+	`"`, "#quot;",
+	"<", "#lt;",
+	">", "#gt;",
+	"\r", "",
+	"\n", "<br>",
+)
+
+// Returns `s` as a Mermaid quoted label.
+func mermaidQuote(s string) string {
+	return `"` + mermaidEscaper.Replace(s) + `"`
+}
+
+// Writes the node statement of `n`.
+func mermaidNode(b *bytes.Buffer, indent string, n *node) {
+	shape := mermaidShapes[n.kind]
+	fmt.Fprintf(b, "%s%s%s%s%s\n", indent, n.id, shape[0], mermaidQuote(n.label), shape[1])
+}
+
+// Write the graph of `doc` to `w` as a Mermaid flowchart. The options may be
+// nil.
+func WriteMermaid(w io.Writer, doc *spdx.Document, opts *Options) error {
+	g := build(doc, opts)
+	var b bytes.Buffer
+	b.WriteString("flowchart LR\n")
+	mermaidNode(&b, "\t", g.doc)
+	for _, c := range g.clusters {
+		fmt.Fprintf(&b, "\tsubgraph %s [%s]\n", c.id, mermaidQuote(c.label))
+		for _, n := range c.nodes {
+			mermaidNode(&b, "\t\t", n)
+		}
+		b.WriteString("\tend\n")
+	}
+	for _, e := range g.edges {
+		arrow := "-->"
+		if e.dashed {
+			arrow = "-.->"
+		}
+		if e.label != "" {
+			arrow += "|" + mermaidQuote(e.label) + "|"
+		}
+		fmt.Fprintf(&b, "\t%s %s %s\n", e.from.id, arrow, e.to.id)
+	}
+	_, err := b.WriteTo(w)
+	return err
+}
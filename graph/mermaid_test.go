@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMermaid(t *testing.T) {
+	doc := testDoc(t)
+	doc.Packages[0].Name.Val = `<"pkg"> #1`
+	var b bytes.Buffer
+	if err := WriteMermaid(&b, doc, &Options{CollapseDirs: true}); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	expected := []string{
+		"flowchart LR\n",
+		"\tdoc>\"SPDX document<br>SPDX-1.2\"]\n",
+		"\tsubgraph cluster_pkg1 [\"#lt;#quot;pkg#quot;#gt; #35;1 1.0\"]\n",
+		"\t\tpkg1[[\"#lt;#quot;pkg#quot;#gt; #35;1 1.0\"]]\n",
+		"\t\tdir1[/\"src/ (2 files)\"/]\n",
+		"\tend\n",
+		"\t\tproject1{{\"Jena\"}}\n",
+		"\t\tlicence1([\"Apache-2.0\"])\n",
+		"\tdoc -->|\"describes\"| pkg1\n",
+		"\tpkg1 -.->|\"declared\"| licence1\n",
+		"\tdir1 -->|\"depends on\"| dir2\n",
+		"\tdir1 -.-> licence1\n",
+	}
+	last := -1
+	for _, s := range expected {
+		i := strings.Index(out, s)
+		if i < 0 {
+			t.Errorf("%q not found in:\n%s", s, out)
+		} else if i < last {
+			t.Errorf("%q not in order in:\n%s", s, out)
+		}
+		last = i
+	}
+}
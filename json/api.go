@@ -0,0 +1,35 @@
+// Package json reads and writes SPDX documents in the SPDX JSON format.
+//
+// The properties have the names of the SPDX JSON schema; where the schema
+// has more than the spdx.Document model (e.g. multiple checksums or file
+// types) only the values the model can hold are read. The properties the
+// model does not have at all (e.g. the SPDXID or the relationships of SPDX
+// 2.x) are kept as extensions and written back. Licences are licence
+// expressions, as in the Tag format. Files are referred to by their SPDXID
+// ("hasFiles", "fileDependencies"); names are accepted too.
+package json
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"io"
+)
+
+// Decode a io.Reader and Parse it to a *spdx.Document. If there is an error
+// in the input, it is of type *spdx.ParseError. All the elements and values
+// have *spdx.Meta with the lines they span in the input.
+func Build(r io.Reader) (*spdx.Document, error) {
+	root, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(root)
+}
+
+// Write a *spdx.Document to the given io.Writer
+func Write(w io.Writer, doc *spdx.Document) error {
+	if doc == nil {
+		return nil
+	}
+	return Encode(w, DocumentNode(doc))
+}
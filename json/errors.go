@@ -0,0 +1,14 @@
+package json
+
+// Error messages used by the decoder
+var (
+	MsgTrailingData    = "Unexpected data after the JSON document."
+	MsgUnexpectedEOF   = "Unexpected end of input."
+	MsgUnexpectedDelim = "Unexpected delimiter "
+)
+
+// Error messages used by the parser
+var (
+	MsgInvalidType    = "Invalid value type. Expected %s, found %s."
+	MsgAlreadyDefined = "Property already defined"
+)
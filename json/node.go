@@ -0,0 +1,316 @@
+package json
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// Kinds of Node.
+const (
+	KindNull = iota
+	KindString
+	KindNumber
+	KindBool
+	KindObject
+	KindArray
+)
+
+// Node is a JSON value with the lines it spans in the input. It is the
+// intermediate representation between the SPDX JSON documents and
+// spdx.Document, and can be built from other JSON-like formats too.
+//
+// Scalars (strings, numbers and booleans) have their value in Value, objects
+// have their members in Fields (in input order) and arrays their elements in
+// Items.
+type Node struct {
+	Kind   int
+	Value  string
+	Fields []*Field
+	Items  []*Node
+	*spdx.Meta
+}
+
+// A member of an object Node.
+type Field struct {
+	Key   string
+	Value *Node
+}
+
+// Create a new string *Node.
+func String(val string, m *spdx.Meta) *Node {
+	return &Node{Kind: KindString, Value: val, Meta: m}
+}
+
+// Create a new empty object *Node.
+func Object(m *spdx.Meta) *Node {
+	return &Node{Kind: KindObject, Meta: m}
+}
+
+// Create a new empty array *Node.
+func Array(m *spdx.Meta) *Node {
+	return &Node{Kind: KindArray, Meta: m}
+}
+
+// Append a member to an object Node.
+func (n *Node) Add(key string, val *Node) {
+	n.Fields = append(n.Fields, &Field{key, val})
+}
+
+// Returns the member `key` of an object Node or nil if there is none.
+func (n *Node) Get(key string) *Node {
+	for _, f := range n.Fields {
+		if f.Key == key {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+// Returns the name of the kind of Node, as used in error messages.
+func kindName(kind int) string {
+	return [...]string{"null", "string", "number", "boolean", "object", "array"}[kind]
+}
+
+// Decoder reads JSON into a Node tree, keeping track of the lines every value
+// spans.
+type decoder struct {
+	data  []byte
+	dec   *json.Decoder
+	lines []int // offsets of the newlines in data
+	last  int   // offset after the last token read
+}
+
+// Decode the JSON value in `r` to a *Node. Syntax errors are of type
+// *spdx.ParseError.
+func Decode(r io.Reader) (*Node, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &decoder{data: data, dec: json.NewDecoder(bytes.NewReader(data))}
+	d.dec.UseNumber()
+	for i, c := range data {
+		if c == '\n' {
+			d.lines = append(d.lines, i)
+		}
+	}
+
+	n, err := d.value()
+	if err != nil {
+		return nil, err
+	}
+	if _, err = d.dec.Token(); err != io.EOF {
+		return nil, spdx.NewParseError(MsgTrailingData, spdx.NewMetaL(d.line(d.start())))
+	}
+	return n, nil
+}
+
+// Returns the line of the byte at offset.
+func (d *decoder) line(offset int) int {
+	return sort.SearchInts(d.lines, offset) + 1
+}
+
+// Returns the offset of the next token, skipping the spaces and separators
+// after the last token read.
+func (d *decoder) start() int {
+	i := d.last
+	for i < len(d.data) && strings.IndexByte(" \t\r\n,:", d.data[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// Read the next token and return it with the line it starts on.
+func (d *decoder) token() (json.Token, int, error) {
+	line := d.line(d.start())
+	tok, err := d.dec.Token()
+	if err != nil {
+		if e, ok := err.(*json.SyntaxError); ok {
+			return nil, 0, spdx.NewParseError(e.Error(), spdx.NewMetaL(d.line(int(e.Offset))))
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, 0, spdx.NewParseError(MsgUnexpectedEOF, spdx.NewMetaL(d.line(len(d.data))))
+		}
+		return nil, 0, err
+	}
+	d.last = int(d.dec.InputOffset())
+	return tok, line, nil
+}
+
+// Returns the line of the end of the last token read.
+func (d *decoder) lastLine() int {
+	return d.line(d.last - 1)
+}
+
+// Read a JSON value.
+func (d *decoder) value() (*Node, error) {
+	tok, line, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return d.object(line)
+		}
+		if t == '[' {
+			return d.array(line)
+		}
+		return nil, spdx.NewParseError(MsgUnexpectedDelim+string(t), spdx.NewMetaL(line))
+	case string:
+		return &Node{Kind: KindString, Value: t, Meta: spdx.NewMeta(line, d.lastLine())}, nil
+	case json.Number:
+		return &Node{Kind: KindNumber, Value: string(t), Meta: spdx.NewMetaL(line)}, nil
+	case bool:
+		val := "false"
+		if t {
+			val = "true"
+		}
+		return &Node{Kind: KindBool, Value: val, Meta: spdx.NewMetaL(line)}, nil
+	}
+	return &Node{Kind: KindNull, Meta: spdx.NewMetaL(line)}, nil
+}
+
+// Read the members of an object, after the opening brace.
+func (d *decoder) object(line int) (*Node, error) {
+	n := Object(nil)
+	for d.dec.More() {
+		tok, _, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+		val, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		n.Add(tok.(string), val)
+	}
+	if _, _, err := d.token(); err != nil {
+		return nil, err
+	}
+	n.Meta = spdx.NewMeta(line, d.lastLine())
+	return n, nil
+}
+
+// Read the elements of an array, after the opening bracket.
+func (d *decoder) array(line int) (*Node, error) {
+	n := Array(nil)
+	for d.dec.More() {
+		val, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+		n.Items = append(n.Items, val)
+	}
+	if _, _, err := d.token(); err != nil {
+		return nil, err
+	}
+	n.Meta = spdx.NewMeta(line, d.lastLine())
+	return n, nil
+}
+
+// Encode the Node as indented JSON to `w`.
+func Encode(w io.Writer, n *Node) error {
+	var b bytes.Buffer
+	encode(&b, n, "")
+	b.WriteByte('\n')
+	_, err := b.WriteTo(w)
+	return err
+}
+
+// Write the JSON of `n` to `b`. Nested values are indented by two spaces more
+// than `indent`.
+func encode(b *bytes.Buffer, n *Node, indent string) {
+	switch n.Kind {
+	case KindString:
+		b.WriteString(quote(n.Value))
+	case KindNumber, KindBool:
+		b.WriteString(n.Value)
+	case KindObject:
+		if len(n.Fields) == 0 {
+			b.WriteString("{}")
+			return
+		}
+		b.WriteString("{\n")
+		for i, f := range n.Fields {
+			b.WriteString(indent + "  " + quote(f.Key) + ": ")
+			encode(b, f.Value, indent+"  ")
+			if i < len(n.Fields)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent + "}")
+	case KindArray:
+		if len(n.Items) == 0 {
+			b.WriteString("[]")
+			return
+		}
+		b.WriteString("[\n")
+		for i, item := range n.Items {
+			b.WriteString(indent + "  ")
+			encode(b, item, indent+"  ")
+			if i < len(n.Items)-1 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString(indent + "]")
+	default:
+		b.WriteString("null")
+	}
+}
+
+// Returns the JSON of `n` on one line, without spaces.
+func compact(n *Node) string {
+	var b bytes.Buffer
+	var enc func(n *Node)
+	enc = func(n *Node) {
+		switch n.Kind {
+		case KindString:
+			b.WriteString(quote(n.Value))
+		case KindNumber, KindBool:
+			b.WriteString(n.Value)
+		case KindObject:
+			b.WriteByte('{')
+			for i, f := range n.Fields {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				b.WriteString(quote(f.Key) + ":")
+				enc(f.Value)
+			}
+			b.WriteByte('}')
+		case KindArray:
+			b.WriteByte('[')
+			for i, item := range n.Items {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				enc(item)
+			}
+			b.WriteByte(']')
+		default:
+			b.WriteString("null")
+		}
+	}
+	enc(n)
+	return b.String()
+}
+
+// Returns the JSON string literal of `s`, without escaping HTML characters.
+func quote(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
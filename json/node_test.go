@@ -0,0 +1,86 @@
+package json
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDecodeLines(t *testing.T) {
+	input := `{
+  "a": "x",
+  "b": [
+    1,
+    true
+  ],
+  "c": {}
+}`
+	n, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Kind != KindObject || n.LineStart != 1 || n.LineEnd != 8 || len(n.Fields) != 3 {
+		t.Fatalf("Wrong root node %+v", n)
+	}
+	if a := n.Get("a"); a.Kind != KindString || a.Value != "x" || a.LineStart != 2 {
+		t.Errorf("Wrong string node %+v", a)
+	}
+	b := n.Get("b")
+	if b.Kind != KindArray || b.LineStart != 3 || b.LineEnd != 6 || len(b.Items) != 2 {
+		t.Fatalf("Wrong array node %+v", b)
+	}
+	if b.Items[0].Kind != KindNumber || b.Items[0].LineStart != 4 || b.Items[1].Value != "true" || b.Items[1].LineStart != 5 {
+		t.Errorf("Wrong array items %+v %+v", b.Items[0], b.Items[1])
+	}
+	if c := n.Get("c"); c.Kind != KindObject || c.LineStart != 7 {
+		t.Errorf("Wrong object node %+v", c)
+	}
+	if n.Get("d") != nil {
+		t.Error("Found a node that does not exist.")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	inputs := map[string]int{
+		"{\n\"a\": \"b\"\n": 3,
+		"{\n\"a\": x}":      2,
+		"{}\n{}":            2,
+	}
+	for input, line := range inputs {
+		_, err := Decode(strings.NewReader(input))
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("No parse error for %#v: %v", input, err)
+			continue
+		}
+		if perr.LineStart != line {
+			t.Errorf("Wrong line for %#v: %d, expected %d (%s)", input, perr.LineStart, line, perr.Error())
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	n := Object(nil)
+	n.Add("a", String("<b> & \"c\"", nil))
+	arr := Array(nil)
+	arr.Items = append(arr.Items, String("x", nil), Object(nil))
+	n.Add("list", arr)
+
+	var b bytes.Buffer
+	if err := Encode(&b, n); err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "a": "<b> & \"c\"",
+  "list": [
+    "x",
+    {}
+  ]
+}
+`
+	if b.String() != expected {
+		t.Errorf("Wrong output:\n%s\nExpected:\n%s", b.String(), expected)
+	}
+}
@@ -0,0 +1,400 @@
+package json
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A function that takes a *Node and updates some value in a SPDX element
+// (e.g. value SPDXVersion in spdx.Document).
+//
+// All the upd* functions (upd, updList, updCreator, etc.) return updaters for common SPDX values.
+type updater func(*Node) error
+
+// A map of SPDX JSON properties and updater functions
+type updaterMapping map[string]updater
+
+// Returns an error if the Node is not of the given kind.
+func expect(n *Node, kind int) error {
+	if n.Kind != kind {
+		return spdx.NewParseError(fmt.Sprintf(MsgInvalidType, kindName(kind), kindName(n.Kind)), n.Meta)
+	}
+	return nil
+}
+
+// Returns the string value of a Node. Numbers and booleans are accepted as
+// strings too.
+func str(n *Node) (string, error) {
+	if n.Kind == KindNumber || n.Kind == KindBool {
+		return n.Value, nil
+	}
+	return n.Value, expect(n, KindString)
+}
+
+// Call f for every item of the array Node n.
+func each(n *Node, f updater) error {
+	if err := expect(n, KindArray); err != nil {
+		return err
+	}
+	for _, item := range n.Items {
+		if err := f(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply the mapping to every member of the object Node n. Members without an
+// updater in the mapping (e.g. the "relationships" of SPDX 2.x documents) are
+// kept in `ext` with their JSON text, or ignored if `ext` is nil.
+func applyMapping(n *Node, mapping updaterMapping, ext *[]spdx.Extension) error {
+	if err := expect(n, KindObject); err != nil {
+		return err
+	}
+	set := make(map[string]bool)
+	for _, f := range n.Fields {
+		if set[f.Key] {
+			return spdx.NewParseError(MsgAlreadyDefined, f.Value.Meta)
+		}
+		set[f.Key] = true
+		upd, ok := mapping[f.Key]
+		if !ok {
+			if ext != nil {
+				*ext = append(*ext, spdx.Extension{Key: f.Key, Value: compact(f.Value), Json: true, Meta: f.Value.Meta})
+			}
+			continue
+		}
+		if err := upd(f.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the value of the identifier `key` or "" if there is none.
+func identifier(ids []spdx.Extension, key string) string {
+	for _, id := range ids {
+		if id.Key == key {
+			return id.Value
+		}
+	}
+	return ""
+}
+
+// Update the identifiers of an element (see spdx.Package.Identifiers) with
+// the value of the member `key`.
+func updIdentifier(ids *[]spdx.Extension, key string) updater {
+	return func(n *Node) error {
+		val, err := str(n)
+		*ids = append(*ids, spdx.Extension{Key: key, Value: val, Meta: n.Meta})
+		return err
+	}
+}
+
+// Update the spdx.ValueStr pointer ptr.
+func upd(ptr *spdx.ValueStr) updater {
+	return func(n *Node) error {
+		val, err := str(n)
+		*ptr = spdx.Str(val, n.Meta)
+		return err
+	}
+}
+
+// Update the []spdx.ValueStr pointer arr.
+func updList(arr *[]spdx.ValueStr) updater {
+	return func(n *Node) error {
+		return each(n, func(item *Node) error {
+			val, err := str(item)
+			*arr = append(*arr, spdx.Str(val, item.Meta))
+			return err
+		})
+	}
+}
+
+// Update the []spdx.ValueStr pointer arr with either a string or an array of
+// strings.
+func updStrOrList(arr *[]spdx.ValueStr) updater {
+	return func(n *Node) error {
+		if n.Kind == KindArray {
+			return updList(arr)(n)
+		}
+		val, err := str(n)
+		*arr = append(*arr, spdx.Str(val, n.Meta))
+		return err
+	}
+}
+
+// Update the spdx.ValueCreator pointer ptr.
+func updCreator(ptr *spdx.ValueCreator) updater {
+	return func(n *Node) error {
+		val, err := str(n)
+		*ptr = spdx.NewValueCreator(val, n.Meta)
+		return err
+	}
+}
+
+// Update the []spdx.ValueCreator pointer arr.
+func updCreatorList(arr *[]spdx.ValueCreator) updater {
+	return func(n *Node) error {
+		return each(n, func(item *Node) error {
+			val, err := str(item)
+			*arr = append(*arr, spdx.NewValueCreator(val, item.Meta))
+			return err
+		})
+	}
+}
+
+// Update the spdx.ValueDate pointer ptr.
+func updDate(ptr *spdx.ValueDate) updater {
+	return func(n *Node) error {
+		val, err := str(n)
+		*ptr = spdx.NewValueDate(val, n.Meta)
+		return err
+	}
+}
+
+// Update a AnyLicence pointer. Licences are expressions as in the Tag format;
+// the outer parentheses of a licence set may be left out, as in SPDX 2.x
+// (e.g. "MIT OR Apache-2.0").
+func anyLicence(lic *spdx.AnyLicence) updater {
+	return func(n *Node) error {
+		val, err := str(n)
+		if err != nil {
+			return err
+		}
+		val = strings.TrimSpace(val)
+		if strings.ContainsAny(val, " \t\n") && !strings.HasPrefix(val, "(") {
+			val = "(" + val + ")"
+		}
+		*lic, err = tag.ParseLicence(val, n.Meta)
+		return err
+	}
+}
+
+// Update a []AnyLicence pointer.
+func anyLicenceList(licList *[]spdx.AnyLicence) updater {
+	return func(n *Node) error {
+		return each(n, func(item *Node) error {
+			var lic spdx.AnyLicence
+			if err := anyLicence(&lic)(item); err != nil {
+				return err
+			}
+			*licList = append(*licList, lic)
+			return nil
+		})
+	}
+}
+
+// Update a *spdx.Checksum pointer from a "checksums" array. The model only has
+// one checksum, so the SHA1 checksum is used if there is one, otherwise the
+// first one.
+func updChecksums(ptr **spdx.Checksum) updater {
+	return func(n *Node) error {
+		return each(n, func(item *Node) error {
+			cksum := &spdx.Checksum{Meta: item.Meta}
+			err := applyMapping(item, updaterMapping{
+				"algorithm":     upd(&cksum.Algo),
+				"checksumValue": upd(&cksum.Value),
+			}, nil)
+			if err != nil {
+				return err
+			}
+			if *ptr == nil || ((*ptr).Algo.Val != "SHA1" && cksum.Algo.Val == "SHA1") {
+				*ptr = cksum
+			}
+			return nil
+		})
+	}
+}
+
+// Update a *spdx.VerificationCode pointer.
+func updVerifCode(ptr **spdx.VerificationCode) updater {
+	return func(n *Node) error {
+		vc := &spdx.VerificationCode{Meta: n.Meta}
+		*ptr = vc
+		return applyMapping(n, updaterMapping{
+			"packageVerificationCodeValue":         upd(&vc.Value),
+			"packageVerificationCodeExcludedFiles": updList(&vc.ExcludedFiles),
+		}, nil)
+	}
+}
+
+// Creates files that only have the FileName and appends them to the given
+// []*File pointer. The names may be the SPDX identifiers of the files, as in
+// SPDX 2.x; see resolveFileIds() and tag.ResolveReferences().
+func updFileNameList(fl *[]*spdx.File) updater {
+	return func(n *Node) error {
+		return each(n, func(item *Node) error {
+			name, err := str(item)
+			*fl = append(*fl, &spdx.File{Name: spdx.Str(name, item.Meta)})
+			return err
+		})
+	}
+}
+
+// Returns the mapping of a *spdx.Document in SPDX JSON.
+func documentMap(doc *spdx.Document) updaterMapping {
+	return updaterMapping{
+		"SPDXID":            updIdentifier(&doc.Identifiers, "SPDXID"),
+		"name":              updIdentifier(&doc.Identifiers, "name"),
+		"documentNamespace": updIdentifier(&doc.Identifiers, "documentNamespace"),
+		"spdxVersion":       upd(&doc.SpecVersion),
+		"dataLicense":       upd(&doc.DataLicence),
+		"comment":           upd(&doc.Comment),
+		"creationInfo": func(n *Node) error {
+			ci := &spdx.CreationInfo{Meta: n.Meta}
+			doc.CreationInfo = ci
+			return applyMapping(n, updaterMapping{
+				"creators":           updCreatorList(&ci.Creator),
+				"created":            updDate(&ci.Created),
+				"comment":            upd(&ci.Comment),
+				"licenseListVersion": upd(&ci.LicenceListVersion),
+			}, &ci.Extensions)
+		},
+		"packages": func(n *Node) error {
+			return each(n, func(item *Node) error {
+				pkg := &spdx.Package{Meta: item.Meta}
+				doc.Packages = append(doc.Packages, pkg)
+				return applyMapping(item, packageMap(pkg), &pkg.Extensions)
+			})
+		},
+		"files": func(n *Node) error {
+			return each(n, func(item *Node) error {
+				file := &spdx.File{Meta: item.Meta}
+				doc.Files = append(doc.Files, file)
+				return applyMapping(item, fileMap(file), &file.Extensions)
+			})
+		},
+		"hasExtractedLicensingInfos": func(n *Node) error {
+			return each(n, func(item *Node) error {
+				lic := &spdx.ExtractedLicence{Meta: item.Meta}
+				doc.ExtractedLicences = append(doc.ExtractedLicences, lic)
+				return applyMapping(item, updaterMapping{
+					"licenseId":     upd(&lic.Id),
+					"extractedText": upd(&lic.Text),
+					"name":          updStrOrList(&lic.Name),
+					"seeAlsos":      updList(&lic.CrossReference),
+					"comment":       upd(&lic.Comment),
+				}, &lic.Extensions)
+			})
+		},
+		"revieweds": func(n *Node) error {
+			return each(n, func(item *Node) error {
+				rev := &spdx.Review{Meta: item.Meta}
+				doc.Reviews = append(doc.Reviews, rev)
+				return applyMapping(item, updaterMapping{
+					"reviewer":   updCreator(&rev.Reviewer),
+					"reviewDate": updDate(&rev.Date),
+					"comment":    upd(&rev.Comment),
+				}, &rev.Extensions)
+			})
+		},
+	}
+}
+
+// Returns the mapping of a *spdx.Package in SPDX JSON.
+func packageMap(pkg *spdx.Package) updaterMapping {
+	return updaterMapping{
+		"SPDXID":                  updIdentifier(&pkg.Identifiers, "SPDXID"),
+		"name":                    upd(&pkg.Name),
+		"versionInfo":             upd(&pkg.Version),
+		"packageFileName":         upd(&pkg.FileName),
+		"supplier":                updCreator(&pkg.Supplier),
+		"originator":              updCreator(&pkg.Originator),
+		"downloadLocation":        upd(&pkg.DownloadLocation),
+		"packageVerificationCode": updVerifCode(&pkg.VerificationCode),
+		"checksums":               updChecksums(&pkg.Checksum),
+		"homepage":                upd(&pkg.HomePage),
+		"sourceInfo":              upd(&pkg.SourceInfo),
+		"licenseConcluded":        anyLicence(&pkg.LicenceConcluded),
+		"licenseInfoFromFiles":    anyLicenceList(&pkg.LicenceInfoFromFiles),
+		"licenseDeclared":         anyLicence(&pkg.LicenceDeclared),
+		"licenseComments":         upd(&pkg.LicenceComments),
+		"copyrightText":           upd(&pkg.CopyrightText),
+		"summary":                 upd(&pkg.Summary),
+		"description":             upd(&pkg.Description),
+		"hasFiles":                updFileNameList(&pkg.Files),
+	}
+}
+
+// Returns the mapping of a *spdx.File in SPDX JSON.
+func fileMap(file *spdx.File) updaterMapping {
+	return updaterMapping{
+		"SPDXID":   updIdentifier(&file.Identifiers, "SPDXID"),
+		"fileName": upd(&file.Name),
+		"fileTypes": func(n *Node) error {
+			var types []spdx.ValueStr
+			if err := updList(&types)(n); err != nil {
+				return err
+			}
+			if len(types) > 0 {
+				file.Type = types[0]
+			}
+			return nil
+		},
+		"checksums":          updChecksums(&file.Checksum),
+		"licenseConcluded":   anyLicence(&file.LicenceConcluded),
+		"licenseInfoInFiles": anyLicenceList(&file.LicenceInfoInFile),
+		"licenseComments":    upd(&file.LicenceComments),
+		"copyrightText":      upd(&file.CopyrightText),
+		"noticeText":         upd(&file.Notice),
+		"fileContributors":   updList(&file.Contributor),
+		"fileDependencies":   updFileNameList(&file.Dependency),
+		"comment":            upd(&file.Comment),
+		"artifactOfs": func(n *Node) error {
+			return each(n, func(item *Node) error {
+				artif := &spdx.ArtifactOf{Meta: item.Meta}
+				file.ArtifactOf = append(file.ArtifactOf, artif)
+				return applyMapping(item, updaterMapping{
+					"name":       upd(&artif.Name),
+					"homePage":   upd(&artif.HomePage),
+					"projectUri": upd(&artif.ProjectUri),
+				}, nil)
+			})
+		},
+	}
+}
+
+// Replaces the SPDX identifiers in the files of the packages and the file
+// dependencies by the names of the files they identify.
+func resolveFileIds(doc *spdx.Document) {
+	names := make(map[string]string)
+	for _, file := range doc.Files {
+		if id := identifier(file.Identifiers, "SPDXID"); id != "" {
+			names[id] = file.Name.Val
+		}
+	}
+	resolve := func(files []*spdx.File) {
+		for _, f := range files {
+			if name, ok := names[f.Name.Val]; ok {
+				f.Name = spdx.Str(name, f.Name.Meta)
+			}
+		}
+	}
+	for _, pkg := range doc.Packages {
+		resolve(pkg.Files)
+	}
+	for _, file := range doc.Files {
+		resolve(file.Dependency)
+	}
+}
+
+// Parse a Node tree in the SPDX JSON structure to a *spdx.Document. Licence
+// references and file names are resolved as in the Tag format (see
+// tag.ResolveReferences()). The SPDXID of the elements and the name and the
+// documentNamespace of the document are kept in their Identifiers, and the
+// other properties which are not in the model in their Extensions.
+func Parse(root *Node) (*spdx.Document, error) {
+	doc := &spdx.Document{Meta: root.Meta}
+	if err := applyMapping(root, documentMap(doc), &doc.Extensions); err != nil {
+		return nil, err
+	}
+	resolveFileIds(doc)
+	tag.ResolveReferences(doc)
+	return doc, nil
+}
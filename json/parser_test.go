@@ -0,0 +1,182 @@
+package json
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDocument = `{
+  "spdxVersion": "SPDX-1.2",
+  "dataLicense": "CC0-1.0",
+  "creationInfo": {
+    "creators": [
+      "Tool: spdx-go"
+    ],
+    "created": "2014-08-01T00:00:00Z"
+  },
+  "packages": [
+    {
+      "name": "pkg",
+      "checksums": [
+        {
+          "algorithm": "MD5",
+          "checksumValue": "d41d8cd98f00b204e9800998ecf8427e"
+        },
+        {
+          "algorithm": "SHA1",
+          "checksumValue": "da39a3ee5e6b4b0d3255bfef95601890afd80709"
+        }
+      ],
+      "licenseDeclared": "(MIT or LicenseRef-1)",
+      "hasFiles": [
+        "./a.c"
+      ]
+    }
+  ],
+  "files": [
+    {
+      "fileName": "./a.c",
+      "fileTypes": [
+        "SOURCE"
+      ],
+      "licenseInfoInFiles": [
+        "LicenseRef-1"
+      ],
+      "fileDependencies": [
+        "./b.c"
+      ]
+    },
+    {
+      "fileName": "./b.c"
+    }
+  ],
+  "hasExtractedLicensingInfos": [
+    {
+      "licenseId": "LicenseRef-1",
+      "extractedText": "Some licence",
+      "name": "Some"
+    }
+  ]
+}`
+
+func TestBuild(t *testing.T) {
+	doc, err := Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SpecVersion.Val != "SPDX-1.2" || doc.SpecVersion.Meta.LineStart != 2 {
+		t.Errorf("Wrong spec version %+v", doc.SpecVersion)
+	}
+	if doc.CreationInfo == nil || len(doc.CreationInfo.Creator) != 1 || doc.CreationInfo.Creator[0].What() != "Tool" {
+		t.Fatalf("Wrong creation info %+v", doc.CreationInfo)
+	}
+	if doc.CreationInfo.LineStart != 4 || doc.CreationInfo.LineEnd != 9 {
+		t.Errorf("Wrong creation info lines %+v", doc.CreationInfo.Meta)
+	}
+
+	if len(doc.Packages) != 1 || len(doc.Files) != 2 || len(doc.ExtractedLicences) != 1 {
+		t.Fatalf("Wrong number of elements: %d packages, %d files, %d licences", len(doc.Packages), len(doc.Files), len(doc.ExtractedLicences))
+	}
+	pkg, lic := doc.Packages[0], doc.ExtractedLicences[0]
+	if pkg.LineStart != 11 || pkg.LineEnd != 27 {
+		t.Errorf("Wrong package lines %+v", pkg.Meta)
+	}
+	if pkg.Checksum == nil || pkg.Checksum.Algo.Val != "SHA1" || pkg.Checksum.LineStart != 18 {
+		t.Errorf("Wrong package checksum %+v", pkg.Checksum)
+	}
+	set, ok := pkg.LicenceDeclared.(spdx.DisjunctiveLicenceSet)
+	if !ok || len(set.Members) != 2 || set.Members[1] != lic {
+		t.Errorf("Wrong declared licence %#v", pkg.LicenceDeclared)
+	}
+	if len(pkg.Files) != 1 || pkg.Files[0] != doc.Files[0] {
+		t.Errorf("Package files not resolved %+v", pkg.Files)
+	}
+
+	file := doc.Files[0]
+	if file.Type.Val != "SOURCE" || file.LicenceInfoInFile[0] != lic {
+		t.Errorf("Wrong file %+v", file)
+	}
+	if len(file.Dependency) != 1 || file.Dependency[0] != doc.Files[1] {
+		t.Errorf("File dependencies not resolved %+v", file.Dependency)
+	}
+	if len(lic.Name) != 1 || lic.Name[0].Val != "Some" {
+		t.Errorf("Wrong licence names %+v", lic.Name)
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	inputs := map[string]int{
+		"{\n\"spdxVersion\": \"SPDX-1.2\",\n\"dataLicense\": {}\n}": 3,
+		"{\n\"packages\": {}\n}":                                    2,
+		"{\n\"packages\": [\n{\"name\": []}\n]\n}":                  3,
+		"{\n\"comment\": \"a\",\n\"comment\": \"b\"\n}":             3,
+		"{\n\"unknown\": 1,\n\"unknown\": 2\n}":                     3,
+		"[]":                                                        1,
+	}
+	for input, line := range inputs {
+		_, err := Build(strings.NewReader(input))
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("No parse error for %#v: %v", input, err)
+			continue
+		}
+		if perr.LineStart != line {
+			t.Errorf("Wrong line for %#v: %d, expected %d (%s)", input, perr.LineStart, line, perr.Error())
+		}
+	}
+}
+
+func TestBuildExtensions(t *testing.T) {
+	input := `{
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "documentDescribes": ["SPDXRef-Package"],
+  "packages": [
+    {
+      "SPDXID": "SPDXRef-Package",
+      "name": "pkg",
+      "filesAnalyzed": false,
+      "attributionTexts": "123",
+      "licenseDeclared": "MIT OR Apache-2.0"
+    }
+  ]
+}`
+	doc, err := Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []spdx.Extension{
+		{Key: "documentDescribes", Value: `["SPDXRef-Package"]`, Json: true, Meta: spdx.NewMetaL(3)},
+	}
+	if !reflect.DeepEqual(doc.Extensions, expected) {
+		t.Errorf("Wrong document extensions %+v", doc.Extensions)
+	}
+	if ids := []spdx.Extension{{Key: "SPDXID", Value: "SPDXRef-DOCUMENT", Meta: spdx.NewMetaL(2)}}; !reflect.DeepEqual(doc.Identifiers, ids) {
+		t.Errorf("Wrong document identifiers %+v", doc.Identifiers)
+	}
+	pkg := doc.Packages[0]
+	expected = []spdx.Extension{
+		{Key: "filesAnalyzed", Value: "false", Json: true, Meta: spdx.NewMetaL(8)},
+		{Key: "attributionTexts", Value: `"123"`, Json: true, Meta: spdx.NewMetaL(9)},
+	}
+	if !reflect.DeepEqual(pkg.Extensions, expected) {
+		t.Errorf("Wrong package extensions %+v", pkg.Extensions)
+	}
+	if identifier(pkg.Identifiers, "SPDXID") != "SPDXRef-Package" {
+		t.Errorf("Wrong package identifiers %+v", pkg.Identifiers)
+	}
+
+	// the extensions are written back with the same JSON type
+	n := DocumentNode(doc).Get("packages").Items[0]
+	if v := n.Get("filesAnalyzed"); v.Kind != KindBool || v.Value != "false" {
+		t.Errorf("Wrong filesAnalyzed %+v", v)
+	}
+	if v := n.Get("attributionTexts"); v.Kind != KindString || v.Value != "123" {
+		t.Errorf("Wrong attributionTexts %+v", v)
+	}
+	if set, ok := pkg.LicenceDeclared.(spdx.DisjunctiveLicenceSet); !ok || len(set.Members) != 2 {
+		t.Errorf("Wrong declared licence %#v", pkg.LicenceDeclared)
+	}
+}
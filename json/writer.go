@@ -0,0 +1,332 @@
+package json
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Add a string member to an object Node if `val` is not empty.
+func addStr(n *Node, key, val string) {
+	if val != "" {
+		n.Add(key, String(val, nil))
+	}
+}
+
+// Add an array of strings member to an object Node if `vals` is not empty.
+func addList(n *Node, key string, vals []string) {
+	if len(vals) == 0 {
+		return
+	}
+	arr := Array(nil)
+	for _, v := range vals {
+		arr.Items = append(arr.Items, String(v, nil))
+	}
+	n.Add(key, arr)
+}
+
+// Add an array member to an object Node if `arr` has any items.
+func addArray(n *Node, key string, arr *Node) {
+	if len(arr.Items) > 0 {
+		n.Add(key, arr)
+	}
+}
+
+// Returns the values of a []spdx.ValueStr.
+func strs(vals []spdx.ValueStr) []string {
+	res := make([]string, len(vals))
+	for i, v := range vals {
+		res[i] = v.Val
+	}
+	return res
+}
+
+// Add the extensions to an object Node, but for the members it already has.
+// The extensions read from SPDX JSON are written with their JSON value, the
+// others as strings.
+func addExtensions(n *Node, exts []spdx.Extension) {
+	for _, ext := range exts {
+		if n.Get(ext.Key) != nil {
+			continue
+		}
+		if ext.Json {
+			if v, err := Decode(strings.NewReader(ext.Value)); err == nil {
+				n.Add(ext.Key, v)
+				continue
+			}
+		}
+		n.Add(ext.Key, String(ext.Value, nil))
+	}
+}
+
+// Returns the value of the identifier `key` or `def` if there is none.
+func identifierOr(ids []spdx.Extension, key, def string) string {
+	if val := identifier(ids, key); val != "" {
+		return val
+	}
+	return def
+}
+
+// Gives the SPDXIDs of the elements of a document, without duplicates.
+type idSet map[string]bool
+
+// Returns the SPDXID of an element with the identifiers `ids`: its own if it
+// is not taken, else `def` or, if `def` is taken too, `def` with a suffix.
+func (s idSet) id(ids []spdx.Extension, def string) string {
+	id := identifier(ids, "SPDXID")
+	if id == "" || s[id] {
+		id = def
+		for i := 2; s[id]; i++ {
+			id = def + "-" + strconv.Itoa(i)
+		}
+	}
+	s[id] = true
+	return id
+}
+
+// Returns the licence expression of `lic`, with the operators of SPDX 2.x
+// (AND, OR), or "" if lic is nil.
+func licenceId(lic spdx.AnyLicence) string {
+	join := func(members []spdx.AnyLicence, op string) string {
+		ids := make([]string, len(members))
+		for i, m := range members {
+			ids[i] = licenceId(m)
+		}
+		return "(" + strings.Join(ids, " "+op+" ") + ")"
+	}
+	switch l := lic.(type) {
+	case nil:
+		return ""
+	case spdx.ConjunctiveLicenceSet:
+		return join(l.Members, "AND")
+	case *spdx.ConjunctiveLicenceSet:
+		return join(l.Members, "AND")
+	case spdx.DisjunctiveLicenceSet:
+		return join(l.Members, "OR")
+	case *spdx.DisjunctiveLicenceSet:
+		return join(l.Members, "OR")
+	}
+	return lic.LicenceId()
+}
+
+// Returns the licence IDs of `lics`.
+func licenceIds(lics []spdx.AnyLicence) []string {
+	res := make([]string, len(lics))
+	for i, lic := range lics {
+		res[i] = licenceId(lic)
+	}
+	return res
+}
+
+// Returns the SPDX identifiers of the files in `files`: those of `ids`, or
+// their names for the files not in the document.
+func fileIds(files []*spdx.File, ids map[*spdx.File]string) []string {
+	res := make([]string, len(files))
+	for i, f := range files {
+		if id, ok := ids[f]; ok {
+			res[i] = id
+		} else {
+			res[i] = f.Name.Val
+		}
+	}
+	return res
+}
+
+// Checks if the given *spdx.File is in the given []*spdx.File.
+func fileInList(file *spdx.File, list []*spdx.File) bool {
+	for _, f := range list {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the name of a document: its "name" identifier, the name of its
+// first package or NOASSERTION.
+func documentName(doc *spdx.Document) string {
+	if len(doc.Packages) > 0 && doc.Packages[0].Name.Val != "" {
+		return identifierOr(doc.Identifiers, "name", doc.Packages[0].Name.Val)
+	}
+	return identifierOr(doc.Identifiers, "name", "NOASSERTION")
+}
+
+// Returns the namespace of a document: its "documentNamespace" identifier or
+// a URI made of its name and its digest (see spdx.Digest()).
+func documentNamespace(doc *spdx.Document, name string) string {
+	digest := spdx.Digest(doc)
+	def := "http://spdx.org/spdxdocs/" + url.PathEscape(name) + "-" + hex.EncodeToString(digest[:])
+	return identifierOr(doc.Identifiers, "documentNamespace", def)
+}
+
+// Returns the Node tree of `doc` in the SPDX JSON structure. Empty values are
+// left out, but for the values the SPDX 2.x schema requires: the SPDXID,
+// name and documentNamespace of the document, the SPDXID, name and
+// downloadLocation of the packages and the SPDXID of the files. They are
+// taken from the Identifiers of the elements (see Parse()) or generated, so
+// that the SPDXIDs are unique.
+// Files of packages that are not in doc.Files are added to the document
+// "files", and files are referred to by their SPDXID.
+func DocumentNode(doc *spdx.Document) *Node {
+	n := Object(nil)
+	addStr(n, "spdxVersion", doc.SpecVersion.Val)
+	addStr(n, "dataLicense", doc.DataLicence.Val)
+	used := make(idSet)
+	n.Add("SPDXID", String(used.id(doc.Identifiers, "SPDXRef-DOCUMENT"), nil))
+	name := documentName(doc)
+	n.Add("name", String(name, nil))
+	n.Add("documentNamespace", String(documentNamespace(doc, name), nil))
+	addStr(n, "comment", doc.Comment.Val)
+
+	if ci := doc.CreationInfo; ci != nil {
+		cin := Object(nil)
+		creators := make([]string, len(ci.Creator))
+		for i, cr := range ci.Creator {
+			creators[i] = cr.V()
+		}
+		addList(cin, "creators", creators)
+		addStr(cin, "created", ci.Created.V())
+		addStr(cin, "comment", ci.Comment.Val)
+		addStr(cin, "licenseListVersion", ci.LicenceListVersion.Val)
+		addExtensions(cin, ci.Extensions)
+		n.Add("creationInfo", cin)
+	}
+
+	files := doc.Files
+	for _, pkg := range doc.Packages {
+		for _, file := range pkg.Files {
+			if !fileInList(file, files) {
+				files = append(files, file)
+			}
+		}
+	}
+	ids := make(map[*spdx.File]string)
+	for i, file := range files {
+		ids[file] = used.id(file.Identifiers, "SPDXRef-File-"+strconv.Itoa(i+1))
+	}
+
+	pkgs := Array(nil)
+	for i, pkg := range doc.Packages {
+		pkgs.Items = append(pkgs.Items, packageNode(pkg, used.id(pkg.Identifiers, "SPDXRef-Package-"+strconv.Itoa(i+1)), ids))
+	}
+	addArray(n, "packages", pkgs)
+
+	fls := Array(nil)
+	for _, file := range files {
+		fls.Items = append(fls.Items, fileNode(file, ids))
+	}
+	addArray(n, "files", fls)
+
+	lics := Array(nil)
+	for _, lic := range doc.ExtractedLicences {
+		ln := Object(nil)
+		addStr(ln, "licenseId", lic.Id.Val)
+		addStr(ln, "extractedText", lic.Text.Val)
+		if len(lic.Name) == 1 {
+			addStr(ln, "name", lic.Name[0].Val)
+		} else {
+			addList(ln, "name", strs(lic.Name))
+		}
+		addList(ln, "seeAlsos", strs(lic.CrossReference))
+		addStr(ln, "comment", lic.Comment.Val)
+		addExtensions(ln, lic.Extensions)
+		lics.Items = append(lics.Items, ln)
+	}
+	addArray(n, "hasExtractedLicensingInfos", lics)
+
+	revs := Array(nil)
+	for _, rev := range doc.Reviews {
+		rn := Object(nil)
+		addStr(rn, "reviewer", rev.Reviewer.V())
+		addStr(rn, "reviewDate", rev.Date.V())
+		addStr(rn, "comment", rev.Comment.Val)
+		addExtensions(rn, rev.Extensions)
+		revs.Items = append(revs.Items, rn)
+	}
+	addArray(n, "revieweds", revs)
+
+	addExtensions(n, doc.Extensions)
+	return n
+}
+
+// Returns the "checksums" array Node of `cksum`.
+func checksumsNode(cksum *spdx.Checksum) *Node {
+	arr := Array(nil)
+	if cksum != nil && (cksum.Algo.Val != "" || cksum.Value.Val != "") {
+		cn := Object(nil)
+		addStr(cn, "algorithm", cksum.Algo.Val)
+		addStr(cn, "checksumValue", cksum.Value.Val)
+		arr.Items = append(arr.Items, cn)
+	}
+	return arr
+}
+
+// Returns the Node of `pkg`, with the SPDXID `id`. The files have the SPDX
+// identifiers `ids`.
+func packageNode(pkg *spdx.Package, id string, ids map[*spdx.File]string) *Node {
+	n := Object(nil)
+	n.Add("SPDXID", String(id, nil))
+	n.Add("name", String(pkg.Name.Val, nil))
+	addStr(n, "versionInfo", pkg.Version.Val)
+	addStr(n, "packageFileName", pkg.FileName.Val)
+	addStr(n, "supplier", pkg.Supplier.V())
+	addStr(n, "originator", pkg.Originator.V())
+	if pkg.DownloadLocation.Val != "" {
+		n.Add("downloadLocation", String(pkg.DownloadLocation.Val, nil))
+	} else {
+		n.Add("downloadLocation", String("NOASSERTION", nil))
+	}
+	if vc := pkg.VerificationCode; vc != nil && (vc.Value.Val != "" || len(vc.ExcludedFiles) > 0) {
+		vn := Object(nil)
+		addStr(vn, "packageVerificationCodeValue", vc.Value.Val)
+		addList(vn, "packageVerificationCodeExcludedFiles", strs(vc.ExcludedFiles))
+		n.Add("packageVerificationCode", vn)
+	}
+	addArray(n, "checksums", checksumsNode(pkg.Checksum))
+	addStr(n, "homepage", pkg.HomePage.Val)
+	addStr(n, "sourceInfo", pkg.SourceInfo.Val)
+	addStr(n, "licenseConcluded", licenceId(pkg.LicenceConcluded))
+	addList(n, "licenseInfoFromFiles", licenceIds(pkg.LicenceInfoFromFiles))
+	addStr(n, "licenseDeclared", licenceId(pkg.LicenceDeclared))
+	addStr(n, "licenseComments", pkg.LicenceComments.Val)
+	addStr(n, "copyrightText", pkg.CopyrightText.Val)
+	addStr(n, "summary", pkg.Summary.Val)
+	addStr(n, "description", pkg.Description.Val)
+	addList(n, "hasFiles", fileIds(pkg.Files, ids))
+	addExtensions(n, pkg.Extensions)
+	return n
+}
+
+// Returns the Node of `file`. The files have the SPDX identifiers `ids`.
+func fileNode(file *spdx.File, ids map[*spdx.File]string) *Node {
+	n := Object(nil)
+	n.Add("SPDXID", String(ids[file], nil))
+	addStr(n, "fileName", file.Name.Val)
+	if file.Type.Val != "" {
+		addList(n, "fileTypes", []string{file.Type.Val})
+	}
+	addArray(n, "checksums", checksumsNode(file.Checksum))
+	addStr(n, "licenseConcluded", licenceId(file.LicenceConcluded))
+	addList(n, "licenseInfoInFiles", licenceIds(file.LicenceInfoInFile))
+	addStr(n, "licenseComments", file.LicenceComments.Val)
+	addStr(n, "copyrightText", file.CopyrightText.Val)
+	addStr(n, "noticeText", file.Notice.Val)
+	addList(n, "fileContributors", strs(file.Contributor))
+	addList(n, "fileDependencies", fileIds(file.Dependency, ids))
+	addStr(n, "comment", file.Comment.Val)
+
+	artifs := Array(nil)
+	for _, artif := range file.ArtifactOf {
+		an := Object(nil)
+		addStr(an, "name", artif.Name.Val)
+		addStr(an, "homePage", artif.HomePage.Val)
+		addStr(an, "projectUri", artif.ProjectUri.Val)
+		artifs.Items = append(artifs.Items, an)
+	}
+	addArray(n, "artifactOfs", artifs)
+	addExtensions(n, file.Extensions)
+	return n
+}
@@ -0,0 +1,388 @@
+package json
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteBuild(t *testing.T) {
+	doc, err := Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = Write(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	doc2, err := Build(&b)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	b.Reset()
+	if err = Write(&b, doc2); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != out {
+		t.Errorf("Different output after writing and building.\n%s\n%s", out, b.String())
+	}
+}
+
+func TestWriteTagDocument(t *testing.T) {
+	input := `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+Creator: Person: Jane Doe (jane@example.com)
+Created: 2014-08-01T00:00:00Z
+
+PackageName: pkg
+PackageVersion: 1.0
+PackageDownloadLocation: http://example.com/pkg-1.0.tar.gz
+PackageVerificationCode: 4e3211c67a2d28fced849ee1bb76e7391b93feba (a.rdf, b.txt)
+PackageLicenseConcluded: (Apache-2.0 and MIT)
+
+FileName: a.c
+FileType: SOURCE
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12
+LicenseConcluded: NOASSERTION
+ArtifactOfProjectName: project
+ArtifactOfProjectHomePage: http://example.com/
+
+Reviewer: Person: Joe Reviewer
+ReviewDate: 2010-02-10T00:00:00Z
+`
+	doc, err := tag.Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = Write(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, s := range []string{`"packageVerificationCodeExcludedFiles": [`, `"licenseConcluded": "(Apache-2.0 AND MIT)"`, `"SPDXID": "SPDXRef-Package-1"`, `"name": "pkg",`, `"homePage": "http://example.com/"`, `"reviewer": "Person: Joe Reviewer"`} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%s not found in output:\n%s", s, out)
+		}
+	}
+	doc2, err := Build(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Equal(doc2) {
+		t.Errorf("Different document after converting to JSON:\n%s", out)
+	}
+}
+
+func TestWriteNil(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, nil); err != nil || b.Len() != 0 {
+		t.Errorf("Unexpected output for nil document: %v %#v", err, b.String())
+	}
+	n := DocumentNode(&spdx.Document{})
+	if len(n.Fields) != 3 || n.Get("SPDXID").Value != "SPDXRef-DOCUMENT" || n.Get("name").Value != "NOASSERTION" ||
+		!strings.HasPrefix(n.Get("documentNamespace").Value, "http://spdx.org/spdxdocs/NOASSERTION-") {
+		t.Errorf("Wrong values written: %s", compact(n))
+	}
+}
+
+// Adapted from the example document of the SPDX 2.3 specification
+// (SPDXJSONExample-v2.3.spdx.json), with fewer elements.
+const specExample = `{
+  "SPDXID": "SPDXRef-DOCUMENT",
+  "spdxVersion": "SPDX-2.3",
+  "creationInfo": {
+    "comment": "This package has been shipped in source and binary form.\nThe binaries were created with gcc 4.5.1 and expect to link to\ncompatible system run time libraries.",
+    "created": "2010-01-29T18:30:22Z",
+    "creators": [
+      "Tool: LicenseFind-1.0",
+      "Organization: ExampleCodeInspect ()",
+      "Person: Jane Doe ()"
+    ],
+    "licenseListVersion": "3.17"
+  },
+  "name": "SPDX-Tools-v2.0",
+  "dataLicense": "CC0-1.0",
+  "comment": "This document was created using SPDX 2.0 using licenses from the web site.",
+  "externalDocumentRefs": [
+    {
+      "externalDocumentId": "DocumentRef-spdx-tool-1.2",
+      "checksum": {
+        "algorithm": "SHA1",
+        "checksumValue": "d6a770ba38583ed4bb4525bd96e50461655d2759"
+      },
+      "spdxDocument": "http://spdx.org/spdxdocs/spdx-tools-v1.2-3F2504E0-4F89-41D3-9A0C-0305E82C3301"
+    }
+  ],
+  "hasExtractedLicensingInfos": [
+    {
+      "licenseId": "LicenseRef-1",
+      "extractedText": "/*\n * (c) Copyright 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008, 2009 Hewlett-Packard Development Company, LP\n * All rights reserved.\n */"
+    },
+    {
+      "licenseId": "LicenseRef-Beerware-4.2",
+      "comment": "The beerware license has a couple of other standard variants.",
+      "extractedText": "\"THE BEER-WARE LICENSE\" (Revision 42):\nphk@FreeBSD.ORG wrote this file. As long as you retain this notice you\ncan do whatever you want with this stuff. If we meet some day, and you think this stuff is worth it, you can buy me a beer in return Poul-Henning Kamp",
+      "name": "Beer-Ware License (Version 42)",
+      "seeAlsos": [
+        "http://people.freebsd.org/~phk/"
+      ]
+    }
+  ],
+  "annotations": [
+    {
+      "annotationDate": "2010-01-29T18:30:22Z",
+      "annotationType": "OTHER",
+      "annotator": "Person: Jane Doe ()",
+      "comment": "Document level annotation"
+    }
+  ],
+  "documentNamespace": "http://spdx.org/spdxdocs/spdx-example-444504E0-4F89-41D3-9A0C-0305E82C3301",
+  "documentDescribes": [
+    "SPDXRef-File",
+    "SPDXRef-Package"
+  ],
+  "packages": [
+    {
+      "SPDXID": "SPDXRef-Package",
+      "annotations": [
+        {
+          "annotationDate": "2011-01-29T18:30:22Z",
+          "annotationType": "OTHER",
+          "annotator": "Person: Package Commenter",
+          "comment": "Package level annotation"
+        }
+      ],
+      "attributionTexts": [
+        "The GNU C Library is free software.  See the file COPYING.LIB for copying conditions, and LICENSES for notices about a few contributions that require these additional notices to be distributed.  License copyright years may be listed using range notation, e.g., 1996-2015, indicating that every year in the range, inclusive, is a copyrightable year that would otherwise be listed individually."
+      ],
+      "builtDate": "2011-01-29T18:30:22Z",
+      "checksums": [
+        {
+          "algorithm": "MD5",
+          "checksumValue": "624c1abb3664f4b35547e7c73864ad24"
+        },
+        {
+          "algorithm": "SHA1",
+          "checksumValue": "85ed0817af83a24ad8da68c2b5094de69833983c"
+        }
+      ],
+      "copyrightText": "Copyright 2008-2010 John Smith",
+      "description": "The GNU C Library defines functions that are specified by the ISO C standard, as well as additional features specific to POSIX and other derivatives of the Unix operating system, and extensions specific to GNU systems.",
+      "downloadLocation": "http://ftp.gnu.org/gnu/glibc/glibc-ports-2.15.tar.gz",
+      "externalRefs": [
+        {
+          "referenceCategory": "SECURITY",
+          "referenceLocator": "cpe:2.3:a:pivotal_software:spring_framework:4.1.0:*:*:*:*:*:*:*",
+          "referenceType": "cpe23Type"
+        }
+      ],
+      "filesAnalyzed": true,
+      "hasFiles": [
+        "SPDXRef-JenaLib",
+        "SPDXRef-DoapSource"
+      ],
+      "homepage": "http://ftp.gnu.org/gnu/glibc",
+      "licenseComments": "The license for this project changed with the release of version x.y.  The version of the project included here post-dates the license change.",
+      "licenseConcluded": "(LGPL-2.0-only OR LicenseRef-3)",
+      "licenseDeclared": "(LGPL-2.0-only AND LicenseRef-3)",
+      "licenseInfoFromFiles": [
+        "GPL-2.0-only",
+        "LicenseRef-2",
+        "LicenseRef-1"
+      ],
+      "name": "glibc",
+      "originator": "Organization: ExampleCodeInspect (contact@example.com)",
+      "packageFileName": "glibc-2.11.1.tar.gz",
+      "packageVerificationCode": {
+        "packageVerificationCodeExcludedFiles": [
+          "./package.spdx"
+        ],
+        "packageVerificationCodeValue": "d6a770ba38583ed4bb4525bd96e50461655d2758"
+      },
+      "primaryPackagePurpose": "SOURCE",
+      "releaseDate": "2012-01-29T18:30:22Z",
+      "sourceInfo": "uses glibc-2_11-branch from git://sourceware.org/git/glibc.git.",
+      "summary": "GNU C library.",
+      "supplier": "Person: Jane Doe (jane.doe@example.com)",
+      "validUntilDate": "2014-01-29T18:30:22Z",
+      "versionInfo": "2.11.1"
+    },
+    {
+      "SPDXID": "SPDXRef-fromDoap-0",
+      "downloadLocation": "https://search.maven.org/remotecontent?filepath=org/apache/jena/apache-jena/3.12.0/apache-jena-3.12.0.tar.gz",
+      "externalRefs": [
+        {
+          "referenceCategory": "PACKAGE-MANAGER",
+          "referenceLocator": "pkg:maven/org.apache.jena/apache-jena@3.12.0",
+          "referenceType": "purl"
+        }
+      ],
+      "filesAnalyzed": false,
+      "homepage": "http://www.openjena.org/",
+      "name": "Jena",
+      "versionInfo": "3.12.0"
+    }
+  ],
+  "files": [
+    {
+      "SPDXID": "SPDXRef-DoapSource",
+      "checksums": [
+        {
+          "algorithm": "SHA1",
+          "checksumValue": "2fd4e1c67a2d28fced849ee1bb76e7391b93eb12"
+        }
+      ],
+      "copyrightText": "Copyright 2010, 2011 Source Auditor Inc.",
+      "fileContributors": [
+        "Protecode Inc.",
+        "SPDX Technical Team Members",
+        "Open Logic Inc.",
+        "Source Auditor Inc.",
+        "Black Duck Software In.c"
+      ],
+      "fileName": "./src/org/spdx/parser/DOAPProject.java",
+      "fileTypes": [
+        "SOURCE"
+      ],
+      "licenseConcluded": "Apache-2.0",
+      "licenseInfoInFiles": [
+        "Apache-2.0"
+      ]
+    },
+    {
+      "SPDXID": "SPDXRef-JenaLib",
+      "checksums": [
+        {
+          "algorithm": "SHA1",
+          "checksumValue": "3ab4e1c67a2d28fced849ee1bb76e7391b93f125"
+        }
+      ],
+      "comment": "This file belongs to Jena",
+      "copyrightText": "(c) Copyright 2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008, 2009 Hewlett-Packard Development Company, LP",
+      "fileContributors": [
+        "Apache Software Foundation",
+        "Hewlett Packard Inc."
+      ],
+      "fileName": "./lib-source/jena-2.6.3-sources.jar",
+      "fileTypes": [
+        "ARCHIVE"
+      ],
+      "licenseComments": "This license is used by Jena",
+      "licenseConcluded": "LicenseRef-1",
+      "licenseInfoInFiles": [
+        "LicenseRef-1"
+      ]
+    }
+  ],
+  "snippets": [
+    {
+      "SPDXID": "SPDXRef-Snippet",
+      "copyrightText": "Copyright 2008-2010 John Smith",
+      "licenseConcluded": "GPL-2.0-only",
+      "licenseInfoInSnippets": [
+        "GPL-2.0-only"
+      ],
+      "name": "from linux kernel",
+      "ranges": [
+        {
+          "endPointer": {
+            "offset": 420,
+            "reference": "SPDXRef-DoapSource"
+          },
+          "startPointer": {
+            "offset": 310,
+            "reference": "SPDXRef-DoapSource"
+          }
+        }
+      ],
+      "snippetFromFile": "SPDXRef-DoapSource"
+    }
+  ],
+  "relationships": [
+    {
+      "spdxElementId": "SPDXRef-DOCUMENT",
+      "relationshipType": "DESCRIBES",
+      "relatedSpdxElement": "SPDXRef-Package"
+    },
+    {
+      "spdxElementId": "SPDXRef-Package",
+      "relationshipType": "DYNAMIC_LINK",
+      "relatedSpdxElement": "SPDXRef-fromDoap-0"
+    }
+  ]
+}`
+
+func TestSpecExample(t *testing.T) {
+	doc, err := Build(strings.NewReader(specExample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Packages) != 2 || len(doc.Files) != 2 || len(doc.ExtractedLicences) != 2 {
+		t.Fatalf("Wrong number of elements: %d packages, %d files, %d licences", len(doc.Packages), len(doc.Files), len(doc.ExtractedLicences))
+	}
+	pkg := doc.Packages[0]
+	if len(pkg.Files) != 2 || pkg.Files[0] != doc.Files[1] || pkg.Files[1] != doc.Files[0] {
+		t.Errorf("Package files not resolved by SPDXID %+v", pkg.Files)
+	}
+	if doc.Files[1].LicenceConcluded != doc.ExtractedLicences[0] {
+		t.Errorf("Licence reference not resolved %#v", doc.Files[1].LicenceConcluded)
+	}
+
+	var b bytes.Buffer
+	if err = Write(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, s := range []string{
+		`"SPDXID": "SPDXRef-DOCUMENT"`,
+		`"name": "SPDX-Tools-v2.0"`,
+		`"documentNamespace": "http://spdx.org/spdxdocs/spdx-example-444504E0-4F89-41D3-9A0C-0305E82C3301"`,
+		`"filesAnalyzed": false`,
+		`"offset": 420`,
+		`"hasFiles": [
+        "SPDXRef-JenaLib",
+        "SPDXRef-DoapSource"
+      ]`,
+		`"licenseDeclared": "(LGPL-2.0-only AND LicenseRef-3)"`,
+	} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%s not found in output:\n%s", s, out)
+		}
+	}
+
+	doc2, err := Build(&b)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	if !doc.Equal(doc2) || spdx.Digest(doc) != spdx.Digest(doc2) {
+		t.Errorf("Different document after writing and building:\n%s\n%s", spdx.Canonical(doc), spdx.Canonical(doc2))
+	}
+}
+
+func TestSpecExampleTag(t *testing.T) {
+	doc, err := Build(strings.NewReader(specExample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = tag.Write(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if strings.Contains(out, "\nSPDXID:") || strings.Contains(out, "\nrelationships:") || !strings.Contains(out, "# relationships: [") {
+		t.Errorf("SPDX JSON members written as Tag properties:\n%s", out)
+	}
+	doc2, err := tag.Build(&b)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	if len(doc2.Packages) != len(doc.Packages) || len(doc2.Files) != len(doc.Files) || len(doc2.ExtractedLicences) != len(doc.ExtractedLicences) {
+		t.Errorf("Wrong elements after writing as Tag:\n%s", out)
+	}
+}
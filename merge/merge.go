@@ -0,0 +1,385 @@
+// Package merge combines several SPDX documents into one, such as the
+// documents of the components of a product.
+//
+// The merged document has:
+//   - the SPDX version and data licence of the first document which has them
+//   - the creators of all the documents, without duplicates, the latest
+//     creation date and the document and creator comments of all the
+//     documents
+//   - all the packages, files and reviews; files with the same name and
+//     checksum are the same file and are kept once (the first one), packages
+//     with the same name and version are the same package and are kept once
+//     with the files of all of them, and identical reviews are kept once
+//   - all the extracted licences: a licence ID defined with different
+//     contents in two documents is renamed in the later one, and all the
+//     references to it (licences of packages and files) are rewritten
+//
+// The input documents are not modified: the merged document is a deep copy,
+// in which the file dependencies refer to the merged files. It has no
+// metadata, as the line numbers of the input documents are meaningless in it.
+package merge
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// A licence renamed because its ID was already defined, with another content,
+// in a previous document.
+type Rename struct {
+	Doc int    // Index of the document in the input documents
+	Old string // ID in the document
+	New string // ID in the merged document
+}
+
+// State of a merge.
+type merger struct {
+	res      *spdx.Document
+	licences map[string]*spdx.ExtractedLicence // merged licences by ID
+	files    map[string]*spdx.File             // merged files by name and checksum
+	packages map[string]*spdx.Package          // merged packages by name and version
+	renames  []Rename
+}
+
+// Merges the documents `docs` into a new document. Returns the merged
+// document and the licences which were renamed.
+func Documents(docs ...*spdx.Document) (*spdx.Document, []Rename) {
+	m := &merger{
+		res:      &spdx.Document{CreationInfo: new(spdx.CreationInfo)},
+		licences: make(map[string]*spdx.ExtractedLicence),
+		files:    make(map[string]*spdx.File),
+		packages: make(map[string]*spdx.Package),
+	}
+	for i, doc := range docs {
+		if doc != nil {
+			m.document(i, doc)
+		}
+	}
+	res := make(copier).copy(reflect.ValueOf(m.res))
+	return res.Interface().(*spdx.Document), m.renames
+}
+
+// Appends `val` to the paragraphs of `str` if it is not there yet.
+func appendText(str *spdx.ValueStr, val string) {
+	if val == "" {
+		return
+	}
+	for _, p := range strings.Split(str.Val, "\n\n") {
+		if p == val {
+			return
+		}
+	}
+	if str.Val != "" {
+		str.Val += "\n\n"
+	}
+	str.Val += val
+}
+
+// Merges the document `doc`, the `n`th of the input.
+func (m *merger) document(n int, doc *spdx.Document) {
+	res := m.res
+	if res.SpecVersion.Val == "" {
+		res.SpecVersion = spdx.Str(doc.SpecVersion.Val, nil)
+	}
+	if res.DataLicence.Val == "" {
+		res.DataLicence = spdx.Str(doc.DataLicence.Val, nil)
+	}
+	appendText(&res.Comment, doc.Comment.Val)
+	m.creationInfo(doc.CreationInfo)
+	res.Extensions = append(res.Extensions, doc.Extensions...)
+
+	refs := m.extractedLicences(n, doc.ExtractedLicences)
+
+	// files first, so that packages and dependencies refer to the copies
+	files := make(map[*spdx.File]*spdx.File)
+	var copyOf func(file *spdx.File) *spdx.File
+	copyOf = func(file *spdx.File) *spdx.File {
+		if f, ok := files[file]; ok {
+			return f
+		}
+		f, isNew := m.file(file, refs)
+		files[file] = f
+		if isNew && file.Dependency != nil {
+			f.Dependency = make([]*spdx.File, len(file.Dependency))
+			for i, dep := range file.Dependency {
+				if dep != nil {
+					f.Dependency[i] = copyOf(dep)
+				}
+			}
+		}
+		return f
+	}
+	for _, file := range doc.Files {
+		if file != nil {
+			res.Files = appendFile(res.Files, copyOf(file))
+		}
+	}
+
+	for _, pkg := range doc.Packages {
+		if pkg == nil {
+			continue
+		}
+		p := m.pkg(pkg, refs)
+		for _, file := range pkg.Files {
+			if file != nil {
+				p.Files = appendFile(p.Files, copyOf(file))
+			}
+		}
+	}
+
+	for _, rev := range doc.Reviews {
+		if rev != nil && !hasReview(res.Reviews, rev) {
+			r := *rev
+			res.Reviews = append(res.Reviews, &r)
+		}
+	}
+}
+
+// Returns the merged package with the same name and version as `pkg`, or a
+// new copy of `pkg` without its files, added to the merged document.
+func (m *merger) pkg(pkg *spdx.Package, refs map[string]*spdx.ExtractedLicence) *spdx.Package {
+	key := pkg.Name.Val + "\x00" + pkg.Version.Val
+	if p, ok := m.packages[key]; ok {
+		for _, lic := range rewriteList(pkg.LicenceInfoFromFiles, refs) {
+			if !hasLicence(p.LicenceInfoFromFiles, lic) {
+				p.LicenceInfoFromFiles = append(p.LicenceInfoFromFiles, lic)
+			}
+		}
+		return p
+	}
+	p := *pkg
+	p.LicenceConcluded = rewrite(pkg.LicenceConcluded, refs)
+	p.LicenceDeclared = rewrite(pkg.LicenceDeclared, refs)
+	p.LicenceInfoFromFiles = rewriteList(pkg.LicenceInfoFromFiles, refs)
+	p.Files = nil
+	m.packages[key] = &p
+	m.res.Packages = append(m.res.Packages, &p)
+	return &p
+}
+
+// Checks whether `list` has a licence with the same ID as `lic`.
+func hasLicence(list []spdx.AnyLicence, lic spdx.AnyLicence) bool {
+	for _, l := range list {
+		if l != nil && lic != nil && l.LicenceId() == lic.LicenceId() {
+			return true
+		}
+	}
+	return false
+}
+
+// Appends `file` to `list` unless it is there already.
+func appendFile(list []*spdx.File, file *spdx.File) []*spdx.File {
+	for _, f := range list {
+		if f == file {
+			return list
+		}
+	}
+	return append(list, file)
+}
+
+// Checks whether `list` has a review equal to `rev`.
+func hasReview(list []*spdx.Review, rev *spdx.Review) bool {
+	for _, r := range list {
+		if r.Equal(rev) {
+			return true
+		}
+	}
+	return false
+}
+
+// Merges the creation info: creators without duplicates, the latest creation
+// date, the first licence list version and all the comments.
+func (m *merger) creationInfo(ci *spdx.CreationInfo) {
+	if ci == nil {
+		return
+	}
+	res := m.res.CreationInfo
+	for _, cr := range ci.Creator {
+		found := false
+		for _, c := range res.Creator {
+			found = found || c.V() == cr.V()
+		}
+		if !found {
+			res.Creator = append(res.Creator, spdx.NewValueCreator(cr.V(), nil))
+		}
+	}
+	if t := ci.Created.Time(); res.Created.V() == "" || (t != nil && res.Created.Time() != nil && t.After(*res.Created.Time())) {
+		res.Created = spdx.NewValueDate(ci.Created.V(), nil)
+	}
+	if res.LicenceListVersion.Val == "" {
+		res.LicenceListVersion = spdx.Str(ci.LicenceListVersion.Val, nil)
+	}
+	appendText(&res.Comment, ci.Comment.Val)
+	res.Extensions = append(res.Extensions, ci.Extensions...)
+}
+
+// Checks whether two extracted licences have the same content, regardless of
+// their IDs.
+func sameContent(a, b *spdx.ExtractedLicence) bool {
+	x, y := *a, *b
+	x.Id, y.Id = b.Id, b.Id
+	return x.Equal(&y)
+}
+
+// Merges the extracted licences of the `n`th document. Returns the merged
+// licences by their ID in the document.
+func (m *merger) extractedLicences(n int, lics []*spdx.ExtractedLicence) map[string]*spdx.ExtractedLicence {
+	refs := make(map[string]*spdx.ExtractedLicence)
+	for _, lic := range lics {
+		if lic == nil {
+			continue
+		}
+		id := lic.Id.Val
+		existing, ok := m.licences[id]
+		if ok && sameContent(existing, lic) {
+			refs[id] = existing
+			continue
+		}
+		if ok {
+			id = m.rename(lic)
+			m.renames = append(m.renames, Rename{Doc: n, Old: lic.Id.Val, New: id})
+			if existing = m.licences[id]; existing != nil {
+				refs[lic.Id.Val] = existing
+				continue
+			}
+		}
+		l := *lic
+		l.Id = spdx.Str(id, lic.Id.Meta)
+		m.licences[id] = &l
+		m.res.ExtractedLicences = append(m.res.ExtractedLicences, &l)
+		refs[lic.Id.Val] = &l
+	}
+	return refs
+}
+
+// Returns the new ID of a licence whose ID is already taken: a merged licence
+// with the same content and an ID `<ID>-<n>`, or the first free such ID.
+func (m *merger) rename(lic *spdx.ExtractedLicence) string {
+	for i := 2; ; i++ {
+		id := lic.Id.Val + "-" + strconv.Itoa(i)
+		existing, ok := m.licences[id]
+		if !ok || sameContent(existing, lic) {
+			return id
+		}
+	}
+}
+
+// Returns the merged file with the same name and checksum as `file`, or a new
+// copy of `file`, in which case `isNew` is true. Files without checksum are
+// always copied.
+func (m *merger) file(file *spdx.File, refs map[string]*spdx.ExtractedLicence) (f *spdx.File, isNew bool) {
+	key := ""
+	if file.Checksum != nil && file.Checksum.Value.Val != "" {
+		key = file.Name.Val + "\x00" + strings.ToUpper(file.Checksum.Algo.Val) + "\x00" + strings.ToLower(file.Checksum.Value.Val)
+		if f, ok := m.files[key]; ok {
+			return f, false
+		}
+	}
+	copied := *file
+	copied.LicenceConcluded = rewrite(file.LicenceConcluded, refs)
+	copied.LicenceInfoInFile = rewriteList(file.LicenceInfoInFile, refs)
+	if key != "" {
+		m.files[key] = &copied
+	}
+	return &copied, true
+}
+
+// Returns `lic` with the references to extracted licences replaced by the
+// merged licences of `refs`.
+func rewrite(lic spdx.AnyLicence, refs map[string]*spdx.ExtractedLicence) spdx.AnyLicence {
+	switch l := lic.(type) {
+	case *spdx.ExtractedLicence:
+		if r, ok := refs[l.Id.Val]; ok {
+			return r
+		}
+	case spdx.Licence:
+		if r, ok := refs[l.LicenceId()]; ok && l.IsReference() {
+			return r
+		}
+	case spdx.ConjunctiveLicenceSet:
+		return spdx.NewConjunctiveSet(l.Meta, rewriteList(l.Members, refs)...)
+	case *spdx.ConjunctiveLicenceSet:
+		set := spdx.NewConjunctiveSet(l.Meta, rewriteList(l.Members, refs)...)
+		return &set
+	case spdx.DisjunctiveLicenceSet:
+		return spdx.NewDisjunctiveSet(l.Meta, rewriteList(l.Members, refs)...)
+	case *spdx.DisjunctiveLicenceSet:
+		set := spdx.NewDisjunctiveSet(l.Meta, rewriteList(l.Members, refs)...)
+		return &set
+	}
+	return lic
+}
+
+// Returns a copy of `lics` rewritten by rewrite().
+func rewriteList(lics []spdx.AnyLicence, refs map[string]*spdx.ExtractedLicence) []spdx.AnyLicence {
+	if lics == nil {
+		return nil
+	}
+	res := make([]spdx.AnyLicence, len(lics))
+	for i, lic := range lics {
+		res[i] = rewrite(lic, refs)
+	}
+	return res
+}
+
+// The type of the metadata of the SPDX elements and values.
+var metaType = reflect.TypeOf((*spdx.Meta)(nil))
+
+// A pointer of a given type; the pointers to a struct and to its first field
+// have the same address.
+type pointer struct {
+	t reflect.Type
+	p uintptr
+}
+
+// Deep copies values, without metadata. Pointers to the same value are copied
+// once, so that the copies share the same copy.
+type copier map[pointer]reflect.Value
+
+// Returns a deep copy of `v` in which all the *spdx.Meta are nil. The
+// unexported fields of structs are copied as they are.
+func (c copier) copy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Type() == metaType {
+			return reflect.Zero(v.Type())
+		}
+		key := pointer{v.Type(), v.Pointer()}
+		if res, ok := c[key]; ok {
+			return res
+		}
+		res := reflect.New(v.Type().Elem())
+		c[key] = res
+		res.Elem().Set(c.copy(v.Elem()))
+		return res
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.New(v.Type()).Elem()
+		res.Set(c.copy(v.Elem()))
+		return res
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		res := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			res.Index(i).Set(c.copy(v.Index(i)))
+		}
+		return res
+	case reflect.Struct:
+		res := reflect.New(v.Type()).Elem()
+		res.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := res.Field(i); f.CanSet() {
+				f.Set(c.copy(v.Field(i)))
+			}
+		}
+		return res
+	}
+	return v
+}
@@ -0,0 +1,218 @@
+package merge
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const firstInput = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+Creator: Tool: x
+Created: 2014-01-01T00:00:00Z
+
+PackageName: a
+PackageLicenseDeclared: LicenseRef-1
+
+FileName: common.c
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12
+LicenseConcluded: LicenseRef-2
+
+FileName: a.c
+LicenseConcluded: LicenseRef-1
+
+LicenseID: LicenseRef-1
+ExtractedText: first
+
+LicenseID: LicenseRef-2
+ExtractedText: shared
+`
+
+const secondInput = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+Creator: Person: Me
+Creator: Tool: x
+Created: 2015-01-01T00:00:00Z
+
+PackageName: b
+PackageLicenseDeclared: (MIT and LicenseRef-1)
+
+FileName: common.c
+FileChecksum: SHA1: 2FD4E1C67A2D28FCED849EE1BB76E7391B93EB12
+LicenseConcluded: LicenseRef-2
+
+FileName: a.c
+FileChecksum: SHA1: da39a3ee5e6b4b0d3255bfef95601890afd80709
+LicenseConcluded: (LicenseRef-1 or LicenseRef-2)
+
+LicenseID: LicenseRef-1
+ExtractedText: second
+
+LicenseID: LicenseRef-2
+ExtractedText: shared
+`
+
+func build(t *testing.T, input string) *spdx.Document {
+	doc, err := tag.Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return doc
+}
+
+func TestDocuments(t *testing.T) {
+	first, second := build(t, firstInput), build(t, secondInput)
+	doc, renames := Documents(first, second)
+
+	if !reflect.DeepEqual(renames, []Rename{{Doc: 1, Old: "LicenseRef-1", New: "LicenseRef-1-2"}}) {
+		t.Errorf("Wrong renames %+v", renames)
+	}
+
+	var ids []string
+	for _, lic := range doc.ExtractedLicences {
+		ids = append(ids, lic.Id.Val+"="+lic.Text.Val)
+	}
+	if expected := []string{"LicenseRef-1=first", "LicenseRef-2=shared", "LicenseRef-1-2=second"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Expected licences %v but found %v", expected, ids)
+	}
+
+	var files []string
+	for _, file := range doc.Files {
+		files = append(files, file.Name.Val+"="+file.LicenceConcluded.LicenceId())
+	}
+	expected := []string{"common.c=LicenseRef-2", "a.c=LicenseRef-1", "a.c=(LicenseRef-1-2 or LicenseRef-2)"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected files %v but found %v", expected, files)
+	}
+
+	if len(doc.Packages) != 2 || doc.Packages[1].LicenceDeclared.LicenceId() != "(MIT and LicenseRef-1-2)" {
+		t.Errorf("Wrong packages %+v", doc.Packages)
+	}
+	set := doc.Packages[1].LicenceDeclared.(spdx.ConjunctiveLicenceSet)
+	if set.Members[1] != doc.ExtractedLicences[2] {
+		t.Error("The licence reference is not the merged licence.")
+	}
+
+	ci := doc.CreationInfo
+	if len(ci.Creator) != 2 || ci.Creator[0].V() != "Tool: x" || ci.Creator[1].V() != "Person: Me" || ci.Created.V() != "2015-01-01T00:00:00Z" {
+		t.Errorf("Wrong creation info %+v", ci)
+	}
+
+	// inputs are not modified
+	if second.ExtractedLicences[0].Id.Val != "LicenseRef-1" || second.Files[1].LicenceConcluded.LicenceId() != "(LicenseRef-1 or LicenseRef-2)" {
+		t.Error("The input document was modified.")
+	}
+}
+
+func TestDocumentsRenameReuse(t *testing.T) {
+	third := strings.Replace(secondInput, "PackageName: b", "PackageName: c", 1)
+	_, renames := Documents(build(t, firstInput), build(t, secondInput), build(t, third))
+	expected := []Rename{
+		{Doc: 1, Old: "LicenseRef-1", New: "LicenseRef-1-2"},
+		{Doc: 2, Old: "LicenseRef-1", New: "LicenseRef-1-2"},
+	}
+	if !reflect.DeepEqual(renames, expected) {
+		t.Errorf("Expected renames %+v but found %+v", expected, renames)
+	}
+}
+
+func TestDocumentsWrite(t *testing.T) {
+	doc, _ := Documents(build(t, firstInput), build(t, secondInput))
+	buf := new(bytes.Buffer)
+	if err := tag.Write(buf, doc); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	res, err := tag.Build(buf)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if spdx.Digest(res) != spdx.Digest(doc) {
+		t.Errorf("Written document differs:\n%s\n%s", spdx.Canonical(res), spdx.Canonical(doc))
+	}
+}
+
+const thirdInput = `SPDXVersion: SPDX-1.2
+
+PackageName: a
+
+FileName: common.c
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12
+LicenseConcluded: LicenseRef-2
+
+FileName: src/a/__init__.py
+FileChecksum: SHA1: da39a3ee5e6b4b0d3255bfef95601890afd80709
+LicenseConcluded: MIT
+
+FileName: src/b/__init__.py
+FileChecksum: SHA1: da39a3ee5e6b4b0d3255bfef95601890afd80709
+LicenseConcluded: GPL-2.0
+
+FileName: b.c
+LicenseConcluded: NOASSERTION
+FileDependency: common.c
+FileDependency: missing.c
+
+LicenseID: LicenseRef-2
+ExtractedText: shared
+`
+
+func TestDocumentsSameElements(t *testing.T) {
+	first, third := build(t, firstInput), build(t, thirdInput)
+	first.Packages[0].Files = first.Files
+	third.Packages[0].Files = third.Files
+	doc, _ := Documents(first, third)
+
+	var files []string
+	for _, file := range doc.Files {
+		files = append(files, file.Name.Val)
+	}
+	expected := []string{"common.c", "a.c", "src/a/__init__.py", "src/b/__init__.py", "b.c"}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("Expected files %v but found %v", expected, files)
+	}
+	if len(doc.Packages) != 1 || len(doc.Packages[0].Files) != 5 {
+		t.Fatalf("Wrong packages %+v", doc.Packages)
+	}
+	if doc.Files[3].LicenceConcluded.LicenceId() != "GPL-2.0" {
+		t.Errorf("Wrong licence of the file with the same content %s", doc.Files[3].LicenceConcluded.LicenceId())
+	}
+
+	deps := doc.Files[4].Dependency
+	if len(deps) != 2 || deps[0] != doc.Files[0] {
+		t.Fatalf("Wrong dependencies %+v", deps)
+	}
+	if deps[1].Name.Val != "missing.c" || deps[1] == third.Files[3].Dependency[1] {
+		t.Error("The dependency outside the file lists is not a copy.")
+	}
+
+	if doc.Meta != nil || doc.Packages[0].Meta != nil || doc.Packages[0].Name.Meta != nil || deps[1].Name.Meta != nil {
+		t.Error("The merged document has metadata.")
+	}
+	if third.Files[3].Meta == nil {
+		t.Error("The input document was modified.")
+	}
+}
+
+func TestRewritePointerSets(t *testing.T) {
+	lic := &spdx.ExtractedLicence{Id: spdx.Str("LicenseRef-1-2", nil)}
+	refs := map[string]*spdx.ExtractedLicence{"LicenseRef-1": lic}
+	or := spdx.NewDisjunctiveSet(nil, spdx.NewLicence("MIT", nil), spdx.NewLicence("LicenseRef-1", nil))
+	and := spdx.NewConjunctiveSet(nil, &or)
+	res, ok := rewrite(&and, refs).(*spdx.ConjunctiveLicenceSet)
+	if !ok {
+		t.Fatalf("Wrong licence %#v", res)
+	}
+	inner, ok := res.Members[0].(*spdx.DisjunctiveLicenceSet)
+	if !ok || inner.Members[1] != lic {
+		t.Errorf("Licence reference not rewritten %#v", res.Members[0])
+	}
+	if or.Members[1] == lic {
+		t.Error("The licence was modified.")
+	}
+}
@@ -0,0 +1,226 @@
+// Package notice generates third-party notices (attribution files) from SPDX
+// documents, as text, Markdown or HTML.
+//
+// The packages of the document are grouped by their concluded licence (or the
+// declared licence if the concluded one is NOASSERTION, NONE or missing). Each
+// package has the copyright texts of the package and of its files. In SPDX-1.2
+// documents the files are document files; if the document has only one
+// package, they are counted as files of that package.
+//
+// The notices end with the full text of every licence used: the text of the
+// SPDX Licence List, read from the directory LicenceTextDir, for listed
+// licences and the extracted text for licence references (LicenseRef-...).
+//
+// The output is made by a template, which may be one of the built-in
+// templates or any text/template or html/template which uses the *Notice
+// fields.
+package notice
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The directory with the texts of the SPDX Licence List, one <ID>.txt file
+// per licence, as in the SPDX license-list repository (the git submodule
+// updated by update-list.sh).
+var LicenceTextDir = "spdx/license-list"
+
+// The URL of the SPDX Licence List pages, used when a text is missing.
+var LicenceListUrl = "http://spdx.org/licenses/"
+
+// Licence IDs which may be used as file names.
+var licenceIdRegex = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+
+// The third-party notices of a document.
+type Notice struct {
+	Title    string     // Title of the notices
+	Groups   []*Group   // Packages grouped by licence, ordered by licence
+	Licences []*Licence // Licence texts, ordered by licence ID
+	Missing  []string   // IDs of the licences without a text
+}
+
+// The packages under the same licence.
+type Group struct {
+	Licence    string     // Licence expression (Tag format)
+	Licences   []*Licence // Licences in the expression
+	Components []*Component
+}
+
+// A package in the notices.
+type Component struct {
+	Name       string
+	Version    string
+	HomePage   string   // Home page or download location URL, if any
+	Copyrights []string // Copyright texts of the package and its files
+	Notices    []string // Notice texts of the files of the package
+}
+
+// A licence and its text.
+type Licence struct {
+	Id      string
+	Name    string // Name of an extracted licence, if any
+	Text    string // Licence text, empty if missing
+	Url     string // Link to the licence
+	Anchor  string // ID to be used in links inside a page
+	Missing bool   // Whether the licence text could not be found
+}
+
+// Appends `val` to `list` if it is not empty and not already in the list.
+func appendNew(list []string, val string) []string {
+	val = strings.TrimSpace(val)
+	if spdx.IsEmpty(val) {
+		return list
+	}
+	for _, v := range list {
+		if v == val {
+			return list
+		}
+	}
+	return append(list, val)
+}
+
+// Returns the licence of `pkg` the notices are grouped by.
+func pkgLicence(pkg *spdx.Package) spdx.AnyLicence {
+	if pkg.LicenceConcluded != nil && !spdx.IsEmpty(pkg.LicenceConcluded.LicenceId()) {
+		return pkg.LicenceConcluded
+	}
+	if pkg.LicenceDeclared != nil && !spdx.IsEmpty(pkg.LicenceDeclared.LicenceId()) {
+		return pkg.LicenceDeclared
+	}
+	return spdx.NewLicence(spdx.NOASSERTION, nil)
+}
+
+// Returns the Component of `pkg` with the given files.
+func component(pkg *spdx.Package, files []*spdx.File) *Component {
+	comp := &Component{Name: pkg.Name.Val, Version: pkg.Version.Val}
+	for _, url := range []string{pkg.HomePage.Val, pkg.DownloadLocation.Val} {
+		if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+			comp.HomePage = url
+			break
+		}
+	}
+	comp.Copyrights = appendNew(comp.Copyrights, pkg.CopyrightText.Val)
+	for _, f := range files {
+		comp.Copyrights = appendNew(comp.Copyrights, f.CopyrightText.Val)
+		comp.Notices = appendNew(comp.Notices, f.Notice.Val)
+	}
+	return comp
+}
+
+// Returns the text of the listed licence `id` from LicenceTextDir, or "" if
+// there is none.
+func listText(id string) string {
+	if !licenceIdRegex.MatchString(id) {
+		return ""
+	}
+	data, err := ioutil.ReadFile(filepath.Join(LicenceTextDir, id+".txt"))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Create the Notice of `doc`. The texts of listed licences are read from
+// LicenceTextDir; the IDs of the licences without a text are in
+// Notice.Missing.
+func New(doc *spdx.Document) *Notice {
+	n := &Notice{Title: "Third-party notices"}
+
+	extracted := make(map[string]*spdx.ExtractedLicence)
+	for _, lic := range doc.ExtractedLicences {
+		extracted[lic.LicenceId()] = lic
+	}
+	licences := make(map[string]*Licence)
+	licence := func(lic spdx.AnyLicence) *Licence {
+		id := lic.LicenceId()
+		if l, ok := licences[id]; ok {
+			return l
+		}
+		l := &Licence{Id: id, Anchor: "licence-" + id}
+		if el, ok := lic.(*spdx.ExtractedLicence); ok {
+			extracted[id] = el
+		}
+		if el, ok := extracted[id]; ok {
+			l.Text = strings.TrimSpace(el.Text.Val)
+			if len(el.Name) > 0 {
+				l.Name = el.Name[0].Val
+			}
+			if len(el.CrossReference) > 0 {
+				l.Url = el.CrossReference[0].Val
+			}
+		} else if !strings.HasPrefix(id, "LicenseRef-") {
+			l.Text = listText(id)
+			l.Url = LicenceListUrl + id
+		}
+		l.Missing = l.Text == ""
+		licences[id] = l
+		return l
+	}
+
+	groups := make(map[string]*Group)
+	for _, pkg := range doc.Packages {
+		files := pkg.Files
+		if len(files) == 0 && len(doc.Packages) == 1 {
+			files = doc.Files
+		}
+		lic := pkgLicence(pkg)
+		g, ok := groups[lic.LicenceId()]
+		if !ok {
+			g = &Group{Licence: lic.LicenceId()}
+			for _, m := range spdx.LicenceMembers(lic) {
+				if !spdx.IsEmpty(m.LicenceId()) {
+					g.Licences = append(g.Licences, licence(m))
+				}
+			}
+			groups[g.Licence] = g
+		}
+		g.Components = append(g.Components, component(pkg, files))
+	}
+
+	for _, g := range groups {
+		sort.Stable(byName(g.Components))
+		n.Groups = append(n.Groups, g)
+	}
+	sort.Sort(byLicence(n.Groups))
+	for _, l := range licences {
+		n.Licences = append(n.Licences, l)
+		if l.Missing {
+			n.Missing = append(n.Missing, l.Id)
+		}
+	}
+	sort.Sort(byId(n.Licences))
+	sort.Strings(n.Missing)
+	return n
+}
+
+// Sorts components by name and version.
+type byName []*Component
+
+func (s byName) Len() int      { return len(s) }
+func (s byName) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byName) Less(i, j int) bool {
+	if s[i].Name != s[j].Name {
+		return s[i].Name < s[j].Name
+	}
+	return s[i].Version < s[j].Version
+}
+
+// Sorts groups by licence.
+type byLicence []*Group
+
+func (s byLicence) Len() int           { return len(s) }
+func (s byLicence) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byLicence) Less(i, j int) bool { return s[i].Licence < s[j].Licence }
+
+// Sorts licences by ID.
+type byId []*Licence
+
+func (s byId) Len() int           { return len(s) }
+func (s byId) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byId) Less(i, j int) bool { return s[i].Id < s[j].Id }
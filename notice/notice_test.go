@@ -0,0 +1,165 @@
+package notice
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDocument = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+Creator: Person: Jane Doe
+Created: 2014-08-01T00:00:00Z
+
+PackageName: zlib
+PackageVersion: 1.2.8
+PackageDownloadLocation: http://zlib.net/zlib-1.2.8.tar.gz
+PackageLicenseConcluded: Zlib
+PackageLicenseDeclared: Zlib
+PackageCopyrightText: <text>Copyright (C) 1995-2013 Jean-loup Gailly and Mark Adler</text>
+
+PackageName: libfoo
+PackageVersion: 2.0
+PackageHomePage: https://example.com/foo
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseDeclared: (MIT and LicenseRef-1)
+PackageCopyrightText: NOASSERTION
+
+PackageName: abc
+PackageDownloadLocation: NOASSERTION
+PackageLicenseConcluded: Zlib
+PackageCopyrightText: <text>Copyright 2014 ABC</text>
+
+LicenseID: LicenseRef-1
+ExtractedText: <text>Foo licence text</text>
+LicenseName: Foo Licence
+LicenseCrossReference: http://example.com/licence
+`
+
+// Build testDocument with the licence texts in a temporary LicenceTextDir.
+func testNotice(t *testing.T, texts map[string]string) *Notice {
+	dir, err := ioutil.TempDir("", "notice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for id, text := range texts {
+		if err := ioutil.WriteFile(filepath.Join(dir, id+".txt"), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func(old string) { LicenceTextDir = old }(LicenceTextDir)
+	LicenceTextDir = dir
+
+	doc, err := tag.Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(doc)
+}
+
+func TestNew(t *testing.T) {
+	n := testNotice(t, map[string]string{"Zlib": "zlib licence text\n"})
+
+	if len(n.Groups) != 2 {
+		t.Fatalf("Wrong number of groups: %d", len(n.Groups))
+	}
+	g := n.Groups[0]
+	if g.Licence != "(MIT and LicenseRef-1)" || len(g.Components) != 1 || len(g.Licences) != 2 {
+		t.Errorf("Wrong group: %+v", g)
+	}
+	expected := &Component{Name: "libfoo", Version: "2.0", HomePage: "https://example.com/foo"}
+	if !reflect.DeepEqual(g.Components[0], expected) {
+		t.Errorf("Wrong component: %+v", g.Components[0])
+	}
+
+	g = n.Groups[1]
+	if g.Licence != "Zlib" || len(g.Components) != 2 {
+		t.Fatalf("Wrong group: %+v", g)
+	}
+	if c := g.Components[0]; c.Name != "abc" || c.HomePage != "" || !reflect.DeepEqual(c.Copyrights, []string{"Copyright 2014 ABC"}) {
+		t.Errorf("Wrong component: %+v", c)
+	}
+	if c := g.Components[1]; c.Name != "zlib" || c.HomePage != "http://zlib.net/zlib-1.2.8.tar.gz" {
+		t.Errorf("Wrong component: %+v", c)
+	}
+
+	ids := make([]string, len(n.Licences))
+	for i, l := range n.Licences {
+		ids[i] = l.Id
+	}
+	if !reflect.DeepEqual(ids, []string{"LicenseRef-1", "MIT", "Zlib"}) {
+		t.Errorf("Wrong licences: %v", ids)
+	}
+	ref := n.Licences[0]
+	if ref.Text != "Foo licence text" || ref.Name != "Foo Licence" || ref.Url != "http://example.com/licence" || ref.Missing {
+		t.Errorf("Wrong extracted licence: %+v", ref)
+	}
+	if zlib := n.Licences[2]; zlib.Text != "zlib licence text" || zlib.Url != LicenceListUrl+"Zlib" || zlib.Anchor != "licence-Zlib" {
+		t.Errorf("Wrong listed licence: %+v", zlib)
+	}
+	if !n.Licences[1].Missing || !reflect.DeepEqual(n.Missing, []string{"MIT"}) {
+		t.Errorf("Wrong missing licences: %v", n.Missing)
+	}
+}
+
+func TestNewFiles(t *testing.T) {
+	file := func(name, copyright, notice string) *spdx.File {
+		return &spdx.File{Name: spdx.Str(name, nil), CopyrightText: spdx.Str(copyright, nil), Notice: spdx.Str(notice, nil)}
+	}
+	doc := &spdx.Document{
+		Packages: []*spdx.Package{{Name: spdx.Str("pkg", nil), CopyrightText: spdx.Str("Copyright A", nil)}},
+		Files: []*spdx.File{
+			file("a.c", "Copyright A", ""),
+			file("b.c", "Copyright B", "Notice B"),
+			file("c.c", "NOASSERTION", "Notice B"),
+		},
+	}
+	n := New(doc)
+	if len(n.Groups) != 1 || n.Groups[0].Licence != spdx.NOASSERTION || len(n.Groups[0].Licences) != 0 {
+		t.Fatalf("Wrong groups: %+v", n.Groups)
+	}
+	c := n.Groups[0].Components[0]
+	if !reflect.DeepEqual(c.Copyrights, []string{"Copyright A", "Copyright B"}) || !reflect.DeepEqual(c.Notices, []string{"Notice B"}) {
+		t.Errorf("Wrong component: %+v", c)
+	}
+	if len(n.Licences) != 0 || len(n.Missing) != 0 {
+		t.Errorf("Wrong licences: %+v", n.Licences)
+	}
+
+	// document files are not attributed when there are several packages
+	doc.Packages = append(doc.Packages, &spdx.Package{Name: spdx.Str("other", nil)})
+	if c := New(doc).Groups[0].Components[1]; len(c.Copyrights) != 1 || len(c.Notices) != 0 {
+		t.Errorf("Document files attributed to a package: %+v", c)
+	}
+}
+
+func TestListText(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(dir, "list"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(old string) { LicenceTextDir = old }(LicenceTextDir)
+	LicenceTextDir = filepath.Join(dir, "list")
+
+	for _, id := range []string{"../secret", "MIT", ""} {
+		if text := listText(id); text != "" {
+			t.Errorf("Text found for %q: %s", id, text)
+		}
+	}
+}
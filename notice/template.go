@@ -0,0 +1,169 @@
+package notice
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Output formats of the built-in templates
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatHtml     = "html"
+)
+
+// A parsed template: a *text/template.Template or a *html/template.Template.
+type Template interface {
+	Execute(w io.Writer, data interface{}) error
+}
+
+// The functions available in the templates, in addition to the
+// text/template functions:
+//   - join: strings.Join
+//   - indent: prefix every line of a text with a string
+//   - underline: a line of a character as long as a text
+//   - fence: a Markdown code fence longer than any backtick run in a text
+var funcs = map[string]interface{}{
+	"join": strings.Join,
+	"indent": func(text, prefix string) string {
+		return prefix + strings.Replace(text, "\n", "\n"+prefix, -1)
+	},
+	"underline": func(text, char string) string {
+		return strings.Repeat(char, len([]rune(text)))
+	},
+	"fence": func(text string) string {
+		fence := "```"
+		for strings.Contains(text, fence) {
+			fence += "`"
+		}
+		return fence
+	},
+}
+
+// Parse the template `text` for the given format. HTML templates are parsed
+// with html/template, so that the values are escaped, and the others with
+// text/template.
+func Parse(format, text string) (Template, error) {
+	if format == FormatHtml {
+		return htmltemplate.New(format).Funcs(funcs).Parse(text)
+	}
+	return template.New(format).Funcs(funcs).Parse(text)
+}
+
+// Returns the built-in template of the given format, or nil if there is
+// none. Valid formats are FormatText, FormatMarkdown and FormatHtml.
+func Builtin(format string) Template {
+	text, ok := builtin[format]
+	if !ok {
+		return nil
+	}
+	tmpl, err := Parse(format, text)
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// Write the notices with the template `tmpl`.
+func (n *Notice) Execute(w io.Writer, tmpl Template) error {
+	return tmpl.Execute(w, n)
+}
+
+// The built-in templates by format.
+var builtin = map[string]string{
+	FormatText:     TextTemplate,
+	FormatMarkdown: MarkdownTemplate,
+	FormatHtml:     HtmlTemplate,
+}
+
+// The built-in text template.
+const TextTemplate = `{{.Title}}
+{{underline .Title "="}}
+
+This product includes the following third-party software.
+{{range .Groups}}
+{{.Licence}}
+{{underline .Licence "-"}}
+{{range .Components}}
+* {{.Name}}{{with .Version}} {{.}}{{end}}{{with .HomePage}}
+  {{.}}{{end}}{{range .Copyrights}}
+{{indent . "  "}}{{end}}{{range .Notices}}
+
+{{indent . "  "}}{{end}}
+{{end}}{{end}}
+Licence texts
+=============
+{{range .Licences}}
+{{.Id}}
+{{underline .Id "-"}}
+{{with .Name}}{{.}}
+{{end}}
+{{if .Missing}}See {{.Url}}{{else}}{{.Text}}{{end}}
+{{end}}`
+
+// The built-in Markdown template.
+const MarkdownTemplate = `# {{.Title}}
+
+This product includes the following third-party software.
+{{range .Groups}}
+## {{.Licence}}
+{{with .Licences}}
+Licence texts: {{range $i, $l := .}}{{if $i}}, {{end}}[{{$l.Id}}](#{{$l.Anchor}}){{end}}
+{{end}}{{range .Components}}
+### {{.Name}}{{with .Version}} {{.}}{{end}}
+{{with .HomePage}}
+<{{.}}>
+{{end}}{{with .Copyrights}}{{$text := join . "\n"}}{{$fence := fence $text}}
+{{$fence}}
+{{$text}}
+{{$fence}}
+{{end}}{{range .Notices}}{{$fence := fence .}}
+{{$fence}}
+{{.}}
+{{$fence}}
+{{end}}{{end}}{{end}}
+## Licence texts
+{{range .Licences}}
+<a id="{{.Anchor}}"></a>
+### {{.Id}}{{with .Name}} ({{.}}){{end}}
+{{if .Missing}}
+See <{{.Url}}>.
+{{else}}{{$fence := fence .Text}}
+{{$fence}}
+{{.Text}}
+{{$fence}}
+{{end}}{{end}}`
+
+// The built-in HTML template. The page is self-contained.
+const HtmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #222; max-width: 960px; margin: 0 auto; padding: 0 20px; }
+h2 { border-bottom: 1px solid #ccc; }
+pre { white-space: pre-wrap; background: #f8f8f8; padding: 8px; border: 1px solid #eee; }
+.component { margin-bottom: 15px; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>This product includes the following third-party software.</p>
+{{range .Groups}}<h2>{{.Licence}}</h2>
+{{with .Licences}}<p>Licence texts: {{range $i, $l := .}}{{if $i}}, {{end}}<a href="#{{$l.Anchor}}">{{$l.Id}}</a>{{end}}</p>
+{{end}}{{range .Components}}<div class="component">
+<h3>{{.Name}}{{with .Version}} {{.}}{{end}}</h3>
+{{with .HomePage}}<p><a href="{{.}}">{{.}}</a></p>
+{{end}}{{with .Copyrights}}<pre>{{join . "\n"}}</pre>
+{{end}}{{range .Notices}}<pre>{{.}}</pre>
+{{end}}</div>
+{{end}}{{end}}<h2>Licence texts</h2>
+{{range .Licences}}<h3 id="{{.Anchor}}">{{.Id}}{{with .Name}} ({{.}}){{end}}</h3>
+{{if .Missing}}<p>See <a href="{{.Url}}">{{.Url}}</a>.</p>
+{{else}}<pre>{{.Text}}</pre>
+{{end}}{{end}}</body>
+</html>
+`
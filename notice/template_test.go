@@ -0,0 +1,87 @@
+package notice
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// Returns the notices of testDocument written with `tmpl`.
+func execute(t *testing.T, tmpl Template) string {
+	n := testNotice(t, map[string]string{"Zlib": "zlib <licence> text"})
+	n.Groups[1].Components[0].Copyrights = []string{"Copyright <ABC>", "```code```"}
+	var b bytes.Buffer
+	if err := n.Execute(&b, tmpl); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+// Checks that all `expected` strings are in `out`, in order.
+func checkOutput(t *testing.T, out string, expected []string) {
+	last := -1
+	for _, s := range expected {
+		i := strings.Index(out, s)
+		if i < 0 {
+			t.Errorf("%q not found in:\n%s", s, out)
+		} else if i < last {
+			t.Errorf("%q not in order in:\n%s", s, out)
+		}
+		last = i
+	}
+}
+
+func TestText(t *testing.T) {
+	checkOutput(t, execute(t, Builtin(FormatText)), []string{
+		"Third-party notices\n===================\n",
+		"\n(MIT and LicenseRef-1)\n----------------------\n\n* libfoo 2.0\n  https://example.com/foo\n",
+		"\nZlib\n----\n\n* abc\n  Copyright <ABC>\n  ```code```\n",
+		"* zlib 1.2.8\n  http://zlib.net/zlib-1.2.8.tar.gz\n  Copyright (C) 1995-2013",
+		"LicenseRef-1\n------------\nFoo Licence\n\nFoo licence text\n",
+		"MIT\n---\n\nSee http://spdx.org/licenses/MIT\n",
+		"Zlib\n----\n\nzlib <licence> text\n",
+	})
+}
+
+func TestMarkdown(t *testing.T) {
+	checkOutput(t, execute(t, Builtin(FormatMarkdown)), []string{
+		"# Third-party notices\n",
+		"## (MIT and LicenseRef-1)\n\nLicence texts: [MIT](#licence-MIT), [LicenseRef-1](#licence-LicenseRef-1)\n",
+		"### libfoo 2.0\n\n<https://example.com/foo>\n",
+		"### abc\n\n````\nCopyright <ABC>\n```code```\n````\n",
+		"<a id=\"licence-MIT\"></a>\n### MIT\n\nSee <http://spdx.org/licenses/MIT>.\n",
+		"### Zlib\n\n```\nzlib <licence> text\n```\n",
+	})
+}
+
+func TestHtml(t *testing.T) {
+	out := execute(t, Builtin(FormatHtml))
+	checkOutput(t, out, []string{
+		"<title>Third-party notices</title>",
+		`<a href="#licence-LicenseRef-1">LicenseRef-1</a>`,
+		"<pre>Copyright &lt;ABC&gt;\n```code```</pre>",
+		`<h3 id="licence-MIT">MIT</h3>`,
+		`<p>See <a href="http://spdx.org/licenses/MIT">http://spdx.org/licenses/MIT</a>.</p>`,
+		"<pre>zlib &lt;licence&gt; text</pre>",
+	})
+	if strings.Contains(out, "<ABC>") {
+		t.Error("Values not escaped.")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tmpl, err := Parse(FormatText, `{{range .Groups}}{{.Licence}}: {{range .Components}}{{.Name}} {{end}}{{"\n"}}{{end}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := execute(t, tmpl)
+	if out != "(MIT and LicenseRef-1): libfoo \nZlib: abc zlib \n" {
+		t.Errorf("Wrong output: %q", out)
+	}
+	if _, err := Parse(FormatHtml, "{{.Title"); err == nil {
+		t.Error("No error for an invalid template.")
+	}
+	if Builtin("pdf") != nil {
+		t.Error("Built-in template for an unknown format.")
+	}
+}
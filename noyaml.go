@@ -0,0 +1,28 @@
+//go:build !yaml
+// +build !yaml
+
+package main
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"errors"
+	"io"
+)
+
+// The SPDX YAML format needs gopkg.in/yaml.v3 and is not supported by the
+// builds without the "yaml" tag.
+var yamlFormats []string
+
+// Error for SPDX YAML input or output in the builds without the "yaml" tag.
+var errNoYaml = errors.New("SPDX YAML is not supported by this build. Build with the \"yaml\" tag.")
+
+// Returns errNoYaml.
+func readYaml(r io.Reader) (*spdx.Document, error) {
+	return nil, errNoYaml
+}
+
+// Returns errNoYaml.
+func writeYaml(w io.Writer, doc *spdx.Document) error {
+	return errNoYaml
+}
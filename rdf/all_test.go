@@ -1,7 +1,15 @@
 package rdf
 
 import (
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -53,11 +61,17 @@ func TestParseWriteParse(t *testing.T) {
 	r.Close()
 }
 
-// There are known problems writing other RDF formats than xmlrdf-abbrev.
-// Refer to issue #46 on github: https://github.com/vladvelici/spdx-go/issues/46
-func _TestWriteFormat(t *testing.T) {
-	format := Fmt_turtle
+// Write a document in the other formats of the native RDF backend and parse it
+// again. With raptor, there are known problems writing other RDF formats than
+// xmlrdf-abbrev: refer to issue #46 on github:
+// https://github.com/vladvelici/spdx-go/issues/46
+func TestWriteFormat(t *testing.T) {
+	for _, format := range []string{Fmt_rdfxml, Fmt_turtle, Fmt_ntriples, Fmt_jsonld} {
+		testWriteFormat(t, format)
+	}
+}
 
+func testWriteFormat(t *testing.T, format string) {
 	documentReader, err := os.Open(testFile)
 	if err != nil {
 		t.Logf("The RDF package should contain a test file called %s.", testFile)
@@ -89,15 +103,76 @@ func _TestWriteFormat(t *testing.T) {
 
 	parsed2, err := Parse(r, format)
 	if err != nil {
-		t.Errorf("Unexpected error %s", err)
+		t.Errorf("Unexpected error %s (%s)", err, format)
 		t.FailNow()
 	}
 
 	if !parsed1.Equal(parsed2) {
-		t.Error("Documents are not the same.")
+		t.Errorf("Documents are not the same (%s).", format)
 		t.FailNow()
 	}
 
 	documentReader.Close()
 	r.Close()
 }
+
+const digestInput = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+Creator: Tool: x
+Created: 2014-01-01T00:00:00Z
+
+PackageName: p
+PackageVersion: 1.0
+PackageLicenseConcluded: (MIT or LicenseRef-1)
+PackageLicenseDeclared: MIT
+PackageCopyrightText: NONE
+
+FileName: a.c
+FileType: SOURCE
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12
+LicenseConcluded: (MIT and LicenseRef-1)
+LicenseInfoInFile: MIT
+FileCopyrightText: Copyright 2014 Foo
+
+LicenseID: LicenseRef-1
+ExtractedText: text
+`
+
+// The digest of a document does not depend on its serialisation format.
+func TestDigestFormats(t *testing.T) {
+	doc, err := tag.Build(strings.NewReader(digestInput))
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := spdx.Digest(doc)
+
+	var b bytes.Buffer
+	if err = json.Write(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	fromJson, err := json.Build(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spdx.Digest(fromJson) != digest {
+		t.Errorf("Different digest in SPDX JSON:\n%s\n%s", spdx.Canonical(doc), spdx.Canonical(fromJson))
+	}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	err = Write(w, fromJson)
+	w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromRdf, err := Parse(r, "rdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spdx.Digest(fromRdf) != digest {
+		t.Errorf("Different digest in RDF:\n%s\n%s", spdx.Canonical(doc), spdx.Canonical(fromRdf))
+	}
+}
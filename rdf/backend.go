@@ -0,0 +1,63 @@
+package rdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/spdx/tools-go/spdx"
+	"io"
+)
+
+// Error message for formats the RDF backend cannot read or write.
+const msgFormatNotSupported = "RDF format %s is not supported by this build."
+
+// A serializer writes RDF statements in one RDF syntax. It is implemented by
+// the RDF backend (see newSerializer()) and by this package for JSON-LD.
+type serializer interface {
+	// Use `prefix` for the namespace `uri` in the output, if the syntax has
+	// namespace prefixes.
+	SetNamespace(prefix, uri string)
+
+	// Write a statement.
+	Add(stm *Statement) error
+
+	// Finish writing and free the serializer.
+	Close() error
+}
+
+// Called for every statement found by parseFormat(), with the line of the
+// input the statement was found on.
+type statementHandler func(*Statement, *spdx.Meta) error
+
+// Returns the error for a format the RDF backend does not support.
+func formatNotSupported(format string) error {
+	return fmt.Errorf(msgFormatNotSupported, format)
+}
+
+// Parse the statements in `input` like parseStatements(). JSON-LD is parsed by
+// this package and the other formats by the RDF backend. With the format
+// "rdf", input starting with "{" or "[" is parsed as JSON-LD.
+func parseFormat(input io.Reader, format string, ns func(prefix, uri string), handle statementHandler) error {
+	if format == "rdf" {
+		buf := bufio.NewReader(input)
+		input = buf
+		start, _ := buf.Peek(512)
+		start = bytes.TrimLeft(bytes.TrimPrefix(start, []byte("\xef\xbb\xbf")), " \t\r\n")
+		if len(start) > 0 && (start[0] == '{' || start[0] == '[') {
+			format = Fmt_jsonld
+		}
+	}
+	if format == Fmt_jsonld {
+		return parseJsonld(input, ns, handle)
+	}
+	return parseStatements(input, format, ns, handle)
+}
+
+// Create a serializer for `format`, like newSerializer(). JSON-LD is written
+// by this package and the other formats by the RDF backend.
+func newFormatSerializer(output io.Writer, format string) (serializer, error) {
+	if format == Fmt_jsonld {
+		return newJsonldSerializer(output), nil
+	}
+	return newSerializer(output, format)
+}
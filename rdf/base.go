@@ -1,14 +1,17 @@
 package rdf
 
 import (
-	"github.com/deltamobile/goraptor"
 	"strings"
 )
 
-// Constants representing RDF formats supported by raptor.
+// Constants representing RDF formats supported by raptor. The native RDF
+// backend (the default build) supports the RDF/XML formats, Turtle and
+// N-Triples; build with the "raptor" tag to use all of them. JSON-LD is not a
+// raptor format and is supported by all the builds. Formats lists the formats
+// of the build.
 //
 // One of the accepted formats not in this constants is "rdf". When parsing,
-// "rdf" means using raptor's "guess" parser. When writing, it means using
+// "rdf" means guessing the syntax of the input. When writing, it means using
 // Fmt_rdfxmlAbbrev.
 const (
 	Fmt_ntriples     = "ntriples"      // for N-Triples
@@ -23,6 +26,7 @@ const (
 	Fmt_json         = "json"          // for RDF/JSON Resource-Centric
 	Fmt_html         = "html"          // for HTML Table
 	Fmt_nquads       = "nquads"        // for N-Quads
+	Fmt_jsonld       = "json-ld"       // for JSON-LD with the SPDX context
 )
 
 // Useful RDF URIs
@@ -44,25 +48,10 @@ type pair struct {
 	key, val string
 }
 
-// Checks if `fmt` is one of the raptor supported formats (has the value of one
-// of the Fmt_* constants). The special "rdf" value is considered invalid by
-// this function.
+// Checks if `fmt` is one of the formats supported by this build (see
+// Formats). The special "rdf" value is considered invalid by this function.
 func FormatOk(fmt string) bool {
-	fmts := []string{
-		Fmt_ntriples,
-		Fmt_turtle,
-		Fmt_rdfxmlXmp,
-		Fmt_rdfxmlAbbrev,
-		Fmt_rdfxml,
-		Fmt_rss,
-		Fmt_atom,
-		Fmt_dot,
-		Fmt_jsonTriples,
-		Fmt_json,
-		Fmt_html,
-		Fmt_nquads,
-	}
-	for _, f := range fmts {
+	for _, f := range Formats {
 		if fmt == f {
 			return true
 		}
@@ -72,7 +61,7 @@ func FormatOk(fmt string) bool {
 
 // Expands the prefixes "ns:", "doap:" and "rdfs:" to their full URIs.
 // If there is no ":" or there is another prefix, it expands to baseUri.
-func prefix(k string) *goraptor.Uri {
+func prefix(k string) *Uri {
 	var pref string
 	rest := k
 	if i := strings.Index(k, ":"); i >= 0 {
@@ -82,12 +71,12 @@ func prefix(k string) *goraptor.Uri {
 	if long, ok := rdfPrefixes[pref]; ok {
 		pref = long
 	}
-	uri := goraptor.Uri(pref + rest)
+	uri := Uri(pref + rest)
 	return &uri
 }
 
 // Change the RDF prefixes to their short forms.
-func shortPrefix(t goraptor.Term) string {
+func shortPrefix(t Term) string {
 	str := termStr(t)
 	for short, long := range rdfPrefixes {
 		if strings.HasPrefix(str, long) {
@@ -97,32 +86,32 @@ func shortPrefix(t goraptor.Term) string {
 	return str
 }
 
-// goraptor.Term to string. Returns empty string if the term given is not one of
-// the following types: *goraptor.Uri, *goraptor.Blank or *goraptor.Literal.
-func termStr(term goraptor.Term) string {
+// Term to string. Returns empty string if the term given is not one of
+// the following types: *Uri, *Blank or *Literal.
+func termStr(term Term) string {
 	switch t := term.(type) {
-	case *goraptor.Uri:
+	case *Uri:
 		return string(*t)
-	case *goraptor.Blank:
+	case *Blank:
 		return string(*t)
-	case *goraptor.Literal:
+	case *Literal:
 		return t.Value
 	default:
 		return ""
 	}
 }
 
-// Create *goraptor.Uri from string
-func uri(uri string) *goraptor.Uri {
-	return (*goraptor.Uri)(&uri)
+// Create *Uri from string
+func uri(uri string) *Uri {
+	return (*Uri)(&uri)
 }
 
-// Create *goraptor.Literal from string
-func literal(lit string) *goraptor.Literal {
-	return &goraptor.Literal{Value: lit}
+// Create *Literal from string
+func literal(lit string) *Literal {
+	return &Literal{Value: lit}
 }
 
-// Create *goraptor.Blank from string
-func blank(b string) *goraptor.Blank {
-	return (*goraptor.Blank)(&b)
+// Create *Blank from string
+func blank(b string) *Blank {
+	return (*Blank)(&b)
 }
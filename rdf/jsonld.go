@@ -0,0 +1,505 @@
+package rdf
+
+import (
+	"fmt"
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/spdx"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSON-LD parser error messages.
+const (
+	msgJsonldRemoteContext = "Remote JSON-LD contexts are not supported."
+	msgJsonldInvalid       = "Invalid JSON-LD %s."
+	msgJsonldCyclicTerm    = "Cyclic definition of the JSON-LD term %s."
+	msgJsonldUnsupported   = "The JSON-LD keyword %s is not supported."
+)
+
+// Prefixes of the bundled SPDX JSON-LD context. Its vocabulary is the SPDX
+// terms namespace (baseUri).
+var jsonldPrefixes = []pair{
+	{"rdf", rdfNs},
+	{"rdfs", "http://www.w3.org/2000/01/rdf-schema#"},
+	{"doap", "http://usefulinc.com/ns/doap#"},
+	{"xsd", xsdNs},
+}
+
+// SPDX properties with IRI values in the bundled context: node IRIs ("@id")
+// and SPDX terms ("@vocab").
+var (
+	jsonldIdTerms = []string{
+		"dataLicense", "licenseConcluded", "licenseDeclared",
+		"licenseInfoFromFiles", "licenseInfoInFile", "member",
+		"hasExtractedLicensingInfo", "referencesFile", "hasFile",
+		"fileDependency",
+	}
+	jsonldVocabTerms = []string{"algorithm", "fileType"}
+)
+
+// Returns the bundled SPDX JSON-LD context.
+func jsonldContext() *json.Node {
+	ctx := json.Object(nil)
+	ctx.Add("@vocab", json.String(baseUri, nil))
+	for _, p := range jsonldPrefixes {
+		ctx.Add(p.key, json.String(p.val, nil))
+	}
+	for _, term := range jsonldIdTerms {
+		def := json.Object(nil)
+		def.Add("@type", json.String("@id", nil))
+		ctx.Add(term, def)
+	}
+	for _, term := range jsonldVocabTerms {
+		def := json.Object(nil)
+		def.Add("@type", json.String("@vocab", nil))
+		ctx.Add(term, def)
+	}
+	return ctx
+}
+
+// A JSON-LD term definition.
+type ldTerm struct {
+	iri       string
+	typ       string // "@id", "@vocab" or a datatype IRI
+	container string // "@list", "@set" or ""
+}
+
+// A processed JSON-LD context.
+type ldContext struct {
+	base, vocab, lang string
+	terms             map[string]*ldTerm
+}
+
+// Returns a copy of the context, which can be changed.
+func (c *ldContext) copy() *ldContext {
+	res := *c
+	res.terms = make(map[string]*ldTerm, len(c.terms))
+	for k, v := range c.terms {
+		res.terms[k] = v
+	}
+	return &res
+}
+
+// Returns a *spdx.ParseError at the line of the JSON node.
+func ldError(n *json.Node, msg string, args ...interface{}) error {
+	var meta *spdx.Meta
+	if n != nil && n.Meta != nil {
+		meta = spdx.NewMetaL(n.LineStart)
+	}
+	return spdx.NewParseError(fmt.Sprintf(msg, args...), meta)
+}
+
+// Process the local context `n` in the active context.
+func (c *ldContext) process(n *json.Node) (*ldContext, error) {
+	switch n.Kind {
+	case json.KindNull:
+		return &ldContext{base: c.base, terms: make(map[string]*ldTerm)}, nil
+	case json.KindString:
+		return nil, ldError(n, msgJsonldRemoteContext)
+	case json.KindArray:
+		res := c
+		for _, item := range n.Items {
+			var err error
+			if res, err = res.process(item); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
+	case json.KindObject:
+	default:
+		return nil, ldError(n, msgJsonldInvalid, "context")
+	}
+
+	res := c.copy()
+	defs := make(map[string]*json.Node)
+	for _, f := range n.Fields {
+		switch f.Key {
+		case "@base":
+			if f.Value.Kind != json.KindString {
+				return nil, ldError(f.Value, msgJsonldInvalid, "@base")
+			}
+			res.base = resolve(res.base, f.Value.Value)
+		case "@vocab":
+			res.vocab = f.Value.Value
+		case "@language":
+			res.lang = f.Value.Value
+		case "@version", "@protected":
+		default:
+			defs[f.Key] = f.Value
+		}
+	}
+	defining := make(map[string]bool)
+	for _, f := range n.Fields {
+		if _, ok := defs[f.Key]; ok {
+			if err := res.define(f.Key, defs, defining); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return res, nil
+}
+
+// Create the definition of `term` from `defs`, defining the terms it uses
+// first.
+func (c *ldContext) define(term string, defs map[string]*json.Node, defining map[string]bool) error {
+	n, ok := defs[term]
+	if !ok {
+		return nil
+	}
+	if defining[term] {
+		return ldError(n, msgJsonldCyclicTerm, term)
+	}
+	defining[term] = true
+	defer delete(defs, term)
+
+	// terms used in the definition
+	deps := []string{term}
+	if i := strings.Index(term, ":"); i > 0 {
+		deps = append(deps, term[:i])
+	}
+	for _, v := range []*json.Node{n, n.Get("@id"), n.Get("@type")} {
+		if v != nil && v.Kind == json.KindString {
+			deps = append(deps, v.Value)
+			if i := strings.Index(v.Value, ":"); i > 0 {
+				deps = append(deps, v.Value[:i])
+			}
+		}
+	}
+	for _, dep := range deps {
+		if dep != term {
+			if err := c.define(dep, defs, defining); err != nil {
+				return err
+			}
+		}
+	}
+
+	def := &ldTerm{}
+	switch n.Kind {
+	case json.KindNull:
+		c.terms[term] = nil
+		return nil
+	case json.KindString:
+		def.iri = c.expand(n.Value, true, false)
+	case json.KindObject:
+		id := n.Get("@id")
+		switch {
+		case id == nil:
+			def.iri = c.expandNew(term)
+		case id.Kind == json.KindString:
+			def.iri = c.expand(id.Value, true, false)
+		case id.Kind == json.KindNull:
+			c.terms[term] = nil
+			return nil
+		default:
+			return ldError(id, msgJsonldInvalid, "@id")
+		}
+		if typ := n.Get("@type"); typ != nil {
+			def.typ = c.expand(typ.Value, true, false)
+		}
+		if cont := n.Get("@container"); cont != nil && cont.Kind == json.KindString {
+			def.container = cont.Value
+		}
+		if rev := n.Get("@reverse"); rev != nil {
+			return ldError(rev, msgJsonldUnsupported, "@reverse")
+		}
+	default:
+		return ldError(n, msgJsonldInvalid, "term definition")
+	}
+	c.terms[term] = def
+	return nil
+}
+
+// Expand a term without definition: a compact IRI, an absolute IRI or a term
+// in the vocabulary.
+func (c *ldContext) expandNew(term string) string {
+	delete(c.terms, term)
+	return c.expand(term, true, false)
+}
+
+// Expand a compact IRI, term or relative IRI. Terms are used if `vocab` is
+// set, relative IRIs are resolved against the base if `relative` is set.
+// Keywords are returned as they are.
+func (c *ldContext) expand(val string, vocab, relative bool) string {
+	if strings.HasPrefix(val, "@") {
+		return val
+	}
+	if vocab {
+		if def, ok := c.terms[val]; ok {
+			if def == nil {
+				return ""
+			}
+			return def.iri
+		}
+	}
+	if i := strings.Index(val, ":"); i >= 0 {
+		prefix, suffix := val[:i], val[i+1:]
+		if prefix == "_" || strings.HasPrefix(suffix, "//") {
+			return val
+		}
+		if def, ok := c.terms[prefix]; ok && def != nil {
+			return def.iri + suffix
+		}
+		return val
+	}
+	if vocab && c.vocab != "" {
+		return c.vocab + val
+	}
+	if relative {
+		return resolve(c.base, val)
+	}
+	return val
+}
+
+// JSON-LD parser. It converts the JSON-LD document to RDF statements.
+type jsonldParser struct {
+	handle statementHandler
+	blanks int
+}
+
+// Parse the JSON-LD in `input` and call `handle` for each statement, with the
+// line of its object. `ns` is called for the prefixes defined in the
+// top-level context, if it is not nil.
+func parseJsonld(input io.Reader, ns func(prefix, uri string), handle statementHandler) error {
+	root, err := json.Decode(input)
+	if err != nil {
+		return err
+	}
+	p := &jsonldParser{handle: handle}
+	ctx := &ldContext{base: baseUri, terms: make(map[string]*ldTerm)}
+
+	if root.Kind == json.KindObject {
+		if local := root.Get("@context"); local != nil {
+			if ctx, err = ctx.process(local); err != nil {
+				return err
+			}
+			if ns != nil {
+				for term, def := range ctx.terms {
+					if def != nil && (strings.HasSuffix(def.iri, "#") || strings.HasSuffix(def.iri, "/")) {
+						ns(term, def.iri)
+					}
+				}
+			}
+		}
+		// a document with only a context and the default graph
+		if graph := root.Get("@graph"); graph != nil && root.Get("@id") == nil && len(root.Fields) <= 2 {
+			return p.nodes(ctx, graph)
+		}
+	}
+	return p.nodes(ctx, root)
+}
+
+// Parse top-level node objects: a node object or an array of them.
+func (p *jsonldParser) nodes(ctx *ldContext, n *json.Node) error {
+	if n.Kind == json.KindArray {
+		for _, item := range n.Items {
+			if err := p.nodes(ctx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if n.Kind != json.KindObject {
+		return ldError(n, msgJsonldInvalid, "node object")
+	}
+	_, err := p.node(ctx, n)
+	return err
+}
+
+// Returns a new blank node.
+func (p *jsonldParser) newBlank() *Blank {
+	p.blanks++
+	return blank("genid" + strconv.Itoa(p.blanks))
+}
+
+// Returns the node with the IRI or blank node identifier `id`.
+func nodeTerm(id string) Term {
+	if strings.HasPrefix(id, "_:") {
+		return blank(id[2:])
+	}
+	return uri(id)
+}
+
+// Emit a statement with the line of the JSON node `n`.
+func (p *jsonldParser) emit(subj, pred, obj Term, n *json.Node) error {
+	var meta *spdx.Meta
+	if n.Meta != nil {
+		meta = spdx.NewMetaL(n.LineStart)
+	}
+	return p.handle(&Statement{subj, pred, obj}, meta)
+}
+
+// Parse a node object and emit its statements. Returns the node.
+func (p *jsonldParser) node(ctx *ldContext, n *json.Node) (Term, error) {
+	if local := n.Get("@context"); local != nil {
+		var err error
+		if ctx, err = ctx.process(local); err != nil {
+			return nil, err
+		}
+	}
+
+	var subj Term
+	if id := n.Get("@id"); id != nil {
+		if id.Kind != json.KindString {
+			return nil, ldError(id, msgJsonldInvalid, "@id")
+		}
+		subj = nodeTerm(ctx.expand(id.Value, false, true))
+	} else {
+		subj = p.newBlank()
+	}
+
+	if types := n.Get("@type"); types != nil {
+		items := []*json.Node{types}
+		if types.Kind == json.KindArray {
+			items = types.Items
+		}
+		for _, t := range items {
+			if t.Kind != json.KindString {
+				return nil, ldError(t, msgJsonldInvalid, "@type")
+			}
+			if err := p.emit(subj, uri(rdfNs+"type"), nodeTerm(ctx.expand(t.Value, true, true)), t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, f := range n.Fields {
+		switch f.Key {
+		case "@context", "@id", "@type", "@index":
+			continue
+		case "@graph":
+			if err := p.nodes(ctx, f.Value); err != nil {
+				return nil, err
+			}
+			continue
+		case "@reverse", "@nest", "@included":
+			return nil, ldError(f.Value, msgJsonldUnsupported, f.Key)
+		}
+
+		pred := ctx.expand(f.Key, true, false)
+		if !strings.Contains(pred, ":") || strings.HasPrefix(pred, "_:") {
+			// not mapped to an IRI: dropped, as by JSON-LD expansion
+			continue
+		}
+		def := ctx.terms[f.Key]
+		if def == nil {
+			def = &ldTerm{}
+		}
+		if err := p.values(ctx, def, subj, uri(pred), f.Value); err != nil {
+			return nil, err
+		}
+	}
+	return subj, nil
+}
+
+// Parse the values of the property `pred` of `subj` and emit the statements.
+func (p *jsonldParser) values(ctx *ldContext, def *ldTerm, subj, pred Term, n *json.Node) error {
+	if n.Kind == json.KindArray && def.container == "@list" {
+		list, err := p.list(ctx, def, n)
+		if err != nil {
+			return err
+		}
+		return p.emit(subj, pred, list, n)
+	}
+	if n.Kind == json.KindArray {
+		for _, item := range n.Items {
+			if err := p.values(ctx, def, subj, pred, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if set := n.Get("@set"); n.Kind == json.KindObject && set != nil {
+		return p.values(ctx, def, subj, pred, set)
+	}
+
+	obj, err := p.value(ctx, def, n)
+	if err != nil || obj == nil {
+		return err
+	}
+	return p.emit(subj, pred, obj, n)
+}
+
+// Returns the term of a value, emitting the statements of node objects.
+// Returns nil for null values.
+func (p *jsonldParser) value(ctx *ldContext, def *ldTerm, n *json.Node) (Term, error) {
+	switch n.Kind {
+	case json.KindNull:
+		return nil, nil
+	case json.KindString:
+		switch def.typ {
+		case "@id":
+			return nodeTerm(ctx.expand(n.Value, false, true)), nil
+		case "@vocab":
+			return nodeTerm(ctx.expand(n.Value, true, true)), nil
+		case "":
+			return &Literal{Value: n.Value, Lang: ctx.lang}, nil
+		}
+		return &Literal{Value: n.Value, Datatype: def.typ}, nil
+	case json.KindNumber:
+		datatype := xsdInteger
+		if strings.ContainsAny(n.Value, ".eE") {
+			datatype = xsdDouble
+		}
+		if def.typ != "" && !strings.HasPrefix(def.typ, "@") {
+			datatype = def.typ
+		}
+		return &Literal{Value: n.Value, Datatype: datatype}, nil
+	case json.KindBool:
+		return &Literal{Value: n.Value, Datatype: xsdBoolean}, nil
+	case json.KindArray:
+		// nested arrays are flattened, only lists of lists are not
+		return p.list(ctx, def, n)
+	}
+
+	if val := n.Get("@value"); val != nil {
+		lit := &Literal{Value: val.Value}
+		if val.Kind != json.KindString && val.Kind != json.KindNumber && val.Kind != json.KindBool {
+			return nil, ldError(val, msgJsonldInvalid, "@value")
+		}
+		if t := n.Get("@type"); t != nil {
+			lit.Datatype = ctx.expand(t.Value, true, true)
+		} else if lang := n.Get("@language"); lang != nil {
+			lit.Lang = lang.Value
+		} else if val.Kind == json.KindString {
+			lit.Lang = ctx.lang
+		}
+		return lit, nil
+	}
+	if list := n.Get("@list"); list != nil {
+		if list.Kind != json.KindArray {
+			list = &json.Node{Kind: json.KindArray, Items: []*json.Node{list}, Meta: list.Meta}
+		}
+		return p.list(ctx, def, list)
+	}
+	return p.node(ctx, n)
+}
+
+// Emit the RDF list of the items of the array `n` and return it.
+func (p *jsonldParser) list(ctx *ldContext, def *ldTerm, n *json.Node) (Term, error) {
+	items := make([]Term, 0, len(n.Items))
+	nodes := make([]*json.Node, 0, len(n.Items))
+	for _, item := range n.Items {
+		obj, err := p.value(ctx, def, item)
+		if err != nil {
+			return nil, err
+		}
+		if obj != nil {
+			items = append(items, obj)
+			nodes = append(nodes, item)
+		}
+	}
+
+	var list Term = uri(rdfNs + "nil")
+	for i := len(items) - 1; i >= 0; i-- {
+		cell := p.newBlank()
+		if err := p.emit(cell, uri(rdfNs+"first"), items[i], nodes[i]); err != nil {
+			return nil, err
+		}
+		if err := p.emit(cell, uri(rdfNs+"rest"), list, nodes[i]); err != nil {
+			return nil, err
+		}
+		list = cell
+	}
+	return list, nil
+}
@@ -0,0 +1,147 @@
+package rdf
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"strings"
+	"testing"
+)
+
+// Parse JSON-LD and return the statements and their lines.
+func parseJsonldString(t *testing.T, input string) ([]*Statement, []int) {
+	stms := make([]*Statement, 0)
+	lines := make([]int, 0)
+	err := parseJsonld(strings.NewReader(input), nil, func(stm *Statement, meta *spdx.Meta) error {
+		stms = append(stms, stm)
+		lines = append(lines, meta.LineStart)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return stms, lines
+}
+
+const testJsonld = `{
+  "@context": {
+    "@vocab": "http://spdx.org/rdf/terms#",
+    "rdfs": "http://www.w3.org/2000/01/rdf-schema#",
+    "comment": "rdfs:comment",
+    "dataLicense": {"@type": "@id"},
+    "algorithm": {"@type": "@vocab"},
+    "items": {"@id": "http://example.org/items", "@container": "@list"},
+    "ignored": null
+  },
+  "@graph": [
+    {
+      "@id": "http://example.org/doc",
+      "@type": "SpdxDocument",
+      "dataLicense": "http://spdx.org/licenses/CC0-1.0",
+      "comment": {"@value": "bonjour", "@language": "fr"},
+      "creationInfo": {
+        "@type": "CreationInfo",
+        "creator": ["Tool: spdx-go", "Person: x"]
+      },
+      "algorithm": "checksumAlgorithm_sha1",
+      "items": ["a", 1, true],
+      "ignored": "x",
+      "rdfs:label": {"@id": "_:b1"}
+    },
+    {
+      "@id": "_:b1",
+      "http://example.org/p": {"@value": "2", "@type": "http://www.w3.org/2001/XMLSchema#int"}
+    }
+  ]
+}`
+
+func TestParseJsonld(t *testing.T) {
+	stms, lines := parseJsonldString(t, testJsonld)
+
+	doc := uri("http://example.org/doc")
+	tests := []struct {
+		subj, pred, obj Term
+		line            int
+	}{
+		{doc, uri(rdfNs + "type"), uri(baseUri + "SpdxDocument"), 14},
+		{doc, uri(baseUri + "dataLicense"), uri("http://spdx.org/licenses/CC0-1.0"), 15},
+		{doc, uri("http://www.w3.org/2000/01/rdf-schema#comment"), &Literal{Value: "bonjour", Lang: "fr"}, 16},
+		{doc, uri(baseUri + "algorithm"), uri(baseUri + "checksumAlgorithm_sha1"), 21},
+		{doc, uri("http://www.w3.org/2000/01/rdf-schema#label"), blank("b1"), 24},
+		{blank("b1"), uri("http://example.org/p"), &Literal{Value: "2", Datatype: xsdNs + "int"}, 28},
+	}
+	for _, test := range tests {
+		i := findStatement(stms, test.subj, test.pred, test.obj)
+		if i < 0 {
+			t.Errorf("Statement not found: %s %s %s", termStr(test.subj), termStr(test.pred), termStr(test.obj))
+			continue
+		}
+		if lines[i] != test.line {
+			t.Errorf("Wrong line for %s: %d (expected %d)", termStr(test.pred), lines[i], test.line)
+		}
+	}
+
+	var info, list Term
+	for _, stm := range stms {
+		switch termStr(stm.Predicate) {
+		case baseUri + "creationInfo":
+			info = stm.Object
+		case "http://example.org/items":
+			list = stm.Object
+		case baseUri + "ignored":
+			t.Error("Terms defined as null must be ignored.")
+		}
+	}
+	if info == nil ||
+		findStatement(stms, info, uri(rdfNs+"type"), uri(baseUri+"CreationInfo")) < 0 ||
+		findStatement(stms, info, uri(baseUri+"creator"), literal("Person: x")) < 0 {
+		t.Error("Embedded node object not parsed.")
+	}
+	if list == nil ||
+		findStatement(stms, list, uri(rdfNs+"first"), literal("a")) < 0 {
+		t.Error("List not parsed.")
+	}
+	for _, lit := range []*Literal{{Value: "1", Datatype: xsdInteger}, {Value: "true", Datatype: xsdBoolean}} {
+		found := false
+		for _, stm := range stms {
+			found = found || (termStr(stm.Predicate) == rdfNs+"first" && stm.Object.Equals(lit))
+		}
+		if !found {
+			t.Errorf("List item %s not found.", lit.Value)
+		}
+	}
+}
+
+func TestParseJsonldErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"{\n\"@context\": \"http://example.org/context.jsonld\"}", 2},
+		{"{\n\"@id\": 1}", 2},
+		{"{\"@context\": {\"a\": \"b:x\", \"b\": \"a:y\"}}", 1},
+		{"{\n\n\"@reverse\": {}}", 3},
+		{"{\n\"a\": ", 2},
+	}
+	for _, test := range tests {
+		err := parseJsonld(strings.NewReader(test.input), nil, func(*Statement, *spdx.Meta) error { return nil })
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("Expected *spdx.ParseError for %q, got %v", test.input, err)
+			continue
+		}
+		if perr.LineStart != test.line {
+			t.Errorf("Wrong line for %q: %d (expected %d)", test.input, perr.LineStart, test.line)
+		}
+	}
+}
+
+func TestParseFormatGuessJsonld(t *testing.T) {
+	count := 0
+	input := "\n [{\"@id\": \"http://example.org/a\", \"http://example.org/p\": \"v\"}]"
+	err := parseFormat(strings.NewReader(input), "rdf", nil, func(*Statement, *spdx.Meta) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 1 {
+		t.Errorf("JSON-LD not guessed: %d statements, error %v", count, err)
+	}
+}
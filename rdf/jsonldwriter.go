@@ -0,0 +1,209 @@
+package rdf
+
+import (
+	"github.com/spdx/tools-go/json"
+	"io"
+	"strings"
+)
+
+// Writes statements as JSON-LD with the bundled SPDX context (see
+// jsonldContext()). The statements are kept in memory and written when the
+// serializer is closed, as a "@graph" of node objects in the order the
+// subjects were first seen. Blank nodes which are the object of only one
+// statement are embedded in the node object referring to them.
+type jsonldSerializer struct {
+	w   io.Writer
+	ctx *ldContext
+
+	subjects []Term
+	stms     map[string][]*Statement // by termKey() of the subject
+	refs     map[string]int          // number of statements with the node as object
+	written  map[string]bool
+	seen     map[string]bool // statements added, in N-Triples
+}
+
+// Create a new JSON-LD serializer.
+func newJsonldSerializer(w io.Writer) *jsonldSerializer {
+	base := &ldContext{base: baseUri, terms: make(map[string]*ldTerm)}
+	ctx, err := base.process(jsonldContext())
+	if err != nil {
+		panic(err) // the bundled context is valid
+	}
+	return &jsonldSerializer{
+		w:       w,
+		ctx:     ctx,
+		stms:    make(map[string][]*Statement),
+		refs:    make(map[string]int),
+		written: make(map[string]bool),
+		seen:    make(map[string]bool),
+	}
+}
+
+// The prefixes of the bundled context are always used.
+func (s *jsonldSerializer) SetNamespace(prefix, uri string) {}
+
+// Add a statement. Statements already added are ignored.
+func (s *jsonldSerializer) Add(stm *Statement) error {
+	line, err := ntriple(stm)
+	if err != nil {
+		return err
+	}
+	if s.seen[line] {
+		return nil
+	}
+	s.seen[line] = true
+
+	key := termKey(stm.Subject)
+	if _, ok := s.stms[key]; !ok {
+		s.subjects = append(s.subjects, stm.Subject)
+	}
+	s.stms[key] = append(s.stms[key], stm)
+	if _, ok := stm.Object.(*Blank); ok {
+		s.refs[termKey(stm.Object)]++
+	}
+	return nil
+}
+
+// Write the JSON-LD document.
+func (s *jsonldSerializer) Close() error {
+	graph := json.Array(nil)
+	for _, subj := range s.subjects {
+		if !s.nested(subj) {
+			graph.Items = append(graph.Items, s.node(subj, true))
+		}
+	}
+	// blank nodes only referred to from each other
+	for _, subj := range s.subjects {
+		if !s.written[termKey(subj)] {
+			graph.Items = append(graph.Items, s.node(subj, true))
+		}
+	}
+
+	root := json.Object(nil)
+	root.Add("@context", jsonldContext())
+	root.Add("@graph", graph)
+	return json.Encode(s.w, root)
+}
+
+// Checks if the node is embedded in the node object referring to it.
+func (s *jsonldSerializer) nested(t Term) bool {
+	key := termKey(t)
+	_, isBlank := t.(*Blank)
+	return isBlank && s.refs[key] == 1 && len(s.stms[key]) > 0
+}
+
+// Returns the node object of `subj`. Embedded blank nodes (not `top`) have
+// no "@id".
+func (s *jsonldSerializer) node(subj Term, top bool) *json.Node {
+	s.written[termKey(subj)] = true
+	res := json.Object(nil)
+	switch t := subj.(type) {
+	case *Uri:
+		res.Add("@id", json.String(s.compact(string(*t), false), nil))
+	case *Blank:
+		if top {
+			res.Add("@id", json.String("_:"+string(*t), nil))
+		}
+	}
+
+	keys := make([]string, 0)
+	values := make(map[string][]*json.Node)
+	for _, stm := range s.stms[termKey(subj)] {
+		key := "@type"
+		var val *json.Node
+		if t, ok := stm.Object.(*Uri); ok && stm.Predicate.Equals(uri(rdfNs+"type")) {
+			val = json.String(s.compact(string(*t), true), nil)
+		} else {
+			key = s.compact(termStr(stm.Predicate), true)
+			val = s.value(key, stm.Object)
+		}
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = append(values[key], val)
+	}
+
+	for _, key := range keys {
+		if len(values[key]) == 1 {
+			res.Add(key, values[key][0])
+			continue
+		}
+		arr := json.Array(nil)
+		arr.Items = values[key]
+		res.Add(key, arr)
+	}
+	return res
+}
+
+// Returns the JSON-LD value of `obj` for the property `key`.
+func (s *jsonldSerializer) value(key string, obj Term) *json.Node {
+	def := s.ctx.terms[key]
+	if def == nil {
+		def = &ldTerm{}
+	}
+
+	switch t := obj.(type) {
+	case *Uri:
+		switch def.typ {
+		case "@id":
+			return json.String(s.compact(string(*t), false), nil)
+		case "@vocab":
+			return json.String(s.compact(string(*t), true), nil)
+		}
+		return s.object("@id", json.String(s.compact(string(*t), false), nil))
+	case *Blank:
+		if s.nested(t) && !s.written[termKey(t)] {
+			return s.node(t, false)
+		}
+		if def.typ == "@id" {
+			return json.String("_:"+string(*t), nil)
+		}
+		return s.object("@id", json.String("_:"+string(*t), nil))
+	case *Literal:
+		val := json.String(t.Value, nil)
+		switch {
+		case t.Lang != "":
+			res := s.object("@value", val)
+			res.Add("@language", json.String(t.Lang, nil))
+			return res
+		case t.Datatype != "" && t.Datatype != def.typ:
+			res := s.object("@value", val)
+			res.Add("@type", json.String(s.compact(t.Datatype, true), nil))
+			return res
+		case def.typ == "@id" || def.typ == "@vocab":
+			return s.object("@value", val)
+		}
+		return val
+	}
+	return json.Object(nil)
+}
+
+// Returns an object with one property.
+func (s *jsonldSerializer) object(key string, val *json.Node) *json.Node {
+	res := json.Object(nil)
+	res.Add(key, val)
+	return res
+}
+
+// Returns the shortest form of the IRI using the context: a term of the
+// vocabulary (if `vocab` is set), a compact IRI or the IRI itself.
+func (s *jsonldSerializer) compact(iri string, vocab bool) string {
+	if vocab && strings.HasPrefix(iri, s.ctx.vocab) {
+		term := iri[len(s.ctx.vocab):]
+		if term != "" && !strings.ContainsAny(term, ":@") && s.ctx.expand(term, true, false) == iri {
+			return term
+		}
+	}
+	best := iri
+	for name, def := range s.ctx.terms {
+		if def == nil || def.typ != "" || !strings.HasPrefix(iri, def.iri) || def.iri == "" {
+			continue
+		}
+		suffix := iri[len(def.iri):]
+		compact := name + ":" + suffix
+		if suffix != "" && !strings.HasPrefix(suffix, "//") && len(compact) < len(best) && s.ctx.expand(compact, vocab, false) == iri {
+			best = compact
+		}
+	}
+	return best
+}
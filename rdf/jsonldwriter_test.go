@@ -0,0 +1,81 @@
+package rdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestJsonldSerializer(t *testing.T) {
+	var buf bytes.Buffer
+	s := newJsonldSerializer(&buf)
+	stms := testWriterStatements()
+	stms = append(stms,
+		&Statement{blank("shared"), uri(baseUri + "algorithm"), uri(baseUri + "checksumAlgorithm_sha1")},
+		&Statement{blank("shared"), uri(baseUri + "licenseConcluded"), uri("http://spdx.org/licenses/MIT")},
+		&Statement{blank("shared"), uri(baseUri + "member"), blank("shared")},
+	)
+	for _, stm := range stms {
+		if err := s.Add(stm); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	out := buf.String()
+
+	parsed, _ := parseJsonldString(t, out)
+	if len(parsed) != len(stms)-1 {
+		t.Errorf("Wrong number of statements: %d\n%s", len(parsed), out)
+	}
+	for _, stm := range stms {
+		if stm.Subject.Equals(blank("info")) || stm.Object.Equals(blank("info")) {
+			// embedded, without node ID
+			continue
+		}
+		if findStatement(parsed, stm.Subject, stm.Predicate, stm.Object) < 0 {
+			t.Errorf("Statement not found: %s %s %s\n%s", termStr(stm.Subject), termStr(stm.Predicate), termStr(stm.Object), out)
+		}
+	}
+
+	expected := []string{
+		`"@type": "SpdxDocument"`,
+		`"creationInfo": {`,
+		`"algorithm": "checksumAlgorithm_sha1"`,
+		`"licenseConcluded": "http://spdx.org/licenses/MIT"`,
+		`"member": "_:shared"`,
+		`"a": {` + "\n" + `        "@id": "_:shared"`,
+		`"@language": "en"`,
+		`"@type": "xsd:integer"`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(out, e) {
+			t.Errorf("Expected %q in the output:\n%s", e, out)
+		}
+	}
+	if strings.Contains(out, "_:info") {
+		t.Errorf("Nodes referred to once must be embedded:\n%s", out)
+	}
+}
+
+func TestJsonldCompact(t *testing.T) {
+	s := newJsonldSerializer(nil)
+	tests := []struct {
+		iri      string
+		vocab    bool
+		expected string
+	}{
+		{baseUri + "Package", true, "Package"},
+		{baseUri + "Package", false, baseUri + "Package"},
+		{rdfNs + "type", true, "rdf:type"},
+		{"http://www.w3.org/2000/01/rdf-schema#comment", false, "rdfs:comment"},
+		{baseUri + "rdf", true, baseUri + "rdf"},
+		{"http://example.org/a", true, "http://example.org/a"},
+	}
+	for _, test := range tests {
+		if res := s.compact(test.iri, test.vocab); res != test.expected {
+			t.Errorf("Wrong compaction of %s: %s (expected %s)", test.iri, res, test.expected)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+//go:build !raptor
+// +build !raptor
+
+package rdf
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+)
+
+// The native RDF backend is written in Go and supports RDF/XML, Turtle and
+// N-Triples. Build with the
+// "raptor" tag to use libraptor2 (through goraptor) and all the raptor formats
+// instead.
+
+// The formats the native backend reads and writes, and JSON-LD. RDF/XML with
+// the XMP profile is only read.
+var Formats = []string{
+	Fmt_ntriples,
+	Fmt_turtle,
+	Fmt_rdfxmlAbbrev,
+	Fmt_rdfxml,
+	Fmt_jsonld,
+}
+
+// Parse the statements in `input`, written in the RDF syntax `format`, and
+// call `handle` for each of them. `ns` is called for the namespace prefixes
+// declared in the input; it may be nil.
+//
+// The format "rdf" means guessing the syntax of the input: RDF/XML or Turtle
+// (which includes N-Triples).
+func parseStatements(input io.Reader, format string, ns func(prefix, uri string), handle statementHandler) error {
+	if format == "rdf" {
+		buf := bufio.NewReader(input)
+		input = buf
+		format = Fmt_turtle
+		if isXml(buf) {
+			format = Fmt_rdfxml
+		}
+	}
+
+	switch format {
+	case Fmt_rdfxml, Fmt_rdfxmlAbbrev, Fmt_rdfxmlXmp:
+		return parseXml(input, baseUri, format == Fmt_rdfxmlXmp, ns, handle)
+	case Fmt_turtle, Fmt_ntriples:
+		return parseTurtle(input, baseUri, ns, handle)
+	}
+	return formatNotSupported(format)
+}
+
+// Checks if the input starts like a XML document: with a XML declaration,
+// comment or element. Turtle IRIs (<...>) are not elements.
+func isXml(r *bufio.Reader) bool {
+	start, _ := r.Peek(512)
+	start = bytes.TrimPrefix(start, []byte("\xef\xbb\xbf"))
+	start = bytes.TrimLeft(start, " \t\r\n")
+	if bytes.HasPrefix(start, []byte("<?")) || bytes.HasPrefix(start, []byte("<!")) {
+		return true
+	}
+	if len(start) < 2 || start[0] != '<' {
+		return false
+	}
+	end := bytes.IndexAny(start, " \t\r\n>")
+	if end < 0 {
+		return false
+	}
+	name := start[1:end]
+	if end > 1 && start[end] == '>' && name[len(name)-1] == '/' {
+		name = name[:len(name)-1]
+	}
+	return len(name) > 0 && !bytes.ContainsAny(name, "/#")
+}
+
+// Create a serializer that writes the RDF syntax `format` to `output`.
+func newSerializer(output io.Writer, format string) (serializer, error) {
+	switch format {
+	case Fmt_rdfxml:
+		return newXmlSerializer(output, false), nil
+	case Fmt_rdfxmlAbbrev:
+		return newXmlSerializer(output, true), nil
+	case Fmt_turtle:
+		return newTurtleSerializer(output), nil
+	case Fmt_ntriples:
+		return newNtriplesSerializer(output), nil
+	}
+	return nil, formatNotSupported(format)
+}
@@ -0,0 +1,41 @@
+//go:build !raptor
+// +build !raptor
+
+package rdf
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestIsXml(t *testing.T) {
+	tests := map[string]bool{
+		`<?xml version="1.0"?>`:              true,
+		"\n  <rdf:RDF xmlns:rdf=\"...\">":    true,
+		"<!-- comment -->":                   true,
+		"<rdf:RDF/>":                         true,
+		"<http://example.org/a> <b> <c> .":   false,
+		"<#doc> a <http://example.org/T> .":  false,
+		"@prefix : <http://example.org/> .":  false,
+		"_:a <http://example.org/p> \"v\" .": false,
+	}
+	for input, expected := range tests {
+		if isXml(bufio.NewReader(strings.NewReader(input))) != expected {
+			t.Errorf("Wrong guess for %q", input)
+		}
+	}
+}
+
+func TestFormatOkNative(t *testing.T) {
+	for _, format := range []string{Fmt_rss, Fmt_atom, Fmt_dot, Fmt_html, Fmt_nquads, Fmt_json} {
+		if FormatOk(format) {
+			t.Errorf("Accepted %s, which the native backend cannot write.", format)
+		}
+	}
+	for _, format := range []string{Fmt_rdfxml, Fmt_turtle, Fmt_jsonld} {
+		if !FormatOk(format) {
+			t.Errorf("Not accepted %s.", format)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package rdf
 
 import (
 	"fmt"
-	"github.com/deltamobile/goraptor"
 	"github.com/spdx/tools-go/spdx"
 	"io"
 	"strings"
@@ -51,7 +50,7 @@ func Parse(input io.Reader, format string) (*spdx.Document, error) {
 // Update a ValString pointer
 func upd(ptr *spdx.ValueStr) updater {
 	set := false
-	return func(term goraptor.Term, meta *spdx.Meta) error {
+	return func(term Term, meta *spdx.Meta) error {
 		if set {
 			return spdx.NewParseError(msgAlreadyDefined, meta)
 		}
@@ -66,7 +65,7 @@ func upd(ptr *spdx.ValueStr) updater {
 // Updates a ValString pointer, but cuts the prefix from the value
 func updCutPrefix(prefix string, ptr *spdx.ValueStr) updater {
 	set := false
-	return func(term goraptor.Term, meta *spdx.Meta) error {
+	return func(term Term, meta *spdx.Meta) error {
 		if set {
 			return spdx.NewParseError(msgAlreadyDefined, meta)
 		}
@@ -80,7 +79,7 @@ func updCutPrefix(prefix string, ptr *spdx.ValueStr) updater {
 
 // Update a []ValString pointer
 func updList(arr *[]spdx.ValueStr) updater {
-	return func(term goraptor.Term, meta *spdx.Meta) error {
+	return func(term Term, meta *spdx.Meta) error {
 		*arr = append(*arr, spdx.Str(termStr(term), meta))
 		return nil
 	}
@@ -89,7 +88,7 @@ func updList(arr *[]spdx.ValueStr) updater {
 // Update a ValueCreator pointer
 func updCreator(ptr *spdx.ValueCreator) updater {
 	set := false
-	return func(term goraptor.Term, meta *spdx.Meta) error {
+	return func(term Term, meta *spdx.Meta) error {
 		if set {
 			return spdx.NewParseError(msgAlreadyDefined, meta)
 		}
@@ -103,7 +102,7 @@ func updCreator(ptr *spdx.ValueCreator) updater {
 // Update a ValueDate pointer
 func updDate(ptr *spdx.ValueDate) updater {
 	set := false
-	return func(term goraptor.Term, meta *spdx.Meta) error {
+	return func(term Term, meta *spdx.Meta) error {
 		if set {
 			return spdx.NewParseError(msgAlreadyDefined, meta)
 		}
@@ -116,88 +115,102 @@ func updDate(ptr *spdx.ValueDate) updater {
 
 // Update a []ValueCreator pointer
 func updListCreator(arr *[]spdx.ValueCreator) updater {
-	return func(term goraptor.Term, meta *spdx.Meta) error {
+	return func(term Term, meta *spdx.Meta) error {
 		*arr = append(*arr, spdx.NewValueCreator(termStr(term), meta))
 		return nil
 	}
 }
 
 type builder struct {
-	t        goraptor.Term // type of element this builder represents
-	ptr      interface{}   // the spdx element that this builder builds
+	t        Term        // type of element this builder represents
+	ptr      interface{} // the spdx element that this builder builds
 	updaters map[string]updater
+	ext      *[]spdx.Extension // where unknown properties are kept, in lenient mode
 }
 
-func (b *builder) apply(pred, obj goraptor.Term, meta *spdx.Meta) error {
+func (b *builder) apply(pred, obj Term, meta *spdx.Meta) error {
 	property := shortPrefix(pred)
 	f, ok := b.updaters[property]
 	if !ok {
+		if b.ext != nil {
+			*b.ext = append(*b.ext, spdx.Extension{Key: termStr(pred), Value: termStr(obj), Meta: meta})
+			return nil
+		}
 		return spdx.NewParseError(fmt.Sprintf(msgPropertyNotSupported, property, b.t), meta)
 	}
 	return f(obj, meta)
 }
 
+// Returns the extensions of the SPDX element `ptr`, or nil if the element
+// cannot have extensions.
+func extensions(ptr interface{}) *[]spdx.Extension {
+	switch elem := ptr.(type) {
+	case *spdx.Document:
+		return &elem.Extensions
+	case *spdx.CreationInfo:
+		return &elem.Extensions
+	case *spdx.Package:
+		return &elem.Extensions
+	case *spdx.File:
+		return &elem.Extensions
+	case *spdx.Review:
+		return &elem.Extensions
+	case *spdx.ExtractedLicence:
+		return &elem.Extensions
+	}
+	return nil
+}
+
 func (b *builder) has(pred string) bool {
 	_, ok := b.updaters[pred]
 	return ok
 }
 
-type updater func(goraptor.Term, *spdx.Meta) error
+type updater func(Term, *spdx.Meta) error
 
 type bufferEntry struct {
-	*goraptor.Statement
+	*Statement
 	*spdx.Meta
 }
 
 // RDF Parser. Use a RDF Parser to parse SPDX RDF files to SPDX documents.
-// It uses the goraptor library to parse the RDF syntaxes.
+// The RDF syntaxes are parsed by parseFormat().
 //
 // Always use the `NewParser()` method to create a new parser.
+//
+// In lenient mode, the statements with predicates that are not SPDX
+// properties are kept as spdx.Extension values (keyed by the predicate URI) of
+// the document, creation info, packages, files, reviews and extracted
+// licences, and the nodes of unknown types are ignored.
 type Parser struct {
-	rdfparser *goraptor.Parser
-	input     io.Reader
-	index     map[string]*builder
-	buffer    map[string][]bufferEntry
-	doc       *spdx.Document
+	Lenient bool // Keep unknown properties instead of failing
+
+	format string
+	input  io.Reader
+	index  map[string]*builder
+	buffer map[string][]bufferEntry
+	doc    *spdx.Document
 }
 
-// This creates a goraptor.Parser object that needs to be freed after use.
-// Call Parser.Free() after using the Parser.
+// Create a new *Parser that reads the given RDF format from `input`. Call
+// Parser.Free() after using the Parser.
 func NewParser(input io.Reader, format string) *Parser {
-	if format == "rdf" {
-		format = "guess"
-	}
-
 	return &Parser{
-		rdfparser: goraptor.NewParser(format),
-		input:     input,
-		index:     make(map[string]*builder),
-		buffer:    make(map[string][]bufferEntry),
+		format: format,
+		input:  input,
+		index:  make(map[string]*builder),
+		buffer: make(map[string][]bufferEntry),
 	}
 }
 
 // Parse the whole input stream and return the resulting spdx.Document or the first error that occurred.
 func (p *Parser) Parse() (*spdx.Document, error) {
-	ch := p.rdfparser.Parse(p.input, baseUri)
-	locCh := p.rdfparser.LocatorChan()
-	var err error
-	for statement := range ch {
-		locator := <-locCh
-		meta := spdx.NewMetaL(locator.Line)
-		if err = p.processTruple(statement, meta); err != nil {
-			break
-		}
-	}
-	// Consume input channel in case of error. Otherwise goraptor will keep the goroutine busy.
-	for _ = range ch {
-		<-locCh
-	}
+	err := parseFormat(p.input, p.format, nil, p.processTruple)
 	return p.doc, err
 }
 
-// Free the goraptor parser.
+// Free the parser.
 func (p *Parser) Free() {
-	p.rdfparser.Free()
 	p.doc = nil
 }
 
@@ -205,7 +218,7 @@ func (p *Parser) Free() {
 // If the node does not exist, a builder of the required type is created and the buffered
 // statements will be applied in fifo order.
 // If the node exists and the types are not compatible, a ParseError is returned.
-func (p *Parser) setType(node, t goraptor.Term, meta *spdx.Meta) (interface{}, error) {
+func (p *Parser) setType(node, t Term, meta *spdx.Meta) (interface{}, error) {
 	nodeStr := termStr(node)
 	bldr, ok := p.index[nodeStr]
 	if ok {
@@ -241,7 +254,7 @@ func (p *Parser) setType(node, t goraptor.Term, meta *spdx.Meta) (interface{}, e
 		bldr = p.reviewMap(&spdx.Review{Meta: meta})
 	case t.Equals(typeArtifactOf):
 		artif := &spdx.ArtifactOf{Meta: meta}
-		if artifUri, ok := node.(*goraptor.Uri); ok {
+		if artifUri, ok := node.(*Uri); ok {
 			artif.ProjectUri.Val = termStr(artifUri)
 			artif.ProjectUri.Meta = meta
 		}
@@ -250,9 +263,9 @@ func (p *Parser) setType(node, t goraptor.Term, meta *spdx.Meta) (interface{}, e
 		bldr = p.extractedLicensingInfoMap(&spdx.ExtractedLicence{Meta: meta})
 	case t.Equals(typeAnyLicence):
 		switch t := node.(type) {
-		case *goraptor.Uri: // licence in spdx licence list
+		case *Uri: // licence in spdx licence list
 			bldr = p.licenceReferenceBuilder(node, meta)
-		case *goraptor.Blank: // licence reference or abstract set
+		case *Blank: // licence reference or abstract set
 			if strings.HasPrefix(strings.ToLower(termStr(t)), "licenseref") {
 				bldr = p.extractedLicensingInfoMap(&spdx.ExtractedLicence{Meta: meta})
 			} else {
@@ -273,10 +286,17 @@ func (p *Parser) setType(node, t goraptor.Term, meta *spdx.Meta) (interface{}, e
 		bldr = p.conjunctiveSetBuilder(meta)
 	case t.Equals(typeDisjunctiveSet):
 		bldr = p.disjuntiveSetBuilder(meta)
+	case p.Lenient:
+		// not part of the document; the properties are discarded
+		bldr = &builder{t: t, ext: new([]spdx.Extension)}
 	default:
 		return nil, spdx.NewParseError(fmt.Sprintf(msgUnknownType, t), meta)
 	}
 
+	if p.Lenient && bldr.ext == nil {
+		bldr.ext = extensions(bldr.ptr)
+	}
+
 	p.index[nodeStr] = bldr
 
 	// run buffer
@@ -292,7 +312,7 @@ func (p *Parser) setType(node, t goraptor.Term, meta *spdx.Meta) (interface{}, e
 }
 
 // Process a SPDX Truple.
-func (p *Parser) processTruple(stm *goraptor.Statement, meta *spdx.Meta) error {
+func (p *Parser) processTruple(stm *Statement, meta *spdx.Meta) error {
 	node := termStr(stm.Subject)
 	if stm.Predicate.Equals(uri_nstype) {
 		_, err := p.setType(stm.Subject, stm.Object, meta)
@@ -315,9 +335,9 @@ func (p *Parser) processTruple(stm *goraptor.Statement, meta *spdx.Meta) error {
 }
 
 // Checks if found is any of the need types. Note: a type term of type
-// goraptor.Uri is not the same type as one of type goraptor.Blank; same
+// Uri is not the same type as one of type Blank; same
 // applies for other combinations.
-func equalTypes(found goraptor.Term, need ...goraptor.Term) bool {
+func equalTypes(found Term, need ...Term) bool {
 	for _, b := range need {
 		if found == b || found.Equals(b) {
 			return true
@@ -331,7 +351,7 @@ func equalTypes(found goraptor.Term, need ...goraptor.Term) bool {
 // If need is any of typeLicence, typeDisjunctiveSet, typeConjunctiveSet
 // and typeExtractedLicence and found is AnyLicence, it  is permitted and
 // the function returns true.
-func compatibleTypes(found, need goraptor.Term) bool {
+func compatibleTypes(found, need Term) bool {
 	if equalTypes(found, need) {
 		return true
 	}
@@ -351,7 +371,7 @@ func compatibleTypes(found, need goraptor.Term) bool {
 //
 // Parser.req* functions are supposed to get the node from either the index check,
 // if it's the required type and return a pointer to the relevant spdx.* object.
-func (p *Parser) reqType(node, t goraptor.Term) (interface{}, error) {
+func (p *Parser) reqType(node, t Term) (interface{}, error) {
 	bldr, ok := p.index[termStr(node)]
 	if ok {
 		if !compatibleTypes(bldr.t, t) {
@@ -362,63 +382,63 @@ func (p *Parser) reqType(node, t goraptor.Term) (interface{}, error) {
 	return p.setType(node, t, nil)
 }
 
-func (p *Parser) reqDocument(node goraptor.Term) (*spdx.Document, error) {
+func (p *Parser) reqDocument(node Term) (*spdx.Document, error) {
 	obj, err := p.reqType(node, typeDocument)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.Document), err
 }
-func (p *Parser) reqCreationInfo(node goraptor.Term) (*spdx.CreationInfo, error) {
+func (p *Parser) reqCreationInfo(node Term) (*spdx.CreationInfo, error) {
 	obj, err := p.reqType(node, typeCreationInfo)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.CreationInfo), err
 }
-func (p *Parser) reqPackage(node goraptor.Term) (*spdx.Package, error) {
+func (p *Parser) reqPackage(node Term) (*spdx.Package, error) {
 	obj, err := p.reqType(node, typePackage)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.Package), err
 }
-func (p *Parser) reqFile(node goraptor.Term) (*spdx.File, error) {
+func (p *Parser) reqFile(node Term) (*spdx.File, error) {
 	obj, err := p.reqType(node, typeFile)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.File), err
 }
-func (p *Parser) reqVerificationCode(node goraptor.Term) (*spdx.VerificationCode, error) {
+func (p *Parser) reqVerificationCode(node Term) (*spdx.VerificationCode, error) {
 	obj, err := p.reqType(node, typeVerificationCode)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.VerificationCode), err
 }
-func (p *Parser) reqChecksum(node goraptor.Term) (*spdx.Checksum, error) {
+func (p *Parser) reqChecksum(node Term) (*spdx.Checksum, error) {
 	obj, err := p.reqType(node, typeChecksum)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.Checksum), err
 }
-func (p *Parser) reqReview(node goraptor.Term) (*spdx.Review, error) {
+func (p *Parser) reqReview(node Term) (*spdx.Review, error) {
 	obj, err := p.reqType(node, typeReview)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.Review), err
 }
-func (p *Parser) reqExtractedLicence(node goraptor.Term) (*spdx.ExtractedLicence, error) {
+func (p *Parser) reqExtractedLicence(node Term) (*spdx.ExtractedLicence, error) {
 	obj, err := p.reqType(node, typeExtractedLicence)
 	if err != nil {
 		return nil, err
 	}
 	return obj.(*spdx.ExtractedLicence), err
 }
-func (p *Parser) reqAnyLicence(node goraptor.Term) (spdx.AnyLicence, error) {
+func (p *Parser) reqAnyLicence(node Term) (spdx.AnyLicence, error) {
 	obj, err := p.reqType(node, typeAnyLicence)
 	if err != nil {
 		return nil, err
@@ -440,7 +460,7 @@ func (p *Parser) reqAnyLicence(node goraptor.Term) (spdx.AnyLicence, error) {
 		return nil, fmt.Errorf("Unexpected error, an element of type AnyLicence cannot be casted to any licence type. %s || %#v", node, obj)
 	}
 }
-func (p *Parser) reqArtifactOf(node goraptor.Term) (*spdx.ArtifactOf, error) {
+func (p *Parser) reqArtifactOf(node Term) (*spdx.ArtifactOf, error) {
 	obj, err := p.reqType(node, typeArtifactOf)
 	if err != nil {
 		return nil, err
@@ -455,12 +475,12 @@ func (p *Parser) documentMap(doc *spdx.Document) *builder {
 		"specVersion":  upd(&doc.SpecVersion),
 		"dataLicense":  updCutPrefix(licenceUri, &doc.DataLicence),
 		"rdfs:comment": upd(&doc.Comment),
-		"creationInfo": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"creationInfo": func(obj Term, meta *spdx.Meta) error {
 			cri, err := p.reqCreationInfo(obj)
 			doc.CreationInfo = cri
 			return err
 		},
-		"describesPackage": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"describesPackage": func(obj Term, meta *spdx.Meta) error {
 			pkg, err := p.reqPackage(obj)
 			if err != nil {
 				return err
@@ -468,7 +488,7 @@ func (p *Parser) documentMap(doc *spdx.Document) *builder {
 			doc.Packages = append(doc.Packages, pkg)
 			return nil
 		},
-		"referencesFile": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"referencesFile": func(obj Term, meta *spdx.Meta) error {
 			file, err := p.reqFile(obj)
 			if err != nil {
 				return err
@@ -476,7 +496,7 @@ func (p *Parser) documentMap(doc *spdx.Document) *builder {
 			doc.Files = append(doc.Files, file)
 			return nil
 		},
-		"reviewed": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"reviewed": func(obj Term, meta *spdx.Meta) error {
 			rev, err := p.reqReview(obj)
 			if err != nil {
 				return err
@@ -484,7 +504,7 @@ func (p *Parser) documentMap(doc *spdx.Document) *builder {
 			doc.Reviews = append(doc.Reviews, rev)
 			return nil
 		},
-		"hasExtractedLicensingInfo": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"hasExtractedLicensingInfo": func(obj Term, meta *spdx.Meta) error {
 			lic, err := p.reqExtractedLicence(obj)
 			if err != nil {
 				return err
@@ -530,24 +550,24 @@ func (p *Parser) packageMap(pkg *spdx.Package) *builder {
 		"supplier":         updCreator(&pkg.Supplier),
 		"originator":       updCreator(&pkg.Originator),
 		"downloadLocation": upd(&pkg.DownloadLocation),
-		"packageVerificationCode": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"packageVerificationCode": func(obj Term, meta *spdx.Meta) error {
 			vc, err := p.reqVerificationCode(obj)
 			pkg.VerificationCode = vc
 			return err
 		},
-		"checksum": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"checksum": func(obj Term, meta *spdx.Meta) error {
 			cksum, err := p.reqChecksum(obj)
 			pkg.Checksum = cksum
 			return err
 		},
 		"doap:homepage": upd(&pkg.HomePage),
 		"sourceInfo":    upd(&pkg.SourceInfo),
-		"licenseConcluded": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"licenseConcluded": func(obj Term, meta *spdx.Meta) error {
 			lic, err := p.reqAnyLicence(obj)
 			pkg.LicenceConcluded = lic
 			return err
 		},
-		"licenseInfoFromFiles": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"licenseInfoFromFiles": func(obj Term, meta *spdx.Meta) error {
 			lic, err := p.reqAnyLicence(obj)
 			if err != nil {
 				return err
@@ -555,7 +575,7 @@ func (p *Parser) packageMap(pkg *spdx.Package) *builder {
 			pkg.LicenceInfoFromFiles = append(pkg.LicenceInfoFromFiles, lic)
 			return nil
 		},
-		"licenseDeclared": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"licenseDeclared": func(obj Term, meta *spdx.Meta) error {
 			lic, err := p.reqAnyLicence(obj)
 			pkg.LicenceDeclared = lic
 			return err
@@ -564,7 +584,7 @@ func (p *Parser) packageMap(pkg *spdx.Package) *builder {
 		"copyrightText":   upd(&pkg.CopyrightText),
 		"summary":         upd(&pkg.Summary),
 		"description":     upd(&pkg.Description),
-		"hasFile": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"hasFile": func(obj Term, meta *spdx.Meta) error {
 			file, err := p.reqFile(obj)
 			if err != nil {
 				return err
@@ -581,7 +601,7 @@ func (p *Parser) checksumMap(cksum *spdx.Checksum) *builder {
 	bldr := &builder{t: typeChecksum, ptr: cksum}
 	algoSet := false
 	bldr.updaters = map[string]updater{
-		"algorithm": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"algorithm": func(obj Term, meta *spdx.Meta) error {
 			if algoSet {
 				return spdx.NewParseError(msgAlreadyDefined, meta)
 			}
@@ -614,19 +634,19 @@ func (p *Parser) fileMap(file *spdx.File) *builder {
 		"fileName":     upd(&file.Name),
 		"rdfs:comment": upd(&file.Comment),
 		"fileType":     updCutPrefix("http://spdx.org/rdf/terms#", &file.Type),
-		"checksum": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"checksum": func(obj Term, meta *spdx.Meta) error {
 			cksum, err := p.reqChecksum(obj)
 			file.Checksum = cksum
 			return err
 		},
 		"copyrightText": upd(&file.CopyrightText),
 		"noticeText":    upd(&file.Notice),
-		"licenseConcluded": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"licenseConcluded": func(obj Term, meta *spdx.Meta) error {
 			lic, err := p.reqAnyLicence(obj)
 			file.LicenceConcluded = lic
 			return err
 		},
-		"licenseInfoInFile": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"licenseInfoInFile": func(obj Term, meta *spdx.Meta) error {
 			lic, err := p.reqAnyLicence(obj)
 			if err != nil {
 				return err
@@ -636,7 +656,7 @@ func (p *Parser) fileMap(file *spdx.File) *builder {
 		},
 		"licenseComments": upd(&file.LicenceComments),
 		"fileContributor": updList(&file.Contributor),
-		"fileDependency": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"fileDependency": func(obj Term, meta *spdx.Meta) error {
 			f, err := p.reqFile(obj)
 			if err != nil {
 				return err
@@ -644,7 +664,7 @@ func (p *Parser) fileMap(file *spdx.File) *builder {
 			file.Dependency = append(file.Dependency, f)
 			return nil
 		},
-		"artifactOf": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"artifactOf": func(obj Term, meta *spdx.Meta) error {
 			artif, err := p.reqArtifactOf(obj)
 			if err != nil {
 				return err
@@ -683,7 +703,7 @@ func (p *Parser) extractedLicensingInfoMap(lic *spdx.ExtractedLicence) *builder
 func (p *Parser) licenceSetMap(set abstractLicenceSet) *builder {
 	bldr := &builder{t: typeAbstractLicenceSet, ptr: set}
 	bldr.updaters = map[string]updater{
-		"member": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"member": func(obj Term, meta *spdx.Meta) error {
 			lic, err := p.reqAnyLicence(obj)
 			if err != nil {
 				return err
@@ -691,7 +711,7 @@ func (p *Parser) licenceSetMap(set abstractLicenceSet) *builder {
 			set.Add(lic)
 			return nil
 		},
-		"ns:type": func(obj goraptor.Term, meta *spdx.Meta) error {
+		"ns:type": func(obj Term, meta *spdx.Meta) error {
 			if !equalTypes(bldr.t, typeAbstractLicenceSet) {
 				return spdx.NewParseError(msgAlreadyDefined, meta)
 			}
@@ -736,14 +756,14 @@ func (p *Parser) disjuntiveSetBuilder(meta *spdx.Meta) *builder {
 }
 
 // Creates a new Licence object, using `node` as the value.
-func licenceReferenceTerm(node goraptor.Term, meta *spdx.Meta) *spdx.Licence {
+func licenceReferenceTerm(node Term, meta *spdx.Meta) *spdx.Licence {
 	str := strings.TrimPrefix(termStr(node), licenceUri)
 	lic := spdx.NewLicence(str, meta)
 	return &lic
 }
 
 // Creates a builder for a new Licence, using `node` as the value.
-func (p *Parser) licenceReferenceBuilder(node goraptor.Term, meta *spdx.Meta) *builder {
+func (p *Parser) licenceReferenceBuilder(node Term, meta *spdx.Meta) *builder {
 	lic := licenceReferenceTerm(node, meta)
 	return &builder{t: typeLicence, ptr: lic}
 }
@@ -4,8 +4,8 @@ import "testing"
 
 import (
 	"errors"
-	"github.com/vladvelici/goraptor"
 	"github.com/vladvelici/spdx-go/spdx"
+	"strings"
 )
 
 // Test goraptor term to string
@@ -132,12 +132,12 @@ func TestBuilder(t *testing.T) {
 	var meta *spdx.Meta
 	builder := &builder{t: blank("test"), ptr: &a}
 	builder.updaters = map[string]updater{
-		"change_value": func(val goraptor.Term, m *spdx.Meta) error {
+		"change_value": func(val Term, m *spdx.Meta) error {
 			a = termStr(val)
 			meta = m
 			return nil
 		},
-		"return_error": func(val goraptor.Term, m *spdx.Meta) error {
+		"return_error": func(val Term, m *spdx.Meta) error {
 			return errors.New(termStr(val))
 		},
 	}
@@ -190,7 +190,7 @@ func TestEqualTypes(t *testing.T) {
 }
 
 func TestCompatibleTyeps(t *testing.T) {
-	licTypes := []goraptor.Term{
+	licTypes := []Term{
 		typeLicence,
 		typeDisjunctiveSet,
 		typeConjunctiveSet,
@@ -224,7 +224,7 @@ func TestSetType(t *testing.T) {
 
 	// node does not exist, all types.
 	// typeAnyLicence will be tested regarding its variants.
-	types := map[string]goraptor.Term{
+	types := map[string]Term{
 		"Document":           typeDocument,
 		"CreationInfo":       typeCreationInfo,
 		"Package":            typePackage,
@@ -316,8 +316,8 @@ func TestSetTypeAnyLicence(t *testing.T) {
 		buffer: make(map[string][]bufferEntry),
 	}
 
-	terms := []goraptor.Term{blank("AnyLicenceToSet"), blank("LicenseRef-test"), uri("AnyLicenceInList")}
-	typeSlice := []goraptor.Term{typeAbstractLicenceSet, typeExtractedLicence, typeLicence}
+	terms := []Term{blank("AnyLicenceToSet"), blank("LicenseRef-test"), uri("AnyLicenceInList")}
+	typeSlice := []Term{typeAbstractLicenceSet, typeExtractedLicence, typeLicence}
 	for i, term := range terms {
 		meta := spdx.NewMeta(i, i+1)
 		bldr, err := parser.setType(term, typeAnyLicence, meta)
@@ -357,7 +357,7 @@ func TestSetTypeNsType(t *testing.T) {
 		t:   typeDocument, // this type is ignored in this use case
 	}
 	fakeBuilder.updaters = map[string]updater{
-		"ns:type": func(term goraptor.Term, meta *spdx.Meta) error {
+		"ns:type": func(term Term, meta *spdx.Meta) error {
 			if str := termStr(term); str != "error" {
 				*(fakeBuilder.ptr.(*string)) = termStr(term)
 				return nil
@@ -387,7 +387,7 @@ func TestSetTypeApplyBuffer(t *testing.T) {
 		buffer: make(map[string][]bufferEntry),
 	}
 	k := "document"
-	stm := &goraptor.Statement{
+	stm := &Statement{
 		Subject:   blank(k),
 		Predicate: prefix("specVersion"),
 		Object:    literal("SPDX-1.2"),
@@ -432,7 +432,7 @@ func TestDocumentMap(t *testing.T) {
 		"hasExtractedLicensingInfo": "extractedLicence_node",
 	}
 
-	fakes := map[string]goraptor.Term{
+	fakes := map[string]Term{
 		"ci_node":               typeCreationInfo,
 		"pkg_node":              typePackage,
 		"file_node":             typeFile,
@@ -549,7 +549,7 @@ func TestPackageMap(t *testing.T) {
 		"hasFile":                 "file_node",
 	}
 
-	fakes := map[string]goraptor.Term{
+	fakes := map[string]Term{
 		"verif_node":              typeVerificationCode,
 		"cksum_node":              typeChecksum,
 		"lic_concluded_node":      typeLicence,
@@ -635,7 +635,7 @@ func TestFileMap(t *testing.T) {
 		"artifactOf":        "artif_node",
 	}
 
-	fakes := map[string]goraptor.Term{
+	fakes := map[string]Term{
 		"cksum_node":            typeChecksum,
 		"lic_concluded_node":    typeDisjunctiveSet,
 		"lic_info_in_file_node": typeConjunctiveSet,
@@ -732,7 +732,7 @@ func TestLicenceSetMap(t *testing.T) {
 		{"ns:type", termStr(typeConjunctiveSet)},
 	}
 
-	fakes := map[string]goraptor.Term{
+	fakes := map[string]Term{
 		"lic1": typeLicence,
 		"lic2": typeLicence,
 	}
@@ -767,7 +767,7 @@ func TestProcessTruple(t *testing.T) {
 		buffer: make(map[string][]bufferEntry),
 	}
 
-	statements := []*goraptor.Statement{
+	statements := []*Statement{
 		{
 			Subject:   blank("document"),
 			Predicate: prefix("ns:type"),
@@ -851,7 +851,7 @@ func TestReqTypeParse(t *testing.T) {
 
 	nodeName := "wrong"
 
-	statements := []*goraptor.Statement{
+	statements := []*Statement{
 		{
 			Subject:   blank(nodeName),
 			Predicate: prefix("ns:type"),
@@ -876,3 +876,43 @@ func TestReqTypeParse(t *testing.T) {
 		t.Errorf("Found %T: %#v", lic, err)
 	}
 }
+
+func TestParserLenient(t *testing.T) {
+	input := `@prefix spdx: <http://spdx.org/rdf/terms#> .
+@prefix vendor: <http://example.org/vendor#> .
+
+<http://example.org/doc> a spdx:SpdxDocument ;
+	spdx:specVersion "SPDX-1.2" ;
+	vendor:tool "scanner 1.0" ;
+	spdx:describesPackage _:pkg .
+
+_:pkg a spdx:Package ;
+	spdx:name "p" ;
+	vendor:info _:info ;
+	vendor:risk "low" .
+
+_:info a vendor:Info ;
+	vendor:level "3" .
+`
+	if _, err := Parse(strings.NewReader(input), Fmt_turtle); err == nil {
+		t.Error("Expected an error when not in lenient mode.")
+	}
+
+	parser := NewParser(strings.NewReader(input), Fmt_turtle)
+	defer parser.Free()
+	parser.Lenient = true
+	doc, err := parser.Parse()
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(doc.Extensions) != 1 || doc.Extensions[0].Key != "http://example.org/vendor#tool" || doc.Extensions[0].Value != "scanner 1.0" {
+		t.Errorf("Wrong document extensions %v", doc.Extensions)
+	}
+	if len(doc.Packages) != 1 || doc.Packages[0].Name.Val != "p" {
+		t.Fatalf("Wrong packages %v", doc.Packages)
+	}
+	exts := doc.Packages[0].Extensions
+	if len(exts) != 2 || exts[0].Key != "http://example.org/vendor#info" || exts[1].Key != "http://example.org/vendor#risk" || exts[1].Value != "low" {
+		t.Errorf("Wrong package extensions %v", exts)
+	}
+}
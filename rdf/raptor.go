@@ -0,0 +1,135 @@
+//go:build raptor
+// +build raptor
+
+package rdf
+
+import (
+	"errors"
+	"github.com/deltamobile/goraptor"
+	"github.com/spdx/tools-go/spdx"
+	"io"
+	"os"
+)
+
+// The raptor RDF backend uses libraptor2 through goraptor and supports all the
+// formats in the Fmt_* constants. It is used when building with the "raptor"
+// tag.
+
+// The formats raptor reads and writes, and JSON-LD.
+var Formats = []string{
+	Fmt_ntriples,
+	Fmt_turtle,
+	Fmt_rdfxmlXmp,
+	Fmt_rdfxmlAbbrev,
+	Fmt_rdfxml,
+	Fmt_rss,
+	Fmt_atom,
+	Fmt_dot,
+	Fmt_jsonTriples,
+	Fmt_json,
+	Fmt_html,
+	Fmt_nquads,
+	Fmt_jsonld,
+}
+
+// Parse the statements in `input`, written in the RDF syntax `format`, and
+// call `handle` for each of them. `ns` is called for the namespace prefixes
+// declared in the input; it may be nil.
+//
+// The format "rdf" means using raptor's "guess" parser.
+func parseStatements(input io.Reader, format string, ns func(prefix, uri string), handle statementHandler) error {
+	if format == "rdf" {
+		format = "guess"
+	}
+	parser := goraptor.NewParser(format)
+	defer parser.Free()
+	if ns != nil {
+		parser.SetNamespaceHandler(ns)
+	}
+
+	ch := parser.Parse(input, baseUri)
+	locCh := parser.LocatorChan()
+	var err error
+	for statement := range ch {
+		locator := <-locCh
+		if err = handle(fromRaptor(statement), spdx.NewMetaL(locator.Line)); err != nil {
+			break
+		}
+	}
+	// Consume input channel in case of error. Otherwise goraptor will keep the goroutine busy.
+	for _ = range ch {
+		<-locCh
+	}
+	return err
+}
+
+// Serializer using goraptor.
+type raptorSerializer struct {
+	*goraptor.Serializer
+}
+
+// Create a serializer that writes the RDF syntax `format` to `output`, which
+// must be an *os.File.
+func newSerializer(output io.Writer, format string) (serializer, error) {
+	file, ok := output.(*os.File)
+	if !ok {
+		return nil, errors.New("The raptor RDF backend can only write to files.")
+	}
+	s := goraptor.NewSerializer(format)
+	if err := s.StartStream(file, baseUri); err != nil {
+		s.Free()
+		return nil, err
+	}
+	return raptorSerializer{s}, nil
+}
+
+func (s raptorSerializer) Add(stm *Statement) error {
+	return s.Serializer.Add(&goraptor.Statement{
+		Subject:   toRaptor(stm.Subject),
+		Predicate: toRaptor(stm.Predicate),
+		Object:    toRaptor(stm.Object),
+	})
+}
+
+func (s raptorSerializer) Close() error {
+	err := s.EndStream()
+	s.Free()
+	return err
+}
+
+// Convert a goraptor statement to a *Statement.
+func fromRaptor(stm *goraptor.Statement) *Statement {
+	return &Statement{
+		Subject:   fromRaptorTerm(stm.Subject),
+		Predicate: fromRaptorTerm(stm.Predicate),
+		Object:    fromRaptorTerm(stm.Object),
+	}
+}
+
+// Convert a goraptor.Term to a Term.
+func fromRaptorTerm(term goraptor.Term) Term {
+	switch t := term.(type) {
+	case *goraptor.Uri:
+		return uri(string(*t))
+	case *goraptor.Blank:
+		return blank(string(*t))
+	case *goraptor.Literal:
+		return &Literal{Value: t.Value, Datatype: t.Datatype, Lang: t.Lang}
+	}
+	return nil
+}
+
+// Convert a Term to a goraptor.Term.
+func toRaptor(term Term) goraptor.Term {
+	switch t := term.(type) {
+	case *Uri:
+		u := goraptor.Uri(*t)
+		return &u
+	case *Blank:
+		b := goraptor.Blank(*t)
+		return &b
+	case *Literal:
+		return &goraptor.Literal{Value: t.Value, Datatype: t.Datatype, Lang: t.Lang}
+	}
+	return nil
+}
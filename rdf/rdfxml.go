@@ -0,0 +1,442 @@
+package rdf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/spdx/tools-go/spdx"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Namespaces used by RDF/XML.
+const (
+	rdfNs = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlNs = "http://www.w3.org/XML/1998/namespace"
+)
+
+// RDF/XML parser error messages.
+const (
+	msgXmlUnexpectedText = "Unexpected text in %s."
+	msgXmlMixedContent   = "Property element %s has both text and elements."
+	msgXmlManyNodes      = "Property element %s has more than one node element."
+	msgXmlUnexpectedEnd  = "Unexpected end of RDF/XML input."
+)
+
+// Datatype of the literals of rdf:parseType="Literal" property elements.
+const xmlLiteral = rdfNs + "XMLLiteral"
+
+// The xml:base and xml:lang in scope of an element.
+type xmlScope struct {
+	base, lang string
+}
+
+// RDF/XML parser. It reads the whole input and parses it with encoding/xml,
+// keeping track of the line every element starts on.
+type xmlParser struct {
+	data   []byte
+	dec    *xml.Decoder
+	lines  []int // offsets of the newlines in data
+	handle statementHandler
+	ns     func(prefix, uri string)
+	xmp    bool // skip the elements around rdf:RDF
+	blanks int  // number of generated blank node IDs
+}
+
+// Parse the RDF/XML in `input` and call `handle` for each statement. Relative
+// URIs are resolved against `base`. If `xmp` is set, the elements around
+// the rdf:RDF element are skipped (as in the XMP profile). `ns` is called for
+// the namespace declarations found, if it is not nil.
+func parseXml(input io.Reader, base string, xmp bool, ns func(prefix, uri string), handle statementHandler) error {
+	data, err := ioutil.ReadAll(input)
+	if err != nil {
+		return err
+	}
+	p := &xmlParser{
+		data:   data,
+		dec:    xml.NewDecoder(bytes.NewReader(data)),
+		handle: handle,
+		ns:     ns,
+		xmp:    xmp,
+	}
+	for i, c := range data {
+		if c == '\n' {
+			p.lines = append(p.lines, i)
+		}
+	}
+	return p.document(xmlScope{base: base})
+}
+
+// Returns the line of the byte at offset.
+func (p *xmlParser) line(offset int64) int {
+	return sort.SearchInts(p.lines, int(offset)) + 1
+}
+
+// Read the next token and return it with the line it starts on. Returns
+// io.EOF at the end of the input.
+func (p *xmlParser) token() (xml.Token, int, error) {
+	line := p.line(p.dec.InputOffset())
+	tok, err := p.dec.Token()
+	if err != nil {
+		if e, ok := err.(*xml.SyntaxError); ok {
+			return nil, 0, spdx.NewParseError(e.Msg, spdx.NewMetaL(e.Line))
+		}
+		return nil, 0, err
+	}
+	if t, ok := tok.(xml.CharData); ok {
+		tok = t.Copy()
+	}
+	return tok, line, nil
+}
+
+// Read the next token, treating the end of the input as an error.
+func (p *xmlParser) next() (xml.Token, int, error) {
+	tok, line, err := p.token()
+	if err == io.EOF {
+		return nil, 0, spdx.NewParseError(msgXmlUnexpectedEnd, spdx.NewMetaL(p.line(int64(len(p.data)))))
+	}
+	return tok, line, err
+}
+
+// Emit a statement.
+func (p *xmlParser) emit(subj, pred, obj Term, line int) error {
+	return p.handle(&Statement{subj, pred, obj}, spdx.NewMetaL(line))
+}
+
+// Returns a new blank node.
+func (p *xmlParser) newBlank() *Blank {
+	p.blanks++
+	return blank("genid" + strconv.Itoa(p.blanks))
+}
+
+// Returns the scope of element `el` in the scope `sc` and reports the
+// namespaces it declares.
+func (p *xmlParser) scope(sc xmlScope, el xml.StartElement) xmlScope {
+	for _, attr := range el.Attr {
+		switch {
+		case attr.Name.Space == xmlNs && attr.Name.Local == "base":
+			sc.base = resolve(sc.base, attr.Value)
+		case attr.Name.Space == xmlNs && attr.Name.Local == "lang":
+			sc.lang = attr.Value
+		case p.ns != nil && attr.Name.Space == "xmlns":
+			p.ns(attr.Name.Local, attr.Value)
+		case p.ns != nil && attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			p.ns("", attr.Value)
+		}
+	}
+	return sc
+}
+
+// Resolve the URI reference `ref` against `base`. If `ref` is absolute or
+// either of them cannot be parsed, `ref` is returned as it is.
+func resolve(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	r, err := url.Parse(ref)
+	if err != nil || r.IsAbs() {
+		return ref
+	}
+	res := b.ResolveReference(r).String()
+	if strings.HasSuffix(ref, "#") && !strings.HasSuffix(res, "#") {
+		// net/url drops empty fragments
+		res += "#"
+	}
+	if ref == "" {
+		// same document reference: the base without fragment
+		res = strings.SplitN(res, "#", 2)[0]
+	}
+	return res
+}
+
+// Returns the URI of an element or attribute name.
+func nameUri(name xml.Name) string {
+	return name.Space + name.Local
+}
+
+// Checks if the name is rdf:`local`.
+func isRdf(name xml.Name, local string) bool {
+	return name.Space == rdfNs && name.Local == local
+}
+
+// Checks if an attribute is used by XML itself (namespace declarations,
+// xml:* attributes) or has no namespace, and so is not a property attribute.
+func isXmlAttr(attr xml.Attr) bool {
+	return attr.Name.Space == "" || attr.Name.Space == "xmlns" || attr.Name.Space == xmlNs
+}
+
+// Parse the document: the rdf:RDF element, or a single node element.
+func (p *xmlParser) document(sc xmlScope) error {
+	for {
+		tok, line, err := p.token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		el, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		esc := p.scope(sc, el)
+		switch {
+		case isRdf(el.Name, "RDF"):
+			if err = p.nodeElements(esc, el.Name); err != nil {
+				return err
+			}
+		case p.xmp:
+			// wrapper element, look for rdf:RDF inside it
+			sc = esc
+		default:
+			if _, err = p.nodeElement(el, esc, line); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Parse the node elements until the end of the current element.
+func (p *xmlParser) nodeElements(sc xmlScope, parent xml.Name) error {
+	for {
+		tok, line, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if _, err = p.nodeElement(t, p.scope(sc, t), line); err != nil {
+				return err
+			}
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return spdx.NewParseError(fmt.Sprintf(msgXmlUnexpectedText, parent.Local), spdx.NewMetaL(line))
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Returns the subject of a node element.
+func (p *xmlParser) subject(el xml.StartElement, sc xmlScope) Term {
+	for _, attr := range el.Attr {
+		switch {
+		case isRdf(attr.Name, "about"):
+			return uri(resolve(sc.base, attr.Value))
+		case isRdf(attr.Name, "ID"):
+			return uri(resolve(sc.base, "#"+attr.Value))
+		case isRdf(attr.Name, "nodeID"):
+			return blank(attr.Value)
+		}
+	}
+	return p.newBlank()
+}
+
+// Parse a node element, after its start tag. Returns the subject of the node.
+func (p *xmlParser) nodeElement(el xml.StartElement, sc xmlScope, line int) (Term, error) {
+	subj := p.subject(el, sc)
+
+	if !isRdf(el.Name, "Description") {
+		if err := p.emit(subj, uri(rdfNs+"type"), uri(nameUri(el.Name)), line); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, attr := range el.Attr {
+		if isXmlAttr(attr) || isRdf(attr.Name, "about") || isRdf(attr.Name, "ID") || isRdf(attr.Name, "nodeID") {
+			continue
+		}
+		var obj Term = &Literal{Value: attr.Value, Lang: sc.lang}
+		if isRdf(attr.Name, "type") {
+			obj = uri(resolve(sc.base, attr.Value))
+		}
+		if err := p.emit(subj, uri(nameUri(attr.Name)), obj, line); err != nil {
+			return nil, err
+		}
+	}
+
+	return subj, p.propertyElements(subj, sc, el.Name)
+}
+
+// Parse the property elements of `subj` until the end of the current element.
+func (p *xmlParser) propertyElements(subj Term, sc xmlScope, parent xml.Name) error {
+	li := 0
+	for {
+		tok, line, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err = p.propertyElement(subj, t, p.scope(sc, t), line, &li); err != nil {
+				return err
+			}
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return spdx.NewParseError(fmt.Sprintf(msgXmlUnexpectedText, parent.Local), spdx.NewMetaL(line))
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// Parse a property element of `subj`, after its start tag. `li` counts the
+// rdf:li elements of the subject.
+func (p *xmlParser) propertyElement(subj Term, el xml.StartElement, sc xmlScope, line int, li *int) error {
+	pred := uri(nameUri(el.Name))
+	if isRdf(el.Name, "li") {
+		*li++
+		pred = uri(rdfNs + "_" + strconv.Itoa(*li))
+	}
+
+	var parseType, datatype string
+	var obj Term
+	props := make([]xml.Attr, 0)
+	for _, attr := range el.Attr {
+		switch {
+		case isXmlAttr(attr) || isRdf(attr.Name, "ID"):
+			// rdf:ID on property elements (reification) is not supported
+		case isRdf(attr.Name, "parseType"):
+			parseType = attr.Value
+		case isRdf(attr.Name, "datatype"):
+			datatype = resolve(sc.base, attr.Value)
+		case isRdf(attr.Name, "resource"):
+			obj = uri(resolve(sc.base, attr.Value))
+		case isRdf(attr.Name, "nodeID"):
+			obj = blank(attr.Value)
+		default:
+			props = append(props, attr)
+		}
+	}
+
+	switch parseType {
+	case "":
+	case "Resource":
+		node := p.newBlank()
+		if err := p.emit(subj, pred, node, line); err != nil {
+			return err
+		}
+		return p.propertyElements(node, sc, el.Name)
+	case "Collection":
+		return p.collection(subj, pred, sc, el.Name, line)
+	default:
+		lit, err := p.xmlLiteral()
+		if err != nil {
+			return err
+		}
+		return p.emit(subj, pred, &Literal{Value: lit, Datatype: xmlLiteral}, line)
+	}
+
+	// element content: text or one node element
+	var text bytes.Buffer
+	var node Term
+	for {
+		tok, tokLine, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if node != nil {
+				return spdx.NewParseError(fmt.Sprintf(msgXmlManyNodes, el.Name.Local), spdx.NewMetaL(tokLine))
+			}
+			if node, err = p.nodeElement(t, p.scope(sc, t), tokLine); err != nil {
+				return err
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if node != nil {
+				if len(bytes.TrimSpace(text.Bytes())) > 0 {
+					return spdx.NewParseError(fmt.Sprintf(msgXmlMixedContent, el.Name.Local), spdx.NewMetaL(line))
+				}
+				return p.emit(subj, pred, node, line)
+			}
+			if obj == nil && len(props) == 0 {
+				return p.emit(subj, pred, &Literal{Value: text.String(), Datatype: datatype, Lang: sc.lang}, line)
+			}
+			if obj == nil {
+				obj = p.newBlank()
+			}
+			for _, attr := range props {
+				var val Term = &Literal{Value: attr.Value, Lang: sc.lang}
+				if isRdf(attr.Name, "type") {
+					val = uri(resolve(sc.base, attr.Value))
+				}
+				if err = p.emit(obj, uri(nameUri(attr.Name)), val, line); err != nil {
+					return err
+				}
+			}
+			return p.emit(subj, pred, obj, line)
+		}
+	}
+}
+
+// Read the content of a rdf:parseType="Literal" property element, as it is
+// written in the input.
+func (p *xmlParser) xmlLiteral() (string, error) {
+	start := p.dec.InputOffset()
+	depth := 0
+	for {
+		end := p.dec.InputOffset()
+		tok, _, err := p.next()
+		if err != nil {
+			return "", err
+		}
+		switch tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			if depth == 0 {
+				return string(p.data[start:end]), nil
+			}
+			depth--
+		}
+	}
+}
+
+// Parse the node elements of a rdf:parseType="Collection" property element
+// and emit the RDF list of them.
+func (p *xmlParser) collection(subj, pred Term, sc xmlScope, parent xml.Name, line int) error {
+	items := make([]Term, 0)
+	lines := make([]int, 0)
+	for {
+		tok, tokLine, err := p.next()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			item, err := p.nodeElement(t, p.scope(sc, t), tokLine)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+			lines = append(lines, tokLine)
+		case xml.CharData:
+			if len(bytes.TrimSpace(t)) > 0 {
+				return spdx.NewParseError(fmt.Sprintf(msgXmlUnexpectedText, parent.Local), spdx.NewMetaL(tokLine))
+			}
+		case xml.EndElement:
+			var list Term = uri(rdfNs + "nil")
+			for i := len(items) - 1; i >= 0; i-- {
+				cell := p.newBlank()
+				if err := p.emit(cell, uri(rdfNs+"first"), items[i], lines[i]); err != nil {
+					return err
+				}
+				if err := p.emit(cell, uri(rdfNs+"rest"), list, lines[i]); err != nil {
+					return err
+				}
+				list = cell
+			}
+			return p.emit(subj, pred, list, line)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package rdf
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"strings"
+	"testing"
+)
+
+// Parse RDF/XML and return the statements and their lines.
+func parseXmlString(t *testing.T, input string) ([]*Statement, []int) {
+	stms := make([]*Statement, 0)
+	lines := make([]int, 0)
+	err := parseXml(strings.NewReader(input), baseUri, false, nil, func(stm *Statement, meta *spdx.Meta) error {
+		stms = append(stms, stm)
+		lines = append(lines, meta.LineStart)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return stms, lines
+}
+
+// Checks if the statement is found in stms and returns its index or -1.
+func findStatement(stms []*Statement, subj, pred, obj Term) int {
+	for i, stm := range stms {
+		if stm.Subject.Equals(subj) && stm.Predicate.Equals(pred) && stm.Object.Equals(obj) {
+			return i
+		}
+	}
+	return -1
+}
+
+const testXml = `<?xml version="1.0"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+    xmlns="http://spdx.org/rdf/terms#" xmlns:spdx="http://spdx.org/rdf/terms#">
+  <SpdxDocument rdf:about="#SPDXRef-DOCUMENT">
+    <dataLicense rdf:resource="../licenses/CC0-1.0"/>
+    <creationInfo>
+      <CreationInfo>
+        <creator>Tool: spdx-go</creator>
+      </CreationInfo>
+    </creationInfo>
+    <referencesFile rdf:nodeID="file1"/>
+    <rdf:li xml:lang="en">first</rdf:li>
+  </SpdxDocument>
+  <rdf:Description rdf:nodeID="file1" spdx:fileName="a.c">
+    <checksum rdf:parseType="Resource">
+      <checksumValue>abc</checksumValue>
+    </checksum>
+    <noticeText rdf:parseType="Literal"><b>notice</b></noticeText>
+    <member rdf:parseType="Collection">
+      <rdf:Description rdf:about="http://example.org/x"/>
+    </member>
+  </rdf:Description>
+</rdf:RDF>
+`
+
+func TestParseXml(t *testing.T) {
+	stms, lines := parseXmlString(t, testXml)
+
+	doc := uri(baseUri + "SPDXRef-DOCUMENT")
+	file := blank("file1")
+	tests := []struct {
+		subj, pred, obj Term
+		line            int
+	}{
+		{doc, uri(rdfNs + "type"), uri(baseUri + "SpdxDocument"), 4},
+		{doc, uri(baseUri + "dataLicense"), uri("http://spdx.org/licenses/CC0-1.0"), 5},
+		{doc, uri(baseUri + "referencesFile"), file, 11},
+		{doc, uri(rdfNs + "_1"), &Literal{Value: "first", Lang: "en"}, 12},
+		{file, uri(baseUri + "fileName"), literal("a.c"), 14},
+		{file, uri(baseUri + "noticeText"), &Literal{Value: "<b>notice</b>", Datatype: xmlLiteral}, 18},
+	}
+	for _, test := range tests {
+		i := findStatement(stms, test.subj, test.pred, test.obj)
+		if i < 0 {
+			t.Errorf("Statement not found: %s %s %s", termStr(test.subj), termStr(test.pred), termStr(test.obj))
+			continue
+		}
+		if lines[i] != test.line {
+			t.Errorf("Wrong line for %s: %d (expected %d)", termStr(test.pred), lines[i], test.line)
+		}
+	}
+
+	// nested nodes
+	var info, checksum, list Term
+	for i, stm := range stms {
+		switch termStr(stm.Predicate) {
+		case baseUri + "creationInfo":
+			info = stm.Object
+		case baseUri + "checksum":
+			checksum = stm.Object
+			if lines[i] != 15 {
+				t.Errorf("Wrong line for checksum: %d", lines[i])
+			}
+		case baseUri + "member":
+			list = stm.Object
+		}
+	}
+	if info == nil || findStatement(stms, info, uri(baseUri+"creator"), literal("Tool: spdx-go")) < 0 {
+		t.Error("Nested node element not parsed.")
+	}
+	if checksum == nil || findStatement(stms, checksum, uri(baseUri+"checksumValue"), literal("abc")) < 0 {
+		t.Error("rdf:parseType=\"Resource\" not parsed.")
+	}
+	if list == nil ||
+		findStatement(stms, list, uri(rdfNs+"first"), uri("http://example.org/x")) < 0 ||
+		findStatement(stms, list, uri(rdfNs+"rest"), uri(rdfNs+"nil")) < 0 {
+		t.Error("rdf:parseType=\"Collection\" not parsed.")
+	}
+}
+
+func TestParseXmlNamespaces(t *testing.T) {
+	ns := make(map[string]string)
+	input := `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns:doap="http://usefulinc.com/ns/doap#"/>`
+	err := parseXml(strings.NewReader(input), baseUri, false, func(prefix, uri string) { ns[prefix] = uri }, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if ns["rdf"] != rdfNs || ns["doap"] != "http://usefulinc.com/ns/doap#" {
+		t.Errorf("Wrong namespaces: %v", ns)
+	}
+}
+
+func TestParseXmlErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n<a:b>", 2},
+		{"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n\ntext</rdf:RDF>", 1},
+		{"<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\">\n<rdf:Description>\n</rdf:RDF>", 3},
+	}
+	for _, test := range tests {
+		err := parseXml(strings.NewReader(test.input), baseUri, false, nil, func(*Statement, *spdx.Meta) error { return nil })
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("Expected *spdx.ParseError for %q, got %v", test.input, err)
+			continue
+		}
+		if perr.LineStart != test.line {
+			t.Errorf("Wrong line for %q: %d (expected %d)", test.input, perr.LineStart, test.line)
+		}
+	}
+}
+
+func TestParseXmlXmp(t *testing.T) {
+	input := `<x:xmpmeta xmlns:x="adobe:ns:meta/">
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">
+<rdf:Description rdf:about="http://example.org/a" rdf:value="v"/>
+</rdf:RDF>
+</x:xmpmeta>`
+	count := 0
+	err := parseXml(strings.NewReader(input), baseUri, true, nil, func(stm *Statement, meta *spdx.Meta) error {
+		count++
+		if termStr(stm.Subject) != "http://example.org/a" || meta.LineStart != 3 {
+			t.Errorf("Unexpected statement %s %s on line %d", termStr(stm.Subject), termStr(stm.Predicate), meta.LineStart)
+		}
+		return nil
+	})
+	if err != nil || count != 1 {
+		t.Errorf("Unexpected result: %d statements, error %v", count, err)
+	}
+}
@@ -0,0 +1,47 @@
+package rdf
+
+// A RDF term. It is one of *Uri, *Blank and *Literal.
+type Term interface {
+	Equals(Term) bool
+	String() string
+}
+
+// A URI reference.
+type Uri string
+
+// Checks if `t` is an *Uri with the same value.
+func (u *Uri) Equals(t Term) bool {
+	o, ok := t.(*Uri)
+	return ok && *o == *u
+}
+
+func (u *Uri) String() string { return string(*u) }
+
+// A blank node, identified by its node ID.
+type Blank string
+
+// Checks if `t` is a *Blank with the same node ID.
+func (b *Blank) Equals(t Term) bool {
+	o, ok := t.(*Blank)
+	return ok && *o == *b
+}
+
+func (b *Blank) String() string { return string(*b) }
+
+// A literal value with an optional datatype URI or language.
+type Literal struct {
+	Value, Datatype, Lang string
+}
+
+// Checks if `t` is a *Literal with the same value, datatype and language.
+func (l *Literal) Equals(t Term) bool {
+	o, ok := t.(*Literal)
+	return ok && *o == *l
+}
+
+func (l *Literal) String() string { return l.Value }
+
+// A RDF statement (triple).
+type Statement struct {
+	Subject, Predicate, Object Term
+}
@@ -0,0 +1,844 @@
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/spdx/tools-go/spdx"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Turtle parser error messages.
+const (
+	msgTurtleUnexpected      = "Unexpected %s."
+	msgTurtleExpected        = "Expected %s, found %s."
+	msgTurtleUnterminated    = "Unterminated %s."
+	msgTurtleInvalidEscape   = "Invalid escape sequence \\%c."
+	msgTurtleInvalidChar     = "Invalid character %q."
+	msgTurtleUndefinedPrefix = "Undefined prefix %s:."
+)
+
+// XML Schema datatypes of the Turtle number and boolean literals.
+const (
+	xsdNs      = "http://www.w3.org/2001/XMLSchema#"
+	xsdInteger = xsdNs + "integer"
+	xsdDecimal = xsdNs + "decimal"
+	xsdDouble  = xsdNs + "double"
+	xsdBoolean = xsdNs + "boolean"
+)
+
+// Kinds of Turtle tokens.
+const (
+	tokEof       = iota
+	tokIri       // <iri>, val is the IRI
+	tokPname     // prefix:local, val is the prefix and local the local name
+	tokBlank     // _:label, val is the label
+	tokString    // val is the unescaped string
+	tokLang      // @lang, val is the language tag
+	tokNumber    // val is the number and local its datatype
+	tokWord      // a, true, false, PREFIX or BASE
+	tokDirective // @prefix or @base, val is the name without @
+	tokPunct     // . ; , [ ] ( ) ^^
+)
+
+// A Turtle token and the line it starts on.
+type turtleToken struct {
+	kind       int
+	val, local string
+	line       int
+}
+
+// Describe a token in error messages.
+func (t *turtleToken) String() string {
+	switch t.kind {
+	case tokEof:
+		return "end of input"
+	case tokIri:
+		return "<" + t.val + ">"
+	case tokPname:
+		return t.val + ":" + t.local
+	case tokBlank:
+		return "_:" + t.val
+	case tokString:
+		return "string"
+	case tokLang:
+		return "@" + t.val
+	case tokNumber:
+		return "number " + t.val
+	case tokDirective:
+		return "@" + t.val
+	}
+	return "\"" + t.val + "\""
+}
+
+// Splits Turtle or N-Triples input into tokens. It reads the input as it
+// goes, so statements are parsed while the input is read.
+type turtleLexer struct {
+	r    *bufio.Reader
+	back []rune // runes read but not consumed, the last one is read first
+	line int
+}
+
+// Read a rune. Returns -1 at the end of the input.
+func (l *turtleLexer) read() (rune, error) {
+	var c rune
+	if n := len(l.back); n > 0 {
+		c = l.back[n-1]
+		l.back = l.back[:n-1]
+	} else {
+		var err error
+		c, _, err = l.r.ReadRune()
+		if err == io.EOF {
+			return -1, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if c == '\n' {
+		l.line++
+	}
+	return c, nil
+}
+
+// Put back a rune read, so it is read again.
+func (l *turtleLexer) unread(c rune) {
+	if c == -1 {
+		return
+	}
+	if c == '\n' {
+		l.line--
+	}
+	l.back = append(l.back, c)
+}
+
+// Returns a *spdx.ParseError on the current line.
+func (l *turtleLexer) error(msg string, args ...interface{}) error {
+	return spdx.NewParseError(fmt.Sprintf(msg, args...), spdx.NewMetaL(l.line))
+}
+
+// Read the next token.
+func (l *turtleLexer) next() (*turtleToken, error) {
+	c, err := l.skipSpace()
+	if err != nil {
+		return nil, err
+	}
+	tok := &turtleToken{line: l.line}
+
+	switch {
+	case c == -1:
+		tok.kind = tokEof
+	case c == '<':
+		tok.kind = tokIri
+		tok.val, err = l.iri()
+	case c == '"' || c == '\'':
+		tok.kind = tokString
+		tok.val, err = l.string(c)
+	case c == '@':
+		tok.val, err = l.run(func(c rune, i int) bool {
+			return c < 128 && (unicode.IsLetter(c) || (i > 0 && (c == '-' || unicode.IsDigit(c))))
+		})
+		tok.kind = tokLang
+		if tok.val == "prefix" || tok.val == "base" {
+			tok.kind = tokDirective
+		}
+	case c == '^':
+		c, err = l.read()
+		if err == nil && c != '^' {
+			err = l.error(msgTurtleInvalidChar, '^')
+		}
+		tok.kind, tok.val = tokPunct, "^^"
+	case c == '_':
+		c, err = l.read()
+		if err == nil && c != ':' {
+			err = l.error(msgTurtleInvalidChar, '_')
+		}
+		tok.kind = tokBlank
+		if err == nil {
+			tok.val, err = l.name(false)
+		}
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		if c == '.' {
+			d, err := l.read()
+			if err != nil {
+				return nil, err
+			}
+			l.unread(d)
+			if d < '0' || d > '9' {
+				tok.kind, tok.val = tokPunct, "."
+				break
+			}
+		}
+		l.unread(c)
+		tok.kind = tokNumber
+		tok.val, tok.local, err = l.number()
+	case strings.ContainsRune(";,[]()", c):
+		tok.kind, tok.val = tokPunct, string(c)
+	case c == ':' || isNameStart(c):
+		l.unread(c)
+		tok.kind = tokPname
+		if tok.val, err = l.name(false); err != nil {
+			break
+		}
+		if c, err = l.read(); err != nil {
+			break
+		}
+		if c != ':' {
+			l.unread(c)
+			if tok.val != "a" && tok.val != "true" && tok.val != "false" &&
+				!strings.EqualFold(tok.val, "prefix") && !strings.EqualFold(tok.val, "base") {
+				return nil, l.error(msgTurtleUnexpected, tok.val)
+			}
+			tok.kind = tokWord
+			break
+		}
+		tok.local, err = l.name(true)
+	default:
+		err = l.error(msgTurtleInvalidChar, c)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
+
+// Skip white space and comments and return the next rune.
+func (l *turtleLexer) skipSpace() (rune, error) {
+	for {
+		c, err := l.read()
+		if err != nil || c == -1 {
+			return c, err
+		}
+		if c == '#' {
+			for c != '\n' && c != -1 {
+				if c, err = l.read(); err != nil {
+					return 0, err
+				}
+			}
+		}
+		if !unicode.IsSpace(c) && c != '\ufeff' {
+			return c, nil
+		}
+	}
+}
+
+// Read the runes for which ok(rune, index) is true.
+func (l *turtleLexer) run(ok func(c rune, i int) bool) (string, error) {
+	var buf []rune
+	for {
+		c, err := l.read()
+		if err != nil {
+			return "", err
+		}
+		if c == -1 || !ok(c, len(buf)) {
+			l.unread(c)
+			return string(buf), nil
+		}
+		buf = append(buf, c)
+	}
+}
+
+// Read a prefix, local name or blank node label. Only local names can have
+// colons (`colon`). Names cannot end with a dot, which is left as the end of
+// the statement.
+func (l *turtleLexer) name(colon bool) (string, error) {
+	var buf []rune
+	for {
+		c, err := l.read()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == '\\':
+			if c, err = l.read(); err != nil {
+				return "", err
+			}
+			if c == -1 || !strings.ContainsRune("_~.-!$&'()*+,;=/?#@%", c) {
+				return "", l.error(msgTurtleInvalidEscape, c)
+			}
+			buf = append(buf, c)
+			continue
+		case c == '%' || c == '.' || isNameChar(c) || (colon && c == ':'):
+			buf = append(buf, c)
+			continue
+		}
+		l.unread(c)
+		for len(buf) > 0 && buf[len(buf)-1] == '.' {
+			l.unread('.')
+			buf = buf[:len(buf)-1]
+		}
+		return string(buf), nil
+	}
+}
+
+// Read a number and return it with its datatype.
+func (l *turtleLexer) number() (string, string, error) {
+	sign, err := l.read()
+	if err != nil {
+		return "", "", err
+	}
+	if sign != '+' && sign != '-' {
+		l.unread(sign)
+		sign = 0
+	}
+	isDigit := func(c rune, i int) bool { return c >= '0' && c <= '9' }
+	val, err := l.run(isDigit)
+	if err != nil {
+		return "", "", err
+	}
+	datatype := xsdInteger
+
+	c, err := l.read()
+	if err != nil {
+		return "", "", err
+	}
+	if c == '.' {
+		d, err := l.read()
+		if err != nil {
+			return "", "", err
+		}
+		l.unread(d)
+		if d >= '0' && d <= '9' {
+			frac, err := l.run(isDigit)
+			if err != nil {
+				return "", "", err
+			}
+			val += "." + frac
+			datatype = xsdDecimal
+			if c, err = l.read(); err != nil {
+				return "", "", err
+			}
+		} else {
+			// the end of the statement
+			l.unread(c)
+			c = 0
+		}
+	}
+	if c == 'e' || c == 'E' {
+		exp, err := l.run(func(c rune, i int) bool {
+			return (c >= '0' && c <= '9') || (i == 0 && (c == '+' || c == '-'))
+		})
+		if err != nil {
+			return "", "", err
+		}
+		val += string(c) + exp
+		datatype = xsdDouble
+	} else if c != 0 {
+		l.unread(c)
+	}
+
+	if val == "" || strings.HasSuffix(val, "e") || strings.HasSuffix(val, "E") {
+		return "", "", l.error(msgTurtleUnexpected, "number")
+	}
+	if sign != 0 {
+		val = string(sign) + val
+	}
+	return val, datatype, nil
+}
+
+// Read an IRI, after the opening <.
+func (l *turtleLexer) iri() (string, error) {
+	start := l.line
+	var buf []rune
+	for {
+		c, err := l.read()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == '>':
+			return string(buf), nil
+		case c == -1 || c == '\n':
+			return "", spdx.NewParseError(fmt.Sprintf(msgTurtleUnterminated, "IRI"), spdx.NewMeta(start, l.line))
+		case c == '\\':
+			if c, err = l.read(); err != nil {
+				return "", err
+			}
+			if c != 'u' && c != 'U' {
+				return "", l.error(msgTurtleInvalidEscape, c)
+			}
+			if c, err = l.unicode(c); err != nil {
+				return "", err
+			}
+		case c <= ' ' || strings.ContainsRune("<\"{}|^`", c):
+			return "", l.error(msgTurtleInvalidChar, c)
+		}
+		buf = append(buf, c)
+	}
+}
+
+// Read the hex digits of a \u (4 digits) or \U (8 digits) escape sequence.
+func (l *turtleLexer) unicode(esc rune) (rune, error) {
+	n := 4
+	if esc == 'U' {
+		n = 8
+	}
+	hex := make([]rune, 0, n)
+	for len(hex) < n {
+		c, err := l.read()
+		if err != nil {
+			return 0, err
+		}
+		hex = append(hex, c)
+	}
+	val, err := strconv.ParseUint(string(hex), 16, 32)
+	if err != nil {
+		return 0, l.error(msgTurtleInvalidEscape, esc)
+	}
+	return rune(val), nil
+}
+
+// Read a string, after the opening quote `q`.
+func (l *turtleLexer) string(q rune) (string, error) {
+	start := l.line
+	long := false
+	c, err := l.read()
+	if err != nil {
+		return "", err
+	}
+	if c == q {
+		d, err := l.read()
+		if err != nil {
+			return "", err
+		}
+		if d != q {
+			// empty string
+			l.unread(d)
+			return "", nil
+		}
+		long = true
+	} else {
+		l.unread(c)
+	}
+
+	var buf []rune
+	quotes := 0 // quotes read at the end of buf, for long strings
+	for {
+		c, err := l.read()
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case c == -1 || (c == '\n' && !long):
+			return "", spdx.NewParseError(fmt.Sprintf(msgTurtleUnterminated, "string"), spdx.NewMeta(start, l.line))
+		case c == q && !long:
+			return string(buf), nil
+		case c == q:
+			quotes++
+			if quotes == 3 {
+				return string(buf[:len(buf)-2]), nil
+			}
+			buf = append(buf, c)
+			continue
+		case c == '\\':
+			if c, err = l.read(); err != nil {
+				return "", err
+			}
+			switch c {
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 'f':
+				c = '\f'
+			case '"', '\'', '\\':
+			case 'u', 'U':
+				if c, err = l.unicode(c); err != nil {
+					return "", err
+				}
+			default:
+				return "", l.error(msgTurtleInvalidEscape, c)
+			}
+		}
+		quotes = 0
+		buf = append(buf, c)
+	}
+}
+
+// Turtle parser. N-Triples is a subset of Turtle and is parsed by it too.
+type turtleParser struct {
+	lex      *turtleLexer
+	tok      *turtleToken // the next token, if read
+	base     string
+	prefixes map[string]string
+	ns       func(prefix, uri string)
+	handle   statementHandler
+	blanks   int
+}
+
+// Parse the Turtle or N-Triples in `input` and call `handle` for each
+// statement, with the line of its object. Relative IRIs are resolved against
+// `base`. `ns` is called for the prefixes defined, if it is not nil.
+func parseTurtle(input io.Reader, base string, ns func(prefix, uri string), handle statementHandler) error {
+	p := &turtleParser{
+		lex:      &turtleLexer{r: bufio.NewReader(input), line: 1},
+		base:     base,
+		prefixes: make(map[string]string),
+		ns:       ns,
+		handle:   handle,
+	}
+	for {
+		tok, err := p.peek()
+		if err != nil {
+			return err
+		}
+		switch {
+		case tok.kind == tokEof:
+			return nil
+		case tok.kind == tokDirective:
+			err = p.directive(true)
+		case tok.kind == tokWord && (strings.EqualFold(tok.val, "prefix") || strings.EqualFold(tok.val, "base")):
+			err = p.directive(false)
+		default:
+			err = p.triples()
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Returns the next token without consuming it.
+func (p *turtleParser) peek() (*turtleToken, error) {
+	if p.tok == nil {
+		tok, err := p.lex.next()
+		if err != nil {
+			return nil, err
+		}
+		p.tok = tok
+	}
+	return p.tok, nil
+}
+
+// Consume the next token.
+func (p *turtleParser) next() (*turtleToken, error) {
+	tok, err := p.peek()
+	p.tok = nil
+	return tok, err
+}
+
+// Checks if the next token is the punctuation `val`.
+func (p *turtleParser) isPunct(val string) (bool, error) {
+	tok, err := p.peek()
+	if err != nil {
+		return false, err
+	}
+	return tok.kind == tokPunct && tok.val == val, nil
+}
+
+// Consume the punctuation `val` or return an error.
+func (p *turtleParser) expect(val string) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	if tok.kind != tokPunct || tok.val != val {
+		return unexpected(tok, "\""+val+"\"")
+	}
+	return nil
+}
+
+// Returns the error for an unexpected token. `expected` describes what was
+// expected, if it is not empty.
+func unexpected(tok *turtleToken, expected string) error {
+	msg := fmt.Sprintf(msgTurtleUnexpected, tok)
+	if expected != "" {
+		msg = fmt.Sprintf(msgTurtleExpected, expected, tok)
+	}
+	return spdx.NewParseError(msg, spdx.NewMetaL(tok.line))
+}
+
+// Parse a prefix or base directive. The @ forms end with a dot.
+func (p *turtleParser) directive(dot bool) error {
+	tok, err := p.next()
+	if err != nil {
+		return err
+	}
+	name := strings.ToLower(tok.val)
+
+	var prefix *turtleToken
+	if name == "prefix" {
+		if prefix, err = p.next(); err != nil {
+			return err
+		}
+		if prefix.kind != tokPname || prefix.local != "" {
+			return unexpected(prefix, "prefix")
+		}
+	}
+
+	iri, err := p.next()
+	if err != nil {
+		return err
+	}
+	if iri.kind != tokIri {
+		return unexpected(iri, "IRI")
+	}
+	uri := resolve(p.base, iri.val)
+	if prefix != nil {
+		p.prefixes[prefix.val] = uri
+		if p.ns != nil {
+			p.ns(prefix.val, uri)
+		}
+	} else {
+		p.base = uri
+	}
+
+	if dot {
+		return p.expect(".")
+	}
+	return nil
+}
+
+// Parse triples, up to the final dot.
+func (p *turtleParser) triples() error {
+	list, err := p.isPunct("[")
+	if err != nil {
+		return err
+	}
+	if list {
+		p.next()
+		subj, err := p.blankNodePropertyList()
+		if err != nil {
+			return err
+		}
+		if end, err := p.isPunct("."); err != nil || end {
+			p.next()
+			return err
+		}
+		if err = p.predicateObjectList(subj); err != nil {
+			return err
+		}
+		return p.expect(".")
+	}
+
+	subj, _, err := p.subject()
+	if err != nil {
+		return err
+	}
+	if err = p.predicateObjectList(subj); err != nil {
+		return err
+	}
+	return p.expect(".")
+}
+
+// Parse a subject.
+func (p *turtleParser) subject() (Term, int, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, 0, err
+	}
+	switch tok.kind {
+	case tokIri, tokPname, tokBlank:
+		t, err := p.term(tok)
+		return t, tok.line, err
+	case tokPunct:
+		if tok.val == "(" {
+			return p.collection(tok.line)
+		}
+	}
+	return nil, 0, unexpected(tok, "subject")
+}
+
+// Returns the term of an IRI, prefixed name or blank node token.
+func (p *turtleParser) term(tok *turtleToken) (Term, error) {
+	switch tok.kind {
+	case tokIri:
+		return uri(resolve(p.base, tok.val)), nil
+	case tokPname:
+		ns, ok := p.prefixes[tok.val]
+		if !ok {
+			return nil, spdx.NewParseError(fmt.Sprintf(msgTurtleUndefinedPrefix, tok.val), spdx.NewMetaL(tok.line))
+		}
+		return uri(ns + tok.local), nil
+	case tokBlank:
+		return blank(tok.val), nil
+	}
+	return nil, unexpected(tok, "")
+}
+
+// Parse predicates and their objects for `subj`.
+func (p *turtleParser) predicateObjectList(subj Term) error {
+	for {
+		tok, err := p.next()
+		if err != nil {
+			return err
+		}
+		var pred Term
+		switch {
+		case tok.kind == tokWord && tok.val == "a":
+			pred = uri(rdfNs + "type")
+		case tok.kind == tokIri || tok.kind == tokPname:
+			if pred, err = p.term(tok); err != nil {
+				return err
+			}
+		default:
+			return unexpected(tok, "predicate")
+		}
+
+		if err = p.objectList(subj, pred); err != nil {
+			return err
+		}
+
+		// ";" may be repeated and may end the list
+		more := false
+		for {
+			sep, err := p.isPunct(";")
+			if err != nil {
+				return err
+			}
+			if !sep {
+				break
+			}
+			p.next()
+			more = true
+		}
+		if !more {
+			return nil
+		}
+		tok, err = p.peek()
+		if err != nil {
+			return err
+		}
+		if tok.kind == tokEof || (tok.kind == tokPunct && (tok.val == "." || tok.val == "]")) {
+			return nil
+		}
+	}
+}
+
+// Parse the objects of `subj` and `pred`, separated by commas.
+func (p *turtleParser) objectList(subj, pred Term) error {
+	for {
+		obj, line, err := p.object()
+		if err != nil {
+			return err
+		}
+		if err = p.handle(&Statement{subj, pred, obj}, spdx.NewMetaL(line)); err != nil {
+			return err
+		}
+		if more, err := p.isPunct(","); err != nil || !more {
+			return err
+		}
+		p.next()
+	}
+}
+
+// Parse an object and return it with the line it starts on. The statements
+// of nested blank nodes and collections are emitted.
+func (p *turtleParser) object() (Term, int, error) {
+	tok, err := p.next()
+	if err != nil {
+		return nil, 0, err
+	}
+	switch tok.kind {
+	case tokIri, tokPname, tokBlank:
+		t, err := p.term(tok)
+		return t, tok.line, err
+	case tokNumber:
+		return &Literal{Value: tok.val, Datatype: tok.local}, tok.line, nil
+	case tokWord:
+		if tok.val == "true" || tok.val == "false" {
+			return &Literal{Value: tok.val, Datatype: xsdBoolean}, tok.line, nil
+		}
+	case tokString:
+		lit, err := p.literal(tok.val)
+		return lit, tok.line, err
+	case tokPunct:
+		switch tok.val {
+		case "[":
+			node, err := p.blankNodePropertyList()
+			return node, tok.line, err
+		case "(":
+			return p.collection(tok.line)
+		}
+	}
+	return nil, 0, unexpected(tok, "object")
+}
+
+// Parse the language tag or datatype of a literal, if there is one.
+func (p *turtleParser) literal(val string) (*Literal, error) {
+	lit := &Literal{Value: val}
+	tok, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tok.kind == tokLang:
+		p.next()
+		lit.Lang = tok.val
+	case tok.kind == tokPunct && tok.val == "^^":
+		p.next()
+		dt, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if dt.kind != tokIri && dt.kind != tokPname {
+			return nil, unexpected(dt, "datatype IRI")
+		}
+		t, err := p.term(dt)
+		if err != nil {
+			return nil, err
+		}
+		lit.Datatype = termStr(t)
+	}
+	return lit, nil
+}
+
+// Returns a new blank node.
+func (p *turtleParser) newBlank() *Blank {
+	p.blanks++
+	return blank("genid" + strconv.Itoa(p.blanks))
+}
+
+// Parse the properties of a blank node, after the opening [.
+func (p *turtleParser) blankNodePropertyList() (Term, error) {
+	node := p.newBlank()
+	if end, err := p.isPunct("]"); err != nil || end {
+		p.next()
+		return node, err
+	}
+	if err := p.predicateObjectList(node); err != nil {
+		return nil, err
+	}
+	return node, p.expect("]")
+}
+
+// Parse a collection, after the opening (, and emit its RDF list.
+func (p *turtleParser) collection(line int) (Term, int, error) {
+	items := make([]Term, 0)
+	lines := make([]int, 0)
+	for {
+		end, err := p.isPunct(")")
+		if err != nil {
+			return nil, 0, err
+		}
+		if end {
+			p.next()
+			break
+		}
+		item, itemLine, err := p.object()
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+		lines = append(lines, itemLine)
+	}
+
+	var list Term = uri(rdfNs + "nil")
+	for i := len(items) - 1; i >= 0; i-- {
+		cell := p.newBlank()
+		if err := p.handle(&Statement{cell, uri(rdfNs + "first"), items[i]}, spdx.NewMetaL(lines[i])); err != nil {
+			return nil, 0, err
+		}
+		if err := p.handle(&Statement{cell, uri(rdfNs + "rest"), list}, spdx.NewMetaL(lines[i])); err != nil {
+			return nil, 0, err
+		}
+		list = cell
+	}
+	return list, line, nil
+}
@@ -0,0 +1,128 @@
+package rdf
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"strings"
+	"testing"
+)
+
+// Parse Turtle and return the statements and their lines.
+func parseTurtleString(t *testing.T, input string) ([]*Statement, []int) {
+	stms := make([]*Statement, 0)
+	lines := make([]int, 0)
+	err := parseTurtle(strings.NewReader(input), baseUri, nil, func(stm *Statement, meta *spdx.Meta) error {
+		stms = append(stms, stm)
+		lines = append(lines, meta.LineStart)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	return stms, lines
+}
+
+const testTurtle = `# comment
+@prefix : <http://spdx.org/rdf/terms#> .
+PREFIX rdfs: <http://www.w3.org/2000/01/rdf-schema#>
+@base <http://spdx.org/rdf/> .
+
+<terms#doc> a :SpdxDocument ;
+    :dataLicense <../licenses/CC0-1.0> ;
+    rdfs:comment "a \"quoted\"\tcomment"@en ;
+    :creationInfo [
+        :creator "Tool: spdx-go" , 'Person: x'
+    ] ;
+    :noticeText """line 1
+line "2\"""" ;
+    :member ( _:b1 :x ) ;
+    :count 12 , -1.5 , 1e3 , true ;
+    :typed "1"^^<http://www.w3.org/2001/XMLSchema#int> .
+
+_:b1 :name "b1" .
+`
+
+func TestParseTurtle(t *testing.T) {
+	stms, lines := parseTurtleString(t, testTurtle)
+
+	doc := uri(baseUri + "doc")
+	tests := []struct {
+		subj, pred, obj Term
+		line            int
+	}{
+		{doc, uri(rdfNs + "type"), uri(baseUri + "SpdxDocument"), 6},
+		{doc, uri(baseUri + "dataLicense"), uri("http://spdx.org/licenses/CC0-1.0"), 7},
+		{doc, uri("http://www.w3.org/2000/01/rdf-schema#comment"), &Literal{Value: "a \"quoted\"\tcomment", Lang: "en"}, 8},
+		{doc, uri(baseUri + "noticeText"), literal("line 1\nline \"2\""), 12},
+		{doc, uri(baseUri + "count"), &Literal{Value: "12", Datatype: xsdInteger}, 15},
+		{doc, uri(baseUri + "count"), &Literal{Value: "-1.5", Datatype: xsdDecimal}, 15},
+		{doc, uri(baseUri + "count"), &Literal{Value: "1e3", Datatype: xsdDouble}, 15},
+		{doc, uri(baseUri + "count"), &Literal{Value: "true", Datatype: xsdBoolean}, 15},
+		{doc, uri(baseUri + "typed"), &Literal{Value: "1", Datatype: xsdNs + "int"}, 16},
+		{blank("b1"), uri(baseUri + "name"), literal("b1"), 18},
+	}
+	for _, test := range tests {
+		i := findStatement(stms, test.subj, test.pred, test.obj)
+		if i < 0 {
+			t.Errorf("Statement not found: %s %s %s", termStr(test.subj), termStr(test.pred), termStr(test.obj))
+			continue
+		}
+		if lines[i] != test.line {
+			t.Errorf("Wrong line for %s: %d (expected %d)", termStr(test.pred), lines[i], test.line)
+		}
+	}
+
+	var info, list Term
+	for _, stm := range stms {
+		switch termStr(stm.Predicate) {
+		case baseUri + "creationInfo":
+			info = stm.Object
+		case baseUri + "member":
+			list = stm.Object
+		}
+	}
+	if info == nil ||
+		findStatement(stms, info, uri(baseUri+"creator"), literal("Tool: spdx-go")) < 0 ||
+		findStatement(stms, info, uri(baseUri+"creator"), literal("Person: x")) < 0 {
+		t.Error("Blank node property list not parsed.")
+	}
+	if list == nil || findStatement(stms, list, uri(rdfNs+"first"), blank("b1")) < 0 {
+		t.Error("Collection not parsed.")
+	}
+}
+
+func TestParseNtriples(t *testing.T) {
+	input := "<http://example.org/a> <http://example.org/p> \"v\\u00E9\" .\n" +
+		"_:x <http://example.org/p> <http://example.org/b> .\n"
+	stms, lines := parseTurtleString(t, input)
+	if len(stms) != 2 {
+		t.Fatalf("Wrong number of statements: %d", len(stms))
+	}
+	if termStr(stms[0].Object) != "vé" || lines[1] != 2 {
+		t.Errorf("Wrong statements: %s, line %d", termStr(stms[0].Object), lines[1])
+	}
+}
+
+func TestParseTurtleErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		line  int
+	}{
+		{"<a> <b> <c>", 1},
+		{"<a> <b>\n\n\"unterminated\n", 3},
+		{"<a> x:b <c> .", 1},
+		{"<a>\n<b> \"c\"^^\"d\" .", 2},
+		{"<a> <b> \"\\q\" .", 1},
+		{"\n[ <b> <c> .", 2},
+	}
+	for _, test := range tests {
+		err := parseTurtle(strings.NewReader(test.input), baseUri, nil, func(*Statement, *spdx.Meta) error { return nil })
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("Expected *spdx.ParseError for %q, got %v", test.input, err)
+			continue
+		}
+		if perr.LineStart != test.line {
+			t.Errorf("Wrong line for %q: %d (expected %d)", test.input, perr.LineStart, test.line)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Writes statements as Turtle. The statements are kept in memory and written
+// grouped by subject, in the order the subjects were first seen, when the
+// serializer is closed. Blank nodes which are the object of only one
+// statement are written inside it ([ ... ]). The prefixes in rdfPrefixes are
+// defined by default.
+type turtleSerializer struct {
+	out      *bufio.Writer
+	prefixes map[string]string // namespace URI to prefix
+
+	subjects []Term
+	stms     map[string][]*Statement // by termKey() of the subject
+	refs     map[string]int          // number of statements with the node as object
+	written  map[string]bool
+	seen     map[string]bool // statements added, in N-Triples
+}
+
+// Create a new Turtle serializer.
+func newTurtleSerializer(w io.Writer) *turtleSerializer {
+	s := &turtleSerializer{
+		out:      bufio.NewWriter(w),
+		prefixes: make(map[string]string),
+		stms:     make(map[string][]*Statement),
+		refs:     make(map[string]int),
+		written:  make(map[string]bool),
+		seen:     make(map[string]bool),
+	}
+	for short, long := range rdfPrefixes {
+		s.SetNamespace(strings.TrimSuffix(short, ":"), long)
+	}
+	return s
+}
+
+// Use `prefix` for namespace `uri`. A prefix set again replaces the previous
+// namespace.
+func (s *turtleSerializer) SetNamespace(prefix, uri string) {
+	for u, p := range s.prefixes {
+		if p == prefix {
+			delete(s.prefixes, u)
+		}
+	}
+	s.prefixes[uri] = prefix
+}
+
+// Add a statement. Statements already added are ignored.
+func (s *turtleSerializer) Add(stm *Statement) error {
+	line, err := ntriple(stm)
+	if err != nil {
+		return err
+	}
+	if s.seen[line] {
+		return nil
+	}
+	s.seen[line] = true
+
+	key := termKey(stm.Subject)
+	if _, ok := s.stms[key]; !ok {
+		s.subjects = append(s.subjects, stm.Subject)
+	}
+	s.stms[key] = append(s.stms[key], stm)
+	if _, ok := stm.Object.(*Blank); ok {
+		s.refs[termKey(stm.Object)]++
+	}
+	return nil
+}
+
+// Write the statements and flush the output.
+func (s *turtleSerializer) Close() error {
+	uris := make([]string, 0, len(s.prefixes))
+	for u := range s.prefixes {
+		uris = append(uris, u)
+	}
+	sort.Slice(uris, func(i, j int) bool { return s.prefixes[uris[i]] < s.prefixes[uris[j]] })
+	for _, u := range uris {
+		fmt.Fprintf(s.out, "@prefix %s: %s .\n", s.prefixes[u], ntriplesTerm(uri(u)))
+	}
+
+	for _, subj := range s.subjects {
+		if !s.nested(subj) {
+			s.subject(subj)
+		}
+	}
+	// blank nodes only referred to from each other
+	for _, subj := range s.subjects {
+		if !s.written[termKey(subj)] {
+			s.subject(subj)
+		}
+	}
+	return s.out.Flush()
+}
+
+// Checks if the node is written inside the statement referring to it.
+func (s *turtleSerializer) nested(t Term) bool {
+	key := termKey(t)
+	_, isBlank := t.(*Blank)
+	return isBlank && s.refs[key] == 1 && len(s.stms[key]) > 0
+}
+
+// Write the statements of a subject.
+func (s *turtleSerializer) subject(subj Term) {
+	s.written[termKey(subj)] = true
+	s.out.WriteString("\n" + s.term(subj) + "\n")
+	s.predicates(subj, 1)
+	s.out.WriteString(" .\n")
+}
+
+// Write the predicates and objects of `subj`, with the objects of the same
+// predicate grouped, in the order the predicates were first seen.
+func (s *turtleSerializer) predicates(subj Term, depth int) {
+	indent := strings.Repeat("    ", depth)
+	preds := make([]string, 0)
+	objects := make(map[string][]Term)
+	for _, stm := range s.stms[termKey(subj)] {
+		pred := s.term(stm.Predicate)
+		if stm.Predicate.Equals(uri(rdfNs + "type")) {
+			pred = "a"
+		}
+		if _, ok := objects[pred]; !ok {
+			preds = append(preds, pred)
+		}
+		objects[pred] = append(objects[pred], stm.Object)
+	}
+
+	for i, pred := range preds {
+		if i > 0 {
+			s.out.WriteString(" ;\n")
+		}
+		s.out.WriteString(indent + pred + " ")
+		for j, obj := range objects[pred] {
+			nested := s.nested(obj) && !s.written[termKey(obj)]
+			if j > 0 && nested {
+				s.out.WriteString(" , ")
+			} else if j > 0 {
+				s.out.WriteString(" ,\n" + indent + "    ")
+			}
+			if nested {
+				s.written[termKey(obj)] = true
+				s.out.WriteString("[\n")
+				s.predicates(obj, depth+1)
+				s.out.WriteString("\n" + indent + "]")
+				continue
+			}
+			s.out.WriteString(s.term(obj))
+		}
+	}
+}
+
+// Returns a term in Turtle syntax, using the prefixes where possible.
+func (s *turtleSerializer) term(t Term) string {
+	switch v := t.(type) {
+	case *Uri:
+		str := string(*v)
+		ns := ""
+		for u := range s.prefixes {
+			if strings.HasPrefix(str, u) && len(u) > len(ns) && isLocalName(str[len(u):]) {
+				ns = u
+			}
+		}
+		if ns != "" {
+			return s.prefixes[ns] + ":" + str[len(ns):]
+		}
+	case *Literal:
+		if strings.ContainsAny(v.Value, "\n\r") {
+			res := `"""` + longStringEscaper.Replace(v.Value) + `"""`
+			if v.Lang != "" {
+				return res + "@" + v.Lang
+			}
+			if v.Datatype != "" {
+				return res + "^^" + s.term(uri(v.Datatype))
+			}
+			return res
+		}
+		if v.Datatype != "" && v.Lang == "" {
+			return quoteString(v.Value) + "^^" + s.term(uri(v.Datatype))
+		}
+	}
+	return ntriplesTerm(t)
+}
+
+// Checks if `str` can be written as the local part of a prefixed name,
+// without escapes.
+func isLocalName(str string) bool {
+	for i, r := range str {
+		if !isNameChar(r) || (i == 0 && (r == '-' || r == '.')) {
+			return false
+		}
+	}
+	return !strings.HasSuffix(str, ".")
+}
+
+// Writes statements as N-Triples, one statement per line, as they are added.
+type ntriplesSerializer struct {
+	out  *bufio.Writer
+	seen map[string]bool
+}
+
+// Create a new N-Triples serializer.
+func newNtriplesSerializer(w io.Writer) *ntriplesSerializer {
+	return &ntriplesSerializer{
+		out:  bufio.NewWriter(w),
+		seen: make(map[string]bool),
+	}
+}
+
+// N-Triples has no prefixes.
+func (s *ntriplesSerializer) SetNamespace(prefix, uri string) {}
+
+// Write a statement. Statements already written are ignored.
+func (s *ntriplesSerializer) Add(stm *Statement) error {
+	line, err := ntriple(stm)
+	if err != nil || s.seen[line] {
+		return err
+	}
+	s.seen[line] = true
+	_, err = s.out.WriteString(line + "\n")
+	return err
+}
+
+// Flush the output.
+func (s *ntriplesSerializer) Close() error {
+	return s.out.Flush()
+}
+
+// Returns the statement as a N-Triples line, without the newline.
+func ntriple(stm *Statement) (string, error) {
+	if _, ok := stm.Subject.(*Literal); ok {
+		return "", fmt.Errorf("Invalid subject %s.", termStr(stm.Subject))
+	}
+	if _, ok := stm.Predicate.(*Uri); !ok {
+		return "", fmt.Errorf("Invalid predicate %s.", termStr(stm.Predicate))
+	}
+	return ntriplesTerm(stm.Subject) + " " + ntriplesTerm(stm.Predicate) + " " + ntriplesTerm(stm.Object) + " .", nil
+}
+
+var (
+	stringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+	// in long strings, all quotes are escaped so none ends the string
+	longStringEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\r", `\r`)
+)
+
+// Returns a string in double quotes with escapes.
+func quoteString(str string) string {
+	return `"` + stringEscaper.Replace(str) + `"`
+}
+
+// Escape the characters not allowed in IRIs with \u escapes.
+func escapeIri(iri string) string {
+	var buf strings.Builder
+	for _, r := range iri {
+		if r <= ' ' || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&buf, "\\u%04X", r)
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// Returns a term in N-Triples syntax.
+func ntriplesTerm(t Term) string {
+	switch v := t.(type) {
+	case *Uri:
+		return "<" + escapeIri(string(*v)) + ">"
+	case *Blank:
+		return "_:" + blankLabel(string(*v))
+	case *Literal:
+		res := quoteString(v.Value)
+		if v.Lang != "" {
+			return res + "@" + v.Lang
+		}
+		if v.Datatype != "" {
+			return res + "^^" + ntriplesTerm(uri(v.Datatype))
+		}
+		return res
+	}
+	return ""
+}
+
+// Returns a valid blank node label for the node ID. Characters not allowed in
+// labels are replaced with "_".
+func blankLabel(id string) string {
+	if id == "" {
+		return "_"
+	}
+	res := []rune(id)
+	for i, r := range res {
+		if !isNameChar(r) || (i == 0 && r != '_' && !isNameStart(r) && (r < '0' || r > '9')) {
+			res[i] = '_'
+		}
+	}
+	if res[len(res)-1] == '.' {
+		res[len(res)-1] = '_'
+	}
+	return string(res)
+}
@@ -0,0 +1,107 @@
+package rdf
+
+import (
+	"bytes"
+	"github.com/spdx/tools-go/spdx"
+	"strings"
+	"testing"
+)
+
+// Statements to write, with the blank node "info" referred to once and
+// "shared" referred to twice.
+func testWriterStatements() []*Statement {
+	doc := uri(baseUri + "doc")
+	info := blank("info")
+	shared := blank("shared")
+	return []*Statement{
+		{doc, uri(rdfNs + "type"), uri(baseUri + "SpdxDocument")},
+		{doc, uri(baseUri + "creationInfo"), info},
+		{info, uri(baseUri + "creator"), literal("Tool: \"spdx-go\" \\")},
+		{info, uri(baseUri + "creator"), literal("Person: x")},
+		{doc, uri(baseUri + "dataLicense"), uri("http://spdx.org/licenses/CC0-1.0")},
+		{doc, uri("http://example.org/a b"), &Literal{Value: "v", Lang: "en"}},
+		{doc, uri(baseUri + "notice"), literal("line 1\nline \"\"\"2")},
+		{doc, uri(baseUri + "count"), &Literal{Value: "1", Datatype: xsdInteger}},
+		{doc, uri(baseUri + "a"), shared},
+		{doc, uri(baseUri + "b"), shared},
+		{shared, uri(baseUri + "name"), literal("s")},
+		{doc, uri(baseUri + "a"), shared}, // duplicate
+	}
+}
+
+// Write the statements with the serializer and parse the output back.
+func writeParseTurtle(t *testing.T, s serializer, out *bytes.Buffer) []*Statement {
+	stms := testWriterStatements()
+	for _, stm := range stms {
+		if err := s.Add(stm); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	parsed := make([]*Statement, 0)
+	err := parseTurtle(bytes.NewReader(out.Bytes()), baseUri, nil, func(stm *Statement, meta *spdx.Meta) error {
+		parsed = append(parsed, stm)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Cannot parse the output: %s\n%s", err, out.String())
+	}
+	if len(parsed) != len(stms)-1 {
+		t.Errorf("Wrong number of statements: %d\n%s", len(parsed), out.String())
+	}
+	return parsed
+}
+
+func TestTurtleSerializer(t *testing.T) {
+	var buf bytes.Buffer
+	parsed := writeParseTurtle(t, newTurtleSerializer(&buf), &buf)
+	out := buf.String()
+
+	for _, stm := range testWriterStatements() {
+		if stm.Subject.Equals(blank("info")) || stm.Object.Equals(blank("info")) {
+			// nested, without node ID
+			continue
+		}
+		if findStatement(parsed, stm.Subject, stm.Predicate, stm.Object) < 0 {
+			t.Errorf("Statement not found: %s %s %s\n%s", termStr(stm.Subject), termStr(stm.Predicate), termStr(stm.Object), out)
+		}
+	}
+	for _, expected := range []string{"@prefix : <" + baseUri + "> .", "a :SpdxDocument", ":creationInfo [", `"""line 1`, "_:shared"} {
+		if !strings.Contains(out, expected) {
+			t.Errorf("Expected %q in the output:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(out, "_:info") {
+		t.Errorf("Nodes referred to once must be nested:\n%s", out)
+	}
+}
+
+func TestNtriplesSerializer(t *testing.T) {
+	var buf bytes.Buffer
+	parsed := writeParseTurtle(t, newNtriplesSerializer(&buf), &buf)
+	for _, stm := range testWriterStatements() {
+		if findStatement(parsed, stm.Subject, stm.Predicate, stm.Object) < 0 {
+			t.Errorf("Statement not found: %s %s %s\n%s", termStr(stm.Subject), termStr(stm.Predicate), termStr(stm.Object), buf.String())
+		}
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(parsed) {
+		t.Errorf("Expected one line per statement:\n%s", buf.String())
+	}
+}
+
+func TestBlankLabel(t *testing.T) {
+	tests := map[string]string{
+		"doc":          "doc",
+		"LicenseRef-1": "LicenseRef-1",
+		"-a b.":        "_a_b_",
+		"":             "_",
+	}
+	for id, expected := range tests {
+		if label := blankLabel(id); label != expected {
+			t.Errorf("Wrong label for %q: %q (expected %q)", id, label, expected)
+		}
+	}
+}
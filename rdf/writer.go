@@ -2,7 +2,6 @@ package rdf
 
 import (
 	"errors"
-	"github.com/deltamobile/goraptor"
 	"github.com/spdx/tools-go/spdx"
 	"os"
 	"strconv"
@@ -10,39 +9,35 @@ import (
 )
 
 // Writes the input to the specified RDF format. Does not parse the RDF file into a
-// spdx.Document struct and only converts between RDF formats using goraptor.
+// spdx.Document struct and only converts between RDF formats.
 func WriteRdf(input *os.File, output *os.File, formatIn, formatOut string) error {
-	if formatIn == "rdf" {
-		formatIn = "guess"
-	}
 	if formatOut == "rdf" {
 		formatOut = Fmt_rdfxmlAbbrev
 	}
-	parser := goraptor.NewParser(formatIn)
-	defer parser.Free()
-
-	serializer := goraptor.NewSerializer(formatOut)
-	defer serializer.Free()
-
-	parser.SetNamespaceHandler(func(pfx, uri string) { serializer.SetNamespace(pfx, uri) })
-
-	statements := parser.Parse(input, baseUri)
-	err := serializer.SetFile(output, baseUri)
+	s, err := newFormatSerializer(output, formatOut)
 	if err != nil {
 		return err
 	}
-	return serializer.AddN(statements)
+	setNamespace := func(pfx, uri string) { s.SetNamespace(pfx, uri) }
+	add := func(stm *Statement, meta *spdx.Meta) error { return s.Add(stm) }
+	if err = parseFormat(input, formatIn, setNamespace, add); err != nil {
+		s.Close()
+		return err
+	}
+	return s.Close()
 }
 
 // Writes a SPDX Document to rdf/xml abbreviated format.
 func Write(output *os.File, doc *spdx.Document) error {
 	f := NewFormatter(output, Fmt_rdfxmlAbbrev)
 	_, err := f.Document(doc)
-	f.Close()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
 	return err
 }
 
-// Writes a SPDX Document to raptor format. format must be one of the format
+// Writes a SPDX Document to a RDF format. format must be one of the format
 // constants (Fmt_*)
 func WriteFormat(output *os.File, doc *spdx.Document, format string) error {
 	if format == "rdf" {
@@ -52,64 +47,72 @@ func WriteFormat(output *os.File, doc *spdx.Document, format string) error {
 	}
 	f := NewFormatter(output, format)
 	_, err := f.Document(doc)
-	f.Close()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
 	return err
 }
 
 // Used to write SPDX Documents in RDF format
 type Formatter struct {
-	serializer *goraptor.Serializer
+	serializer serializer
+	err        error // error creating the serializer
 	nodeIds    map[string]int
 
 	// index file nodes by name
-	fileIds map[string]goraptor.Term
+	fileIds map[string]Term
 }
 
-// Create a new Formatter that writes to output
+// Create a new Formatter that writes to output. If the format is not supported
+// by the RDF backend, writing returns an error.
 func NewFormatter(output *os.File, format string) *Formatter {
-	s := goraptor.NewSerializer(format)
-	s.StartStream(output, baseUri)
-
-	s.SetNamespace("rdf", "http://www.w3.org/1999/02/22-rdf-syntax-ns#")
-	s.SetNamespace("", "http://spdx.org/rdf/terms#")
-	s.SetNamespace("rdfs", "http://www.w3.org/2000/01/rdf-schema#")
-	s.SetNamespace("doap", "http://usefulinc.com/ns/doap#")
+	s, err := newFormatSerializer(output, format)
+	if err == nil {
+		s.SetNamespace("rdf", "http://www.w3.org/1999/02/22-rdf-syntax-ns#")
+		s.SetNamespace("", "http://spdx.org/rdf/terms#")
+		s.SetNamespace("rdfs", "http://www.w3.org/2000/01/rdf-schema#")
+		s.SetNamespace("doap", "http://usefulinc.com/ns/doap#")
+	}
 
 	return &Formatter{
 		serializer: s,
+		err:        err,
 		nodeIds:    make(map[string]int),
-		fileIds:    make(map[string]goraptor.Term),
+		fileIds:    make(map[string]Term),
 	}
 }
 
 // Create a new node id for the given prefix
-func (f *Formatter) newId(prefix string) *goraptor.Blank {
+func (f *Formatter) newId(prefix string) *Blank {
 	f.nodeIds[prefix]++
-	id := goraptor.Blank(prefix + strconv.Itoa(f.nodeIds[prefix]))
+	id := Blank(prefix + strconv.Itoa(f.nodeIds[prefix]))
 	return &id
 }
 
 // Sets the type t to node
-func (f *Formatter) setType(node, t goraptor.Term) error {
+func (f *Formatter) setType(node, t Term) error {
 	return f.add(node, prefix("ns:type"), t)
 }
 
 // Add `key`=`value` at object `to`.
-func (f *Formatter) add(to, key, value goraptor.Term) error {
-	return f.serializer.Add(&goraptor.Statement{
+func (f *Formatter) add(to, key, value Term) error {
+	if f.err != nil {
+		return f.err
+	}
+	return f.serializer.Add(&Statement{
 		Subject:   to,
 		Predicate: key,
 		Object:    value,
 	})
 }
 
-// Using the SPDX baseUri, add a goraptor.Term.
-func (f *Formatter) addTerm(to goraptor.Term, key string, value goraptor.Term) error {
+// Using the SPDX baseUri, add a Term.
+func (f *Formatter) addTerm(to Term, key string, value Term) error {
 	return f.add(to, prefix(key), value)
 }
 
 // Using the SPDX baseUri, add pairs of literals.
-func (f *Formatter) addPairs(to goraptor.Term, pairs ...pair) error {
+func (f *Formatter) addPairs(to Term, pairs ...pair) error {
 	for _, p := range pairs {
 		if err := f.addLiteral(to, p.key, p.val); err != nil {
 			return err
@@ -120,15 +123,15 @@ func (f *Formatter) addPairs(to goraptor.Term, pairs ...pair) error {
 
 // Using the SPDX baseUri, add one literal. Does not write anything and returns
 // nil if the value is empty.
-func (f *Formatter) addLiteral(to goraptor.Term, key, value string) error {
+func (f *Formatter) addLiteral(to Term, key, value string) error {
 	if value == "" {
 		return nil
 	}
-	return f.add(to, prefix(key), &goraptor.Literal{Value: value})
+	return f.add(to, prefix(key), &Literal{Value: value})
 }
 
 // Write a document.
-func (f *Formatter) Document(doc *spdx.Document) (docId goraptor.Term, err error) {
+func (f *Formatter) Document(doc *spdx.Document) (docId Term, err error) {
 	if doc == nil {
 		return nil, errors.New("Cannot print nil document.")
 	}
@@ -181,7 +184,7 @@ func (f *Formatter) Document(doc *spdx.Document) (docId goraptor.Term, err error
 }
 
 // Write creation info.
-func (f *Formatter) CreationInfo(cr *spdx.CreationInfo) (id goraptor.Term, err error) {
+func (f *Formatter) CreationInfo(cr *spdx.CreationInfo) (id Term, err error) {
 	id = f.newId("cri")
 
 	if err = f.setType(id, typeCreationInfo); err != nil {
@@ -208,7 +211,7 @@ func (f *Formatter) CreationInfo(cr *spdx.CreationInfo) (id goraptor.Term, err e
 }
 
 // Write a slice of reviews.
-func (f *Formatter) Reviews(parent goraptor.Term, element string, rs []*spdx.Review) error {
+func (f *Formatter) Reviews(parent Term, element string, rs []*spdx.Review) error {
 	if len(rs) == 0 {
 		return nil
 	}
@@ -228,7 +231,7 @@ func (f *Formatter) Reviews(parent goraptor.Term, element string, rs []*spdx.Rev
 }
 
 // Write a review.
-func (f *Formatter) Review(r *spdx.Review) (id goraptor.Term, err error) {
+func (f *Formatter) Review(r *spdx.Review) (id Term, err error) {
 	id = f.newId("rev")
 
 	if err = f.setType(id, typeReview); err != nil {
@@ -245,7 +248,7 @@ func (f *Formatter) Review(r *spdx.Review) (id goraptor.Term, err error) {
 }
 
 // Write a slice of packages.
-func (f *Formatter) Packages(parent goraptor.Term, element string, pkgs []*spdx.Package) error {
+func (f *Formatter) Packages(parent Term, element string, pkgs []*spdx.Package) error {
 	if len(pkgs) == 0 {
 		return nil
 	}
@@ -262,7 +265,7 @@ func (f *Formatter) Packages(parent goraptor.Term, element string, pkgs []*spdx.
 }
 
 // Write a package.
-func (f *Formatter) Package(pkg *spdx.Package) (id goraptor.Term, err error) {
+func (f *Formatter) Package(pkg *spdx.Package) (id Term, err error) {
 	id = f.newId("pkg")
 
 	if err = f.setType(id, typePackage); err != nil {
@@ -332,7 +335,7 @@ func (f *Formatter) Package(pkg *spdx.Package) (id goraptor.Term, err error) {
 }
 
 // Write a VerificationCode
-func (f *Formatter) VerificationCode(vc *spdx.VerificationCode) (id goraptor.Term, err error) {
+func (f *Formatter) VerificationCode(vc *spdx.VerificationCode) (id Term, err error) {
 	id = f.newId("vc")
 
 	if err = f.setType(id, typeVerificationCode); err != nil {
@@ -355,7 +358,7 @@ func (f *Formatter) VerificationCode(vc *spdx.VerificationCode) (id goraptor.Ter
 }
 
 // Write a Checksum
-func (f *Formatter) Checksum(cksum *spdx.Checksum) (id goraptor.Term, err error) {
+func (f *Formatter) Checksum(cksum *spdx.Checksum) (id Term, err error) {
 	id = f.newId("cksum")
 
 	if err = f.setType(id, typeChecksum); err != nil {
@@ -378,7 +381,7 @@ func (f *Formatter) Checksum(cksum *spdx.Checksum) (id goraptor.Term, err error)
 }
 
 // Write a slice of AnyLicence
-func (f *Formatter) Licences(parent goraptor.Term, element string, lics []spdx.AnyLicence) error {
+func (f *Formatter) Licences(parent Term, element string, lics []spdx.AnyLicence) error {
 	if len(lics) == 0 {
 		return nil
 	}
@@ -398,7 +401,7 @@ func (f *Formatter) Licences(parent goraptor.Term, element string, lics []spdx.A
 }
 
 // Write AnyLicence
-func (f *Formatter) Licence(licence spdx.AnyLicence) (id goraptor.Term, err error) {
+func (f *Formatter) Licence(licence spdx.AnyLicence) (id Term, err error) {
 	switch lic := licence.(type) {
 	case spdx.Licence:
 		val := lic.LicenceId()
@@ -443,7 +446,7 @@ func (f *Formatter) Licence(licence spdx.AnyLicence) (id goraptor.Term, err erro
 }
 
 // Write a slice of ExtractedLicence
-func (f *Formatter) ExtrLicInfos(parent goraptor.Term, element string, lics []*spdx.ExtractedLicence) error {
+func (f *Formatter) ExtrLicInfos(parent Term, element string, lics []*spdx.ExtractedLicence) error {
 	if len(lics) == 0 {
 		return nil
 	}
@@ -460,7 +463,7 @@ func (f *Formatter) ExtrLicInfos(parent goraptor.Term, element string, lics []*s
 }
 
 // Write an ExtractedLicence
-func (f *Formatter) ExtrLicInfo(lic *spdx.ExtractedLicence) (id goraptor.Term, err error) {
+func (f *Formatter) ExtrLicInfo(lic *spdx.ExtractedLicence) (id Term, err error) {
 	id = blank(lic.LicenceId())
 	if lic.LicenceId() == "" {
 		id = f.newId("LicenseRef-spdxGoGenId")
@@ -496,7 +499,7 @@ func (f *Formatter) ExtrLicInfo(lic *spdx.ExtractedLicence) (id goraptor.Term, e
 }
 
 // Write a slice of files.
-func (f *Formatter) Files(parent goraptor.Term, element string, files []*spdx.File) error {
+func (f *Formatter) Files(parent Term, element string, files []*spdx.File) error {
 	if len(files) == 0 {
 		return nil
 	}
@@ -516,7 +519,7 @@ func (f *Formatter) Files(parent goraptor.Term, element string, files []*spdx.Fi
 }
 
 // Write a file.
-func (f *Formatter) File(file *spdx.File) (id goraptor.Term, err error) {
+func (f *Formatter) File(file *spdx.File) (id Term, err error) {
 	id, ok := f.fileIds[file.Name.Val]
 	if ok {
 		return
@@ -577,7 +580,9 @@ func (f *Formatter) File(file *spdx.File) (id goraptor.Term, err error) {
 
 // Closes the stream and frees the serializer. Always call after writing using
 // the Formatter.
-func (f *Formatter) Close() {
-	f.serializer.EndStream()
-	f.serializer.Free()
+func (f *Formatter) Close() error {
+	if f.err != nil {
+		return nil
+	}
+	return f.serializer.Close()
 }
@@ -0,0 +1,375 @@
+package rdf
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Error message for predicate URIs that cannot be written as XML names.
+const msgXmlInvalidPredicate = "Predicate %s cannot be written in RDF/XML."
+
+// Writes statements as RDF/XML. The statements are kept in memory and
+// written when the serializer is closed.
+//
+// In the abbreviated form, nodes get typed elements and blank nodes which are
+// the object of only one statement are written inside its property element.
+// Otherwise, the statements are written in the order they were added, in flat
+// rdf:Description elements grouping the consecutive statements of a subject.
+type xmlSerializer struct {
+	out    *bufio.Writer
+	abbrev bool
+
+	prefixes map[string]string // namespace URI to prefix
+
+	order    []*Statement            // in the order they were added
+	subjects []Term                  // in the order they were first seen
+	stms     map[string][]*Statement // by termKey() of the subject
+	refs     map[string]int          // number of statements with the node as object
+	written  map[string]bool
+
+	names map[string]string // qualified names of URIs
+	err   error
+}
+
+// Create a new RDF/XML serializer. If `abbrev` is set, the abbreviated form
+// of RDF/XML is written.
+func newXmlSerializer(w io.Writer, abbrev bool) *xmlSerializer {
+	return &xmlSerializer{
+		out:      bufio.NewWriter(w),
+		abbrev:   abbrev,
+		prefixes: make(map[string]string),
+		stms:     make(map[string][]*Statement),
+		refs:     make(map[string]int),
+		written:  make(map[string]bool),
+		names:    make(map[string]string),
+	}
+}
+
+// Returns a key identifying the node `t` in the serializer's maps.
+func termKey(t Term) string {
+	switch v := t.(type) {
+	case *Uri:
+		return "u" + string(*v)
+	case *Blank:
+		return "b" + string(*v)
+	}
+	return ""
+}
+
+// Use `prefix` for namespace `uri`. An empty prefix makes it the default
+// namespace. A prefix set again replaces the previous namespace.
+func (s *xmlSerializer) SetNamespace(prefix, uri string) {
+	if prefix == "xml" || prefix == "xmlns" {
+		return
+	}
+	for u, p := range s.prefixes {
+		if p == prefix {
+			delete(s.prefixes, u)
+		}
+	}
+	s.prefixes[uri] = prefix
+}
+
+// Add a statement. Literal subjects are not valid RDF. Statements already
+// added are ignored, as a RDF graph is a set of statements.
+func (s *xmlSerializer) Add(stm *Statement) error {
+	key := termKey(stm.Subject)
+	if key == "" {
+		return fmt.Errorf("Invalid subject %s.", termStr(stm.Subject))
+	}
+	if _, ok := stm.Predicate.(*Uri); !ok {
+		return fmt.Errorf("Invalid predicate %s.", termStr(stm.Predicate))
+	}
+	if _, ok := s.stms[key]; !ok {
+		s.subjects = append(s.subjects, stm.Subject)
+	}
+	for _, other := range s.stms[key] {
+		if other.Predicate.Equals(stm.Predicate) && other.Object.Equals(stm.Object) {
+			return nil
+		}
+	}
+	s.stms[key] = append(s.stms[key], stm)
+	s.order = append(s.order, stm)
+	if _, ok := stm.Object.(*Blank); ok {
+		s.refs[termKey(stm.Object)]++
+	}
+	return nil
+}
+
+// Write all the statements and flush the output.
+func (s *xmlSerializer) Close() error {
+	if err := s.qualifyNames(); err != nil {
+		return err
+	}
+
+	s.write(`<?xml version="1.0" encoding="utf-8"?>` + "\n")
+	s.write("<" + s.rdf("RDF"))
+	uris := make([]string, 0, len(s.prefixes))
+	for u := range s.prefixes {
+		uris = append(uris, u)
+	}
+	sort.Slice(uris, func(i, j int) bool { return s.prefixes[uris[i]] < s.prefixes[uris[j]] })
+	for _, u := range uris {
+		attr := "xmlns"
+		if p := s.prefixes[u]; p != "" {
+			attr += ":" + p
+		}
+		s.write("\n    " + attr + `="` + escapeAttr(u) + `"`)
+	}
+	s.write(">\n")
+
+	if s.abbrev {
+		for _, subj := range s.subjects {
+			if !s.nested(subj) {
+				s.node(subj, 1)
+			}
+		}
+		// blank nodes only referred to from each other
+		for _, subj := range s.subjects {
+			if !s.written[termKey(subj)] {
+				s.node(subj, 1)
+			}
+		}
+	} else {
+		for i := 0; i < len(s.order); {
+			j := i + 1
+			for j < len(s.order) && s.order[j].Subject.Equals(s.order[i].Subject) {
+				j++
+			}
+			s.description(s.order[i:j])
+			i = j
+		}
+	}
+
+	s.write("</" + s.rdf("RDF") + ">\n")
+	if s.err != nil {
+		return s.err
+	}
+	return s.out.Flush()
+}
+
+// Checks if the node is written inside the property element referring to it.
+func (s *xmlSerializer) nested(t Term) bool {
+	key := termKey(t)
+	_, isBlank := t.(*Blank)
+	return s.abbrev && isBlank && s.refs[key] == 1 && len(s.stms[key]) > 0
+}
+
+// Find the qualified names of all predicates and types, adding namespaces
+// where needed.
+func (s *xmlSerializer) qualifyNames() error {
+	if _, ok := s.prefixes[rdfNs]; !ok {
+		s.SetNamespace(s.freePrefix("rdf"), rdfNs)
+	}
+	s.names[rdfNs] = s.prefixes[rdfNs]
+
+	for _, subj := range s.subjects {
+		for _, stm := range s.stms[termKey(subj)] {
+			pred := string(*stm.Predicate.(*Uri))
+			if _, err := s.qname(pred); err != nil {
+				return err
+			}
+			if s.abbrev && pred == rdfNs+"type" {
+				if t, ok := stm.Object.(*Uri); ok {
+					s.qname(string(*t))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Returns a prefix based on `want` that is not used yet.
+func (s *xmlSerializer) freePrefix(want string) string {
+	used := make(map[string]bool)
+	for _, p := range s.prefixes {
+		used[p] = true
+	}
+	if want != "" && !used[want] {
+		return want
+	}
+	for i := 1; ; i++ {
+		if p := "ns" + strconv.Itoa(i); !used[p] {
+			return p
+		}
+	}
+}
+
+// Returns the qualified name of the URI, declaring a new namespace for it if
+// no namespace matches.
+func (s *xmlSerializer) qname(uri string) (string, error) {
+	if n, ok := s.names[uri]; ok {
+		return n, nil
+	}
+
+	ns, local := "", ""
+	for u := range s.prefixes {
+		if strings.HasPrefix(uri, u) && len(u) > len(ns) && isNCName(uri[len(u):]) {
+			ns, local = u, uri[len(u):]
+		}
+	}
+	if ns == "" {
+		i := len(uri) - 1
+		for i >= 0 && isNameChar(rune(uri[i])) {
+			i--
+		}
+		for i+1 < len(uri) && !isNameStart(rune(uri[i+1])) {
+			i++
+		}
+		if i+1 >= len(uri) || i < 0 {
+			return "", fmt.Errorf(msgXmlInvalidPredicate, uri)
+		}
+		ns, local = uri[:i+1], uri[i+1:]
+		s.SetNamespace(s.freePrefix(""), ns)
+	}
+
+	name := local
+	if p := s.prefixes[ns]; p != "" {
+		name = p + ":" + local
+	}
+	s.names[uri] = name
+	return name, nil
+}
+
+// Returns the qualified name of rdf:`local`.
+func (s *xmlSerializer) rdf(local string) string {
+	if p := s.names[rdfNs]; p != "" {
+		return p + ":" + local
+	}
+	return local
+}
+
+// Write statements with the same subject as a rdf:Description element.
+func (s *xmlSerializer) description(stms []*Statement) {
+	elem := s.rdf("Description")
+	s.write("  <" + elem)
+	switch t := stms[0].Subject.(type) {
+	case *Uri:
+		s.write(" " + s.rdf("about") + `="` + escapeAttr(string(*t)) + `"`)
+	case *Blank:
+		s.write(" " + s.rdf("nodeID") + `="` + escapeAttr(string(*t)) + `"`)
+	}
+	s.write(">\n")
+	for _, stm := range stms {
+		s.property(stm, 2)
+	}
+	s.write("  </" + elem + ">\n")
+}
+
+// Write the node element of `subj` and its properties.
+func (s *xmlSerializer) node(subj Term, depth int) {
+	key := termKey(subj)
+	s.written[key] = true
+	indent := strings.Repeat("  ", depth)
+
+	elem := s.rdf("Description")
+	stms := s.stms[key]
+	if s.abbrev {
+		for i, stm := range stms {
+			t, ok := stm.Object.(*Uri)
+			if !ok || string(*stm.Predicate.(*Uri)) != rdfNs+"type" {
+				continue
+			}
+			if name, ok := s.names[string(*t)]; ok {
+				elem = name
+				stms = append(stms[:i:i], stms[i+1:]...)
+				break
+			}
+		}
+	}
+
+	s.write(indent + "<" + elem)
+	switch t := subj.(type) {
+	case *Uri:
+		s.write(" " + s.rdf("about") + `="` + escapeAttr(string(*t)) + `"`)
+	case *Blank:
+		if depth == 1 || s.refs[key] != 1 {
+			s.write(" " + s.rdf("nodeID") + `="` + escapeAttr(string(*t)) + `"`)
+		}
+	}
+	if len(stms) == 0 {
+		s.write("/>\n")
+		return
+	}
+	s.write(">\n")
+	for _, stm := range stms {
+		s.property(stm, depth+1)
+	}
+	s.write(indent + "</" + elem + ">\n")
+}
+
+// Write a property element.
+func (s *xmlSerializer) property(stm *Statement, depth int) {
+	indent := strings.Repeat("  ", depth)
+	name := s.names[string(*stm.Predicate.(*Uri))]
+	s.write(indent + "<" + name)
+
+	switch obj := stm.Object.(type) {
+	case *Uri:
+		s.write(" " + s.rdf("resource") + `="` + escapeAttr(string(*obj)) + `"/>` + "\n")
+	case *Blank:
+		if s.nested(obj) && !s.written[termKey(obj)] {
+			s.write(">\n")
+			s.node(obj, depth+1)
+			s.write(indent + "</" + name + ">\n")
+			return
+		}
+		s.write(" " + s.rdf("nodeID") + `="` + escapeAttr(string(*obj)) + `"/>` + "\n")
+	case *Literal:
+		if obj.Datatype == xmlLiteral {
+			s.write(" " + s.rdf("parseType") + `="Literal">` + obj.Value + "</" + name + ">\n")
+			return
+		}
+		if obj.Lang != "" {
+			s.write(` xml:lang="` + escapeAttr(obj.Lang) + `"`)
+		}
+		if obj.Datatype != "" {
+			s.write(" " + s.rdf("datatype") + `="` + escapeAttr(obj.Datatype) + `"`)
+		}
+		s.write(">" + escapeText(obj.Value) + "</" + name + ">\n")
+	}
+}
+
+// Write a string to the output, keeping the first error.
+func (s *xmlSerializer) write(str string) {
+	if s.err == nil {
+		_, s.err = s.out.WriteString(str)
+	}
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#13;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#13;",
+		`"`, "&quot;", "\n", "&#10;", "\t", "&#9;")
+)
+
+// Escape XML character data.
+func escapeText(str string) string { return textEscaper.Replace(str) }
+
+// Escape a XML attribute value (in double quotes).
+func escapeAttr(str string) string { return attrEscaper.Replace(str) }
+
+// Checks if `r` can start a XML name (without namespace).
+func isNameStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+// Checks if `r` can be part of a XML name (without namespace).
+func isNameChar(r rune) bool {
+	return isNameStart(r) || r == '-' || r == '.' || unicode.IsDigit(r)
+}
+
+// Checks if `str` is a XML name without namespace.
+func isNCName(str string) bool {
+	for i, r := range str {
+		if !isNameChar(r) || (i == 0 && !isNameStart(r)) {
+			return false
+		}
+	}
+	return str != ""
+}
@@ -0,0 +1,93 @@
+package rdf
+
+import (
+	"bytes"
+	"github.com/spdx/tools-go/spdx"
+	"strings"
+	"testing"
+)
+
+// Write the statements with a xmlSerializer and parse them back.
+func writeParseXml(t *testing.T, abbrev bool, stms []*Statement) (string, []*Statement) {
+	var buf bytes.Buffer
+	s := newXmlSerializer(&buf, abbrev)
+	s.SetNamespace("", baseUri)
+	for _, stm := range stms {
+		if err := s.Add(stm); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	parsed := make([]*Statement, 0)
+	err := parseXml(bytes.NewReader(buf.Bytes()), baseUri, false, nil, func(stm *Statement, meta *spdx.Meta) error {
+		parsed = append(parsed, stm)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Cannot parse the output: %s\n%s", err, buf.String())
+	}
+	return buf.String(), parsed
+}
+
+func TestXmlSerializer(t *testing.T) {
+	doc := blank("doc")
+	info := blank("info")
+	shared := blank("shared")
+	stms := []*Statement{
+		{doc, uri(rdfNs + "type"), uri(baseUri + "SpdxDocument")},
+		{doc, uri(baseUri + "creationInfo"), info},
+		{info, uri(baseUri + "creator"), literal("Tool: <spdx-go> & \"more\"")},
+		{doc, uri(baseUri + "dataLicense"), uri("http://spdx.org/licenses/CC0-1.0")},
+		{doc, uri("http://example.org/ns/other"), &Literal{Value: "v", Lang: "en"}},
+		{doc, uri(baseUri + "notice"), &Literal{Value: "<b>x</b>", Datatype: xmlLiteral}},
+		{doc, uri(baseUri + "a"), shared},
+		{doc, uri(baseUri + "b"), shared},
+		{shared, uri(baseUri + "name"), literal("line\nbreak")},
+		{doc, uri(baseUri + "a"), shared}, // duplicate
+	}
+
+	for _, abbrev := range []bool{true, false} {
+		out, parsed := writeParseXml(t, abbrev, stms)
+		if len(parsed) != len(stms)-1 {
+			t.Errorf("Wrong number of statements: %d\n%s", len(parsed), out)
+		}
+		for _, stm := range stms {
+			if abbrev && (stm.Subject.Equals(info) || stm.Object.Equals(info)) {
+				// nested, without node ID
+				continue
+			}
+			if findStatement(parsed, stm.Subject, stm.Predicate, stm.Object) < 0 {
+				t.Errorf("Statement not found: %s %s %s\n%s", termStr(stm.Subject), termStr(stm.Predicate), termStr(stm.Object), out)
+			}
+		}
+		var nested Term
+		for _, stm := range parsed {
+			if termStr(stm.Predicate) == baseUri+"creationInfo" {
+				nested = stm.Object
+			}
+		}
+		if nested == nil || findStatement(parsed, nested, stms[2].Predicate, stms[2].Object) < 0 {
+			t.Errorf("Nested node not written:\n%s", out)
+		}
+		if abbrev != strings.Contains(out, "<SpdxDocument") {
+			t.Errorf("Typed node elements must only be written in the abbreviated form:\n%s", out)
+		}
+		if abbrev == strings.Contains(out, `rdf:nodeID="info"`) {
+			t.Errorf("Nodes referred to once must be nested in the abbreviated form:\n%s", out)
+		}
+	}
+}
+
+func TestXmlSerializerInvalid(t *testing.T) {
+	s := newXmlSerializer(new(bytes.Buffer), true)
+	if err := s.Add(&Statement{literal("a"), uri(baseUri + "a"), literal("b")}); err == nil {
+		t.Error("Literal subjects must be rejected.")
+	}
+	s.Add(&Statement{blank("a"), uri("http://example.org/1"), literal("b")})
+	if err := s.Close(); err == nil {
+		t.Error("Predicates that are not XML names must be rejected.")
+	}
+}
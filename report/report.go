@@ -0,0 +1,276 @@
+// Package report renders a SPDX document as a human-readable HTML page.
+//
+// The page is self-contained (the styles and scripts are inline) and has the
+// document information, a summary of the licences used, the packages, the
+// files, the extracted licence texts and the reviews. Licences link to the
+// extracted licence texts in the page or to the SPDX Licence List. The files
+// can be searched and filtered by licence and type in the browser.
+package report
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The URL of the SPDX Licence List pages, to which listed licences link.
+var LicenceListUrl = "http://spdx.org/licenses/"
+
+// A file in the report.
+type file struct {
+	*spdx.File
+	Anchor   string   // HTML id of the file
+	Packages []string // names of the packages of the file
+	Licences string   // licence IDs of the file (for filtering), space separated
+}
+
+// A row of the licence summary.
+type licence struct {
+	Id       string
+	Name     string
+	Href     string // the extracted licence or Licence List page, if any
+	Files    int    // number of files the licence is found in
+	Packages int    // number of packages the licence is found in
+}
+
+// The data of the report template.
+type page struct {
+	Title    string
+	Doc      *spdx.Document
+	Files    []*file
+	Licences []*licence
+	Types    []string
+}
+
+// Returns the HTML id of the extracted licence `id`.
+func licenceAnchor(id string) string {
+	return "licence-" + id
+}
+
+// Returns the link of a licence: the extracted licence in the page, the
+// SPDX Licence List page or "" for NOASSERTION and NONE.
+func licenceHref(lic spdx.AnyLicence, extracted map[string]bool) string {
+	id := lic.LicenceId()
+	switch {
+	case spdx.IsEmpty(id):
+		return ""
+	case extracted[id]:
+		return "#" + licenceAnchor(id)
+	case strings.HasPrefix(id, "LicenseRef-"):
+		return ""
+	}
+	return LicenceListUrl + id
+}
+
+// Returns the HTML of a licence expression with links to the licences.
+func licenceHtml(lic spdx.AnyLicence, extracted map[string]bool) template.HTML {
+	var members []spdx.AnyLicence
+	op := ""
+	switch l := lic.(type) {
+	case nil:
+		return ""
+	case spdx.ConjunctiveLicenceSet:
+		members, op = l.Members, " and "
+	case spdx.DisjunctiveLicenceSet:
+		members, op = l.Members, " or "
+	case *spdx.ConjunctiveLicenceSet:
+		members, op = l.Members, " and "
+	case *spdx.DisjunctiveLicenceSet:
+		members, op = l.Members, " or "
+	default:
+		id := template.HTMLEscapeString(lic.LicenceId())
+		if href := licenceHref(lic, extracted); href != "" {
+			return template.HTML(`<a href="` + template.HTMLEscapeString(href) + `">` + id + `</a>`)
+		}
+		return template.HTML(id)
+	}
+	parts := make([]string, len(members))
+	for i, m := range members {
+		parts[i] = string(licenceHtml(m, extracted))
+	}
+	return template.HTML("(" + strings.Join(parts, op) + ")")
+}
+
+// Returns the files of `doc`: doc.Files and the files of the packages which
+// are not in doc.Files.
+func files(doc *spdx.Document) []*file {
+	var res []*file
+	index := make(map[*spdx.File]*file)
+	add := func(f *spdx.File) *file {
+		if rf, ok := index[f]; ok {
+			return rf
+		}
+		rf := &file{File: f, Anchor: "file-" + strconv.Itoa(len(res)+1)}
+		index[f] = rf
+		res = append(res, rf)
+		return rf
+	}
+	for _, f := range doc.Files {
+		add(f)
+	}
+	for _, pkg := range doc.Packages {
+		for _, f := range pkg.Files {
+			rf := add(f)
+			rf.Packages = append(rf.Packages, pkg.Name.Val)
+		}
+	}
+	for _, rf := range res {
+		ids := make(map[string]bool)
+		for _, lic := range spdx.LicenceMembers(append([]spdx.AnyLicence{rf.LicenceConcluded}, rf.LicenceInfoInFile...)...) {
+			ids[lic.LicenceId()] = true
+		}
+		rf.Licences = strings.Join(sortedKeys(ids), " ")
+	}
+	return res
+}
+
+// Returns the keys of `m` in order.
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Returns the licence summary of `doc`: every licence found in the files and
+// packages, with the number of files and packages it is found in.
+func licences(doc *spdx.Document, files []*file, extracted map[string]bool) []*licence {
+	byId := make(map[string]*licence)
+	get := func(lic spdx.AnyLicence) *licence {
+		id := lic.LicenceId()
+		if l, ok := byId[id]; ok {
+			return l
+		}
+		l := &licence{Id: id, Href: licenceHref(lic, extracted)}
+		if el, ok := lic.(*spdx.ExtractedLicence); ok && len(el.Name) > 0 {
+			l.Name = el.Name[0].Val
+		}
+		byId[id] = l
+		return l
+	}
+	for _, lic := range doc.ExtractedLicences {
+		get(lic)
+	}
+
+	for _, f := range files {
+		seen := make(map[*licence]bool)
+		for _, lic := range spdx.LicenceMembers(append([]spdx.AnyLicence{f.LicenceConcluded}, f.LicenceInfoInFile...)...) {
+			if l := get(lic); !seen[l] {
+				seen[l] = true
+				l.Files++
+			}
+		}
+	}
+	for _, pkg := range doc.Packages {
+		seen := make(map[*licence]bool)
+		lics := append([]spdx.AnyLicence{pkg.LicenceConcluded, pkg.LicenceDeclared}, pkg.LicenceInfoFromFiles...)
+		for _, lic := range spdx.LicenceMembers(lics...) {
+			if l := get(lic); !seen[l] {
+				seen[l] = true
+				l.Packages++
+			}
+		}
+	}
+
+	res := make([]*licence, 0, len(byId))
+	for _, l := range byId {
+		res = append(res, l)
+	}
+	sort.Sort(byLicenceId(res))
+	return res
+}
+
+// Sorts licences by ID.
+type byLicenceId []*licence
+
+func (s byLicenceId) Len() int           { return len(s) }
+func (s byLicenceId) Less(i, j int) bool { return s[i].Id < s[j].Id }
+func (s byLicenceId) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Returns the title of the report of `doc`, from the names of its packages.
+func Title(doc *spdx.Document) string {
+	names := make([]string, 0, len(doc.Packages))
+	for _, pkg := range doc.Packages {
+		if pkg.Name.Val != "" {
+			names = append(names, pkg.Name.Val)
+		}
+	}
+	if len(names) == 0 {
+		return "SPDX report"
+	}
+	return "SPDX report: " + strings.Join(names, ", ")
+}
+
+// Write the HTML report of `doc` to `w`. The title of the page is Title(doc).
+func Write(w io.Writer, doc *spdx.Document) error {
+	return WriteTitle(w, doc, Title(doc))
+}
+
+// Write the HTML report of `doc` to `w` with the given page title.
+func WriteTitle(w io.Writer, doc *spdx.Document, title string) error {
+	extracted := make(map[string]bool)
+	for _, lic := range doc.ExtractedLicences {
+		extracted[lic.LicenceId()] = true
+	}
+	fs := files(doc)
+	anchors := make(map[*spdx.File]string)
+	types := make(map[string]bool)
+	for _, f := range fs {
+		anchors[f.File] = f.Anchor
+		if f.Type.Val != "" {
+			types[f.Type.Val] = true
+		}
+	}
+
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"licence": func(lic spdx.AnyLicence) template.HTML { return licenceHtml(lic, extracted) },
+		"licences": func(lics []spdx.AnyLicence) template.HTML {
+			parts := make([]string, len(lics))
+			for i, lic := range lics {
+				parts[i] = string(licenceHtml(lic, extracted))
+			}
+			return template.HTML(strings.Join(parts, ", "))
+		},
+		"anchor":     licenceAnchor,
+		"fileAnchor": func(f *spdx.File) string { return anchors[f] },
+		"join":       strings.Join,
+		"joinStr":    spdx.Join,
+		"isUrl":      func(s string) bool { return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://") },
+		"checksum":   checksum,
+		"add":        func(a, b int) int { return a + b },
+		"creators":   creators,
+	}).Parse(reportTemplate)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, &page{
+		Title:    title,
+		Doc:      doc,
+		Files:    fs,
+		Licences: licences(doc, fs, extracted),
+		Types:    sortedKeys(types),
+	})
+}
+
+// Returns the checksum as "<algorithm>: <value>", or "" if there is none.
+func checksum(cksum *spdx.Checksum) string {
+	if cksum == nil || (cksum.Algo.Val == "" && cksum.Value.Val == "") {
+		return ""
+	}
+	return cksum.Algo.Val + ": " + cksum.Value.Val
+}
+
+// Returns the creators of `ci`.
+func creators(ci *spdx.CreationInfo) []string {
+	res := make([]string, len(ci.Creator))
+	for i, cr := range ci.Creator {
+		res[i] = cr.V()
+	}
+	return res
+}
@@ -0,0 +1,143 @@
+package report
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testDocument = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+DocumentComment: <text><script>alert(1)</script></text>
+Creator: Person: Jane Doe (jane@example.com)
+Created: 2014-08-01T00:00:00Z
+
+PackageName: pkg
+PackageVersion: 1.0
+PackageDownloadLocation: http://example.com/pkg-1.0.tar.gz
+PackageChecksum: SHA1: 85ed0817af83a24ad8da68c2b5094de69833983c
+PackageVerificationCode: 4e3211c67a2d28fced849ee1bb76e7391b93feba (a.rdf)
+PackageLicenseConcluded: (Apache-2.0 and LicenseRef-1)
+PackageLicenseDeclared: (Apache-2.0 or MIT)
+PackageCopyrightText: NOASSERTION
+
+FileName: a.c
+FileType: SOURCE
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12
+LicenseConcluded: (Apache-2.0 or LicenseRef-1)
+LicenseInfoInFile: LicenseRef-1
+FileDependency: b.c
+
+FileName: b.c
+FileType: BINARY
+LicenseConcluded: Apache-2.0
+
+LicenseID: LicenseRef-1
+ExtractedText: <text>Some <licence> text</text>
+LicenseName: Some
+LicenseCrossReference: http://example.com/licence
+
+Reviewer: Person: Joe Reviewer
+ReviewDate: 2010-02-10T00:00:00Z
+ReviewComment: Looks good.
+`
+
+// Returns the report of testDocument.
+func testReport(t *testing.T) string {
+	doc, err := tag.Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = Write(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestWrite(t *testing.T) {
+	out := testReport(t)
+	expected := []string{
+		`<title>SPDX report: pkg</title>`,
+		`<td class="text">Person: Jane Doe (jane@example.com)</td>`,
+		`&lt;script&gt;alert(1)&lt;/script&gt;`,
+		`<a href="http://example.com/pkg-1.0.tar.gz">`,
+		`<td class="checksum">SHA1: 85ed0817af83a24ad8da68c2b5094de69833983c</td>`,
+		`4e3211c67a2d28fced849ee1bb76e7391b93feba (excludes: a.rdf)`,
+		`(<a href="http://spdx.org/licenses/Apache-2.0">Apache-2.0</a> and <a href="#licence-LicenseRef-1">LicenseRef-1</a>)`,
+		`<h3 id="licence-LicenseRef-1">LicenseRef-1 (Some)</h3>`,
+		`<pre>Some &lt;licence&gt; text</pre>`,
+		`<tr id="file-1" class="file" data-licences="Apache-2.0 LicenseRef-1" data-type="SOURCE">`,
+		`Depends on: <a href="#file-2">b.c</a>`,
+		`<option value="BINARY">BINARY</option>`,
+		`<td>Person: Joe Reviewer</td><td>2010-02-10T00:00:00Z</td><td class="text">Looks good.</td>`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(out, s) {
+			t.Errorf("%s not found in report:\n%s", s, out)
+		}
+	}
+	if strings.Contains(out, "<script>alert") {
+		t.Error("Document values not escaped.")
+	}
+}
+
+func TestLicenceSummary(t *testing.T) {
+	out := testReport(t)
+	rows := []string{
+		`<tr><td><a href="http://spdx.org/licenses/Apache-2.0">Apache-2.0</a></td><td></td><td>2</td><td>1</td></tr>`,
+		`<tr><td><a href="#licence-LicenseRef-1">LicenseRef-1</a></td><td>Some</td><td>1</td><td>1</td></tr>`,
+		`<tr><td><a href="http://spdx.org/licenses/MIT">MIT</a></td><td></td><td>0</td><td>1</td></tr>`,
+	}
+	last := -1
+	for _, row := range rows {
+		i := strings.Index(out, row)
+		if i < 0 {
+			t.Errorf("%s not found in report", row)
+		} else if i < last {
+			t.Errorf("%s not in order", row)
+		}
+		last = i
+	}
+	if strings.Contains(out, `<option value="MIT">`) {
+		t.Error("Licence without files in the file filter.")
+	}
+}
+
+func TestWritePackageFiles(t *testing.T) {
+	file := &spdx.File{Name: spdx.Str("a.c", nil), LicenceConcluded: spdx.NewLicence(spdx.NOASSERTION, nil)}
+	doc := &spdx.Document{Packages: []*spdx.Package{
+		{Name: spdx.Str("one", nil), Files: []*spdx.File{file}},
+		{Name: spdx.Str("two", nil), Files: []*spdx.File{file}},
+	}}
+	var b bytes.Buffer
+	if err := WriteTitle(&b, doc, "Report & title"); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	if !strings.Contains(out, "<title>Report &amp; title</title>") {
+		t.Errorf("Wrong title in report:\n%s", out)
+	}
+	if n := strings.Count(out, `class="file"`); n != 1 {
+		t.Errorf("Package file written %d times", n)
+	}
+	if !strings.Contains(out, "Package: one, two") || !strings.Contains(out, "<td>NOASSERTION</td>") {
+		t.Errorf("Wrong file row in report:\n%s", out)
+	}
+}
+
+func TestTitle(t *testing.T) {
+	doc := &spdx.Document{}
+	if title := Title(doc); title != "SPDX report" {
+		t.Errorf("Wrong title: %s", title)
+	}
+	doc.Packages = []*spdx.Package{{Name: spdx.Str("a", nil)}, {}, {Name: spdx.Str("b", nil)}}
+	if title := Title(doc); title != "SPDX report: a, b" {
+		t.Errorf("Wrong title: %s", title)
+	}
+}
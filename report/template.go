@@ -0,0 +1,155 @@
+package report
+
+// The html/template of the report page.
+const reportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: 14px; color: #222; margin: 0; }
+header { background: #dfdfdf; padding: 10px 20px; }
+header h1 { margin: 0; font-size: 20px; }
+nav a { margin-right: 15px; }
+section { padding: 0 20px 10px 20px; }
+h2 { border-bottom: 1px solid #ccc; padding-top: 10px; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 10px; }
+th, td { text-align: left; vertical-align: top; padding: 4px 8px; border-bottom: 1px solid #eee; }
+th { background: #f5f5f5; }
+table.info th { width: 20%; }
+.text, pre { white-space: pre-wrap; }
+pre { background: #f8f8f8; padding: 8px; border: 1px solid #eee; }
+.checksum { font-family: "Lucida Console", Monaco, monospace; font-size: 12px; word-break: break-all; }
+#filters { margin: 10px 0; }
+#filters input, #filters select { margin-right: 10px; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<nav>
+<a href="#document">Document</a>
+<a href="#summary">Licence summary</a>
+<a href="#packages">Packages</a>
+<a href="#files">Files</a>
+<a href="#licences">Extracted licences</a>
+<a href="#reviews">Reviews</a>
+</nav>
+</header>
+
+<section id="document">
+<h2>Document</h2>
+<table class="info">
+<tr><th>SPDX version</th><td>{{.Doc.SpecVersion.Val}}</td></tr>
+<tr><th>Data licence</th><td>{{.Doc.DataLicence.Val}}</td></tr>
+{{with .Doc.CreationInfo}}<tr><th>Creators</th><td class="text">{{join (creators .) "\n"}}</td></tr>
+<tr><th>Created</th><td>{{.Created.V}}</td></tr>
+{{with .LicenceListVersion.Val}}<tr><th>Licence list version</th><td>{{.}}</td></tr>
+{{end}}{{with .Comment.Val}}<tr><th>Creator comment</th><td class="text">{{.}}</td></tr>
+{{end}}{{end}}{{with .Doc.Comment.Val}}<tr><th>Comment</th><td class="text">{{.}}</td></tr>
+{{end}}</table>
+</section>
+
+<section id="summary">
+<h2>Licence summary</h2>
+<table>
+<tr><th>Licence</th><th>Name</th><th>Files</th><th>Packages</th></tr>
+{{range .Licences}}<tr><td>{{if .Href}}<a href="{{.Href}}">{{.Id}}</a>{{else}}{{.Id}}{{end}}</td><td>{{.Name}}</td><td>{{.Files}}</td><td>{{.Packages}}</td></tr>
+{{end}}</table>
+</section>
+
+<section id="packages">
+<h2>Packages</h2>
+{{range $i, $pkg := .Doc.Packages}}<h3 id="package-{{add $i 1}}">{{$pkg.Name.Val}}{{with $pkg.Version.Val}} {{.}}{{end}}</h3>
+<table class="info">
+{{with $pkg.FileName.Val}}<tr><th>File name</th><td>{{.}}</td></tr>
+{{end}}{{with $pkg.Supplier.V}}<tr><th>Supplier</th><td>{{.}}</td></tr>
+{{end}}{{with $pkg.Originator.V}}<tr><th>Originator</th><td>{{.}}</td></tr>
+{{end}}{{with $pkg.DownloadLocation.Val}}<tr><th>Download location</th><td>{{if isUrl .}}<a href="{{.}}">{{.}}</a>{{else}}{{.}}{{end}}</td></tr>
+{{end}}{{with $pkg.HomePage.Val}}<tr><th>Home page</th><td>{{if isUrl .}}<a href="{{.}}">{{.}}</a>{{else}}{{.}}{{end}}</td></tr>
+{{end}}{{with checksum $pkg.Checksum}}<tr><th>Checksum</th><td class="checksum">{{.}}</td></tr>
+{{end}}{{with $pkg.VerificationCode}}<tr><th>Verification code</th><td class="checksum">{{.Value.Val}}{{if .ExcludedFiles}} (excludes: {{joinStr .ExcludedFiles ", "}}){{end}}</td></tr>
+{{end}}<tr><th>Licence concluded</th><td>{{licence $pkg.LicenceConcluded}}</td></tr>
+<tr><th>Licence declared</th><td>{{licence $pkg.LicenceDeclared}}</td></tr>
+<tr><th>Licences from files</th><td>{{licences $pkg.LicenceInfoFromFiles}}</td></tr>
+{{with $pkg.LicenceComments.Val}}<tr><th>Licence comments</th><td class="text">{{.}}</td></tr>
+{{end}}<tr><th>Copyright</th><td class="text">{{$pkg.CopyrightText.Val}}</td></tr>
+{{with $pkg.SourceInfo.Val}}<tr><th>Source information</th><td class="text">{{.}}</td></tr>
+{{end}}{{with $pkg.Summary.Val}}<tr><th>Summary</th><td class="text">{{.}}</td></tr>
+{{end}}{{with $pkg.Description.Val}}<tr><th>Description</th><td class="text">{{.}}</td></tr>
+{{end}}{{with $pkg.Files}}<tr><th>Files</th><td>{{len .}}</td></tr>
+{{end}}</table>
+{{end}}</section>
+
+<section id="files">
+<h2>Files</h2>
+<div id="filters">
+<input id="search" type="search" placeholder="Search files">
+<select id="licence-filter"><option value="">All licences</option>
+{{range .Licences}}{{if .Files}}<option value="{{.Id}}">{{.Id}}</option>
+{{end}}{{end}}</select>
+<select id="type-filter"><option value="">All types</option>
+{{range .Types}}<option value="{{.}}">{{.}}</option>
+{{end}}</select>
+<span id="count">{{len .Files}} files</span>
+</div>
+<table id="file-table">
+<tr><th>Name</th><th>Type</th><th>Licence concluded</th><th>Licences in file</th><th>Copyright</th><th>Checksum</th></tr>
+{{range .Files}}<tr id="{{.Anchor}}" class="file" data-licences="{{.Licences}}" data-type="{{.Type.Val}}">
+<td>{{.Name.Val}}{{with .Packages}}<br><small>Package: {{join . ", "}}</small>{{end}}{{with .Dependency}}<br><small>Depends on: {{range $i, $dep := .}}{{if $i}}, {{end}}{{with fileAnchor $dep}}<a href="#{{.}}">{{$dep.Name.Val}}</a>{{else}}{{$dep.Name.Val}}{{end}}{{end}}</small>{{end}}{{with .Comment.Val}}<br><small class="text">{{.}}</small>{{end}}</td>
+<td>{{.Type.Val}}</td>
+<td>{{licence .LicenceConcluded}}</td>
+<td>{{licences .LicenceInfoInFile}}</td>
+<td class="text">{{.CopyrightText.Val}}{{with .Notice.Val}}<br><small>Notice: {{.}}</small>{{end}}</td>
+<td class="checksum">{{checksum .Checksum}}</td>
+</tr>
+{{end}}</table>
+</section>
+
+<section id="licences">
+<h2>Extracted licences</h2>
+{{range .Doc.ExtractedLicences}}<h3 id="{{anchor .Id.Val}}">{{.Id.Val}}{{with .Name}} ({{joinStr . ", "}}){{end}}</h3>
+{{with .CrossReference}}<p>See also: {{range $i, $ref := .}}{{if $i}}, {{end}}{{if isUrl $ref.Val}}<a href="{{$ref.Val}}">{{$ref.Val}}</a>{{else}}{{$ref.Val}}{{end}}{{end}}</p>
+{{end}}{{with .Comment.Val}}<p class="text">{{.}}</p>
+{{end}}<pre>{{.Text.Val}}</pre>
+{{end}}</section>
+
+<section id="reviews">
+<h2>Reviews</h2>
+<table>
+<tr><th>Reviewer</th><th>Date</th><th>Comment</th></tr>
+{{range .Doc.Reviews}}<tr><td>{{.Reviewer.V}}</td><td>{{.Date.V}}</td><td class="text">{{.Comment.Val}}</td></tr>
+{{end}}</table>
+</section>
+
+<script>
+(function() {
+  var search = document.getElementById("search");
+  var licence = document.getElementById("licence-filter");
+  var type = document.getElementById("type-filter");
+  var count = document.getElementById("count");
+  var rows = document.querySelectorAll("#file-table tr.file");
+  function filter() {
+    var text = search.value.toLowerCase();
+    var shown = 0;
+    for (var i = 0; i < rows.length; i++) {
+      var row = rows[i];
+      var show = (!text || row.textContent.toLowerCase().indexOf(text) >= 0) &&
+        (!licence.value || (" " + row.getAttribute("data-licences") + " ").indexOf(" " + licence.value + " ") >= 0) &&
+        (!type.value || row.getAttribute("data-type") === type.value);
+      row.className = show ? "file" : "file hidden";
+      if (show) {
+        shown++;
+      }
+    }
+    count.textContent = shown + " of " + rows.length + " files";
+  }
+  search.addEventListener("input", filter);
+  licence.addEventListener("change", filter);
+  type.addEventListener("change", filter);
+})();
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,231 @@
+// Package sign creates and checks detached signatures of SPDX documents.
+//
+// The signature is over the canonical form of the document (see
+// spdx.Canonical()), so it stays valid when the document is reformatted or
+// converted to another format. Ed25519 and ECDSA keys are supported; private
+// keys are read from PEM files (PKCS #8, or SEC 1 for ECDSA) and public keys
+// from PEM files with a PKIX public key or a certificate.
+//
+// Signing records the signer in the creator comment of the document, as a
+// line "Signed-By: <signer> (key SHA256:<fingerprint>)", before the document is
+// signed. The signature is a PEM block of type "SPDX SIGNATURE" with the
+// algorithm, the signer and the key fingerprint as headers.
+package sign
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Signature algorithms.
+const (
+	AlgoEd25519     = "ed25519"
+	AlgoEcdsaSha256 = "ecdsa-sha256"
+)
+
+// The PEM block type of signatures.
+const pemType = "SPDX SIGNATURE"
+
+// The prefix of the creator comment line which records the signer.
+const signedBy = "Signed-By: "
+
+// A detached signature of a SPDX document.
+type Signature struct {
+	Algorithm   string // AlgoEd25519 or AlgoEcdsaSha256
+	Signer      string // Signer identity, as recorded in the document
+	Fingerprint string // Fingerprint of the public key (see Fingerprint())
+	Value       []byte // The signature
+}
+
+// Returns the signature as a PEM block.
+func (s *Signature) Encode() []byte {
+	return pem.EncodeToMemory(&pem.Block{
+		Type: pemType,
+		Headers: map[string]string{
+			"Algorithm":   s.Algorithm,
+			"Signer":      s.Signer,
+			"Fingerprint": s.Fingerprint,
+		},
+		Bytes: s.Value,
+	})
+}
+
+// Parses a signature written by Signature.Encode().
+func ParseSignature(data []byte) (*Signature, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != pemType {
+		return nil, errors.New("No " + pemType + " PEM block found.")
+	}
+	return &Signature{
+		Algorithm:   block.Headers["Algorithm"],
+		Signer:      block.Headers["Signer"],
+		Fingerprint: block.Headers["Fingerprint"],
+		Value:       block.Bytes,
+	}, nil
+}
+
+// Parses an Ed25519 or ECDSA private key from a PEM file.
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("No PEM block found in the private key.")
+	}
+	if block.Type == "EC PRIVATE KEY" {
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	}
+	return nil, fmt.Errorf("Unsupported private key type %T. Only Ed25519 and ECDSA are supported.", key)
+}
+
+// Parses an Ed25519 or ECDSA public key from a PEM file with a public key or
+// a certificate.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("No PEM block found in the public key.")
+	}
+	var key crypto.PublicKey
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	} else {
+		var err error
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	}
+	switch key.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	}
+	return nil, fmt.Errorf("Unsupported public key type %T. Only Ed25519 and ECDSA are supported.", key)
+}
+
+// Returns the fingerprint of a public key: the hex SHA-256 of its PKIX
+// encoding.
+func Fingerprint(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Returns the line recording the signer in the creator comment.
+func signerLine(signer, fingerprint string) string {
+	return signedBy + signer + " (key SHA256:" + fingerprint + ")"
+}
+
+// Checks whether the creator comment of `doc` records the signer.
+func recorded(doc *spdx.Document, line string) bool {
+	if doc.CreationInfo == nil {
+		return false
+	}
+	for _, l := range strings.Split(doc.CreationInfo.Comment.Val, "\n") {
+		if strings.TrimSpace(l) == line {
+			return true
+		}
+	}
+	return false
+}
+
+// Records `signer` in the creator comment of `doc` and signs the document
+// with `key`. If `signer` is empty, the key fingerprint is the signer.
+func Sign(doc *spdx.Document, key crypto.Signer, signer string) (*Signature, error) {
+	fingerprint, err := Fingerprint(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	if signer == "" {
+		signer = "SHA256:" + fingerprint
+	}
+	if strings.ContainsAny(signer, "\r\n") {
+		return nil, errors.New("The signer cannot have line breaks.")
+	}
+
+	line := signerLine(signer, fingerprint)
+	if doc.CreationInfo == nil {
+		doc.CreationInfo = new(spdx.CreationInfo)
+	}
+	if !recorded(doc, line) {
+		comment := &doc.CreationInfo.Comment
+		if comment.Val != "" {
+			comment.Val += "\n"
+		}
+		comment.Val += line
+	}
+
+	sig := &Signature{Signer: signer, Fingerprint: fingerprint}
+	canonical := spdx.Canonical(doc)
+	switch key.Public().(type) {
+	case ed25519.PublicKey:
+		sig.Algorithm = AlgoEd25519
+		sig.Value, err = key.Sign(rand.Reader, canonical, crypto.Hash(0))
+	case *ecdsa.PublicKey:
+		sig.Algorithm = AlgoEcdsaSha256
+		sum := sha256.Sum256(canonical)
+		sig.Value, err = key.Sign(rand.Reader, sum[:], crypto.SHA256)
+	default:
+		return nil, fmt.Errorf("Unsupported key type %T. Only Ed25519 and ECDSA are supported.", key.Public())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// Checks that `sig` is a valid signature of `doc` made with the private key of
+// `pub`, and that the document records the signer of `sig`.
+func Verify(doc *spdx.Document, sig *Signature, pub crypto.PublicKey) error {
+	fingerprint, err := Fingerprint(pub)
+	if err != nil {
+		return err
+	}
+	if sig.Fingerprint != fingerprint {
+		return errors.New("The signature was made with another key (SHA256:" + sig.Fingerprint + ").")
+	}
+
+	canonical := spdx.Canonical(doc)
+	valid := false
+	switch k := pub.(type) {
+	case ed25519.PublicKey:
+		valid = sig.Algorithm == AlgoEd25519 && ed25519.Verify(k, canonical, sig.Value)
+	case *ecdsa.PublicKey:
+		sum := sha256.Sum256(canonical)
+		valid = sig.Algorithm == AlgoEcdsaSha256 && ecdsa.VerifyASN1(k, sum[:], sig.Value)
+	default:
+		return fmt.Errorf("Unsupported key type %T. Only Ed25519 and ECDSA are supported.", pub)
+	}
+	if !valid {
+		return errors.New("Invalid signature. The document was modified or signed with another key.")
+	}
+
+	if !recorded(doc, signerLine(sig.Signer, sig.Fingerprint)) {
+		return errors.New("The signer " + sig.Signer + " is not recorded in the document.")
+	}
+	return nil
+}
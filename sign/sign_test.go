@@ -0,0 +1,258 @@
+package sign
+
+import (
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/rdf"
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/spreadsheet"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+)
+
+const signInput = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+Creator: Tool: x
+Created: 2014-01-01T00:00:00Z
+
+FileName: b.c
+LicenseConcluded: (MIT and GPL-2.0)
+
+FileName: a.c
+LicenseConcluded: MIT
+`
+
+func parseDoc(t *testing.T, input string) *spdx.Document {
+	doc, err := tag.Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return doc
+}
+
+// Returns the PEM files of a new private key and its public key.
+func genKey(t *testing.T, ecdsaKey bool) (private, public []byte) {
+	var priv crypto.Signer
+	var err error
+	if ecdsaKey {
+		priv, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	} else {
+		_, priv, err = ed25519.GenerateKey(rand.Reader)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDer, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer})
+}
+
+// Signs the document of `input` and returns the Tag output of the signed
+// document and the encoded signature.
+func signDoc(t *testing.T, input string, private []byte, signer string) (string, []byte) {
+	key, err := ParsePrivateKey(private)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	doc := parseDoc(t, input)
+	sig, err := Sign(doc, key, signer)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := tag.Write(buf, doc); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return buf.String(), sig.Encode()
+}
+
+func verifyDoc(t *testing.T, input string, encoded, public []byte) error {
+	sig, err := ParseSignature(encoded)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	pub, err := ParsePublicKey(public)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return Verify(parseDoc(t, input), sig, pub)
+}
+
+func TestSignVerify(t *testing.T) {
+	for _, ecdsaKey := range []bool{false, true} {
+		private, public := genKey(t, ecdsaKey)
+		signed, sig := signDoc(t, signInput, private, "Person: Me")
+		if !strings.Contains(signed, "CreatorComment: <text>Signed-By: Person: Me (key SHA256:") {
+			t.Errorf("Signer not recorded in %s", signed)
+		}
+		if err := verifyDoc(t, signed, sig, public); err != nil {
+			t.Errorf("Unexpected error %s", err)
+		}
+
+		// reordered and reformatted
+		reordered := strings.Replace(signed, "FileName: b.c\nLicenseConcluded: (MIT and GPL-2.0)\n\n", "", 1) +
+			"\nfilename:   b.c\nLicenseConcluded: (GPL-2.0 and MIT)\n"
+		if err := verifyDoc(t, reordered, sig, public); err != nil {
+			t.Errorf("Unexpected error %s", err)
+		}
+
+		modified := strings.Replace(signed, "LicenseConcluded: MIT", "LicenseConcluded: GPL-2.0", 1)
+		if err := verifyDoc(t, modified, sig, public); err == nil {
+			t.Error("No error for a modified document.")
+		}
+
+		_, other := genKey(t, ecdsaKey)
+		if err := verifyDoc(t, signed, sig, other); err == nil {
+			t.Error("No error for another key.")
+		}
+	}
+}
+
+// Writes a document in a format and reads it back.
+type conversion func(t *testing.T, doc *spdx.Document) (*spdx.Document, error)
+
+// Returns the conversion through the formats written by `write` and read by
+// `read`.
+func stream(write func(io.Writer, *spdx.Document) error, read func(io.Reader) (*spdx.Document, error)) conversion {
+	return func(t *testing.T, doc *spdx.Document) (*spdx.Document, error) {
+		buf := new(bytes.Buffer)
+		if err := write(buf, doc); err != nil {
+			return nil, err
+		}
+		return read(buf)
+	}
+}
+
+// Returns the conversion through the RDF format `format`.
+func rdfFormat(format string) conversion {
+	return func(t *testing.T, doc *spdx.Document) (*spdx.Document, error) {
+		f, err := ioutil.TempFile("", "sign")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+		if err = rdf.WriteFormat(f, doc, format); err != nil {
+			return nil, err
+		}
+		if _, err = f.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return rdf.Parse(f, format)
+	}
+}
+
+// The conversions through every format a document can be written in, but
+// CycloneDX, which is another model.
+var conversions = map[string]conversion{
+	"tag":       stream(tag.Write, tag.Build),
+	"spdx-json": stream(json.Write, json.Build),
+	"xlsx":      stream(spreadsheet.WriteXlsx, spreadsheet.BuildXlsx),
+	"csv": func(t *testing.T, doc *spdx.Document) (*spdx.Document, error) {
+		dir, err := ioutil.TempDir("", "sign")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+		if err = spreadsheet.WriteCsv(dir, doc); err != nil {
+			return nil, err
+		}
+		return spreadsheet.BuildCsv(dir)
+	},
+}
+
+func init() {
+	for _, format := range rdf.Formats {
+		conversions[format] = rdfFormat(format)
+	}
+}
+
+func TestVerifyConverted(t *testing.T) {
+	private, public := genKey(t, false)
+	input := strings.Replace(signInput, "\nFileName: b.c", "\nPackageName: p\nPackageLicenseDeclared: MIT\n\nFileName: b.c", 1)
+	signed, encoded := signDoc(t, input, private, "Person: Me")
+	sig, err := ParseSignature(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for format, convert := range conversions {
+		doc, err := convert(t, parseDoc(t, signed))
+		if err != nil {
+			t.Errorf("%s: %s", format, err)
+		} else if err = Verify(doc, sig, pub); err != nil {
+			t.Errorf("%s: %s", format, err)
+		}
+	}
+}
+
+func TestSignTwice(t *testing.T) {
+	private, public := genKey(t, false)
+	signed, _ := signDoc(t, signInput, private, "")
+	signed, sig := signDoc(t, signed, private, "")
+	if n := strings.Count(signed, signedBy); n != 1 {
+		t.Errorf("Signer recorded %d times in %s", n, signed)
+	}
+	if err := verifyDoc(t, signed, sig, public); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+}
+
+func TestVerifySignerNotRecorded(t *testing.T) {
+	private, public := genKey(t, false)
+	key, err := ParsePrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := parseDoc(t, signInput)
+	sig, err := Sign(doc, key, "Person: Me")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := ParsePublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig.Signer = "Person: Someone else"
+	if err := Verify(doc, sig, pub); err == nil || !strings.Contains(err.Error(), "not recorded") {
+		t.Errorf("Wrong error %v", err)
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	sig := &Signature{Algorithm: AlgoEd25519, Signer: "Person: Me", Fingerprint: "ab", Value: []byte{1, 2, 3}}
+	res, err := ParseSignature(sig.Encode())
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if res.Algorithm != sig.Algorithm || res.Signer != sig.Signer || res.Fingerprint != sig.Fingerprint || !bytes.Equal(res.Value, sig.Value) {
+		t.Errorf("Expected %+v but found %+v", sig, res)
+	}
+	if _, err := ParseSignature([]byte("not a signature")); err == nil {
+		t.Error("No error for an invalid signature.")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build yaml
+// +build yaml
+
+package sign
+
+import "github.com/spdx/tools-go/yaml"
+
+func init() {
+	conversions["yaml"] = stream(yaml.Write, yaml.Build)
+}
@@ -1,7 +1,9 @@
 /*
 spdx-go is a tool for pretty-printing, converting and validating SPDX files.
-Formats supported by this tool are RDF (all RDF syntaxes supported by the raptor
-library) and Tag. For a full list of formats, see `-help`.
+Formats supported by this tool are RDF (RDF/XML, Turtle and N-Triples, or all
+RDF syntaxes supported by the raptor library when built with the "raptor" tag),
+Tag, SPDX JSON, SPDX YAML, CycloneDX and the SPDX spreadsheet (XLSX or CSV). For
+a full list of formats, see `-help`.
 
 Basic usage
 ===========
@@ -15,6 +17,12 @@ One action flag must be specified. Those are:
 		-v						# validation
 		-c <format>		# conversion
 		-p						# pretty-printing (formatting)
+		-scan <format>	# describe an archive as a SPDX document
+		-gomod <format>	# describe Go modules as a SPDX document
+		-gobin <format>	# describe a Go binary as a SPDX document
+		-report				# write a HTML report of the document
+		-notice <format>	# write the third-party notices of the document
+		-graph <format>	# draw the structure of the document
 		-help					# print the help message and quit
 		-version			# print the tool version and quit
 
@@ -45,7 +53,48 @@ With every command in this tool, the flag -f <format> makes the parser to use
 
 The format "rdf" is a special format. For output, it means "xmlrdf-abbrev"; for
 input, it means attempt to guess the RDF syntax in the input file (uses raptor's
-"guess" parser).
+"guess" parser when built with the "raptor" tag).
+
+The format "spdx-json" is the SPDX JSON format. The format "json" is raptor's
+RDF/JSON syntax. Files with the extension .spdx.json are detected as SPDX JSON.
+SPDX 2.x JSON documents are read too: the properties the SPDX model of this
+tool does not have (e.g. relationships, annotations) are kept as extensions and
+written back.
+
+The format "json-ld" is the SPDX RDF model in JSON-LD, written with a bundled
+SPDX context. Files with the extension .jsonld are detected as JSON-LD.
+
+The format "yaml" is the SPDX YAML format. Files with the extension .yaml or
+.yml are detected as SPDX YAML. It needs gopkg.in/yaml.v3 and is only
+available when building with the "yaml" tag:
+
+    go build -tags yaml
+
+The formats "cyclonedx-json" and "cyclonedx-xml" are CycloneDX JSON and XML
+(CycloneDX 1.6 for output). The SPDX values CycloneDX cannot express are left
+out and listed on standard error. CycloneDX input is converted to a SPDX-1.2
+document (see the documentation of the cyclonedx package). Files with the
+extensions .cdx.json and .cdx.xml, and files named bom.json and bom.xml, are
+detected as CycloneDX.
+
+The formats "xlsx" and "csv" are the SPDX spreadsheet layout (see the
+documentation of the spreadsheet package), as a XLSX workbook or as a directory
+with one CSV file per sheet. For "csv", the input is the directory and the
+output directory must be given with `-o`. Files with the extension .xlsx and
+directories are detected as XLSX and CSV, respectively.
+
+Lenient parsing
+---------------
+
+By default, the Tag and RDF parsers fail on properties which are not part of
+the SPDX specification, such as vendor-specific extensions. The flag
+`-lenient` makes them keep these properties with the package, file, licence,
+review or document they are found in; they are validation warnings with `-v`
+and they are written back by the Tag writer (RDF predicates as comments).
+
+Example:
+
+		spdx-go -lenient -c tag -o example.tag example.rdf
 
 Pretty-print (format) SPDX file
 ===============================
@@ -56,6 +105,9 @@ input format and pretty-prints the tokens.
 
 This means that invalid SPDX documents may be printed.
 
+SPDX JSON and SPDX YAML documents are parsed and written again, so they must be
+valid JSON or YAML in the SPDX JSON structure.
+
 A known limitation is the fact that comments in any RDF syntax are dismissed.
 The same limitation does not apply to the Tag format, where comments are printed
 and formatted.
@@ -65,16 +117,195 @@ Example:
 		# the -w flag is optional and overwrites the input file
 		spdx-go -p -w example.tag
 
+Tag formatting options
+----------------------
+
+The Tag output of `-p` and `-c tag` can be made canonical, so that a diff of
+two versions of a document only shows the real changes:
+
+    -sort         files sorted by name and extracted licences by ID
+    -align        values aligned in one column
+    -blank <p>    blank lines: default, sections (only before sections) or none
+    -textover <n> values longer than n characters written in <text>
+    -normkeys     property names in the case of the specification
+
+Example:
+
+		spdx-go -p -w -sort -align -blank sections -normkeys example.tag
+
 Convert between formats
 =======================
 
 Use the `-c <format>` flag to convert to and from supported SPDX formats.
 
+The comments of a Tag document are kept with the element they precede and are
+written back in place when the output is Tag as well.
+
 Example:
 
 		# conver example.tag to example.rdf
     spdx-go -c rdf -o example.rdf example.tag
 
+Scan archives
+=============
+
+Use the `-scan <format>` flag to describe a .tar, .tar.gz, .tgz or .zip archive
+as a SPDX document, without unpacking it to disk. The document has a package
+for the archive (with its checksum and file name) and a SHA1-checksummed file
+for each file in the archive.
+
+Archives inside the input archive are recorded as ARCHIVE files. The `-r` flag
+makes the tool scan their contents as well; a nested archive which cannot be
+read is recorded with the error in its file comment and the scan goes on.
+
+The archive can be read from stdin (no input file or `-`): its format is then
+detected from its content and the package is named "stdin".
+
+Example:
+
+		spdx-go -scan tag -r -o release-1.0.tag release-1.0.tar.gz
+
+Go modules
+==========
+
+Use the `-gomod <format>` flag to describe Go modules as a SPDX document. The
+input is the output of `go list -m -json all` or a go.mod file (detected from
+its content) and each module becomes a package. The go.mod and go.sum files
+next to the input file (or in the current directory, for stdin) are read as
+well, if they exist; use `-modfile` and `-sumfile` to read other files.
+Without `go list` output, the modules are the requirements of go.mod.
+
+Example:
+
+		go list -m -json all | spdx-go -gomod tag -o modules.tag
+		spdx-go -gomod tag -o modules.tag go.mod
+
+Use the `-gobin <format>` flag to describe a compiled Go binary, using the
+module information embedded in it by the Go toolchain. The binary is added to
+the document as a BINARY file with its SHA1 checksum.
+
+Example:
+
+		spdx-go -gobin tag -o tool.tag /usr/local/bin/tool
+
+HTML report
+===========
+
+Use the `-report` flag to write a human-readable HTML report of a SPDX
+document: the packages, the files, a summary of the licences, the extracted
+licence texts and the reviews. The page is self-contained and the files can be
+searched and filtered by licence and type.
+
+Example:
+
+		spdx-go -report -o example.html example.tag
+
+Third-party notices
+===================
+
+Use the `-notice <format>` flag to write the third-party notices (attribution
+file) of a SPDX document as `text`, `markdown` or `html`. The packages are
+grouped by concluded licence, with their copyright texts, followed by the full
+text of every licence: the extracted text for LicenseRefs and the SPDX Licence
+List text, read from the `-textdir` directory, for listed licences. The
+licences without a text are listed on stderr.
+
+The flag `-template <file>` writes the notices with a custom text/template (or
+html/template for `html`) instead of the built-in one; see the `notice`
+package for the available fields.
+
+Example:
+
+		spdx-go -notice markdown -o NOTICE.md example.spdx.json
+
+Document graph
+==============
+
+Use the `-graph <format>` flag to draw the structure of a SPDX document as a
+Graphviz DOT graph (`dot`) or a Mermaid flowchart (`mermaid`): the document,
+the packages with their files, the file dependencies, the ArtifactOf projects
+and the licences. With `-collapse`, the files of each package are shown as one
+node per directory. (The `dot` conversion format of `-c` writes the RDF
+triples instead.)
+
+Example:
+
+		spdx-go -graph dot -collapse example.tag | dot -Tsvg -o example.svg
+
+Document digest
+===============
+
+Use the `-digest` flag to print the SHA-256 digest of the canonical form of a
+SPDX document (see spdx.Canonical()). Documents which only differ in
+formatting, element order or serialisation format have the same digest.
+
+Example:
+
+		# prints the same digest twice
+		spdx-go -digest example.tag
+		spdx-go -digest example.rdf
+
+Document diff
+=============
+
+Use the `-diff <format>` flag with two input files, the old and the new
+document, to list the changes between them as text or JSON: the packages,
+files, extracted licences and reviews added or removed, and the properties
+changed, with the line numbers in both documents. Packages and files are
+matched by name and licences by ID, so reordering the elements or converting
+the document to another format is not a change.
+
+Example:
+
+		spdx-go -diff text release-1.0.tag release-1.1.rdf
+
+Changelog
+=========
+
+Use the `-changelog <format>` flag with two input files, the old and the new
+release of a document, to write a changelog as Markdown or JSON: the packages
+added and removed, the version changes, the licence changes (the ones which
+bring new copyleft licences are highlighted), the new extracted licences and
+the changes of copyright holders.
+
+Example:
+
+		spdx-go -changelog markdown -o CHANGES.md release-1.0.tag release-1.1.tag
+
+Merge documents
+===============
+
+Use the `-merge <format>` flag with several input files to combine them into
+one document, such as a product SBOM from the SBOMs of its components. The
+packages, files and reviews are put together; files with the same name and
+checksum and packages with the same name and version are kept once. The creators are
+combined. An extracted licence ID
+defined with another text in a previous document (such as LicenseRef-1) is
+renamed, along with all the references to it; the renamed licences are listed
+on stderr.
+
+Example:
+
+		spdx-go -merge tag -o product.tag component-a.tag component-b.rdf
+
+Signatures
+==========
+
+Use the `-sign <key.pem>` flag to sign a SPDX document with an Ed25519 or
+ECDSA private key, and `-verify <key.pem>` to check a signature with the public
+key or a certificate. The detached signature file is given with `-sig`.
+
+The signature is over the canonical form of the document, so it survives
+reformatting and conversion between formats. Signing records the signer
+(`-signer`, the key fingerprint by default) in the creator comment, so the
+signed document is written again, in the input format.
+
+Example:
+
+		spdx-go -sign private.pem -signer "Person: Jane" -sig example.sig -w example.tag
+		spdx-go -c rdf -o example.rdf example.tag
+		spdx-go -verify public.pem -sig example.sig example.rdf
+
 Validate SPDX file
 ==================
 
@@ -84,6 +315,11 @@ the SPDX Specification. Use the `-v` flag to validate documents. Example:
 		spdx-go -v example.tag
 		spdx-go -v example.rd
 
+Tag documents are parsed in recovering mode: a malformed line or property does
+not stop the parser, which skips to the next property line. All the parse
+errors are reported together with the validation findings of the rest of the
+document.
+
 HTML output validation
 ----------------------
 
@@ -125,8 +361,20 @@ which can be found at:
 package main
 
 import (
+	"github.com/spdx/tools-go/archive"
+	"github.com/spdx/tools-go/changelog"
+	"github.com/spdx/tools-go/cyclonedx"
+	"github.com/spdx/tools-go/diff"
+	"github.com/spdx/tools-go/gomod"
+	"github.com/spdx/tools-go/graph"
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/merge"
+	"github.com/spdx/tools-go/notice"
 	"github.com/spdx/tools-go/rdf"
+	"github.com/spdx/tools-go/report"
+	"github.com/spdx/tools-go/sign"
 	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/spreadsheet"
 	"github.com/spdx/tools-go/tag"
 )
 
@@ -151,19 +399,40 @@ const execName = "spdx-go"
 
 const helpMessage = `Usage: spdx-go <flags> <input-file>
 
-If no input file is specified, the input is read from stdin.
+If no input file is specified, or it is "-", the input is read from stdin.
 
 The formats supported by this tool are: %s.
 
 The format "rdf" is special, when using as input, it means rdfxml_abbrev.
 When used as output, it means try to autodetect the rdf format in the input
-file (using raptor "guess" parser).
+file (using raptor "guess" parser in raptor builds).
+
+The format "spdx-json" is the SPDX JSON format; "json" is RDF/JSON; "json-ld"
+is JSON-LD with the SPDX context.
+
+The formats "cyclonedx-json" and "cyclonedx-xml" are CycloneDX; the values
+which cannot be converted to CycloneDX are listed on stderr.
+
+The formats "xlsx" and "csv" are the SPDX spreadsheet; "csv" reads and writes
+a directory with one CSV file per sheet (use -o <directory> for output).
 
 One (and only one) action flag must be specified. Those are:
 
     -c <format> for convert
     -v for validate
     -p for pretty-print
+    -scan <format> for scanning a .tar, .tar.gz, .tgz or .zip archive
+    -gomod <format> for describing Go modules (input: go list -m -json all or go.mod)
+    -gobin <format> for describing a compiled Go binary
+    -report for a HTML report of the document
+    -notice <format> for the third-party notices (text, markdown or html)
+    -graph <format> for a graph of the document structure (dot or mermaid)
+    -digest for the digest of the canonical form of the document
+    -sign <private key> for signing the document (with -sig <signature file>)
+    -verify <public key> for verifying a signature (with -sig <signature file>)
+    -diff <format> for the changes between two documents (text or json)
+    -changelog <format> for a changelog between two releases (markdown or json)
+    -merge <format> for merging all the input files into one document
     -help
 	-version
 
@@ -173,25 +442,41 @@ A list of the flags supported by this tool:
 const (
 	formatRdf  = "rdf"
 	formatTag  = "tag"
+	formatJson = "spdx-json"
+	formatYaml = "yaml"
 	formatAuto = "auto"
+
+	formatCdxJson = "cyclonedx-json"
+	formatCdxXml  = "cyclonedx-xml"
+
+	formatXlsx = "xlsx"
+	formatCsv  = "csv"
+)
+
+// A list of all formats supported by the tool. SPDX YAML and the RDF formats
+// depend on the build (see yamlFormats and rdf.Formats).
+var formatList = concat(
+	[]string{
+		formatRdf,
+		formatTag,
+		formatJson,
+	},
+	yamlFormats,
+	rdf.Formats,
+	[]string{
+		formatCdxJson,
+		formatCdxXml,
+		formatXlsx,
+		formatCsv,
+	},
 )
 
-// A list of all formats supported by the tool.
-var formatList = []string{
-	formatRdf,
-	formatTag,
-	rdf.Fmt_ntriples,
-	rdf.Fmt_turtle,
-	rdf.Fmt_rdfxmlXmp,
-	rdf.Fmt_rdfxmlAbbrev,
-	rdf.Fmt_rdfxml,
-	rdf.Fmt_rss,
-	rdf.Fmt_atom,
-	rdf.Fmt_dot,
-	rdf.Fmt_jsonTriples,
-	rdf.Fmt_json,
-	rdf.Fmt_html,
-	rdf.Fmt_nquads,
+// Returns the strings of all the `lists`, in order.
+func concat(lists ...[]string) (res []string) {
+	for _, list := range lists {
+		res = append(res, list...)
+	}
+	return
 }
 
 // Flags supported by this tool.
@@ -206,6 +491,32 @@ var (
 	flagHelp          = flag.Bool("help", false, "Show help message.")
 	flagVersion       = flag.Bool("version", false, "Show tool version and supported SPDX spec versions.")
 	flagHTML          = flag.Bool("html", false, "In validation, open a browser with visual validation results. If -o is specified, write HTML to file instead.")
+	flagScan          = flag.String("scan", "-", "Set action to scan. Describe the input archive (.tar, .tar.gz, .tgz or .zip) as a SPDX document in the specified format.")
+	flagRecursive     = flag.Bool("r", false, "In scanning, also scan the archives found inside the input archive.")
+	flagGoMod         = flag.String("gomod", "-", "Set action to Go modules import. Describe the modules in the input (output of `go list -m -json all`, or go.mod) as a SPDX document in the specified format.")
+	flagModFile       = flag.String("modfile", "", "In Go modules import, the go.mod file to read. Default is go.mod next to the input file, if it exists.")
+	flagSumFile       = flag.String("sumfile", "", "In Go modules import, the go.sum file to read. Default is go.sum next to the input file, if it exists.")
+	flagGoBin         = flag.String("gobin", "-", "Set action to Go binary import. Describe the modules compiled in the input Go binary as a SPDX document in the specified format.")
+	flagReport        = flag.Bool("report", false, "Set action to report. Write a HTML report of the input document.")
+	flagNotice        = flag.String("notice", "-", "Set action to notice. Write the third-party notices of the input document in the specified format: text, markdown or html.")
+	flagTemplate      = flag.String("template", "", "In notice, the template file to use instead of the built-in template.")
+	flagTextDir       = flag.String("textdir", notice.LicenceTextDir, "In notice, the directory with the SPDX Licence List texts (<ID>.txt).")
+	flagGraph         = flag.String("graph", "-", "Set action to graph. Draw the structure of the input document in the specified format: dot or mermaid.")
+	flagCollapse      = flag.Bool("collapse", false, "In graph, show one node per directory instead of one per file.")
+	flagLenient       = flag.Bool("lenient", false, "Keep unknown properties instead of failing. Only in tag and RDF formats.")
+	flagDigest        = flag.Bool("digest", false, "Set action to digest. Print the SHA-256 digest of the canonical form of the input document.")
+	flagDiff          = flag.String("diff", "-", "Set action to diff. Compare the input document (the old one) to the second input file (the new one) and write the changes in the specified format: text or json.")
+	flagChangelog     = flag.String("changelog", "-", "Set action to changelog. Summarise the changes from the input document (the old release) to the second input file (the new release) in the specified format: markdown or json.")
+	flagMerge         = flag.String("merge", "-", "Set action to merge. Combine all the input files into one document in the specified format.")
+	flagSign          = flag.String("sign", "-", "Set action to sign. Sign the input document with the private key in the given PEM file; the signed document is written in the input format and the signature to -sig.")
+	flagVerify        = flag.String("verify", "-", "Set action to verify. Check the signature -sig of the input document with the public key (or certificate) in the given PEM file.")
+	flagSig           = flag.String("sig", "", "In sign and verify, the detached signature file.")
+	flagSigner        = flag.String("signer", "", "In sign, the signer identity recorded in the document. Default is the key fingerprint.")
+	flagSort          = flag.Bool("sort", false, "In tag output (-p, -c tag), write the files sorted by name and the extracted licences by ID.")
+	flagAlign         = flag.Bool("align", false, "In tag output (-p, -c tag), align the values of all the properties in one column.")
+	flagBlank         = flag.String("blank", "default", "In tag output (-p, -c tag), where to leave blank lines: default (before sections and comments), sections or none.")
+	flagTextOver      = flag.Int("textover", 0, "In tag output (-p, -c tag), write values longer than this in <text>. 0 means never.")
+	flagNormKeys      = flag.Bool("normkeys", false, "In tag output (-p, -c tag), write the property names in the case of the specification.")
 )
 
 var (
@@ -213,8 +524,60 @@ var (
 	output = os.Stdout // output *os.File
 )
 
-// Simple xor function.
-func xor(a, b bool) bool { return a != b }
+// An action of the tool, with the valid values of its flag if the flag is a
+// format (nil for the actions without a format).
+type action struct {
+	flag    string
+	formats []string
+}
+
+// The actions. Exactly one of their flags must be set.
+var actions = []action{
+	{"c", formatList},
+	{"v", nil},
+	{"p", nil},
+	{"scan", formatList},
+	{"gomod", formatList},
+	{"gobin", formatList},
+	{"report", nil},
+	{"notice", []string{notice.FormatText, notice.FormatMarkdown, notice.FormatHtml}},
+	{"graph", []string{graph.FormatDot, graph.FormatMermaid}},
+	{"digest", nil},
+	{"sign", nil},
+	{"verify", nil},
+	{"diff", []string{diff.FormatText, diff.FormatJson}},
+	{"changelog", []string{changelog.FormatMarkdown, changelog.FormatJson}},
+	{"merge", formatList},
+}
+
+// Checks whether the flag `name` is set to another value than its default.
+func flagSet(name string) bool {
+	f := flag.Lookup(name)
+	return f.Value.String() != f.DefValue
+}
+
+// Checks whether `val` is in `list`.
+func inList(val string, list []string) bool {
+	for _, v := range list {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the values of `list` quoted and separated by commas and "and", as
+// in error messages.
+func validValues(list []string) string {
+	quoted := make([]string, len(list))
+	for i, v := range list {
+		quoted[i] = "'" + v + "'"
+	}
+	if len(quoted) < 2 {
+		return strings.Join(quoted, "")
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " and " + quoted[len(quoted)-1]
+}
 
 // Exits the program and prints err in an appropriate format.
 func exitErr(err error) {
@@ -244,24 +607,72 @@ func main() {
 		return
 	}
 
-	if !xor(*flagConvert != "-", xor(*flagValidate, *flagFmt)) {
+	var act *action
+	for i := range actions {
+		if flagSet(actions[i].flag) {
+			if act != nil {
+				log.Fatal("No or invalid action flag specified. See -help for usage.")
+			}
+			act = &actions[i]
+		}
+	}
+	if act == nil {
 		log.Fatal("No or invalid action flag specified. See -help for usage.")
 	}
 
-	*flagConvert = strings.ToLower(*flagConvert)
-	if *flagConvert != "-" && !validFormat(*flagConvert, false) {
-		log.Fatalf("No or invalid output format (-f) specified (%s). Valid values are '%s' and '%s'.", *flagConvert, formatRdf, formatTag)
+	if act.formats != nil {
+		f := flag.Lookup(act.flag)
+		f.Value.Set(strings.ToLower(f.Value.String()))
+		if !inList(f.Value.String(), act.formats) {
+			log.Fatalf("No or invalid format (-%s) specified (%s). Valid values are %s.", act.flag, f.Value, validValues(act.formats))
+		}
+	}
+
+	if *flagScan != "-" && *flagInPlace {
+		log.Fatal("Cannot use -w flag when scanning archives. See -help for usage.")
+	}
+
+	if *flagDigest && *flagInPlace {
+		log.Fatal("Cannot use -w flag when computing the digest. See -help for usage.")
+	}
+
+	if (*flagSign != "-" || *flagVerify != "-") && *flagSig == "" {
+		log.Fatal("No signature file (-sig) specified. See -help for usage.")
+	}
+
+	if *flagVerify != "-" && *flagInPlace {
+		log.Fatal("Cannot use -w flag when verifying signatures. See -help for usage.")
+	}
+
+	if *flagDiff != "-" && (flag.NArg() != 2 || *flagInPlace) {
+		log.Fatal("The diff action needs two input files, the old and the new document, and cannot use -w. See -help for usage.")
+	}
+
+	if *flagChangelog != "-" && (flag.NArg() != 2 || *flagInPlace) {
+		log.Fatal("The changelog action needs two input files, the old and the new document, and cannot use -w. See -help for usage.")
+	}
+
+	if *flagMerge != "-" && (flag.NArg() < 2 || *flagInPlace) {
+		log.Fatal("The merge action needs at least two input files and cannot use -w. See -help for usage.")
+	}
+
+	if *flagGoMod != "-" && *flagInPlace {
+		log.Fatal("Cannot use -w flag when importing Go modules. See -help for usage.")
+	}
+
+	if *flagGoBin != "-" && *flagInPlace {
+		log.Fatal("Cannot use -w flag when importing Go binaries. See -help for usage.")
 	}
 
 	if !validFormat(*flagInputFormat, true) {
-		log.Fatalf("Invalid input format (-f). Valid values are '%s', '%s' and '%s'.", formatRdf, formatTag, formatAuto)
+		log.Fatalf("Invalid input format (-f). Valid values are %s.", validValues(concat(formatList, []string{formatAuto})))
 	}
 
 	if *flagInPlace && *flagOutput != "-" {
 		log.Fatal("Cannot have both -w and -o set. See -help for usage.")
 	}
 
-	if flag.NArg() >= 1 {
+	if flag.NArg() >= 1 && flag.Arg(0) != "-" {
 		var err error
 		input, err = os.Open(flag.Arg(0))
 		defer input.Close()
@@ -270,7 +681,11 @@ func main() {
 		}
 	}
 
-	if *flagOutput != "-" {
+	if outputFormat() == formatCsv && (*flagOutput == "-" || *flagInPlace) {
+		log.Fatal("CSV output is a directory. Please specify the output directory with -o. See -help for usage.")
+	}
+
+	if *flagOutput != "-" && outputFormat() != formatCsv {
 		var err error
 		output, err = os.Create(*flagOutput)
 		defer output.Close()
@@ -297,6 +712,21 @@ func main() {
 		}()
 	}
 
+	if *flagScan != "-" {
+		scan()
+		return
+	}
+
+	if *flagGoMod != "-" {
+		importGoMod()
+		return
+	}
+
+	if *flagGoBin != "-" {
+		importGoBin()
+		return
+	}
+
 	// auto-detect format
 	if *flagInputFormat == formatAuto {
 		format := detectFormat()
@@ -309,38 +739,104 @@ func main() {
 		validate()
 	} else if *flagFmt {
 		format()
+	} else if *flagReport {
+		writeReport()
+	} else if *flagNotice != "-" {
+		writeNotice()
+	} else if *flagGraph != "-" {
+		writeGraph()
+	} else if *flagDigest {
+		writeDigest()
+	} else if *flagSign != "-" {
+		signDocument()
+	} else if *flagVerify != "-" {
+		verifyDocument()
+	} else if *flagDiff != "-" {
+		writeDiff()
+	} else if *flagChangelog != "-" {
+		writeChangelog()
+	} else if *flagMerge != "-" {
+		mergeDocuments()
+	}
+}
+
+// Returns the output format of the action, or "" for validation and
+// pretty-printing.
+func outputFormat() string {
+	for _, format := range []string{*flagConvert, *flagScan, *flagGoMod, *flagGoBin, *flagMerge} {
+		if format != "-" {
+			return format
+		}
 	}
+	return ""
 }
 
 // Checks whether `val` is a valid input format.
 // If `allowAuto` is set to `false`, the format `"auto" (constant `formatAuto`)
 // will be considered invalid.
 func validFormat(val string, allowAuto bool) bool {
-	if val == formatRdf || val == formatTag || (val == formatAuto && allowAuto) {
-		return true
+	if val == formatAuto {
+		return allowAuto
 	}
-	return rdf.FormatOk(val)
+	return inList(val, formatList)
 }
 
 // Tries to guess the format of the input file. Does not work on stdin.
 // Current method:
-// 1. If input file extension is .tag or .rdf, the format is Tag or RDF, respectively.
-// 2. If the file starts with <?xml, <rdf, <!-- or @import, the format is RDF, otherwise Tag
+// 1. If input file extension is .spdx.json, the format is SPDX JSON.
+// 2. If input file extension is .cdx.json or .cdx.xml, or the file is named
+//    bom.json or bom.xml, the format is CycloneDX JSON or XML.
+// 3. If input file extension is .yaml or .yml, the format is SPDX YAML.
+// 4. If input file extension is .ttl, .nt or .jsonld, the format is Turtle,
+//    N-Triples or JSON-LD, respectively.
+// 5. If input file extension is .tag, .rdf or .xlsx, the format is Tag, RDF or
+//    XLSX, respectively.
+// 6. If the input is a directory, the format is CSV.
+// 7. If the file starts with <?xml, <rdf, <!--, @prefix or @base, the format is
+//    RDF, otherwise Tag
 func detectFormat() string {
 	if input == os.Stdin {
 		log.Fatal("Cannot auto-detect format from stdin.")
 	}
 
+	name := strings.ToLower(input.Name())
+	if strings.HasSuffix(name, ".spdx.json") {
+		return formatJson
+	}
+	if strings.HasSuffix(name, ".cdx.json") || filepath.Base(name) == "bom.json" {
+		return formatCdxJson
+	}
+	if strings.HasSuffix(name, ".cdx.xml") || filepath.Base(name) == "bom.xml" {
+		return formatCdxXml
+	}
+	if strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
+		return formatYaml
+	}
+	if strings.HasSuffix(name, ".ttl") {
+		return rdf.Fmt_turtle
+	}
+	if strings.HasSuffix(name, ".nt") {
+		return rdf.Fmt_ntriples
+	}
+	if strings.HasSuffix(name, ".jsonld") {
+		return rdf.Fmt_jsonld
+	}
+
 	if dot := strings.LastIndex(input.Name(), "."); dot+1 < len(input.Name()) {
-		// check extension (if .tag or .rdf)
+		// check extension (if .tag, .rdf or .xlsx)
 		format := strings.ToLower(input.Name()[dot+1:])
 		if validFormat(format, false) {
 			return format
 		}
 	}
 
+	if info, err := input.Stat(); err == nil && info.IsDir() {
+		return formatCsv
+	}
+
 	// Needs improvement but not a priority.
-	// Only detects XML RDF or files starting with @import (turtle format) as RDF
+	// Only detects XML RDF or files starting with @prefix or @base (turtle
+	// format) as RDF
 	defer func() {
 		input.Close()
 		var err error
@@ -353,7 +849,7 @@ func detectFormat() string {
 	scanner.Split(bufio.ScanWords)
 	if scanner.Scan() {
 		word := strings.ToLower(scanner.Text())
-		if strings.HasPrefix(word, "<?xml") || strings.HasPrefix(word, "<rdf") || strings.HasPrefix(word, "<!--") || strings.HasPrefix(word, "@import") {
+		if strings.HasPrefix(word, "<?xml") || strings.HasPrefix(word, "<rdf") || strings.HasPrefix(word, "<!--") || strings.HasPrefix(word, "@prefix") || strings.HasPrefix(word, "@base") {
 			return formatRdf
 		}
 		return formatTag
@@ -365,61 +861,402 @@ func detectFormat() string {
 
 // Convert between SPDX formats action.
 func convert() {
-	var doc *spdx.Document
-	var err error
+	doc, err := readDocument()
+	if err != nil {
+		exitErr(err)
+	}
 
-	if *flagInputFormat == formatTag {
+	if err = writeDocument(doc, *flagConvert); err != nil {
+		exitErr(err)
+	}
+}
+
+// Parse the input in the input format (-f) to a *spdx.Document.
+func readDocument() (*spdx.Document, error) {
+	switch *flagInputFormat {
+	case formatTag:
 		tag.CaseSensitive(*flagCaseSensitive)
-		doc, err = tag.Build(input)
+		tag.Lenient(*flagLenient)
+		return tag.Build(input)
+	case formatJson:
+		return json.Build(input)
+	case formatYaml:
+		return readYaml(input)
+	case formatCdxJson, formatCdxXml:
+		return readCycloneDX()
+	case formatXlsx:
+		return spreadsheet.BuildXlsx(input)
+	case formatCsv:
+		return spreadsheet.BuildCsv(input.Name())
+	}
+	parser := rdf.NewParser(input, *flagInputFormat)
+	defer parser.Free()
+	parser.Lenient = *flagLenient
+	return parser.Parse()
+}
+
+// Parse the input like readDocument() but, in the Tag format, do not stop at
+// the first error: the document has all the valid properties and the parse
+// errors are returned as validation errors.
+func readDocumentRecover() (*spdx.Document, []*spdx.ValidationError, error) {
+	if *flagInputFormat != formatTag {
+		doc, err := readDocument()
+		return doc, nil, err
+	}
+	tag.CaseSensitive(*flagCaseSensitive)
+	tag.Lenient(*flagLenient)
+	doc, perrs, err := tag.BuildRecover(input)
+	if err != nil {
+		return nil, nil, err
+	}
+	errs := make([]*spdx.ValidationError, len(perrs))
+	for i, e := range perrs {
+		errs[i] = spdx.NewVError("Parse error: "+e.Error(), e.Meta)
+	}
+	return doc, errs, nil
+}
+
+// Read a CycloneDX BOM from the input and convert it to a *spdx.Document.
+func readCycloneDX() (*spdx.Document, error) {
+	var bom *cyclonedx.Bom
+	var err error
+	if *flagInputFormat == formatCdxXml {
+		bom, err = cyclonedx.ReadXml(input)
 	} else {
-		doc, err = rdf.Parse(input, *flagInputFormat)
+		bom, err = cyclonedx.ReadJson(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return cyclonedx.ToSpdx(bom)
+}
+
+// Write `doc` to the output in the given format.
+func writeDocument(doc *spdx.Document, format string) error {
+	switch format {
+	case formatTag:
+		return tagFormatter().Document(doc)
+	case formatJson:
+		return json.Write(output, doc)
+	case formatYaml:
+		return writeYaml(output, doc)
+	case formatCdxJson, formatCdxXml:
+		return writeCycloneDX(doc, format)
+	case formatXlsx:
+		return spreadsheet.WriteXlsx(output, doc)
+	case formatCsv:
+		return spreadsheet.WriteCsv(*flagOutput, doc)
 	}
+	return rdf.WriteFormat(output, doc, format)
+}
+
+// Write `doc` to the output as CycloneDX and list the values which could not
+// be converted on stderr.
+func writeCycloneDX(doc *spdx.Document, format string) error {
+	bom, losses := cyclonedx.FromSpdx(doc)
+	for _, loss := range losses {
+		if loss.Meta != nil {
+			log.Printf("%s:%d Not converted to CycloneDX: %s", input.Name(), loss.LineStart, loss)
+		} else {
+			log.Printf("Not converted to CycloneDX: %s", loss)
+		}
+	}
+	if format == formatCdxXml {
+		return cyclonedx.WriteXml(output, bom)
+	}
+	return cyclonedx.WriteJson(output, bom)
+}
 
+// Scan archive action.
+func scan() {
+	scanner := archive.NewScanner()
+	scanner.Recursive = *flagRecursive
+	doc, err := scanner.Scan(input, filepath.Base(input.Name()))
 	if err != nil {
 		exitErr(err)
 	}
 
-	if *flagConvert == formatTag {
-		err = tag.Write(output, doc)
+	if err = writeDocument(doc, *flagScan); err != nil {
+		exitErr(err)
+	}
+}
+
+// Open the file `name` or, if `name` is empty, the file `def` in the directory
+// of the input file (current directory for stdin). Returns nil if the default
+// file does not exist.
+func openBesideInput(name, def string) *os.File {
+	if name != "" {
+		f, err := os.Open(name)
+		if err != nil {
+			exitErr(err)
+		}
+		return f
+	}
+	dir := "."
+	if input != os.Stdin {
+		dir = filepath.Dir(input.Name())
+	}
+	f, err := os.Open(filepath.Join(dir, def))
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// Go modules import action.
+func importGoMod() {
+	im := gomod.NewImporter()
+	in := bufio.NewReader(input)
+	start, _ := in.Peek(512)
+	readMod := true // whether to read the go.mod file beside the input
+	if gomod.IsList(start) {
+		if err := im.List(in); err != nil {
+			exitErr(err)
+		}
 	} else {
-		err = rdf.WriteFormat(output, doc, *flagConvert)
+		// the input is a go.mod file
+		if err := im.GoMod(in); err != nil {
+			exitErr(err)
+		}
+		readMod = *flagModFile != ""
+	}
+
+	if readMod {
+		if mod := openBesideInput(*flagModFile, "go.mod"); mod != nil {
+			defer mod.Close()
+			if err := im.GoMod(mod); err != nil {
+				exitErr(err)
+			}
+		}
+	}
+
+	if sum := openBesideInput(*flagSumFile, "go.sum"); sum != nil {
+		defer sum.Close()
+		if err := im.GoSum(sum); err != nil {
+			exitErr(err)
+		}
+	}
+
+	if err := writeDocument(im.Document(), *flagGoMod); err != nil {
+		exitErr(err)
+	}
+}
+
+// Go binary import action.
+func importGoBin() {
+	if input == os.Stdin {
+		log.Fatal("Cannot read Go binaries from stdin. Please specify an input file. See -help for usage.")
 	}
 
+	doc, err := gomod.BinaryFile(input.Name())
 	if err != nil {
 		exitErr(err)
 	}
+
+	if err = writeDocument(doc, *flagGoBin); err != nil {
+		exitErr(err)
+	}
 }
 
-// Validate action, text outout.
-func validate() {
-	var doc *spdx.Document
-	var err error
+// HTML report action.
+func writeReport() {
+	doc, err := readDocument()
+	if err != nil {
+		exitErr(err)
+	}
 
-	if *flagInputFormat == formatTag {
-		tag.CaseSensitive(*flagCaseSensitive)
-		doc, err = tag.Build(input)
-	} else {
-		doc, err = rdf.Parse(input, *flagInputFormat)
+	if err = report.Write(output, doc); err != nil {
+		exitErr(err)
+	}
+}
+
+// Third-party notices action.
+func writeNotice() {
+	doc, err := readDocument()
+	if err != nil {
+		exitErr(err)
+	}
+
+	tmpl := notice.Builtin(*flagNotice)
+	if *flagTemplate != "" {
+		text, err := ioutil.ReadFile(*flagTemplate)
+		if err != nil {
+			exitErr(err)
+		}
+		if tmpl, err = notice.Parse(*flagNotice, string(text)); err != nil {
+			exitErr(err)
+		}
+	}
+
+	notice.LicenceTextDir = *flagTextDir
+	n := notice.New(doc)
+	for _, id := range n.Missing {
+		log.Printf("No licence text found for %s.", id)
+	}
+	if err = n.Execute(output, tmpl); err != nil {
+		exitErr(err)
+	}
+}
+
+// Document graph action.
+func writeGraph() {
+	doc, err := readDocument()
+	if err != nil {
+		exitErr(err)
+	}
+
+	if err = graph.Write(output, doc, *flagGraph, &graph.Options{CollapseDirs: *flagCollapse}); err != nil {
+		exitErr(err)
+	}
+}
+
+// Digest action. Prints the hex digest of the canonical form of the input.
+func writeDigest() {
+	doc, err := readDocument()
+	if err != nil {
+		exitErr(err)
+	}
+	fmt.Fprintf(output, "%x\n", spdx.Digest(doc))
+}
+
+// Reads the documents of all the input files, in the input format (-f) or in
+// their own detected format.
+func readDocuments() []*spdx.Document {
+	autoFormat := true
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "f" {
+			autoFormat = *flagInputFormat == formatAuto
+		}
+	})
+
+	docs := make([]*spdx.Document, flag.NArg())
+	for i := range docs {
+		var err error
+		if i > 0 {
+			input.Close()
+			if input, err = os.Open(flag.Arg(i)); err != nil {
+				exitErr(err)
+			}
+			if autoFormat {
+				format := detectFormat()
+				flagInputFormat = &format
+			}
+		}
+		if docs[i], err = readDocument(); err != nil {
+			exitErr(err)
+		}
+	}
+	return docs
+}
+
+// Diff action. Compares the input document to the second input file.
+func writeDiff() {
+	docs := readDocuments()
+	if err := diff.Write(output, diff.Documents(docs[0], docs[1]), *flagDiff); err != nil {
+		exitErr(err)
+	}
+}
+
+// Changelog action. Summarises the changes from the input document to the
+// second input file.
+func writeChangelog() {
+	docs := readDocuments()
+	if err := changelog.Write(output, changelog.Build(docs[0], docs[1]), *flagChangelog); err != nil {
+		exitErr(err)
+	}
+}
+
+// Merge action. Writes the documents of all the input files as one document
+// and lists the renamed licences on stderr.
+func mergeDocuments() {
+	doc, renames := merge.Documents(readDocuments()...)
+	for _, r := range renames {
+		log.Printf("%s: %s renamed to %s", flag.Arg(r.Doc), r.Old, r.New)
+	}
+	if err := writeDocument(doc, *flagMerge); err != nil {
+		exitErr(err)
+	}
+}
+
+// Sign action. Writes the signed document in the input format and the
+// signature to the -sig file.
+func signDocument() {
+	pemKey, err := ioutil.ReadFile(*flagSign)
+	if err != nil {
+		exitErr(err)
+	}
+	key, err := sign.ParsePrivateKey(pemKey)
+	if err != nil {
+		exitErr(err)
+	}
+
+	doc, err := readDocument()
+	if err != nil {
+		exitErr(err)
+	}
+	sig, err := sign.Sign(doc, key, *flagSigner)
+	if err != nil {
+		exitErr(err)
+	}
+
+	if err = ioutil.WriteFile(*flagSig, sig.Encode(), 0644); err != nil {
+		exitErr(err)
+	}
+	if err = writeDocument(doc, *flagInputFormat); err != nil {
+		exitErr(err)
+	}
+}
+
+// Verify action. Checks the -sig signature of the input document.
+func verifyDocument() {
+	pemKey, err := ioutil.ReadFile(*flagVerify)
+	if err != nil {
+		exitErr(err)
+	}
+	pub, err := sign.ParsePublicKey(pemKey)
+	if err != nil {
+		exitErr(err)
+	}
+	data, err := ioutil.ReadFile(*flagSig)
+	if err != nil {
+		exitErr(err)
+	}
+	sig, err := sign.ParseSignature(data)
+	if err != nil {
+		exitErr(err)
 	}
 
+	doc, err := readDocument()
+	if err != nil {
+		exitErr(err)
+	}
+	if err = sign.Verify(doc, sig, pub); err != nil {
+		exitErr(err)
+	}
+	fmt.Fprintf(output, "Signature is valid. Signed by %s (key SHA256:%s).\n", sig.Signer, sig.Fingerprint)
+}
+
+// Validate action, text outout.
+func validate() {
+	doc, errs, err := readDocumentRecover()
 	if err != nil {
 		exitErr(err)
 	}
 
 	validator := spdx.NewValidator()
 	validator.Document(doc)
+	errs = append(errs, validator.Errors()...)
 
 	if *flagHTML {
-		validateHtml(doc, validator)
+		validateHtml(doc, errs)
 		return
 	}
 
-	if validator.Ok() {
+	if len(errs) == 0 {
 		io.WriteString(output, "Document is valid.\n")
 		os.Exit(0)
 	}
 
-	errs := validator.Errors()
 	warnings, errors := 0, 0
 	for _, e := range errs {
 		if e.Type == spdx.ValidError {
@@ -466,13 +1303,13 @@ type summary struct {
 }
 
 // Validate and output HTML.
-func validateHtml(doc *spdx.Document, validator *spdx.Validator) {
+func validateHtml(doc *spdx.Document, errs []*spdx.ValidationError) {
 	sum := new(summary)
 	sum.FileName = input.Name()
 	sum.OtherErrors = make([]*spdx.ValidationError, 0)
 
 	errmap := make(map[int][]*spdx.ValidationError)
-	for _, e := range validator.Errors() {
+	for _, e := range errs {
 		if e.Meta != nil {
 			if _, ok := errmap[e.Meta.LineStart]; ok {
 				errmap[e.Meta.LineStart] = append(errmap[e.Meta.LineStart], e)
@@ -563,9 +1400,37 @@ func startBrowser(url string) bool {
 }
 
 // Format action.
+// Create a tag.Formatter writing to the output, with the formatting flags.
+func tagFormatter() *tag.Formatter {
+	f := tag.NewFormatter(output)
+	f.Sort = *flagSort
+	f.Align = *flagAlign
+	f.TextOver = *flagTextOver
+	f.NormaliseKeys = *flagNormKeys
+	switch *flagBlank {
+	case "default":
+		f.Blank = tag.BlankDefault
+	case "sections":
+		f.Blank = tag.BlankSections
+	case "none":
+		f.Blank = tag.BlankNone
+	default:
+		log.Fatalf("Invalid -blank value %q. See -help for usage.", *flagBlank)
+	}
+	return f
+}
+
 func format() {
+	if *flagInputFormat == formatCdxJson || *flagInputFormat == formatCdxXml {
+		log.Fatal("Cannot pretty-print CycloneDX documents. See -help for usage.")
+	}
+
+	if *flagInputFormat == formatXlsx || *flagInputFormat == formatCsv {
+		log.Fatal("Cannot pretty-print spreadsheets. See -help for usage.")
+	}
+
 	if *flagInputFormat == formatTag {
-		f := tag.NewFormatter(output)
+		f := tagFormatter()
 		lex := tag.NewLexer(input)
 		err := f.Lexer(lex)
 		if err != nil {
@@ -574,6 +1439,17 @@ func format() {
 		return
 	}
 
+	if *flagInputFormat == formatJson || *flagInputFormat == formatYaml {
+		doc, err := readDocument()
+		if err != nil {
+			exitErr(err)
+		}
+		if err = writeDocument(doc, *flagInputFormat); err != nil {
+			exitErr(err)
+		}
+		return
+	}
+
 	err := rdf.WriteRdf(input, output, *flagInputFormat, *flagInputFormat)
 	if err != nil {
 		exitErr(err)
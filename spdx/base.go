@@ -31,6 +31,13 @@ const (
 	NONE        = "NONE"
 )
 
+// Checks if `val` is a SPDX value that does not say anything: an empty
+// string, NOASSERTION or NONE. Surrounding whitespace is ignored.
+func IsEmpty(val string) bool {
+	val = strings.TrimSpace(val)
+	return val == "" || val == NOASSERTION || val == NONE
+}
+
 // Interface to be used for SPDX Elements.
 // Implemented by Value(Str|Bool|Date|Creator)
 type Value interface {
@@ -159,6 +166,28 @@ func NewMeta(start, end int) *Meta {
 	return &Meta{start, end}
 }
 
+// A property which is not defined by the SPDX specification (e.g. a
+// vendor-specific extension), kept by the lenient Tag and RDF parsers and by
+// the SPDX JSON parser. Key is the Tag property name, the RDF predicate or,
+// if Json is set, the SPDX JSON member name, in which case Value is the JSON
+// text of the member value.
+type Extension struct {
+	Key   string
+	Value string
+	Json  bool
+	*Meta
+}
+
+// A comment (# ...) of a Tag document, kept with the element of the property
+// it precedes so that the Tag writer can write it back in place. Text is the
+// comment without the '#'; Before is the property the comment precedes, or ""
+// for the comments at the end of the document.
+type TagComment struct {
+	Text   string
+	Before string
+	*Meta
+}
+
 // strings.Join for ValueStr type.
 func Join(a []ValueStr, sep string) string {
 	if len(a) == 0 {
@@ -52,3 +52,14 @@ func TestJoinValueStr(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestIsEmpty(t *testing.T) {
+	for _, val := range []string{"", " ", NOASSERTION, " NONE "} {
+		if !IsEmpty(val) {
+			t.Errorf("%#v not empty", val)
+		}
+	}
+	if IsEmpty("MIT") {
+		t.Error("MIT is empty")
+	}
+}
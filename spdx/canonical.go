@@ -0,0 +1,371 @@
+package spdx
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Returns the canonical form of `doc`: a deterministic serialisation which
+// only depends on the content of the document.
+//
+// In the canonical form:
+//   - metadata and Tag comments are ignored
+//   - packages, files, extracted licences, reviews and all the multi-valued
+//     properties are sorted
+//   - the files of the packages are listed with the document files, as the
+//     Tag format does not keep the package of a file
+//   - licence sets are flattened and their members sorted and deduplicated
+//   - whitespace is normalised: no leading or trailing whitespace and single
+//     spaces between words, on every line
+//   - dates are in UTC and checksum algorithms, file types and the data licence
+//     are written as in the Tag format
+//   - a missing package download location is NOASSERTION
+//   - the identifiers of serialisation formats (see Package.Identifiers) are
+//     ignored
+//
+// Two documents differing only in formatting, order or serialisation format
+// have the same canonical form.
+func Canonical(doc *Document) []byte {
+	if doc == nil {
+		doc = new(Document)
+	}
+	w := new(canonWriter)
+	w.prop("SPDXVersion", doc.SpecVersion.Val)
+	w.prop("DataLicense", strings.TrimPrefix(normSpace(doc.DataLicence.Val), "http://spdx.org/licenses/"))
+	w.prop("DocumentComment", doc.Comment.Val)
+	if ci := doc.CreationInfo; ci != nil {
+		creators := make([]string, len(ci.Creator))
+		for i, cr := range ci.Creator {
+			creators[i] = cr.V()
+		}
+		w.props("Creator", creators)
+		w.prop("Created", canonDate(ci.Created))
+		w.prop("LicenseListVersion", ci.LicenceListVersion.Val)
+		w.prop("CreatorComment", ci.Comment.Val)
+		w.extensions(ci.Extensions)
+	}
+	w.extensions(doc.Extensions)
+
+	var blocks []string
+	for _, pkg := range doc.Packages {
+		if pkg != nil {
+			blocks = append(blocks, canonPackage(pkg))
+		}
+	}
+	w.blocks(blocks)
+
+	blocks = nil
+	seen := make(map[*File]bool)
+	files := append([]*File(nil), doc.Files...)
+	for _, pkg := range doc.Packages {
+		files = append(files, pkg.Files...)
+	}
+	for _, file := range files {
+		if file != nil && !seen[file] {
+			seen[file] = true
+			blocks = append(blocks, canonFile(file))
+		}
+	}
+	w.blocks(blocks)
+
+	blocks = nil
+	for _, lic := range doc.ExtractedLicences {
+		if lic != nil {
+			blocks = append(blocks, canonExtractedLicence(lic))
+		}
+	}
+	w.blocks(blocks)
+
+	blocks = nil
+	for _, rev := range doc.Reviews {
+		if rev != nil {
+			blocks = append(blocks, canonReview(rev))
+		}
+	}
+	w.blocks(blocks)
+
+	return []byte(w.String())
+}
+
+// Returns the SHA-256 digest of the canonical form of `doc` (see
+// Canonical()).
+func Digest(doc *Document) [32]byte {
+	return sha256.Sum256(Canonical(doc))
+}
+
+// Writes the canonical form, one quoted property value per line.
+type canonWriter struct {
+	strings.Builder
+}
+
+// Write a property, unless its value is empty.
+func (w *canonWriter) prop(key, value string) {
+	value = normSpace(value)
+	if value == "" {
+		return
+	}
+	w.WriteString(key + " " + strconv.Quote(value) + "\n")
+}
+
+// Write a property with multiple values, sorted.
+func (w *canonWriter) props(key string, values []string) {
+	sorted := make([]string, len(values))
+	for i, v := range values {
+		sorted[i] = normSpace(v)
+	}
+	sort.Strings(sorted)
+	for _, v := range sorted {
+		w.prop(key, v)
+	}
+}
+
+// Write a list of ValueStr, sorted.
+func (w *canonWriter) strs(key string, values []ValueStr) {
+	strs := make([]string, len(values))
+	for i, v := range values {
+		strs[i] = v.Val
+	}
+	w.props(key, strs)
+}
+
+// Write a list of licences, sorted and deduplicated.
+func (w *canonWriter) licences(key string, lics []AnyLicence) {
+	var ids []string
+	for _, lic := range lics {
+		ids = append(ids, CanonicalLicence(lic))
+	}
+	for _, id := range dedup(ids) {
+		w.prop(key, id)
+	}
+}
+
+// Write the extensions, sorted. The values of SPDX JSON extensions which are
+// JSON strings are written as the strings, as the other extensions.
+func (w *canonWriter) extensions(exts []Extension) {
+	strs := make([]string, len(exts))
+	for i, ext := range exts {
+		val := ext.Value
+		if ext.Json {
+			var str string
+			if json.Unmarshal([]byte(val), &str) == nil {
+				val = str
+			}
+		}
+		strs[i] = normSpace(ext.Key) + " " + strconv.Quote(normSpace(val))
+	}
+	w.props("Extension", strs)
+}
+
+// Write the element blocks, sorted.
+func (w *canonWriter) blocks(blocks []string) {
+	sort.Strings(blocks)
+	for _, b := range blocks {
+		w.WriteString(b)
+	}
+}
+
+// Returns the canonical form of a package.
+func canonPackage(pkg *Package) string {
+	w := new(canonWriter)
+	w.WriteString("Package\n")
+	w.prop("PackageName", pkg.Name.Val)
+	w.prop("PackageVersion", pkg.Version.Val)
+	w.prop("PackageFileName", pkg.FileName.Val)
+	w.prop("PackageSupplier", pkg.Supplier.V())
+	w.prop("PackageOriginator", pkg.Originator.V())
+	// SPDX JSON requires a download location: none is written NOASSERTION
+	location := pkg.DownloadLocation.Val
+	if strings.TrimSpace(location) == "" {
+		location = NOASSERTION
+	}
+	w.prop("PackageDownloadLocation", location)
+	if vc := pkg.VerificationCode; vc != nil {
+		w.prop("PackageVerificationCode", strings.ToLower(normSpace(vc.Value.Val)))
+		w.strs("PackageVerificationCodeExcludedFile", vc.ExcludedFiles)
+	}
+	w.checksum("PackageChecksum", pkg.Checksum)
+	w.prop("PackageHomePage", pkg.HomePage.Val)
+	w.prop("PackageSourceInfo", pkg.SourceInfo.Val)
+	w.prop("PackageLicenseConcluded", CanonicalLicence(pkg.LicenceConcluded))
+	w.licences("PackageLicenseInfoFromFiles", pkg.LicenceInfoFromFiles)
+	w.prop("PackageLicenseDeclared", CanonicalLicence(pkg.LicenceDeclared))
+	w.prop("PackageLicenseComments", pkg.LicenceComments.Val)
+	w.prop("PackageCopyrightText", pkg.CopyrightText.Val)
+	w.prop("PackageSummary", pkg.Summary.Val)
+	w.prop("PackageDescription", pkg.Description.Val)
+	w.extensions(pkg.Extensions)
+	return w.String()
+}
+
+// Returns the canonical form of a file.
+func canonFile(file *File) string {
+	w := new(canonWriter)
+	w.WriteString("File\n")
+	w.prop("FileName", file.Name.Val)
+	fileType := strings.ToUpper(normSpace(file.Type.Val))
+	w.prop("FileType", strings.TrimPrefix(fileType, "FILETYPE_"))
+	w.checksum("FileChecksum", file.Checksum)
+	w.prop("LicenseConcluded", CanonicalLicence(file.LicenceConcluded))
+	w.licences("LicenseInfoInFile", file.LicenceInfoInFile)
+	w.prop("LicenseComments", file.LicenceComments.Val)
+	w.prop("FileCopyrightText", file.CopyrightText.Val)
+	w.prop("FileNotice", file.Notice.Val)
+	var artifacts []string
+	for _, a := range file.ArtifactOf {
+		if a != nil {
+			artifacts = append(artifacts, strconv.Quote(normSpace(a.Name.Val))+" "+
+				strconv.Quote(normSpace(a.HomePage.Val))+" "+strconv.Quote(normSpace(a.ProjectUri.Val)))
+		}
+	}
+	w.props("ArtifactOf", artifacts)
+	var deps []string
+	for _, dep := range file.Dependency {
+		if dep != nil {
+			deps = append(deps, dep.Name.Val)
+		}
+	}
+	w.props("FileDependency", deps)
+	w.strs("FileContributor", file.Contributor)
+	w.prop("FileComment", file.Comment.Val)
+	w.extensions(file.Extensions)
+	return w.String()
+}
+
+// Returns the canonical form of an extracted licence.
+func canonExtractedLicence(lic *ExtractedLicence) string {
+	w := new(canonWriter)
+	w.WriteString("ExtractedLicense\n")
+	w.prop("LicenseID", lic.Id.Val)
+	w.prop("ExtractedText", lic.Text.Val)
+	w.strs("LicenseName", lic.Name)
+	w.strs("LicenseCrossReference", lic.CrossReference)
+	w.prop("LicenseComment", lic.Comment.Val)
+	w.extensions(lic.Extensions)
+	return w.String()
+}
+
+// Returns the canonical form of a review.
+func canonReview(rev *Review) string {
+	w := new(canonWriter)
+	w.WriteString("Review\n")
+	w.prop("Reviewer", rev.Reviewer.V())
+	w.prop("ReviewDate", canonDate(rev.Date))
+	w.prop("ReviewComment", rev.Comment.Val)
+	w.extensions(rev.Extensions)
+	return w.String()
+}
+
+// Write a checksum, with the algorithm in upper case and the value in lower
+// case.
+func (w *canonWriter) checksum(key string, cksum *Checksum) {
+	if cksum == nil {
+		return
+	}
+	algo := strings.ToUpper(normSpace(cksum.Algo.Val))
+	value := strings.ToLower(normSpace(cksum.Value.Val))
+	if algo != "" || value != "" {
+		w.prop(key, algo+": "+value)
+	}
+}
+
+// Returns the canonical licence expression of `lic`, as in Canonical():
+// licence sets are flattened and their members sorted and deduplicated.
+func CanonicalLicence(lic AnyLicence) string {
+	switch l := lic.(type) {
+	case nil:
+		return ""
+	case *ExtractedLicence:
+		if l == nil {
+			return ""
+		}
+		return normSpace(l.Id.Val)
+	case ConjunctiveLicenceSet:
+		return canonSet(l.Members, " and ", conjunctiveMembers)
+	case *ConjunctiveLicenceSet:
+		return canonSet(l.Members, " and ", conjunctiveMembers)
+	case DisjunctiveLicenceSet:
+		return canonSet(l.Members, " or ", disjunctiveMembers)
+	case *DisjunctiveLicenceSet:
+		return canonSet(l.Members, " or ", disjunctiveMembers)
+	default:
+		return normSpace(lic.LicenceId())
+	}
+}
+
+// Returns the members of `lic` if it is a conjunctive licence set.
+func conjunctiveMembers(lic AnyLicence) []AnyLicence {
+	switch set := lic.(type) {
+	case ConjunctiveLicenceSet:
+		return set.Members
+	case *ConjunctiveLicenceSet:
+		return set.Members
+	}
+	return nil
+}
+
+// Returns the members of `lic` if it is a disjunctive licence set.
+func disjunctiveMembers(lic AnyLicence) []AnyLicence {
+	switch set := lic.(type) {
+	case DisjunctiveLicenceSet:
+		return set.Members
+	case *DisjunctiveLicenceSet:
+		return set.Members
+	}
+	return nil
+}
+
+// Returns the canonical expression of a licence set. `nested` returns the
+// members of a member which is a set of the same kind, to flatten it.
+func canonSet(members []AnyLicence, sep string, nested func(AnyLicence) []AnyLicence) string {
+	var ids []string
+	var add func([]AnyLicence)
+	add = func(members []AnyLicence) {
+		for _, m := range members {
+			if inner := nested(m); inner != nil {
+				add(inner)
+			} else if id := CanonicalLicence(m); id != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	add(members)
+	ids = dedup(ids)
+	if len(ids) == 1 {
+		return ids[0]
+	}
+	return "(" + strings.Join(ids, sep) + ")"
+}
+
+// Returns the date in UTC if it is valid, otherwise the original value.
+func canonDate(d ValueDate) string {
+	if t := d.Time(); t != nil {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return d.V()
+}
+
+// Returns `strs` sorted, without duplicates.
+func dedup(strs []string) []string {
+	sort.Strings(strs)
+	res := strs[:0]
+	for _, s := range strs {
+		if len(res) == 0 || s != res[len(res)-1] {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// Normalises the whitespace of `str`: every line is trimmed and has single
+// spaces between words, and leading and trailing blank lines are removed.
+func normSpace(str string) string {
+	lines := strings.Split(strings.Replace(str, "\r\n", "\n", -1), "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -0,0 +1,104 @@
+package spdx
+
+import "testing"
+
+// Returns a document with the given file names, licence IDs and creator
+// comment, and a file licence set of the given members.
+func canonDoc(files, licences []string, comment string, set AnyLicence) *Document {
+	doc := &Document{
+		SpecVersion: Str("SPDX-1.2", NewMetaL(1)),
+		DataLicence: Str("CC0-1.0", nil),
+		CreationInfo: &CreationInfo{
+			Creator: []ValueCreator{NewValueCreator("Tool: b", nil), NewValueCreator("Person: a", nil)},
+			Created: NewValueDate("2014-01-01T00:00:00Z", nil),
+			Comment: Str(comment, nil),
+		},
+		Packages: []*Package{{Name: Str("p", nil), LicenceDeclared: set}},
+	}
+	for _, name := range files {
+		doc.Files = append(doc.Files, &File{Name: Str(name, nil), LicenceConcluded: set})
+	}
+	for _, id := range licences {
+		doc.ExtractedLicences = append(doc.ExtractedLicences, &ExtractedLicence{Id: Str(id, nil), Text: Str("text", nil)})
+	}
+	return doc
+}
+
+func TestDigestSame(t *testing.T) {
+	mit, gpl := NewLicence("MIT", nil), NewLicence("GPL-2.0", nil)
+	a := canonDoc([]string{"a.c", "b.c"}, []string{"LicenseRef-1", "LicenseRef-2"}, "two\nlines",
+		NewConjunctiveSet(nil, mit, gpl))
+	b := canonDoc([]string{"b.c", "a.c"}, []string{"LicenseRef-2", "LicenseRef-1"}, "  two  \r\nlines\n",
+		NewConjunctiveSet(NewMetaL(3), NewConjunctiveSet(nil, gpl, mit), mit))
+	b.CreationInfo.Creator[0], b.CreationInfo.Creator[1] = b.CreationInfo.Creator[1], b.CreationInfo.Creator[0]
+	b.CreationInfo.Created = NewValueDate("2014-01-01T02:00:00+02:00", nil)
+	b.Files[0].Meta = NewMetaL(10)
+	b.TagComments = []TagComment{{Text: "comment", Before: "SPDXVersion"}}
+
+	if Digest(a) != Digest(b) {
+		t.Errorf("Different digests of\n%s\nand\n%s", Canonical(a), Canonical(b))
+	}
+}
+
+func TestDigestPackageFiles(t *testing.T) {
+	a := canonDoc([]string{"a.c", "b.c"}, nil, "", nil)
+	b := canonDoc([]string{"a.c"}, nil, "", nil)
+	b.Packages[0].Files = []*File{{Name: Str("b.c", nil)}, b.Files[0]}
+	if Digest(a) != Digest(b) {
+		t.Errorf("Different digests of\n%s\nand\n%s", Canonical(a), Canonical(b))
+	}
+}
+
+func TestDigestDifferent(t *testing.T) {
+	mit, gpl := NewLicence("MIT", nil), NewLicence("GPL-2.0", nil)
+	base := Digest(canonDoc([]string{"a.c"}, []string{"LicenseRef-1"}, "", NewConjunctiveSet(nil, mit, gpl)))
+	docs := []*Document{
+		canonDoc([]string{"a.c", "b.c"}, []string{"LicenseRef-1"}, "", NewConjunctiveSet(nil, mit, gpl)),
+		canonDoc([]string{"a.c"}, nil, "", NewConjunctiveSet(nil, mit, gpl)),
+		canonDoc([]string{"a.c"}, []string{"LicenseRef-1"}, "comment", NewConjunctiveSet(nil, mit, gpl)),
+		canonDoc([]string{"a.c"}, []string{"LicenseRef-1"}, "", NewDisjunctiveSet(nil, mit, gpl)),
+		canonDoc([]string{"a.c"}, []string{"LicenseRef-1"}, "", mit),
+	}
+	for i, doc := range docs {
+		if Digest(doc) == base {
+			t.Errorf("%d: Same digest for a different document\n%s", i, Canonical(doc))
+		}
+	}
+}
+
+func TestCanonicalLicence(t *testing.T) {
+	mit, gpl, ref := NewLicence("MIT", nil), NewLicence("GPL-2.0", nil), &ExtractedLicence{Id: Str("LicenseRef-1", nil)}
+	or, and := NewDisjunctiveSet(nil, mit, gpl), NewConjunctiveSet(nil, ref, mit)
+	tests := []struct {
+		lic      AnyLicence
+		expected string
+	}{
+		{nil, ""},
+		{mit, "MIT"},
+		{ref, "LicenseRef-1"},
+		{NewConjunctiveSet(nil, mit), "MIT"},
+		{NewDisjunctiveSet(nil, mit, ref, gpl, mit), "(GPL-2.0 or LicenseRef-1 or MIT)"},
+		{NewConjunctiveSet(nil, NewDisjunctiveSet(nil, mit, gpl), NewConjunctiveSet(nil, ref, mit)), "((GPL-2.0 or MIT) and LicenseRef-1 and MIT)"},
+		{&or, "(GPL-2.0 or MIT)"},
+		{NewConjunctiveSet(nil, &or, &and), "((GPL-2.0 or MIT) and LicenseRef-1 and MIT)"},
+	}
+	for i, test := range tests {
+		if res := CanonicalLicence(test.lic); res != test.expected {
+			t.Errorf("%d: Expected %#v but found %#v", i, test.expected, res)
+		}
+	}
+}
+
+func TestDigestJsonExtensions(t *testing.T) {
+	a := canonDoc(nil, nil, "", nil)
+	a.Extensions = []Extension{{Key: "X-Tool", Value: "x"}}
+	b := canonDoc(nil, nil, "", nil)
+	b.Extensions = []Extension{{Key: "X-Tool", Value: `"x"`, Json: true}}
+	if Digest(a) != Digest(b) {
+		t.Errorf("Different digests:\n%s\n%s", Canonical(a), Canonical(b))
+	}
+	b.Identifiers = []Extension{{Key: "SPDXID", Value: "SPDXRef-DOCUMENT"}}
+	if Digest(a) != Digest(b) {
+		t.Error("The identifiers change the digest.")
+	}
+}
@@ -15,6 +15,9 @@ type Document struct {
 	Files             []*File             // Files referenced in this doc
 	Comment           ValueStr            // Document comment
 	Reviews           []*Review           // Document reviews
+	Extensions        []Extension         // Properties not defined by the specification
+	TagComments       []TagComment        // Comments of the Tag format
+	Identifiers       []Extension         // Identifiers of a serialisation format (see Package.Identifiers)
 	*Meta                                 // Document metadata
 }
 
@@ -74,6 +77,7 @@ type CreationInfo struct {
 	Created            ValueDate      // Creation date
 	LicenceListVersion ValueStr       // Version of the SPDX licence list used
 	Comment            ValueStr       // Creator comment
+	Extensions         []Extension    // Properties not defined by the specification
 	*Meta                             // Creation Info meta
 }
 
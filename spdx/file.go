@@ -14,6 +14,9 @@ type File struct {
 	Dependency        []*File       // File dependecies.
 	Contributor       []ValueStr    // File contributors.
 	Comment           ValueStr      // File comments.
+	Extensions        []Extension   // Properties not defined by the specification.
+	TagComments       []TagComment  // Comments of the Tag format.
+	Identifiers       []Extension   // Identifiers of a serialisation format (see Package.Identifiers).
 	*Meta                           // File metadata.
 }
 
@@ -57,7 +60,7 @@ func (f *File) Equal(other *File) bool {
 		}
 	}
 	for i, v := range f.Contributor {
-		if v.Val == other.Contributor[i].Val {
+		if v.Val != other.Contributor[i].Val {
 			return false
 		}
 	}
@@ -0,0 +1,15 @@
+package spdx
+
+import "testing"
+
+func TestFileEqualContributors(t *testing.T) {
+	a := &File{Name: Str("a.c", nil), Contributor: []ValueStr{Str("Me", nil)}}
+	b := &File{Name: Str("a.c", nil), Contributor: []ValueStr{Str("Me", NewMetaL(3))}}
+	if !a.Equal(b) {
+		t.Error("Files with the same contributors are not equal.")
+	}
+	b.Contributor[0].Val = "You"
+	if a.Equal(b) {
+		t.Error("Files with different contributors are equal.")
+	}
+}
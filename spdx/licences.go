@@ -43,6 +43,8 @@ type ExtractedLicence struct {
 	Text           ValueStr
 	CrossReference []ValueStr
 	Comment        ValueStr
+	Extensions     []Extension  // Properties not defined by the specification
+	TagComments    []TagComment // Comments of the Tag format
 	*Meta
 }
 
@@ -133,6 +135,28 @@ func join(list []AnyLicence, separator string) string {
 	return res
 }
 
+// Returns the licences in `lics`, without the licence sets: the members of
+// the sets are listed instead, recursively. Nil licences are skipped.
+func LicenceMembers(lics ...AnyLicence) []AnyLicence {
+	var res []AnyLicence
+	for _, lic := range lics {
+		switch l := lic.(type) {
+		case nil:
+		case ConjunctiveLicenceSet:
+			res = append(res, LicenceMembers(l.Members...)...)
+		case DisjunctiveLicenceSet:
+			res = append(res, LicenceMembers(l.Members...)...)
+		case *ConjunctiveLicenceSet:
+			res = append(res, LicenceMembers(l.Members...)...)
+		case *DisjunctiveLicenceSet:
+			res = append(res, LicenceMembers(l.Members...)...)
+		default:
+			res = append(res, lic)
+		}
+	}
+	return res
+}
+
 // Returns whether the given ID is a Licence Reference ID (starts with LicenseRef).
 // Does not check if the string after "LicenseRef" satisfies the requirements of any SPDX version.
 // It is case-insensitive.
@@ -0,0 +1,24 @@
+package spdx
+
+import "testing"
+
+func TestLicenceMembers(t *testing.T) {
+	el := &ExtractedLicence{Id: Str("LicenseRef-1", nil)}
+	and := NewConjunctiveSet(nil, NewLicence("MIT", nil), el)
+	or := NewDisjunctiveSet(nil, NewLicence("GPL-2.0", nil), &and)
+	lics := LicenceMembers(or, nil, NewLicence(NONE, nil))
+	ids := make([]string, len(lics))
+	for i, lic := range lics {
+		ids[i] = lic.LicenceId()
+	}
+	expected := []string{"GPL-2.0", "MIT", "LicenseRef-1", NONE}
+	if len(ids) != len(expected) {
+		t.Fatalf("Expected %v but found %v", expected, ids)
+	}
+	for i := range ids {
+		if ids[i] != expected[i] {
+			t.Errorf("Expected %v but found %v", expected, ids)
+			break
+		}
+	}
+}
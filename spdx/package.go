@@ -20,6 +20,9 @@ type Package struct {
 	Summary              ValueStr          // Package summary.
 	Description          ValueStr          // Package description.
 	Files                []*File           // Package files.
+	Extensions           []Extension       // Properties not defined by the specification.
+	TagComments          []TagComment      // Comments of the Tag format.
+	Identifiers          []Extension       // Identifiers of a serialisation format (e.g. the SPDXID of SPDX JSON), not part of the content.
 	*Meta                                  // Package metadata.
 }
 
@@ -60,7 +63,7 @@ func (pkg *Package) Equal(other *Package) bool {
 		return false
 	}
 	for i, file := range pkg.Files {
-		if file.Equal(other.Files[i]) {
+		if !file.Equal(other.Files[i]) {
 			return false
 		}
 	}
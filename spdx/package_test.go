@@ -0,0 +1,15 @@
+package spdx
+
+import "testing"
+
+func TestPackageEqualFiles(t *testing.T) {
+	a := &Package{Name: Str("p", nil), Files: []*File{{Name: Str("a.c", nil)}}}
+	b := &Package{Name: Str("p", nil), Files: []*File{{Name: Str("a.c", NewMetaL(3))}}}
+	if !a.Equal(b) {
+		t.Error("Packages with the same files are not equal.")
+	}
+	b.Files[0].Name.Val = "b.c"
+	if a.Equal(b) {
+		t.Error("Packages with different files are equal.")
+	}
+}
@@ -2,9 +2,11 @@ package spdx
 
 // Represents a review.
 type Review struct {
-	Reviewer ValueCreator
-	Date     ValueDate
-	Comment  ValueStr
+	Reviewer    ValueCreator
+	Date        ValueDate
+	Comment     ValueStr
+	Extensions  []Extension  // Properties not defined by the specification
+	TagComments []TagComment // Comments of the Tag format
 	*Meta
 }
 
@@ -250,6 +250,8 @@ func (v *Validator) Document(doc *Document) bool {
 		// Creation date
 		v.Date(&doc.CreationInfo.Created)
 
+		v.Extensions(doc.CreationInfo.Extensions)
+
 		// LicenceListVersion
 		if llv := doc.CreationInfo.LicenceListVersion; llv.V() != "" {
 			if _, err := fmt.Sscanf(llv.V(), "%d.%d", &v.LicMajor, &v.LicMinor); err != nil {
@@ -285,6 +287,8 @@ func (v *Validator) Document(doc *Document) bool {
 		v.Review(rev)
 	}
 
+	v.Extensions(doc.Extensions)
+
 	v.LicReferences()
 
 	return v.HasErrors()
@@ -422,6 +426,7 @@ func (v *Validator) Review(rev *Review) bool {
 	if rev.Reviewer.val == "" && rev.Date.val == "" {
 		return true
 	}
+	v.Extensions(rev.Extensions)
 	r := rev.Reviewer.val == "" || v.Creator(&rev.Reviewer, false, false, "Reviewer", []string{"Person", "Organization", "Tool"}, 2)
 	return v.Date(&rev.Date) && r
 }
@@ -491,6 +496,9 @@ func (v *Validator) Package(pkg *Package) bool {
 	for _, file := range pkg.Files {
 		r = v.File(file) && r
 	}
+
+	v.Extensions(pkg.Extensions)
+
 	v.validated[pkg] = r
 	return r
 }
@@ -597,6 +605,8 @@ func (v *Validator) File(f *File) bool {
 		r = v.ArtifactOf(artif) && r
 	}
 
+	v.Extensions(f.Extensions)
+
 	v.validated[f] = r
 	return r
 }
@@ -843,9 +853,20 @@ func (v *Validator) ExtractedLicence(lic *ExtractedLicence) bool {
 	for _, url := range lic.CrossReference {
 		r = v.Url(&url, false, false, "Extracted Licence Cross Reference") && r
 	}
+
+	v.Extensions(lic.Extensions)
+
 	if v.validated == nil {
 		v.validated = make(map[interface{}]bool)
 	}
 	v.validated[lic] = r
 	return r
 }
+
+// Adds a warning for each property not defined by the specification, as kept
+// by the lenient parsers. Extensions are not errors.
+func (v *Validator) Extensions(exts []Extension) {
+	for _, ext := range exts {
+		v.addWarn("Unknown property %s.", ext.Meta, ext.Key)
+	}
+}
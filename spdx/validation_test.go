@@ -661,6 +661,20 @@ func TestReviewWrongDate(t *testing.T) {
 	hv(t, v, v.Review(val), false, true, false)
 }
 
+func TestReviewExtensions(t *testing.T) {
+	val := &Review{
+		Reviewer:   NewValueCreator("Person: Me (me@example.org)", nil),
+		Date:       NewValueDate("2014-09-08T14:03:04Z", nil),
+		Extensions: []Extension{{Key: "VendorReviewTool", Value: "checker", Meta: NewMetaL(3)}},
+	}
+
+	v := NewValidator()
+	hv(t, v, v.Review(val), true, false, true)
+	if len(v.Errors()) != 1 || v.Errors()[0].Meta.LineStart != 3 {
+		t.Errorf("Expecting one warning at line 3. Found: %+v", v.Errors())
+	}
+}
+
 // Test date
 func TestDate(t *testing.T) {
 	val := NewValueDate("hahaha", nil)
@@ -0,0 +1,91 @@
+package spreadsheet
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Returns the name of the CSV file of the sheet `name` (e.g.
+// "package-info.csv" for "Package Info").
+func csvName(name string) string {
+	return strings.Replace(strings.ToLower(name), " ", "-", -1) + ".csv"
+}
+
+// The sheets of the spreadsheet layout, in order.
+var sheetNames = []string{SheetOrigins, SheetPackages, SheetLicences, SheetFiles, SheetReviewers}
+
+// Write every sheet to its own CSV file in the directory `dir`, which is
+// created if it does not exist.
+func EncodeCsv(dir string, sheets []*Sheet) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, s := range sheets {
+		f, err := os.Create(filepath.Join(dir, csvName(s.Name)))
+		if err != nil {
+			return err
+		}
+		w := csv.NewWriter(f)
+		w.WriteAll(s.Rows)
+		if err = w.Error(); err != nil {
+			f.Close()
+			return err
+		}
+		if err = f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Read the sheets of the spreadsheet layout from the CSV files in the
+// directory `dir`. Missing files are left out.
+func DecodeCsv(dir string) ([]*Sheet, error) {
+	var sheets []*Sheet
+	for _, name := range sheetNames {
+		f, err := os.Open(filepath.Join(dir, csvName(name)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		r := csv.NewReader(f)
+		r.FieldsPerRecord = -1
+		rows, err := r.ReadAll()
+		f.Close()
+		if err != nil {
+			if perr, ok := err.(*csv.ParseError); ok {
+				return nil, spdx.NewParseError(name+": "+perr.Err.Error(), spdx.NewMetaL(perr.StartLine))
+			}
+			return nil, err
+		}
+		sheets = append(sheets, &Sheet{Name: name, Rows: rows})
+	}
+	return sheets, nil
+}
+
+// Read the CSV files in the directory `dir` to a *spdx.Document. Errors in
+// the values are of type *spdx.ParseError.
+//
+// As quoted values may span several lines, the lines of the metadata are
+// row numbers, which may be different from the line numbers in the files.
+func BuildCsv(dir string) (*spdx.Document, error) {
+	sheets, err := DecodeCsv(dir)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(sheets)
+}
+
+// Write a *spdx.Document to the directory `dir` as one CSV file per sheet.
+func WriteCsv(dir string, doc *spdx.Document) error {
+	if doc == nil {
+		return nil
+	}
+	return EncodeCsv(dir, Sheets(doc))
+}
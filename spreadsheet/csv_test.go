@@ -0,0 +1,57 @@
+package spreadsheet
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteBuildCsv(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spreadsheet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "doc")
+
+	doc := testDoc(t)
+	if err = WriteCsv(out, doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"origins.csv", "package-info.csv", "extracted-license-info.csv", "per-file-info.csv", "reviewers.csv"} {
+		if _, err := os.Stat(filepath.Join(out, name)); err != nil {
+			t.Error(err)
+		}
+	}
+	doc2, err := BuildCsv(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Equal(doc2) {
+		t.Error("Different document after writing and reading CSV.")
+	}
+}
+
+func TestBuildCsvErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spreadsheet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err = BuildCsv(dir); err == nil {
+		t.Error("No error for a directory without CSV files.")
+	}
+
+	data := "SPDX Version,Document Comment\nSPDX-1.2,\"comment\n\nwith a \"quote\"\n"
+	if err = ioutil.WriteFile(filepath.Join(dir, "origins.csv"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = BuildCsv(dir)
+	if perr, ok := err.(*spdx.ParseError); !ok || perr.LineStart != 2 {
+		t.Errorf("Expected *spdx.ParseError on line 2, got %v", err)
+	}
+}
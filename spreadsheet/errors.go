@@ -0,0 +1,19 @@
+package spreadsheet
+
+// Error messages used when reading XLSX workbooks
+var (
+	MsgNotXlsx      = "Not a XLSX workbook."
+	MsgMissingPart  = "Invalid XLSX workbook. Missing part %s."
+	MsgInvalidCell  = "Invalid cell reference %q."
+	MsgInvalidShare = "Invalid shared string index %q."
+)
+
+// Error messages used by the parser
+var (
+	MsgNoSheet         = "The spreadsheet has no %q sheet."
+	MsgCell            = "%s, column %q: %s"
+	MsgDuplicateColumn = "%s: Column %q is defined twice."
+	MsgExtraRow        = "%s: Only one row of values is expected."
+	MsgInvalidChecksum = "Invalid checksum. Expected \"<algorithm>: <value>\"."
+	MsgUnknownPackage  = "Unknown package %q."
+)
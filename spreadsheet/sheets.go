@@ -0,0 +1,549 @@
+// Package spreadsheet reads and writes SPDX documents in the spreadsheet
+// layout of the SPDX reference tools, either as a XLSX workbook or as one CSV
+// file per sheet.
+//
+// The sheets are "Origins" (document and creation info), "Package Info",
+// "Extracted License Info", "Per File Info" and "Reviewers". The first row of
+// every sheet has the column names. Columns are found by name, so they may be
+// reordered, and columns which are not part of the layout are ignored (e.g.
+// notes added by reviewers). Empty rows are ignored as well.
+//
+// Cells with several values (e.g. "Creator" or "License Info in File") have
+// one value per line. Licences are written as in the Tag format and checksums
+// as "<algorithm>: <value>". The "Package" column of "Per File Info" has the
+// names of the packages the file belongs to; files without a package are
+// document files.
+//
+// The lines of *spdx.Meta and *spdx.ParseError are row numbers in the sheet
+// (the column names are row 1) and error messages start with the sheet name.
+package spreadsheet
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The version of the spreadsheet layout written in the "Origins" sheet.
+const Version = "1.2"
+
+// Sheet names
+const (
+	SheetOrigins   = "Origins"
+	SheetPackages  = "Package Info"
+	SheetLicences  = "Extracted License Info"
+	SheetFiles     = "Per File Info"
+	SheetReviewers = "Reviewers"
+)
+
+// Represents a sheet: its name and the values of its cells. The first row has
+// the column names.
+type Sheet struct {
+	Name string
+	Rows [][]string
+}
+
+// A function that takes the value of a cell and updates some value in a SPDX
+// element. All the upd* functions return updaters for common SPDX values.
+type updater func(val string, m *spdx.Meta) error
+
+// A column of a sheet: the name, the value written for an element and the
+// updater used to read the value back.
+type column struct {
+	name string
+	val  string
+	upd  updater
+}
+
+// Splits a cell value with one value per line. Empty lines are left out.
+func lines(val string) []string {
+	var res []string
+	for _, line := range strings.Split(val, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res
+}
+
+// Returns the values of `vals`, one per line.
+func strs(vals []spdx.ValueStr) string {
+	return spdx.Join(vals, "\n")
+}
+
+// Returns the licence expression of `lic`, or "" if it is nil.
+func licenceId(lic spdx.AnyLicence) string {
+	if lic == nil {
+		return ""
+	}
+	return lic.LicenceId()
+}
+
+// Returns the licence expressions of `lics`, one per line.
+func licenceIds(lics []spdx.AnyLicence) string {
+	ids := make([]string, len(lics))
+	for i, lic := range lics {
+		ids[i] = licenceId(lic)
+	}
+	return strings.Join(ids, "\n")
+}
+
+// Returns the names of `files`, one per line.
+func fileNames(files []*spdx.File) string {
+	names := make([]string, len(files))
+	for i, f := range files {
+		names[i] = f.Name.Val
+	}
+	return strings.Join(names, "\n")
+}
+
+// Returns the checksum as "<algorithm>: <value>", or "" if there is none.
+func checksum(cksum *spdx.Checksum) string {
+	if cksum == nil || (cksum.Algo.Val == "" && cksum.Value.Val == "") {
+		return ""
+	}
+	return cksum.Algo.Val + ": " + cksum.Value.Val
+}
+
+// Update the spdx.ValueStr pointer ptr.
+func upd(ptr *spdx.ValueStr) updater {
+	return func(val string, m *spdx.Meta) error {
+		*ptr = spdx.Str(val, m)
+		return nil
+	}
+}
+
+// Update the []spdx.ValueStr pointer arr with one value per line.
+func updList(arr *[]spdx.ValueStr) updater {
+	return func(val string, m *spdx.Meta) error {
+		for _, line := range lines(val) {
+			*arr = append(*arr, spdx.Str(line, m))
+		}
+		return nil
+	}
+}
+
+// Update the spdx.ValueCreator pointer ptr.
+func updCreator(ptr *spdx.ValueCreator) updater {
+	return func(val string, m *spdx.Meta) error {
+		*ptr = spdx.NewValueCreator(val, m)
+		return nil
+	}
+}
+
+// Update the []spdx.ValueCreator pointer arr with one creator per line.
+func updCreatorList(arr *[]spdx.ValueCreator) updater {
+	return func(val string, m *spdx.Meta) error {
+		for _, line := range lines(val) {
+			*arr = append(*arr, spdx.NewValueCreator(line, m))
+		}
+		return nil
+	}
+}
+
+// Update the spdx.ValueDate pointer ptr.
+func updDate(ptr *spdx.ValueDate) updater {
+	return func(val string, m *spdx.Meta) error {
+		*ptr = spdx.NewValueDate(val, m)
+		return nil
+	}
+}
+
+// Update a AnyLicence pointer. Licences are expressions as in the Tag format.
+func anyLicence(lic *spdx.AnyLicence) updater {
+	return func(val string, m *spdx.Meta) (err error) {
+		*lic, err = tag.ParseLicence(val, m)
+		return err
+	}
+}
+
+// Update a []AnyLicence pointer with one licence per line.
+func anyLicenceList(licList *[]spdx.AnyLicence) updater {
+	return func(val string, m *spdx.Meta) error {
+		for _, line := range lines(val) {
+			lic, err := tag.ParseLicence(line, m)
+			if err != nil {
+				return err
+			}
+			*licList = append(*licList, lic)
+		}
+		return nil
+	}
+}
+
+// Update a *spdx.Checksum pointer from a "<algorithm>: <value>" cell.
+func updChecksum(ptr **spdx.Checksum) updater {
+	return func(val string, m *spdx.Meta) error {
+		colon := strings.Index(val, ":")
+		if colon < 0 {
+			return spdx.NewParseError(MsgInvalidChecksum, m)
+		}
+		algo, value := strings.TrimSpace(val[:colon]), strings.TrimSpace(val[colon+1:])
+		if algo == "" || value == "" {
+			return spdx.NewParseError(MsgInvalidChecksum, m)
+		}
+		*ptr = &spdx.Checksum{Algo: spdx.Str(algo, m), Value: spdx.Str(value, m), Meta: m}
+		return nil
+	}
+}
+
+// Creates files that only have the FileName and appends them to the given
+// []*File pointer. See tag.ResolveReferences().
+func updFileNameList(fl *[]*spdx.File) updater {
+	return func(val string, m *spdx.Meta) error {
+		for _, line := range lines(val) {
+			*fl = append(*fl, &spdx.File{Name: spdx.Str(line, m)})
+		}
+		return nil
+	}
+}
+
+// Returns the columns of the "Origins" sheet.
+func originsColumns(doc *spdx.Document, ci *spdx.CreationInfo) []column {
+	creators := make([]string, len(ci.Creator))
+	for i, cr := range ci.Creator {
+		creators[i] = cr.V()
+	}
+	return []column{
+		{"Spreadsheet Version", Version, func(string, *spdx.Meta) error { return nil }},
+		{"SPDX Version", doc.SpecVersion.Val, upd(&doc.SpecVersion)},
+		{"Data License", doc.DataLicence.Val, upd(&doc.DataLicence)},
+		{"Document Comment", doc.Comment.Val, upd(&doc.Comment)},
+		{"Creator", strings.Join(creators, "\n"), updCreatorList(&ci.Creator)},
+		{"Created", ci.Created.V(), updDate(&ci.Created)},
+		{"Creator Comment", ci.Comment.Val, upd(&ci.Comment)},
+		{"License List Version", ci.LicenceListVersion.Val, upd(&ci.LicenceListVersion)},
+	}
+}
+
+// Returns the columns of the "Package Info" sheet.
+func packageColumns(pkg *spdx.Package) []column {
+	vc := func() *spdx.VerificationCode {
+		if pkg.VerificationCode == nil {
+			pkg.VerificationCode = new(spdx.VerificationCode)
+		}
+		return pkg.VerificationCode
+	}
+	var code, excluded string
+	if pkg.VerificationCode != nil {
+		code, excluded = pkg.VerificationCode.Value.Val, strs(pkg.VerificationCode.ExcludedFiles)
+	}
+	return []column{
+		{"Package Name", pkg.Name.Val, upd(&pkg.Name)},
+		{"Package Version", pkg.Version.Val, upd(&pkg.Version)},
+		{"Package FileName", pkg.FileName.Val, upd(&pkg.FileName)},
+		{"Package Supplier", pkg.Supplier.V(), updCreator(&pkg.Supplier)},
+		{"Package Originator", pkg.Originator.V(), updCreator(&pkg.Originator)},
+		{"Home Page", pkg.HomePage.Val, upd(&pkg.HomePage)},
+		{"Package Download Location", pkg.DownloadLocation.Val, upd(&pkg.DownloadLocation)},
+		{"Package Checksum", checksum(pkg.Checksum), updChecksum(&pkg.Checksum)},
+		{"Package Verification Code", code, func(val string, m *spdx.Meta) error {
+			vc().Value = spdx.Str(val, m)
+			vc().Meta = m
+			return nil
+		}},
+		{"Verification Code Excluded Files", excluded, func(val string, m *spdx.Meta) error {
+			vc().Meta = m
+			return updList(&vc().ExcludedFiles)(val, m)
+		}},
+		{"Source Info", pkg.SourceInfo.Val, upd(&pkg.SourceInfo)},
+		{"License Declared", licenceId(pkg.LicenceDeclared), anyLicence(&pkg.LicenceDeclared)},
+		{"License Concluded", licenceId(pkg.LicenceConcluded), anyLicence(&pkg.LicenceConcluded)},
+		{"License Info From Files", licenceIds(pkg.LicenceInfoFromFiles), anyLicenceList(&pkg.LicenceInfoFromFiles)},
+		{"License Comments", pkg.LicenceComments.Val, upd(&pkg.LicenceComments)},
+		{"Package Copyright Text", pkg.CopyrightText.Val, upd(&pkg.CopyrightText)},
+		{"Summary", pkg.Summary.Val, upd(&pkg.Summary)},
+		{"Description", pkg.Description.Val, upd(&pkg.Description)},
+	}
+}
+
+// Returns the columns of the "Extracted License Info" sheet.
+func licenceColumns(lic *spdx.ExtractedLicence) []column {
+	return []column{
+		{"Identifier", lic.Id.Val, upd(&lic.Id)},
+		{"Extracted Text", lic.Text.Val, upd(&lic.Text)},
+		{"License Name", strs(lic.Name), updList(&lic.Name)},
+		{"Cross Reference URLs", strs(lic.CrossReference), updList(&lic.CrossReference)},
+		{"Comment", lic.Comment.Val, upd(&lic.Comment)},
+	}
+}
+
+// Returns the columns of the "Per File Info" sheet. The "Package" column is
+// given by the caller, as it depends on the other packages of the document.
+func fileColumns(file *spdx.File, packages column) []column {
+	// the artifacts are split in three columns with one artifact per line
+	artifact := func(i int) *spdx.ArtifactOf {
+		for len(file.ArtifactOf) <= i {
+			file.ArtifactOf = append(file.ArtifactOf, new(spdx.ArtifactOf))
+		}
+		return file.ArtifactOf[i]
+	}
+	artifactColumn := func(name string, field func(*spdx.ArtifactOf) *spdx.ValueStr) column {
+		vals := make([]string, len(file.ArtifactOf))
+		for i, artif := range file.ArtifactOf {
+			vals[i] = field(artif).Val
+		}
+		return column{name, strings.Join(vals, "\n"), func(val string, m *spdx.Meta) error {
+			for i, line := range strings.Split(val, "\n") {
+				if line = strings.TrimSpace(line); line != "" {
+					artif := artifact(i)
+					artif.Meta = m
+					*field(artif) = spdx.Str(line, m)
+				}
+			}
+			return nil
+		}}
+	}
+
+	return []column{
+		{"File Name", file.Name.Val, upd(&file.Name)},
+		packages,
+		{"File Type", file.Type.Val, upd(&file.Type)},
+		{"File Checksum", checksum(file.Checksum), updChecksum(&file.Checksum)},
+		{"License Concluded", licenceId(file.LicenceConcluded), anyLicence(&file.LicenceConcluded)},
+		{"License Info in File", licenceIds(file.LicenceInfoInFile), anyLicenceList(&file.LicenceInfoInFile)},
+		{"License Comments", file.LicenceComments.Val, upd(&file.LicenceComments)},
+		{"File Copyright Text", file.CopyrightText.Val, upd(&file.CopyrightText)},
+		{"Notice Text", file.Notice.Val, upd(&file.Notice)},
+		artifactColumn("Artifact of Project", func(a *spdx.ArtifactOf) *spdx.ValueStr { return &a.Name }),
+		artifactColumn("Artifact of Homepage", func(a *spdx.ArtifactOf) *spdx.ValueStr { return &a.HomePage }),
+		artifactColumn("Artifact of URL", func(a *spdx.ArtifactOf) *spdx.ValueStr { return &a.ProjectUri }),
+		{"Contributors", strs(file.Contributor), updList(&file.Contributor)},
+		{"File Comment", file.Comment.Val, upd(&file.Comment)},
+		{"File Dependencies", fileNames(file.Dependency), updFileNameList(&file.Dependency)},
+	}
+}
+
+// Returns the columns of the "Reviewers" sheet.
+func reviewColumns(rev *spdx.Review) []column {
+	return []column{
+		{"Reviewer", rev.Reviewer.V(), updCreator(&rev.Reviewer)},
+		{"Review Date", rev.Date.V(), updDate(&rev.Date)},
+		{"Reviewer Comment", rev.Comment.Val, upd(&rev.Comment)},
+	}
+}
+
+// Create a new sheet with the names of `cols` as the first row.
+func newSheet(name string, cols []column) *Sheet {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = c.name
+	}
+	return &Sheet{Name: name, Rows: [][]string{header}}
+}
+
+// Append a row with the values of `cols`.
+func (s *Sheet) add(cols []column) {
+	row := make([]string, len(cols))
+	for i, c := range cols {
+		row[i] = c.val
+	}
+	s.Rows = append(s.Rows, row)
+}
+
+// Returns the sheets of `doc`. Files of packages that are not in doc.Files
+// are added to the "Per File Info" sheet.
+func Sheets(doc *spdx.Document) []*Sheet {
+	ci := doc.CreationInfo
+	if ci == nil {
+		ci = new(spdx.CreationInfo)
+	}
+	origins := newSheet(SheetOrigins, originsColumns(doc, ci))
+	origins.add(originsColumns(doc, ci))
+
+	pkgSheet := newSheet(SheetPackages, packageColumns(new(spdx.Package)))
+	files := doc.Files
+	packages := make(map[*spdx.File][]string)
+	for _, pkg := range doc.Packages {
+		pkgSheet.add(packageColumns(pkg))
+		for _, file := range pkg.Files {
+			if _, ok := packages[file]; !ok && !inList(file, files) {
+				files = append(files, file)
+			}
+			packages[file] = append(packages[file], pkg.Name.Val)
+		}
+	}
+
+	licSheet := newSheet(SheetLicences, licenceColumns(new(spdx.ExtractedLicence)))
+	for _, lic := range doc.ExtractedLicences {
+		licSheet.add(licenceColumns(lic))
+	}
+
+	fileSheet := newSheet(SheetFiles, fileColumns(new(spdx.File), column{name: colPackage}))
+	for _, file := range files {
+		fileSheet.add(fileColumns(file, column{name: colPackage, val: strings.Join(packages[file], "\n")}))
+	}
+
+	revSheet := newSheet(SheetReviewers, reviewColumns(new(spdx.Review)))
+	for _, rev := range doc.Reviews {
+		revSheet.add(reviewColumns(rev))
+	}
+
+	return []*Sheet{origins, pkgSheet, licSheet, fileSheet, revSheet}
+}
+
+// The column of "Per File Info" with the names of the packages of a file.
+const colPackage = "Package"
+
+// Checks if the given *spdx.File is in the given []*spdx.File.
+func inList(file *spdx.File, list []*spdx.File) bool {
+	for _, f := range list {
+		if f == file {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefixes the message of the *spdx.ParseError `err` with the sheet and
+// column name.
+func cellError(s *Sheet, col string, err error) error {
+	if perr, ok := err.(*spdx.ParseError); ok {
+		return spdx.NewParseError(fmt.Sprintf(MsgCell, s.Name, col, perr.Error()), perr.Meta)
+	}
+	return err
+}
+
+// Call f for every row of `s` after the column names, with the Meta of the
+// row. Empty rows are skipped. The row is given as a map from column names to
+// the (non-empty) cell values.
+func eachRow(s *Sheet, f func(row map[string]string, m *spdx.Meta) error) error {
+	if s == nil || len(s.Rows) == 0 {
+		return nil
+	}
+	header := s.Rows[0]
+	seen := make(map[string]bool)
+	for _, name := range header {
+		name = strings.TrimSpace(name)
+		if name != "" && seen[name] {
+			return spdx.NewParseError(fmt.Sprintf(MsgDuplicateColumn, s.Name, name), spdx.NewMetaL(1))
+		}
+		seen[name] = true
+	}
+
+	for i, cells := range s.Rows[1:] {
+		row := make(map[string]string)
+		for j, val := range cells {
+			if j < len(header) && strings.TrimSpace(val) != "" {
+				row[strings.TrimSpace(header[j])] = val
+			}
+		}
+		if len(row) == 0 {
+			continue
+		}
+		if err := f(row, spdx.NewMetaL(i+2)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply the updaters of `cols` to the cells of `row`.
+func apply(s *Sheet, row map[string]string, m *spdx.Meta, cols []column) error {
+	for _, c := range cols {
+		val, ok := row[c.name]
+		if !ok || c.upd == nil {
+			continue
+		}
+		if err := c.upd(val, m); err != nil {
+			return cellError(s, c.name, err)
+		}
+	}
+	return nil
+}
+
+// Parse the sheets in the spreadsheet layout to a *spdx.Document. Sheets
+// other than "Origins" may be missing. Licence references and file
+// dependencies are resolved as in the Tag format (see
+// tag.ResolveReferences()).
+func Parse(sheets []*Sheet) (*spdx.Document, error) {
+	byName := make(map[string]*Sheet)
+	for _, s := range sheets {
+		if _, ok := byName[s.Name]; !ok {
+			byName[s.Name] = s
+		}
+	}
+	origins := byName[SheetOrigins]
+	if origins == nil {
+		return nil, spdx.NewParseError(fmt.Sprintf(MsgNoSheet, SheetOrigins), spdx.NewMetaL(1))
+	}
+
+	doc := new(spdx.Document)
+	err := eachRow(origins, func(row map[string]string, m *spdx.Meta) error {
+		if doc.Meta != nil {
+			return spdx.NewParseError(fmt.Sprintf(MsgExtraRow, origins.Name), m)
+		}
+		doc.Meta = m
+		ci := &spdx.CreationInfo{Meta: m}
+		if err := apply(origins, row, m, originsColumns(doc, ci)); err != nil {
+			return err
+		}
+		if len(ci.Creator) > 0 || ci.Created.V() != "" || ci.Comment.Val != "" || ci.LicenceListVersion.Val != "" {
+			doc.CreationInfo = ci
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	packages := make(map[string]*spdx.Package)
+	err = eachRow(byName[SheetPackages], func(row map[string]string, m *spdx.Meta) error {
+		pkg := &spdx.Package{Meta: m}
+		doc.Packages = append(doc.Packages, pkg)
+		if err := apply(byName[SheetPackages], row, m, packageColumns(pkg)); err != nil {
+			return err
+		}
+		if _, ok := packages[pkg.Name.Val]; !ok {
+			packages[pkg.Name.Val] = pkg
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(byName[SheetLicences], func(row map[string]string, m *spdx.Meta) error {
+		lic := &spdx.ExtractedLicence{Meta: m}
+		doc.ExtractedLicences = append(doc.ExtractedLicences, lic)
+		return apply(byName[SheetLicences], row, m, licenceColumns(lic))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(byName[SheetFiles], func(row map[string]string, m *spdx.Meta) error {
+		file := &spdx.File{Meta: m}
+		doc.Files = append(doc.Files, file)
+		inPackages := func(val string, m *spdx.Meta) error {
+			for _, name := range lines(val) {
+				pkg, ok := packages[name]
+				if !ok {
+					return spdx.NewParseError(fmt.Sprintf(MsgUnknownPackage, name), m)
+				}
+				pkg.Files = append(pkg.Files, file)
+			}
+			return nil
+		}
+		return apply(byName[SheetFiles], row, m, fileColumns(file, column{name: colPackage, upd: inPackages}))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = eachRow(byName[SheetReviewers], func(row map[string]string, m *spdx.Meta) error {
+		rev := &spdx.Review{Meta: m}
+		doc.Reviews = append(doc.Reviews, rev)
+		return apply(byName[SheetReviewers], row, m, reviewColumns(rev))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tag.ResolveReferences(doc)
+	return doc, nil
+}
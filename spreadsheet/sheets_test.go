@@ -0,0 +1,251 @@
+package spreadsheet
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/tag"
+)
+
+import (
+	"strings"
+	"testing"
+)
+
+const testDocument = `SPDXVersion: SPDX-1.2
+DataLicense: CC0-1.0
+DocumentComment: <text>A document
+with two lines</text>
+Creator: Person: Jane Doe (jane@example.com)
+Creator: Tool: spdx-go
+Created: 2014-08-01T00:00:00Z
+LicenseListVersion: 1.20
+
+PackageName: pkg
+PackageVersion: 1.0
+PackageSupplier: Organization: ACME
+PackageDownloadLocation: http://example.com/pkg-1.0.tar.gz
+PackageChecksum: SHA1: 85ed0817af83a24ad8da68c2b5094de69833983c
+PackageVerificationCode: 4e3211c67a2d28fced849ee1bb76e7391b93feba (a.rdf, b.txt)
+PackageLicenseConcluded: (Apache-2.0 and LicenseRef-1)
+PackageLicenseInfoFromFiles: Apache-2.0
+PackageLicenseInfoFromFiles: LicenseRef-1
+PackageLicenseDeclared: (Apache-2.0 or MIT)
+PackageCopyrightText: <text>Copyright ACME</text>
+
+FileName: a.c
+FileType: SOURCE
+FileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12
+LicenseConcluded: NOASSERTION
+LicenseInfoInFile: Apache-2.0
+LicenseInfoInFile: LicenseRef-1
+ArtifactOfProjectName: project
+ArtifactOfProjectHomePage: http://example.com/
+ArtifactOfProjectName: other
+ArtifactOfProjectURI: http://example.com/other
+FileDependency: b.c
+
+FileName: b.c
+FileChecksum: SHA1: da39a3ee5e6b4b0d3255bfef95601890afd80709
+LicenseConcluded: Apache-2.0
+
+LicenseID: LicenseRef-1
+ExtractedText: <text>Some licence</text>
+LicenseName: Some
+LicenseName: Some Licence
+LicenseCrossReference: http://example.com/licence
+
+Reviewer: Person: Joe Reviewer
+ReviewDate: 2010-02-10T00:00:00Z
+ReviewComment: Looks good.
+`
+
+// Returns the document of testDocument.
+func testDoc(t *testing.T) *spdx.Document {
+	doc, err := tag.Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+// Returns the row of `s` with `val` in the first column.
+func findRow(s *Sheet, val string) []string {
+	for _, row := range s.Rows {
+		if len(row) > 0 && row[0] == val {
+			return row
+		}
+	}
+	return nil
+}
+
+// Returns the cell of `row` in the column `col` of `s`.
+func cell(s *Sheet, row []string, col string) string {
+	for i, name := range s.Rows[0] {
+		if name == col && i < len(row) {
+			return row[i]
+		}
+	}
+	return ""
+}
+
+func TestSheets(t *testing.T) {
+	sheets := Sheets(testDoc(t))
+	if len(sheets) != 5 {
+		t.Fatalf("Wrong number of sheets: %d", len(sheets))
+	}
+	for i, name := range sheetNames {
+		if sheets[i].Name != name {
+			t.Errorf("Wrong sheet name: %q (expected %q)", sheets[i].Name, name)
+		}
+	}
+
+	origins := sheets[0]
+	if len(origins.Rows) != 2 {
+		t.Fatalf("Wrong number of rows in Origins: %d", len(origins.Rows))
+	}
+	if cr := cell(origins, origins.Rows[1], "Creator"); cr != "Person: Jane Doe (jane@example.com)\nTool: spdx-go" {
+		t.Errorf("Wrong creators: %q", cr)
+	}
+
+	pkgs := sheets[1]
+	pkg := findRow(pkgs, "pkg")
+	tests := map[string]string{
+		"Package Checksum":                 "SHA1: 85ed0817af83a24ad8da68c2b5094de69833983c",
+		"Package Verification Code":        "4e3211c67a2d28fced849ee1bb76e7391b93feba",
+		"Verification Code Excluded Files": "a.rdf\nb.txt",
+		"License Concluded":                "(Apache-2.0 and LicenseRef-1)",
+		"License Info From Files":          "Apache-2.0\nLicenseRef-1",
+	}
+	for col, expected := range tests {
+		if val := cell(pkgs, pkg, col); val != expected {
+			t.Errorf("Wrong value in %q: %q (expected %q)", col, val, expected)
+		}
+	}
+
+	files := sheets[3]
+	a := findRow(files, "a.c")
+	tests = map[string]string{
+		"Artifact of Project":  "project\nother",
+		"Artifact of Homepage": "http://example.com/\n",
+		"Artifact of URL":      "\nhttp://example.com/other",
+		"File Dependencies":    "b.c",
+		"Package":              "",
+	}
+	for col, expected := range tests {
+		if val := cell(files, a, col); val != expected {
+			t.Errorf("Wrong value in %q: %q (expected %q)", col, val, expected)
+		}
+	}
+}
+
+func TestSheetsParse(t *testing.T) {
+	doc := testDoc(t)
+	doc2, err := Parse(Sheets(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Equal(doc2) {
+		t.Error("Different document after writing and parsing the sheets.")
+	}
+	if lic, ok := doc2.Files[0].LicenceInfoInFile[1].(*spdx.ExtractedLicence); !ok || lic != doc2.ExtractedLicences[0] {
+		t.Errorf("Licence reference not resolved: %#v", doc2.Files[0].LicenceInfoInFile[1])
+	}
+	if dep := doc2.Files[0].Dependency[0]; dep != doc2.Files[1] {
+		t.Errorf("File dependency not resolved: %#v", dep)
+	}
+}
+
+func TestSheetsPackageFiles(t *testing.T) {
+	file := &spdx.File{Name: spdx.Str("a.c", nil)}
+	doc := &spdx.Document{
+		SpecVersion: spdx.Str("SPDX-1.2", nil),
+		Packages: []*spdx.Package{
+			{Name: spdx.Str("one", nil), Files: []*spdx.File{file}},
+			{Name: spdx.Str("two", nil), Files: []*spdx.File{file}},
+		},
+	}
+	sheets := Sheets(doc)
+	if len(sheets[3].Rows) != 2 {
+		t.Fatalf("Package files not written once: %v", sheets[3].Rows)
+	}
+	if val := cell(sheets[3], sheets[3].Rows[1], "Package"); val != "one\ntwo" {
+		t.Errorf("Wrong packages of file: %q", val)
+	}
+
+	doc2, err := Parse(sheets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc2.CreationInfo != nil {
+		t.Errorf("Empty creation info parsed: %+v", doc2.CreationInfo)
+	}
+	if len(doc2.Files) != 1 || len(doc2.Packages) != 2 {
+		t.Fatalf("Wrong number of files or packages: %d, %d", len(doc2.Files), len(doc2.Packages))
+	}
+	for _, pkg := range doc2.Packages {
+		if len(pkg.Files) != 1 || pkg.Files[0] != doc2.Files[0] {
+			t.Errorf("Wrong files of package %s: %v", pkg.Name.Val, pkg.Files)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	sheets := []*Sheet{
+		{Name: SheetOrigins, Rows: [][]string{
+			{"Notes", "Created", "SPDX Version"},
+			{},
+			{"unused", "2014-08-01T00:00:00Z", "SPDX-1.2"},
+		}},
+		{Name: SheetReviewers, Rows: [][]string{
+			{"Reviewer Comment", "Reviewer"},
+			{"", ""},
+			{"Fine.", "Person: Joe"},
+			{"", "Person: Jane"},
+		}},
+	}
+	doc, err := Parse(sheets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SpecVersion.Val != "SPDX-1.2" || doc.LineStart != 3 {
+		t.Errorf("Wrong document: %+v", doc)
+	}
+	if ci := doc.CreationInfo; ci == nil || ci.Created.V() != "2014-08-01T00:00:00Z" || ci.Created.Time() == nil {
+		t.Errorf("Wrong creation info: %+v", ci)
+	}
+	if len(doc.Reviews) != 2 {
+		t.Fatalf("Wrong number of reviews: %d", len(doc.Reviews))
+	}
+	if r := doc.Reviews[0]; r.Reviewer.Name() != "Joe" || r.Comment.Val != "Fine." || r.LineStart != 3 {
+		t.Errorf("Wrong review: %+v", r)
+	}
+	if r := doc.Reviews[1]; r.Reviewer.Name() != "Jane" || r.LineStart != 4 {
+		t.Errorf("Wrong review: %+v", r)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	origins := &Sheet{Name: SheetOrigins, Rows: [][]string{{"SPDX Version"}, {"SPDX-1.2"}}}
+	tests := []struct {
+		sheets []*Sheet
+		msg    string
+		line   int
+	}{
+		{nil, `The spreadsheet has no "Origins" sheet.`, 1},
+		{[]*Sheet{{Name: SheetOrigins, Rows: [][]string{{"SPDX Version", "SPDX Version"}}}}, `Origins: Column "SPDX Version" is defined twice.`, 1},
+		{[]*Sheet{{Name: SheetOrigins, Rows: [][]string{{"SPDX Version"}, {"SPDX-1.2"}, {"SPDX-1.2"}}}}, "Origins: Only one row of values is expected.", 3},
+		{[]*Sheet{origins, {Name: SheetPackages, Rows: [][]string{{"Package Checksum"}, {"SHA1"}}}}, `Package Info, column "Package Checksum": ` + MsgInvalidChecksum, 2},
+		{[]*Sheet{origins, {Name: SheetPackages, Rows: [][]string{{"License Declared"}, {}, {"(MIT"}}}}, `Package Info, column "License Declared": ` + tag.MsgNoClosedParen, 3},
+		{[]*Sheet{origins, {Name: SheetFiles, Rows: [][]string{{"File Name", "Package"}, {"a.c", "pkg"}}}}, `Per File Info, column "Package": Unknown package "pkg".`, 2},
+	}
+	for _, test := range tests {
+		_, err := Parse(test.sheets)
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("Expected *spdx.ParseError, got %v", err)
+			continue
+		}
+		if perr.Error() != test.msg || perr.LineStart != test.line {
+			t.Errorf("Wrong error: %d %q (expected %d %q)", perr.LineStart, perr.Error(), test.line, test.msg)
+		}
+	}
+}
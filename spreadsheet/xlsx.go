@@ -0,0 +1,393 @@
+package spreadsheet
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// XML namespaces and relationship types of the Office Open XML parts.
+const (
+	nsMain          = "http://schemas.openxmlformats.org/spreadsheetml/2006/main"
+	nsRelationships = "http://schemas.openxmlformats.org/officeDocument/2006/relationships"
+	nsPackageRels   = "http://schemas.openxmlformats.org/package/2006/relationships"
+	nsContentTypes  = "http://schemas.openxmlformats.org/package/2006/content-types"
+
+	relOfficeDocument = nsRelationships + "/officeDocument"
+	relWorksheet      = nsRelationships + "/worksheet"
+	relStyles         = nsRelationships + "/styles"
+)
+
+// The styles of the workbook: style 1 is bold (the column names) and style 2
+// is wrapped text, so that values are not converted to numbers or dates when
+// edited.
+const xlsxStyles = `<styleSheet xmlns="` + nsMain + `">` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="49" fontId="1" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyFont="1"/>` +
+	`<xf numFmtId="49" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1" applyAlignment="1"><alignment vertical="top" wrapText="1"/></xf></cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
+
+// Writes the XML parts of a workbook to a zip archive. The first error is
+// kept and all the writes after it are ignored.
+type xlsxWriter struct {
+	zw  *zip.Writer
+	w   *bufio.Writer
+	err error
+}
+
+// Start a new part in the archive.
+func (x *xlsxWriter) part(name string) {
+	if x.err != nil {
+		return
+	}
+	x.flush()
+	var w io.Writer
+	w, x.err = x.zw.Create(name)
+	x.w = bufio.NewWriter(w)
+	x.str(xml.Header)
+}
+
+// Write `s` as is.
+func (x *xlsxWriter) str(s string) {
+	if x.err == nil {
+		_, x.err = x.w.WriteString(s)
+	}
+}
+
+// Write `s` with the XML special characters escaped.
+func (x *xlsxWriter) text(s string) {
+	if x.err == nil {
+		x.err = xml.EscapeText(x.w, []byte(s))
+	}
+}
+
+// Flush the current part.
+func (x *xlsxWriter) flush() {
+	if x.w != nil && x.err == nil {
+		x.err = x.w.Flush()
+	}
+}
+
+// Returns the column letters of the zero-based column index `i` (A, B, ...,
+// Z, AA, AB, ...).
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// Returns the zero-based column and row indexes of a cell reference like
+// "AB12".
+func cellIndex(ref string) (col, row int, err error) {
+	i := 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		col = col*26 + int(ref[i]-'A') + 1
+	}
+	row, err = strconv.Atoi(ref[i:])
+	if i == 0 || err != nil || row < 1 {
+		return 0, 0, fmt.Errorf(MsgInvalidCell, ref)
+	}
+	return col - 1, row - 1, nil
+}
+
+// Write `sheets` as a XLSX workbook. All the cells are text and the first row
+// of every sheet is bold and frozen.
+func EncodeXlsx(w io.Writer, sheets []*Sheet) error {
+	x := &xlsxWriter{zw: zip.NewWriter(w)}
+
+	x.part("[Content_Types].xml")
+	x.str(`<Types xmlns="` + nsContentTypes + `">`)
+	x.str(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	x.str(`<Default Extension="xml" ContentType="application/xml"/>`)
+	x.str(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	x.str(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range sheets {
+		x.str(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1))
+	}
+	x.str(`</Types>`)
+
+	x.part("_rels/.rels")
+	x.str(`<Relationships xmlns="` + nsPackageRels + `">`)
+	x.str(`<Relationship Id="rId1" Type="` + relOfficeDocument + `" Target="xl/workbook.xml"/>`)
+	x.str(`</Relationships>`)
+
+	x.part("xl/workbook.xml")
+	x.str(`<workbook xmlns="` + nsMain + `" xmlns:r="` + nsRelationships + `"><sheets>`)
+	for i, s := range sheets {
+		x.str(`<sheet name="`)
+		x.text(s.Name)
+		x.str(fmt.Sprintf(`" sheetId="%d" r:id="rId%d"/>`, i+1, i+1))
+	}
+	x.str(`</sheets></workbook>`)
+
+	x.part("xl/_rels/workbook.xml.rels")
+	x.str(`<Relationships xmlns="` + nsPackageRels + `">`)
+	for i := range sheets {
+		x.str(fmt.Sprintf(`<Relationship Id="rId%d" Type="%s" Target="worksheets/sheet%d.xml"/>`, i+1, relWorksheet, i+1))
+	}
+	x.str(fmt.Sprintf(`<Relationship Id="rId%d" Type="%s" Target="styles.xml"/>`, len(sheets)+1, relStyles))
+	x.str(`</Relationships>`)
+
+	x.part("xl/styles.xml")
+	x.str(xlsxStyles)
+
+	for i, s := range sheets {
+		x.part(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1))
+		x.str(`<worksheet xmlns="` + nsMain + `">`)
+		x.str(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+		if len(s.Rows) > 0 && len(s.Rows[0]) > 0 {
+			x.str(fmt.Sprintf(`<cols><col min="1" max="%d" width="30" customWidth="1"/></cols>`, len(s.Rows[0])))
+		}
+		x.str(`<sheetData>`)
+		for r, row := range s.Rows {
+			style := 2
+			if r == 0 {
+				style = 1
+			}
+			x.str(fmt.Sprintf(`<row r="%d">`, r+1))
+			for c, val := range row {
+				if val == "" {
+					continue
+				}
+				x.str(fmt.Sprintf(`<c r="%s%d" s="%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(c), r+1, style))
+				x.text(val)
+				x.str(`</t></is></c>`)
+			}
+			x.str(`</row>`)
+		}
+		x.str(`</sheetData></worksheet>`)
+	}
+
+	x.flush()
+	if x.err != nil {
+		return x.err
+	}
+	return x.zw.Close()
+}
+
+// A relationship in a .rels part.
+type xlsxRelationships struct {
+	Rels []struct {
+		Id     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// The sheets of xl/workbook.xml.
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		Id   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+// A shared or inline string, which is either plain text or runs of rich text.
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+// Returns the text of a shared or inline string.
+func (t *xlsxText) String() string {
+	s := t.T
+	for _, r := range t.Runs {
+		s += r.T
+	}
+	return s
+}
+
+// The cells of a worksheet.
+type xlsxWorksheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R  string   `xml:"r,attr"`
+			T  string   `xml:"t,attr"`
+			V  string   `xml:"v"`
+			Is xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// The files of a zip archive by name.
+type zipParts map[string]*zip.File
+
+// Decode the XML part `name` into `v`.
+func (p zipParts) decode(name string, v interface{}) error {
+	f, ok := p[name]
+	if !ok {
+		return fmt.Errorf(MsgMissingPart, name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	if err = xml.NewDecoder(r).Decode(v); err != nil {
+		return fmt.Errorf("%s: %s", name, err)
+	}
+	return nil
+}
+
+// Returns the targets of the relationships of `part` with the given type, by
+// relationship ID. Targets are resolved to part names.
+func (p zipParts) rels(part, relType string) (map[string]string, error) {
+	dir, file := path.Split(part)
+	var rels xlsxRelationships
+	if err := p.decode(dir+"_rels/"+file+".rels", &rels); err != nil {
+		return nil, err
+	}
+	res := make(map[string]string)
+	for _, rel := range rels.Rels {
+		if rel.Type != relType {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			res[rel.Id] = rel.Target[1:]
+		} else {
+			res[rel.Id] = path.Join(dir, rel.Target)
+		}
+	}
+	return res, nil
+}
+
+// Read the sheets of a XLSX workbook. Only the cell values are read; numbers
+// and dates are read as they are stored, without their format.
+func DecodeXlsx(r io.Reader) ([]*Sheet, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New(MsgNotXlsx)
+	}
+	parts := make(zipParts)
+	for _, f := range zr.File {
+		parts[f.Name] = f
+	}
+
+	docs, err := parts.rels("", relOfficeDocument)
+	if err != nil {
+		return nil, err
+	}
+	var book string
+	for _, target := range docs {
+		book = target
+	}
+	if book == "" {
+		return nil, errors.New(MsgNotXlsx)
+	}
+	var wb xlsxWorkbook
+	if err = parts.decode(book, &wb); err != nil {
+		return nil, err
+	}
+	sheetParts, err := parts.rels(book, relWorksheet)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if sharedParts, err := parts.rels(book, nsRelationships+"/sharedStrings"); err == nil {
+		for _, target := range sharedParts {
+			var sst struct {
+				Items []*xlsxText `xml:"si"`
+			}
+			if err = parts.decode(target, &sst); err != nil {
+				return nil, err
+			}
+			for _, item := range sst.Items {
+				shared = append(shared, item.String())
+			}
+		}
+	}
+
+	sheets := make([]*Sheet, 0, len(wb.Sheets))
+	for _, ws := range wb.Sheets {
+		target, ok := sheetParts[ws.Id]
+		if !ok {
+			return nil, fmt.Errorf(MsgMissingPart, ws.Id)
+		}
+		var data xlsxWorksheet
+		if err = parts.decode(target, &data); err != nil {
+			return nil, err
+		}
+		sheet := &Sheet{Name: ws.Name}
+		rowIndex := -1
+		for _, row := range data.Rows {
+			rowIndex++
+			if row.R > 0 {
+				rowIndex = row.R - 1
+			}
+			for len(sheet.Rows) <= rowIndex {
+				sheet.Rows = append(sheet.Rows, nil)
+			}
+			col := -1
+			for _, c := range row.Cells {
+				col++
+				if c.R != "" {
+					if col, _, err = cellIndex(c.R); err != nil {
+						return nil, err
+					}
+				}
+				val := c.V
+				switch c.T {
+				case "s":
+					i, err := strconv.Atoi(c.V)
+					if err != nil || i < 0 || i >= len(shared) {
+						return nil, fmt.Errorf(MsgInvalidShare, c.V)
+					}
+					val = shared[i]
+				case "inlineStr":
+					val = c.Is.String()
+				}
+				cells := sheet.Rows[rowIndex]
+				for len(cells) <= col {
+					cells = append(cells, "")
+				}
+				cells[col] = val
+				sheet.Rows[rowIndex] = cells
+			}
+		}
+		sheets = append(sheets, sheet)
+	}
+	return sheets, nil
+}
+
+// Read a XLSX workbook in the spreadsheet layout to a *spdx.Document. Errors
+// in the values are of type *spdx.ParseError.
+func BuildXlsx(r io.Reader) (*spdx.Document, error) {
+	sheets, err := DecodeXlsx(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(sheets)
+}
+
+// Write a *spdx.Document to the given io.Writer as a XLSX workbook.
+func WriteXlsx(w io.Writer, doc *spdx.Document) error {
+	if doc == nil {
+		return nil
+	}
+	return EncodeXlsx(w, Sheets(doc))
+}
@@ -0,0 +1,167 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for i, name := range tests {
+		if n := columnName(i); n != name {
+			t.Errorf("Wrong name of column %d: %s (expected %s)", i, n, name)
+		}
+		if col, row, err := cellIndex(name + "12"); err != nil || col != i || row != 11 {
+			t.Errorf("Wrong index of %s12: %d, %d, %v", name, col, row, err)
+		}
+	}
+	for _, ref := range []string{"", "A", "12", "A0", "a1", "A1B"} {
+		if _, _, err := cellIndex(ref); err == nil {
+			t.Errorf("No error for cell reference %q", ref)
+		}
+	}
+}
+
+func TestEncodeDecodeXlsx(t *testing.T) {
+	sheets := []*Sheet{
+		{Name: "First & only", Rows: [][]string{
+			{"A", "B", "C"},
+			{"one", "", "<three>\nlines\n"},
+			{},
+			{"", "x"},
+		}},
+		{Name: "Empty"},
+	}
+	var b bytes.Buffer
+	if err := EncodeXlsx(&b, sheets); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeXlsx(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || decoded[0].Name != "First & only" || decoded[1].Name != "Empty" {
+		t.Fatalf("Wrong sheets: %+v", decoded)
+	}
+	rows := decoded[0].Rows
+	if len(rows) != 4 {
+		t.Fatalf("Wrong number of rows: %d", len(rows))
+	}
+	expected := [][]string{{"A", "B", "C"}, {"one", "", "<three>\nlines\n"}, nil, {"", "x"}}
+	for i, row := range expected {
+		if strings.Join(rows[i], "|") != strings.Join(row, "|") {
+			t.Errorf("Wrong row %d: %q (expected %q)", i+1, rows[i], row)
+		}
+	}
+	if len(decoded[1].Rows) != 0 {
+		t.Errorf("Rows in empty sheet: %q", decoded[1].Rows)
+	}
+}
+
+// Returns a zip archive with the given files.
+func zipFiles(t *testing.T, files map[string]string) *bytes.Buffer {
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &b
+}
+
+// A workbook as saved by spreadsheet applications: shared strings with rich
+// text, numbers, absolute targets and rows without references.
+var savedWorkbook = map[string]string{
+	"_rels/.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+		<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="book/main.xml"/>
+	</Relationships>`,
+	"book/main.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:rel="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+		<sheets><sheet name="Origins" sheetId="1" rel:id="s1"/></sheets>
+	</workbook>`,
+	"book/_rels/main.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+		<Relationship Id="s1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/sheets/one.xml"/>
+		<Relationship Id="s2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="strings.xml"/>
+	</Relationships>`,
+	"book/strings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+		<si><t>SPDX Version</t></si>
+		<si><r><t>SPDX-</t></r><r><rPr><b/></rPr><t>1.2</t></r></si>
+		<si><t>License List Version</t></si>
+	</sst>`,
+	"sheets/one.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+		<row><c t="s"><v>0</v></c><c t="s"><v>2</v></c></row>
+		<row r="3"><c r="A3" t="s"><v>1</v></c><c><v>1.2</v></c></row>
+	</sheetData></worksheet>`,
+}
+
+func TestDecodeXlsxSaved(t *testing.T) {
+	sheets, err := DecodeXlsx(zipFiles(t, savedWorkbook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sheets) != 1 || len(sheets[0].Rows) != 3 {
+		t.Fatalf("Wrong sheets: %+v", sheets)
+	}
+	rows := sheets[0].Rows
+	if strings.Join(rows[0], "|") != "SPDX Version|License List Version" || len(rows[1]) != 0 || strings.Join(rows[2], "|") != "SPDX-1.2|1.2" {
+		t.Errorf("Wrong rows: %q", rows)
+	}
+
+	doc, err := Parse(sheets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.SpecVersion.Val != "SPDX-1.2" || doc.CreationInfo.LicenceListVersion.Val != "1.2" || doc.LineStart != 3 {
+		t.Errorf("Wrong document: %+v", doc)
+	}
+}
+
+func TestDecodeXlsxErrors(t *testing.T) {
+	if _, err := DecodeXlsx(strings.NewReader("not a zip")); err == nil || err.Error() != MsgNotXlsx {
+		t.Errorf("Wrong error for invalid archive: %v", err)
+	}
+
+	missing := make(map[string]string)
+	for name, content := range savedWorkbook {
+		if name != "book/strings.xml" {
+			missing[name] = content
+		}
+	}
+	if _, err := DecodeXlsx(zipFiles(t, missing)); err == nil || !strings.Contains(err.Error(), "book/strings.xml") {
+		t.Errorf("Wrong error for missing part: %v", err)
+	}
+
+	invalid := make(map[string]string)
+	for name, content := range savedWorkbook {
+		invalid[name] = content
+	}
+	invalid["sheets/one.xml"] = strings.Replace(savedWorkbook["sheets/one.xml"], `<v>2</v>`, `<v>7</v>`, 1)
+	if _, err := DecodeXlsx(zipFiles(t, invalid)); err == nil || !strings.Contains(err.Error(), `"7"`) {
+		t.Errorf("Wrong error for invalid shared string: %v", err)
+	}
+}
+
+func TestWriteBuildXlsx(t *testing.T) {
+	doc := testDoc(t)
+	var b bytes.Buffer
+	if err := WriteXlsx(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	doc2, err := BuildXlsx(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !doc.Equal(doc2) {
+		t.Error("Different document after writing and reading XLSX.")
+	}
+	if doc2.Packages[0].LineStart != 2 || doc2.Files[1].LineStart != 3 {
+		t.Errorf("Wrong rows: %d, %d", doc2.Packages[0].LineStart, doc2.Files[1].LineStart)
+	}
+}
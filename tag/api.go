@@ -12,6 +12,9 @@ var (
 
 	// Build() settings for Lexer.CaseSensitive
 	caseSensitive = false
+
+	// Lenient parsing of unknown and out-of-context properties
+	lenient = false
 )
 
 // Set the Lexer.IgnoreMeta used by Build() function. Default is false.
@@ -26,16 +29,34 @@ func CaseSensitive(ci bool) { caseSensitive = ci }
 // Get the current option for Lexer.IgnoreCase used by Build().
 func GetCaseSensitive() bool { return caseSensitive }
 
+// Set whether Parse(), ParseRecover() and Stream keep unknown properties and
+// properties found outside their section as spdx.Extension values of the
+// current element (or the document), instead of failing. Default is false.
+func Lenient(l bool) { lenient = l }
+
+// Get the current lenient parsing option.
+func GetLenient() bool { return lenient }
+
 // Lex a io.Reader and Parse it to a *spdx.Document. If there is an error, it is
-// of type *ParseError.
+// of type *ParseError. The comments are kept in the document (see Parse()).
 func Build(f io.Reader) (*spdx.Document, error) {
 	lexer := NewLexer(f)
-	lexer.IgnoreComments = true
 	lexer.IgnoreMeta = noMeta
 	lexer.CaseSensitive = caseSensitive
 	return Parse(lexer)
 }
 
+// Lex a io.Reader in Recover mode and parse it with ParseRecover(): returns
+// the document with all the valid properties and all the lexing and parse
+// errors. The error returned is an I/O error.
+func BuildRecover(f io.Reader) (*spdx.Document, []*spdx.ParseError, error) {
+	lexer := NewLexer(f)
+	lexer.IgnoreMeta = noMeta
+	lexer.CaseSensitive = caseSensitive
+	lexer.Recover = true
+	return ParseRecover(lexer)
+}
+
 // Write a *spdx.Document to the given io.Writer
 func Write(f io.Writer, doc *spdx.Document) error {
 	p := NewFormatter(f)
@@ -0,0 +1,429 @@
+package tag
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// Kinds of CST nodes.
+const (
+	NodeBlank    = iota // empty or white space line
+	NodeComment         // comment line (# ...)
+	NodeProperty        // property (Key: value), on one line or more with <text>
+)
+
+// A node of a CST: one or more lines of the input, kept as they are.
+type Node struct {
+	Kind  int
+	Raw   string      // the lines of the node, with the line ends, as in the input
+	Key   string      // property name as written in the input (original case)
+	Value string      // property value or comment text (without the '#')
+	Text  bool        // whether the value is in <text></text>
+	Elem  interface{} // the element of the property (see CST)
+	*spdx.Meta
+
+	index int // occurrence of the property in the element, from 0
+}
+
+// A lossless concrete syntax tree of a Tag document: every line of the input
+// as a Node, and the spdx.Document parsed from it.
+//
+// Every node belongs to an element of the document: a *spdx.Document,
+// *spdx.Package, *spdx.File, *spdx.ExtractedLicence or *spdx.Review. A property
+// belongs to its element, comments and blank lines to the element of the
+// property following them and the lines at the end of the input to the
+// document.
+//
+// The Document may be edited and written with Write(), which keeps the lines
+// whose values did not change as they are, so that the textual diff is
+// minimal:
+//   - a changed property is written again on its line, with the original key
+//     case and <text> delimiters,
+//   - a new property is written after the properties with the same key of the
+//     element or, if there are none, after the last property of the element,
+//   - the lines of the removed properties and elements are left out,
+//   - a new element is written (by the Formatter) after the last element of
+//     the same kind or, if there is none, after the elements of the kinds the
+//     Formatter writes before it.
+//
+// Properties the Formatter does not write (such as ArtifactOf) are always
+// kept as they are.
+type CST struct {
+	Nodes    []*Node
+	Document *spdx.Document
+
+	parsed map[interface{}][]Pair // properties of the elements when parsed
+}
+
+// A lexer which records the tokens it returns.
+type recordingLexer struct {
+	lexer
+	tokens []Token
+}
+
+func (r *recordingLexer) Lex() bool {
+	if !r.lexer.Lex() {
+		return false
+	}
+	r.tokens = append(r.tokens, *r.lexer.Token())
+	return true
+}
+
+// Splits `data` in lines, keeping the line ends.
+func splitLines(data string) []string {
+	lines := strings.SplitAfter(data, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Returns the node of a line the lexer did not return a token for: a blank
+// line or a comment at the end of the input without a line end.
+func lineNode(line string, n int) *Node {
+	node := &Node{Kind: NodeBlank, Raw: line, Meta: spdx.NewMetaL(n)}
+	if trimmed := strings.TrimSpace(line); strings.HasPrefix(trimmed, "#") {
+		node.Kind = NodeComment
+		node.Value = trimmed[1:]
+	}
+	return node
+}
+
+// Read and parse a Tag document to a *CST, with the settings of Build(). If
+// there is an error, it is of type *ParseError or an I/O error.
+func ParseCST(f io.Reader) (*CST, error) {
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	lexer := NewLexer(bytes.NewReader(data))
+	lexer.CaseSensitive = caseSensitive
+	rec := &recordingLexer{lexer: lexer}
+	doc, err := Parse(rec)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CST{Document: doc, parsed: make(map[interface{}][]Pair)}
+	lines := splitLines(string(data))
+	next := 0 // next line to be used (from 0)
+	var elem interface{} = doc
+	count := make(map[string]int) // sections seen by key
+	index := make(map[interface{}]map[string]int)
+	var pending []*Node // comments and blank lines before the next property
+
+	for i := range rec.tokens {
+		tok := &rec.tokens[i]
+		for ; next < len(lines) && next < tok.LineStart-1; next++ {
+			pending = append(pending, lineNode(lines[next], next+1))
+		}
+		end := tok.LineEnd
+		if end < next+1 {
+			end = next + 1
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		node := &Node{Raw: strings.Join(lines[next:end], ""), Value: tok.Value, Meta: spdx.NewMeta(next+1, end)}
+		next = end
+
+		if tok.Type == TokenComment {
+			node.Kind = NodeComment
+			pending = append(pending, node)
+			continue
+		}
+
+		node.Kind = NodeProperty
+		if column := strings.Index(node.Raw, ":"); column >= 0 {
+			node.Key = strings.TrimSpace(node.Raw[:column])
+			node.Text = strings.HasPrefix(strings.TrimSpace(node.Raw[column+1:]), openTag)
+		}
+		if sectionStart[tok.Key] {
+			elem = c.element(tok.Key, count[tok.Key])
+			count[tok.Key]++
+		}
+		node.Elem = elem
+		if documentProperty[tok.Key] {
+			node.Elem = doc
+		}
+		if index[node.Elem] == nil {
+			index[node.Elem] = make(map[string]int)
+		}
+		node.index = index[node.Elem][strings.ToLower(tok.Key)]
+		index[node.Elem][strings.ToLower(tok.Key)]++
+
+		for _, n := range pending {
+			n.Elem = node.Elem
+		}
+		c.Nodes = append(c.Nodes, pending...)
+		c.Nodes = append(c.Nodes, node)
+		pending = nil
+	}
+	for ; next < len(lines); next++ {
+		pending = append(pending, lineNode(lines[next], next+1))
+	}
+	for _, n := range pending {
+		n.Elem = doc
+	}
+	c.Nodes = append(c.Nodes, pending...)
+
+	for _, e := range c.elements() {
+		c.parsed[e] = elementPairs(e)
+	}
+	return c, nil
+}
+
+// Returns the n-th element created by the section start property `key`.
+func (c *CST) element(key string, n int) interface{} {
+	doc := c.Document
+	switch key {
+	case "PackageName":
+		return doc.Packages[n]
+	case "FileName":
+		return doc.Files[n]
+	case "LicenseID":
+		return doc.ExtractedLicences[n]
+	case "Reviewer":
+		return doc.Reviews[n]
+	}
+	return doc
+}
+
+// Returns the elements of the document in the order Formatter.Document()
+// writes them.
+func (c *CST) elements() []interface{} {
+	doc := c.Document
+	elems := []interface{}{doc}
+	for _, pkg := range doc.Packages {
+		elems = append(elems, pkg)
+	}
+	for _, file := range doc.Files {
+		elems = append(elems, file)
+	}
+	for _, rev := range doc.Reviews {
+		elems = append(elems, rev)
+	}
+	for _, lic := range doc.ExtractedLicences {
+		elems = append(elems, lic)
+	}
+	return elems
+}
+
+// Returns the nodes of the element `elem`.
+func (c *CST) NodesOf(elem interface{}) []*Node {
+	var nodes []*Node
+	for _, n := range c.Nodes {
+		if n.Elem == elem {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+// Returns the properties of `elem` as written by the Formatter, without the
+// nested elements. For the document, these are the document and creation info
+// properties.
+func elementPairs(elem interface{}) []Pair {
+	buf := new(bytes.Buffer)
+	f := NewFormatter(buf)
+	switch e := elem.(type) {
+	case *spdx.Document:
+		f.Properties([]Pair{
+			{"SPDXVersion", e.SpecVersion.Val},
+			{"DataLicense", e.DataLicence.Val},
+			{"DocumentComment", e.Comment.Val},
+		})
+		f.CreationInfo(e.CreationInfo)
+		f.Extensions(e.Extensions)
+	case *spdx.Package:
+		f.Package(e)
+	case *spdx.File:
+		f.File(e)
+	case *spdx.ExtractedLicence:
+		f.ExtractedLicence(e)
+	case *spdx.Review:
+		f.Review(e)
+	}
+	pairs, _ := lexPair(buf)
+	return pairs
+}
+
+// Returns the value of the n-th property `key` in `pairs`.
+func nthValue(pairs []Pair, key string, n int) (string, bool) {
+	for _, p := range pairs {
+		if strings.EqualFold(p.Key, key) {
+			if n == 0 {
+				return p.Value, true
+			}
+			n--
+		}
+	}
+	return "", false
+}
+
+// Returns the line of a property with the original key and <text> style of
+// `node`.
+func (n *Node) rewrite(value string) string {
+	end := "\n"
+	if strings.HasSuffix(n.Raw, "\r\n") {
+		end = "\r\n"
+	}
+	if n.Text || strings.Contains(value, "\n") {
+		value = openTag + value + closeTag
+	}
+	return n.Key + ": " + value + end
+}
+
+// Write the document to `w`. The lines of the input are kept as they are,
+// unless the values in the Document changed (see CST).
+func (c *CST) Write(w io.Writer) error {
+	out := new(bytes.Buffer)
+	elems := c.elements()
+	present := make(map[interface{}]bool)
+	current := make(map[interface{}][]Pair)
+	for _, e := range elems {
+		present[e] = true
+		current[e] = elementPairs(e)
+	}
+
+	// the last node of each element and of each property of the elements, to
+	// write the new properties after them
+	last := make(map[interface{}]*Node)
+	lastKey := make(map[interface{}]map[string]*Node)
+	count := make(map[interface{}]map[string]int)
+	for _, n := range c.Nodes {
+		if n.Kind != NodeProperty {
+			continue
+		}
+		key := strings.ToLower(n.Key)
+		last[n.Elem] = n
+		if lastKey[n.Elem] == nil {
+			lastKey[n.Elem] = make(map[string]*Node)
+			count[n.Elem] = make(map[string]int)
+		}
+		lastKey[n.Elem][key] = n
+		count[n.Elem][key]++
+	}
+
+	// new properties, by the node they are written after
+	after := make(map[*Node][]Pair)
+	for _, e := range elems {
+		if _, ok := c.parsed[e]; !ok || last[e] == nil {
+			continue
+		}
+		seen := make(map[string]int)
+		for _, p := range current[e] {
+			key := strings.ToLower(p.Key)
+			seen[key]++
+			if seen[key] <= count[e][key] {
+				continue
+			}
+			anchor := lastKey[e][key]
+			if anchor == nil {
+				anchor = last[e]
+			}
+			after[anchor] = append(after[anchor], p)
+		}
+	}
+
+	// new elements, by the node they are written after (nil for the end)
+	newElems := make(map[*Node][]interface{})
+	var lastOfKind [5]*Node
+	for _, e := range elems {
+		kind := elementKind(e)
+		if _, ok := c.parsed[e]; ok {
+			if last[e] != nil {
+				lastOfKind[kind] = last[e]
+			}
+			continue
+		}
+		// after the elements of the same kind or of the kinds before
+		anchor := lastOfKind[kind]
+		for k := kind - 1; anchor == nil && k >= 0; k-- {
+			anchor = lastOfKind[k]
+		}
+		newElems[anchor] = append(newElems[anchor], e)
+	}
+
+	for _, n := range c.Nodes {
+		if !present[n.Elem] {
+			continue
+		}
+		switch n.Kind {
+		case NodeProperty:
+			old, wasWritten := nthValue(c.parsed[n.Elem], n.Key, n.index)
+			val, ok := nthValue(current[n.Elem], n.Key, n.index)
+			switch {
+			case !wasWritten || (ok && val == old):
+				// not written by the Formatter (e.g. an empty value) or
+				// unchanged
+				out.WriteString(n.Raw)
+			case ok:
+				out.WriteString(n.rewrite(val))
+			}
+		default:
+			out.WriteString(n.Raw)
+		}
+		if len(after[n]) > 0 || len(newElems[n]) > 0 {
+			endLine(out)
+		}
+		for _, p := range after[n] {
+			NewFormatter(out).Property(p.Key, p.Value)
+		}
+		for _, e := range newElems[n] {
+			writeElement(out, e)
+		}
+	}
+	for _, e := range newElems[nil] {
+		writeElement(out, e)
+	}
+
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// Returns the kind of `elem`: 0 for the document, then 1 to 4 in the order
+// the Formatter writes them.
+func elementKind(elem interface{}) int {
+	switch elem.(type) {
+	case *spdx.Package:
+		return 1
+	case *spdx.File:
+		return 2
+	case *spdx.Review:
+		return 3
+	case *spdx.ExtractedLicence:
+		return 4
+	}
+	return 0
+}
+
+// Ends the last line of `out` if it is not ended.
+func endLine(out *bytes.Buffer) {
+	if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte{'\n'}) {
+		out.WriteByte('\n')
+	}
+}
+
+// Writes the new element `elem` with the Formatter, after a blank line.
+func writeElement(out *bytes.Buffer, elem interface{}) {
+	endLine(out)
+	if out.Len() > 0 && !bytes.HasSuffix(out.Bytes(), []byte("\n\n")) {
+		out.WriteByte('\n')
+	}
+	f := NewFormatter(out)
+	switch e := elem.(type) {
+	case *spdx.Package:
+		f.Package(e)
+	case *spdx.File:
+		f.File(e)
+	case *spdx.ExtractedLicence:
+		f.ExtractedLicence(e)
+	case *spdx.Review:
+		f.Review(e)
+	}
+}
@@ -0,0 +1,149 @@
+package tag
+
+import (
+	"bytes"
+	"github.com/vladvelici/spdx-go/spdx"
+	"strings"
+	"testing"
+)
+
+const cstInput = `# header
+SPDXVersion: SPDX-1.2
+
+documentcomment: <text>first
+second</text>
+Creator: Tool: x
+
+# the package
+PackageName: p
+PackageVersion: 1.0
+
+FileName: a.c
+FileType: SOURCE
+FileContributor: Me
+ArtifactOfProjectName: project
+
+# second file
+FileName: b.c
+FileType: SOURCE
+
+LicenseID: LicenseRef-1
+ExtractedText: text
+# end`
+
+func parseCST(t *testing.T) *CST {
+	c, err := ParseCST(strings.NewReader(cstInput))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return c
+}
+
+func writeCST(t *testing.T, c *CST) string {
+	buf := new(bytes.Buffer)
+	if err := c.Write(buf); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	return buf.String()
+}
+
+func TestCSTRoundTrip(t *testing.T) {
+	c := parseCST(t)
+	if res := writeCST(t, c); res != cstInput {
+		t.Errorf("Printed %#v but expected %#v", res, cstInput)
+	}
+}
+
+func TestCSTNodes(t *testing.T) {
+	c := parseCST(t)
+	doc := c.Document
+	expected := []struct {
+		kind  int
+		key   string
+		elem  interface{}
+		lines [2]int
+	}{
+		{NodeComment, "", doc, [2]int{1, 1}},
+		{NodeProperty, "SPDXVersion", doc, [2]int{2, 2}},
+		{NodeBlank, "", doc, [2]int{3, 3}},
+		{NodeProperty, "documentcomment", doc, [2]int{4, 5}},
+		{NodeProperty, "Creator", doc, [2]int{6, 6}},
+		{NodeBlank, "", doc.Packages[0], [2]int{7, 7}},
+		{NodeComment, "", doc.Packages[0], [2]int{8, 8}},
+		{NodeProperty, "PackageName", doc.Packages[0], [2]int{9, 9}},
+		{NodeProperty, "PackageVersion", doc.Packages[0], [2]int{10, 10}},
+		{NodeBlank, "", doc.Files[0], [2]int{11, 11}},
+		{NodeProperty, "FileName", doc.Files[0], [2]int{12, 12}},
+		{NodeProperty, "FileType", doc.Files[0], [2]int{13, 13}},
+		{NodeProperty, "FileContributor", doc.Files[0], [2]int{14, 14}},
+		{NodeProperty, "ArtifactOfProjectName", doc.Files[0], [2]int{15, 15}},
+		{NodeBlank, "", doc.Files[1], [2]int{16, 16}},
+		{NodeComment, "", doc.Files[1], [2]int{17, 17}},
+		{NodeProperty, "FileName", doc.Files[1], [2]int{18, 18}},
+		{NodeProperty, "FileType", doc.Files[1], [2]int{19, 19}},
+		{NodeBlank, "", doc.ExtractedLicences[0], [2]int{20, 20}},
+		{NodeProperty, "LicenseID", doc.ExtractedLicences[0], [2]int{21, 21}},
+		{NodeProperty, "ExtractedText", doc.ExtractedLicences[0], [2]int{22, 22}},
+		{NodeComment, "", doc, [2]int{23, 23}},
+	}
+	if len(c.Nodes) != len(expected) {
+		t.Fatalf("Expected %d nodes but found %d", len(expected), len(c.Nodes))
+	}
+	for i, e := range expected {
+		n := c.Nodes[i]
+		if n.Kind != e.kind || n.Key != e.key || n.Elem != e.elem || n.LineStart != e.lines[0] || n.LineEnd != e.lines[1] {
+			t.Errorf("%d: Expected %+v but found %+v", i, e, n)
+		}
+	}
+	if n := c.Nodes[3]; !n.Text || n.Value != "first\nsecond" {
+		t.Errorf("Wrong text node %+v", n)
+	}
+	if nodes := c.NodesOf(doc.Files[1]); len(nodes) != 4 {
+		t.Errorf("Expected 4 nodes but found %v", nodes)
+	}
+}
+
+func TestCSTEdit(t *testing.T) {
+	c := parseCST(t)
+	doc := c.Document
+	doc.Comment.Val = "changed"
+	doc.Packages[0].Version.Val = "2.0"
+	doc.Files[1].Type.Val = ""
+	res := writeCST(t, c)
+	expected := strings.NewReplacer(
+		"documentcomment: <text>first\nsecond</text>", "documentcomment: <text>changed</text>",
+		"PackageVersion: 1.0", "PackageVersion: 2.0",
+		"FileName: b.c\nFileType: SOURCE\n", "FileName: b.c\n",
+	).Replace(cstInput)
+	if res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+}
+
+func TestCSTAddProperties(t *testing.T) {
+	c := parseCST(t)
+	file := c.Document.Files[0]
+	file.Contributor = append(file.Contributor, spdx.Str("You", nil))
+	file.Checksum = &spdx.Checksum{Algo: spdx.Str("SHA1", nil), Value: spdx.Str("2fd4e1c67a2d28fced849ee1bb76e7391b93eb12", nil)}
+	res := writeCST(t, c)
+	expected := strings.Replace(cstInput,
+		"FileContributor: Me\nArtifactOfProjectName: project\n",
+		"FileContributor: Me\nFileContributor: You\nArtifactOfProjectName: project\nFileChecksum: SHA1: 2fd4e1c67a2d28fced849ee1bb76e7391b93eb12\n", 1)
+	if res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+}
+
+func TestCSTElements(t *testing.T) {
+	c := parseCST(t)
+	doc := c.Document
+	doc.Files = []*spdx.File{doc.Files[0], {Name: spdx.Str("c.c", nil), Type: spdx.Str("BINARY", nil)}}
+	doc.Reviews = []*spdx.Review{{Reviewer: spdx.NewValueCreator("Person: Me", nil)}}
+	res := writeCST(t, c)
+	expected := strings.Replace(cstInput,
+		"ArtifactOfProjectName: project\n\n# second file\nFileName: b.c\nFileType: SOURCE\n",
+		"ArtifactOfProjectName: project\n\nFileName: c.c\nFileType: BINARY\n\nReviewer: Person: Me\n", 1)
+	if res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+}
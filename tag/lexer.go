@@ -95,9 +95,12 @@ type Lexer struct {
 	ttype          int
 	token          *Token
 	err            error
+	errs           []*spdx.ParseError
+	skip           bool // whether the value being lexed is invalid
 	IgnoreComments bool
 	IgnoreMeta     bool
 	CaseSensitive  bool
+	Recover        bool
 }
 
 // Always use this function to create a new *Lexer. The reader given is used to form the tokens.
@@ -110,6 +113,9 @@ type Lexer struct {
 // - CaseSensitive (default false)
 //   If set to false, it tries to transform the case of Token.Pair.Key (of all non-comment tokens) to
 //   match the one in the SPDX specification. E.g. transform "specversion" to "SpecVersion".
+// - Recover (default false)
+//   Do not stop at the first syntax error: record it, skip to the next property line and
+//   continue. The errors are returned by Errors(); Err() only returns I/O errors.
 func NewLexer(r io.Reader) *Lexer {
 	lexer := &Lexer{
 		r:         r,
@@ -136,6 +142,7 @@ func (l *Lexer) Token() *Token {
 // If there is an error while lexing, this method returns false and the
 // error will be available by calling Err().
 func (l *Lexer) Lex() bool {
+	l.skip = false
 	if !l.scanner.Scan() {
 		l.err = l.scanner.Err()
 		l.token = nil
@@ -180,6 +187,11 @@ func (l *Lexer) Lex() bool {
 		}
 	}
 
+	// invalid value skipped in Recover mode
+	if l.skip {
+		return l.Lex()
+	}
+
 	return true
 }
 
@@ -189,6 +201,22 @@ func (l *Lexer) Err() error {
 	return l.err
 }
 
+// Get the syntax errors found in Recover mode, in the order they were found.
+func (l *Lexer) Errors() []*spdx.ParseError {
+	return l.errs
+}
+
+// Records the error `msg` at the given lines in Recover mode. Returns the
+// error if not in Recover mode.
+func (l *Lexer) fail(msg string, start, end int) error {
+	err := spdx.NewParseError(msg, &spdx.Meta{start, end})
+	if !l.Recover {
+		return err
+	}
+	l.errs = append(l.errs, err)
+	return nil
+}
+
 // Return the line of last token (end line). This property is available even when IgnoreMeta is set to true.
 // Use Token.Meta properties Token.LineStart and Token.LineEnd when those are available.
 func (l *Lexer) Line() int {
@@ -266,14 +294,19 @@ func (l *Lexer) tokenizer() bufio.SplitFunc {
 			column := bytes.IndexByte(data, propertySep)
 			endl := bytes.IndexByte(data, '\n')
 
-			if endl >= 0 && endl < column {
-				return 0, nil, spdx.NewParseError(MsgInvalidText, &spdx.Meta{l.line, l.line})
+			if (endl >= 0 && endl < column) || (column < 0 && atEOF) {
+				if err := l.fail(MsgInvalidText, l.line, l.line); err != nil {
+					return 0, nil, err
+				}
+				// skip the line
+				if endl < 0 {
+					return shifted + len(data), nil, nil
+				}
+				l.line++
+				return shifted + endl + 1, nil, nil
 			}
 
 			if column < 0 {
-				if atEOF {
-					return 0, nil, spdx.NewParseError(MsgInvalidText, &spdx.Meta{l.line, l.line})
-				}
 				return shifted, nil, nil
 			}
 			l.ttype = tokenKey
@@ -289,15 +322,40 @@ func (l *Lexer) tokenizer() bufio.SplitFunc {
 		if startText >= 0 && (endl < 0 || startText < endl) {
 
 			l.lineStart = l.line // lineStart is at the start of property
-			if countSpaces(data[:startText]) != startText {
+			badPrefix := countSpaces(data[:startText]) != startText
+			if badPrefix && !l.Recover {
 				return 0, nil, spdx.NewParseError(MsgInvalidPrefix, &spdx.Meta{l.line, l.line})
 			}
+			// in Recover mode, the value is skipped; the error is recorded once
+			// the value is complete
+			prefixErr := func() {
+				if badPrefix {
+					l.fail(MsgInvalidPrefix, l.lineStart, l.lineStart)
+					l.skip = true
+				}
+			}
 
 			endText := bytes.Index(data, []byte(closeTag))
 			if endText < 0 {
 				if atEOF {
-					l.line += bytes.Count(data, []byte{'\n'})
-					return 0, nil, spdx.NewParseError(MsgNoCloseTag, &spdx.Meta{l.line, l.line})
+					if !l.Recover {
+						l.line += bytes.Count(data, []byte{'\n'})
+						return 0, nil, spdx.NewParseError(MsgNoCloseTag, &spdx.Meta{l.line, l.line})
+					}
+					// skip the value up to the next property line
+					prefixErr()
+					next := nextProperty(data[startText:]) + startText
+					lines := bytes.Count(data[:next], []byte{'\n'})
+					end := l.line + lines // last line skipped
+					if data[next-1] == '\n' {
+						end--
+					}
+					l.fail(MsgNoCloseTag, l.lineStart, end)
+					l.line += lines
+					l.skip = true
+					hasKey = false
+					l.ttype = tokenValue
+					return shifted + next, data[:0], nil
 				}
 				return shifted, nil, nil
 			}
@@ -319,8 +377,16 @@ func (l *Lexer) tokenizer() bufio.SplitFunc {
 				closeToEndl = data[endText+len(closeTag) : endlAfterEndTxt+endText]
 			}
 
+			prefixErr()
 			if closeToEndl != nil && countSpaces(closeToEndl) != len(closeToEndl) {
-				return 0, nil, spdx.NewParseError(MsgInvalidSuffix, &spdx.Meta{l.line, l.line})
+				if err := l.fail(MsgInvalidSuffix, l.line, l.line); err != nil {
+					return 0, nil, err
+				}
+				// skip the rest of the line
+				l.skip = true
+				hasKey = false
+				l.ttype = tokenValue
+				return shifted + endText + len(closeTag) + len(closeToEndl), data[valStart:valEnd], nil
 			}
 
 			hasKey = false
@@ -344,6 +410,28 @@ func (l *Lexer) tokenizer() bufio.SplitFunc {
 	}
 }
 
+// Returns the offset of the first line of `data`, after the first one, which
+// starts with a valid property ("Property:"), or len(data) if there is none.
+func nextProperty(data []byte) int {
+	for i := bytes.IndexByte(data, '\n'); i >= 0; {
+		line := data[i+1:]
+		if end := bytes.IndexByte(line, '\n'); end >= 0 {
+			line = line[:end]
+		}
+		if column := bytes.IndexByte(line, propertySep); column >= 0 {
+			if ok, _ := IsValidPropertyInsensitive(strings.TrimSpace(string(line[:column]))); ok {
+				return i + 1
+			}
+		}
+		next := bytes.IndexByte(data[i+1:], '\n')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return len(data)
+}
+
 // Lex all the pairs.
 func lexPair(f io.Reader) ([]Pair, error) {
 	p := make([]Pair, 0)
@@ -621,3 +621,63 @@ func TestCommentEndingInNewlineAtEOF(t *testing.T) {
 		t.Errorf("Fail with: advance=%d, data=%s, err=%s\n", advance, token, err)
 	}
 }
+
+// Lex all the pairs in Recover mode.
+func lexRecover(s string) ([]Pair, []*spdx.ParseError, error) {
+	p := make([]Pair, 0)
+	lex := NewLexer(strings.NewReader(s))
+	lex.IgnoreComments = true
+	lex.Recover = true
+	for lex.Lex() {
+		p = append(p, lex.Token().Pair)
+	}
+	return p, lex.Errors(), lex.Err()
+}
+
+// Checks that the messages and lines of `errs` are the expected ones.
+func sameErrors(t *testing.T, errs []*spdx.ParseError, msgs []string, lines [][2]int) {
+	if len(errs) != len(msgs) {
+		t.Errorf("Expected %d errors but found %d: %v", len(msgs), len(errs), errs)
+		return
+	}
+	for i, e := range errs {
+		if e.Error() != msgs[i] || e.LineStart != lines[i][0] || e.LineEnd != lines[i][1] {
+			t.Errorf("Error %d: expected %q at %v but found %q at %d-%d", i, msgs[i], lines[i], e.Error(), e.LineStart, e.LineEnd)
+		}
+	}
+}
+
+func TestRecoverInvalidText(t *testing.T) {
+	doc, errs, err := lexRecover("Prop1: val1\nsome invalid text\nProp2: val2\ninvalid at EOF")
+	if err != nil || !sameDoc(doc, []Pair{{"Prop1", "val1"}, {"Prop2", "val2"}}) {
+		t.Errorf("Document: %s. Error: %s", doc, err)
+	}
+	sameErrors(t, errs, []string{MsgInvalidText, MsgInvalidText}, [][2]int{{2, 2}, {4, 4}})
+}
+
+func TestRecoverInvalidTextValue(t *testing.T) {
+	doc, errs, err := lexRecover("Prop1: prefix <text>val\nue</text>\nProp2: <text>val2</text> suffix\nProp3: val3")
+	if err != nil || !sameDoc(doc, []Pair{{"Prop3", "val3"}}) {
+		t.Errorf("Document: %s. Error: %s", doc, err)
+	}
+	sameErrors(t, errs, []string{MsgInvalidPrefix, MsgInvalidSuffix}, [][2]int{{1, 1}, {3, 3}})
+}
+
+func TestRecoverUnclosedText(t *testing.T) {
+	doc, errs, err := lexRecover("Prop1: <text>value\nnot: a property\n\nFileName: f\nFileComment: <text>end")
+	if err != nil || !sameDoc(doc, []Pair{{"FileName", "f"}}) {
+		t.Errorf("Document: %s. Error: %s", doc, err)
+	}
+	sameErrors(t, errs, []string{MsgNoCloseTag, MsgNoCloseTag}, [][2]int{{1, 3}, {5, 5}})
+}
+
+func TestRecoverLines(t *testing.T) {
+	lex := NewLexer(strings.NewReader("bad\nProp1: <text>a\nb</text> bad\n\nProp2: val2\n"))
+	lex.Recover = true
+	if !lex.Lex() || lex.Token().Pair != (Pair{"Prop2", "val2"}) || lex.Token().LineStart != 5 {
+		t.Errorf("Wrong token %v", lex.Token())
+	}
+	if lex.Lex() || lex.Err() != nil {
+		t.Errorf("Unexpected token %v or error %s", lex.Token(), lex.Err())
+	}
+}
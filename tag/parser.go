@@ -4,6 +4,7 @@ import "github.com/spdx/tools-go/spdx"
 
 import (
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -386,6 +387,15 @@ func parseLicenceString(tok *Token) (spdx.AnyLicence, error) {
 	return spdx.NewLicence(strings.TrimSpace(val), tok.Meta), nil
 }
 
+// Parse a licence string as found in the Tag format (e.g. "(MIT or LicenseRef-1)")
+// to a spdx.AnyLicence. The metadata `m` is set to all the licences created.
+//
+// Licence references are not replaced by the *spdx.ExtractedLicence they
+// refer to; see ResolveReferences().
+func ParseLicence(val string, m *spdx.Meta) (spdx.AnyLicence, error) {
+	return parseLicenceString(&Token{Type: TokenPair, Pair: Pair{Value: val}, Meta: m})
+}
+
 // Update a AnyLicence pointer.
 func anyLicence(lic *spdx.AnyLicence) updater {
 	set := false
@@ -612,6 +622,87 @@ func applyMapping(tok *Token, mapping *updaterMapping) (ok bool, err error) {
 	return true, f(tok)
 }
 
+// Apply the mapping like applyMapping(). In lenient mode, a property not in
+// the mapping is appended to `ext`, the extensions of the current element,
+// instead of failing.
+func applyLenient(tok *Token, mapping *updaterMapping, ext *[]spdx.Extension) (ok bool, err error) {
+	ok, err = applyMapping(tok, mapping)
+	if !ok && lenient {
+		*ext = append(*ext, spdx.Extension{Key: tok.Key, Value: tok.Value, Meta: tok.Meta})
+		return false, nil
+	}
+	return ok, err
+}
+
+// The properties of the document itself, which may be anywhere in a Tag
+// document.
+var documentProperty = map[string]bool{
+	"SPDXVersion":        true,
+	"DataLicense":        true,
+	"DocumentComment":    true,
+	"Creator":            true,
+	"Created":            true,
+	"CreatorComment":     true,
+	"LicenseListVersion": true,
+}
+
+// Returns the element the section start property `key` created last in
+// `doc`, or `doc` if `key` does not start a section.
+func sectionElement(doc *spdx.Document, key string) interface{} {
+	switch key {
+	case "PackageName":
+		return doc.Packages[len(doc.Packages)-1]
+	case "FileName":
+		return doc.Files[len(doc.Files)-1]
+	case "LicenseID":
+		return doc.ExtractedLicences[len(doc.ExtractedLicences)-1]
+	case "Reviewer":
+		return doc.Reviews[len(doc.Reviews)-1]
+	}
+	return doc
+}
+
+// Returns the extensions and the Tag comments of `elem`, a *spdx.Document,
+// *spdx.Package, *spdx.File, *spdx.ExtractedLicence or *spdx.Review.
+func elementExtras(elem interface{}) (*[]spdx.Extension, *[]spdx.TagComment) {
+	switch e := elem.(type) {
+	case *spdx.Document:
+		return &e.Extensions, &e.TagComments
+	case *spdx.Package:
+		return &e.Extensions, &e.TagComments
+	case *spdx.File:
+		return &e.Extensions, &e.TagComments
+	case *spdx.ExtractedLicence:
+		return &e.Extensions, &e.TagComments
+	case *spdx.Review:
+		return &e.Extensions, &e.TagComments
+	}
+	return nil, nil
+}
+
+// Returns the extensions of `elem` (see elementExtras()).
+func extensions(elem interface{}) *[]spdx.Extension {
+	ext, _ := elementExtras(elem)
+	return ext
+}
+
+// Attaches the pending `comments` to the element of the property `key`,
+// which is `elem` or, for document properties, `doc`. An empty key attaches
+// them to the end of the document.
+func attachComments(doc *spdx.Document, elem interface{}, key string, comments []spdx.TagComment) {
+	if len(comments) == 0 {
+		return
+	}
+	if key == "" || documentProperty[key] {
+		elem = doc
+	}
+	_, dest := elementExtras(elem)
+	for _, c := range comments {
+		c.Before = key
+		*dest = append(*dest, c)
+	}
+}
+
 func updateLicenceReferences(lic *spdx.AnyLicence, index map[string]*spdx.ExtractedLicence) {
 	switch t := (*lic).(type) {
 	case spdx.Licence:
@@ -631,35 +722,161 @@ func updateLicenceReferences(lic *spdx.AnyLicence, index map[string]*spdx.Extrac
 	}
 }
 
-// Parse Tokens given by a lexer to a *spdx.Document.
-// Errors returned are either I/O errors returned by the io.Reader associated with the given lexer,
-// lexing errors (still have *ParseError type) or parse errors (type *ParseError).
-func Parse(lex lexer) (*spdx.Document, error) {
-	doc := new(spdx.Document)
-	mapping := documentMap(doc)
+// The token loop shared by the parsers: applies every property given by a
+// lexer to a document mapping. Nil functions are not called.
+type tokenLoop struct {
+	doc     *spdx.Document
+	mapping *updaterMapping
+
+	// Returns the extensions of the current element (see applyLenient()).
+	extensions func() *[]spdx.Extension
+	// Called with every comment token.
+	comment func(*Token)
+	// Called with every property before it is applied.
+	before func(*Token) error
+	// Called with every property after it is applied; `ok` is false if the
+	// property was not found in the mapping.
+	after func(tok *Token, ok bool)
+	// Called with the error of a property. The loop stops if it returns an
+	// error and skips the property otherwise.
+	failed func(*Token, error) error
+}
+
+// Runs the loop on the tokens of `lex`. Returns the error of `failed` or of
+// `before`, or the error of the lexer.
+func (l *tokenLoop) run(lex lexer) error {
 	for lex.Lex() {
 		token := lex.Token()
 
-		// ignore comments if they're returned by lexer
+		if token.Type == TokenComment && l.comment != nil {
+			l.comment(token)
+			continue
+		}
 		if token.Type != TokenPair {
 			continue
 		}
 
-		_, err := applyMapping(token, mapping)
+		if l.before != nil {
+			if err := l.before(token); err != nil {
+				return err
+			}
+		}
+		ok, err := applyLenient(token, l.mapping, l.extensions())
+		if l.after != nil {
+			l.after(token, ok)
+		}
 		if err != nil {
-			return nil, err
+			if err = l.failed(token, err); err != nil {
+				return err
+			}
+			continue
 		}
 
 		// first token with non-nil meta is the document meta
-		if doc.Meta == nil {
-			doc.Meta = token.Meta
+		if l.doc.Meta == nil {
+			l.doc.Meta = token.Meta
 		}
 	}
+	return lex.Err()
+}
+
+// Parse Tokens given by a lexer to a *spdx.Document.
+// Errors returned are either I/O errors returned by the io.Reader associated with the given lexer,
+// lexing errors (still have *ParseError type) or parse errors (type *ParseError).
+//
+// In lenient mode (see Lenient()), unknown properties and properties found
+// outside their section are kept in the Extensions of the current element.
+//
+// The comments returned by the lexer are kept in the TagComments of the
+// element of the property following them (see spdx.TagComment).
+func Parse(lex lexer) (*spdx.Document, error) {
+	doc, _, err := parse(lex, false)
+	return doc, err
+}
+
+// Parse Tokens given by a lexer to a *spdx.Document like Parse(), but do not
+// stop at the first error: every parse error is recorded and the property is
+// skipped. If the lexer has an Errors() method (as *Lexer in Recover mode),
+// its syntax errors are included. The errors are ordered by line.
+//
+// The returned document has all the valid properties. The error returned is
+// an I/O error of the io.Reader associated with the lexer; the document is nil
+// in this case.
+func ParseRecover(lex lexer) (*spdx.Document, []*spdx.ParseError, error) {
+	return parse(lex, true)
+}
+
+// Parses the tokens of `lex` for Parse() and ParseRecover(). Without
+// `recover`, the parsing stops at the first parse error, which is returned as
+// the error.
+func parse(lex lexer, recover bool) (*spdx.Document, []*spdx.ParseError, error) {
+	doc := new(spdx.Document)
+	var elem interface{} = doc
+	var comments []spdx.TagComment
+	var errs []*spdx.ParseError
+	loop := &tokenLoop{
+		doc:        doc,
+		mapping:    documentMap(doc),
+		extensions: func() *[]spdx.Extension { return extensions(elem) },
+		comment: func(tok *Token) {
+			comments = append(comments, spdx.TagComment{Text: tok.Value, Meta: tok.Meta})
+		},
+		after: func(tok *Token, ok bool) {
+			if ok && sectionStart[tok.Key] {
+				elem = sectionElement(doc, tok.Key)
+			}
+			attachComments(doc, elem, tok.Key, comments)
+			comments = nil
+		},
+		failed: func(tok *Token, err error) error {
+			if !recover {
+				return err
+			}
+			perr, ok := err.(*spdx.ParseError)
+			if !ok {
+				perr = spdx.NewParseError(err.Error(), tok.Meta)
+			}
+			errs = append(errs, perr)
+			return nil
+		},
+	}
 
-	if lex.Err() != nil {
-		return nil, lex.Err()
+	if err := loop.run(lex); err != nil {
+		return nil, nil, err
 	}
+	if l, ok := lex.(interface {
+		Errors() []*spdx.ParseError
+	}); ok {
+		errs = append(l.Errors(), errs...)
+	}
+	sort.Stable(byLine(errs))
+
+	attachComments(doc, doc, "", comments)
+	ResolveReferences(doc)
+
+	return doc, errs, nil
+}
 
+// Sorts parse errors by line. Errors without metadata come first.
+type byLine []*spdx.ParseError
+
+func (s byLine) Len() int      { return len(s) }
+func (s byLine) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byLine) Less(i, j int) bool {
+	if s[i].Meta == nil || s[j].Meta == nil {
+		return s[i].Meta == nil && s[j].Meta != nil
+	}
+	return s[i].LineStart < s[j].LineStart
+}
+
+// Replace the references in `doc` by pointers to the elements they refer to:
+// - licence references (LicenseRef-...) by the *spdx.ExtractedLicence with
+//   the same ID in doc.ExtractedLicences,
+// - file dependencies and package files that only have a name by the file
+//   with the same name in doc.Files.
+//
+// References to elements not found in the document are left unchanged.
+func ResolveReferences(doc *spdx.Document) {
 	// licence references index
 	licenceMap := make(map[string]*spdx.ExtractedLicence)
 	for _, lic := range doc.ExtractedLicences {
@@ -696,7 +913,10 @@ func Parse(lex lexer) (*spdx.Document, error) {
 		for i := range pkg.LicenceInfoFromFiles {
 			updateLicenceReferences(&pkg.LicenceInfoFromFiles[i], licenceMap)
 		}
+		for i, file := range pkg.Files {
+			if f := fileMap[file.Name.Val]; f != nil {
+				pkg.Files[i] = f
+			}
+		}
 	}
-
-	return doc, nil
 }
@@ -2,6 +2,7 @@ package tag
 
 import (
 	"github.com/vladvelici/spdx-go/spdx"
+	"strings"
 	"testing"
 )
 
@@ -1210,8 +1211,8 @@ func TestVerifCodeAlreadyDefined(t *testing.T) {
 func TestReviewer(t *testing.T) {
 
 	reviews := []spdx.Review{
-		{spdx.NewValueCreator("a", nil), spdx.NewValueDate("b", nil), spdx.Str("c", nil), nil},
-		{spdx.NewValueCreator("d", nil), spdx.NewValueDate("e", nil), spdx.Str("f", nil), nil},
+		{Reviewer: spdx.NewValueCreator("a", nil), Date: spdx.NewValueDate("b", nil), Comment: spdx.Str("c", nil)},
+		{Reviewer: spdx.NewValueCreator("d", nil), Date: spdx.NewValueDate("e", nil), Comment: spdx.Str("f", nil)},
 	}
 
 	input := make([]Pair, 0, 6)
@@ -1273,3 +1274,167 @@ func TestFileDependency(t *testing.T) {
 		t.Errorf("Wrong dependencies. 1) Expected: %+v but found %+v\n2)Expected: %+v but found %+v\n", doc.Files[1], file1.Dependency[0], doc.Files[2], file1.Dependency[1])
 	}
 }
+
+func TestParseRecover(t *testing.T) {
+	input := []Pair{
+		{"SPDXVersion", "SPDX-1.2"},
+		{"PackageVersion", "1.0"},
+		{"PackageName", "p"},
+		{"PackageLicenseConcluded", "(MIT and GPL-2.0 or Apache-2.0)"},
+		{"PackageLicenseConcluded", "LicenseRef-1"},
+		{"PackageLicenseConcluded", "MIT"},
+		{"FileName", "f"},
+		{"LicenseConcluded", "LicenseRef-1"},
+		{"LicenseID", "LicenseRef-1"},
+	}
+	doc, errs, err := ParseRecover(l(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 errors but found %d: %v", len(errs), errs)
+	}
+	if errs[1].Error() != MsgConjunctionAndDisjunction || errs[2].Error() != MsgAlreadyDefined {
+		t.Errorf("Wrong errors %v", errs)
+	}
+	if doc.SpecVersion.Val != "SPDX-1.2" || len(doc.Packages) != 1 || len(doc.Files) != 1 {
+		t.Fatalf("Wrong document %#v", doc)
+	}
+	if doc.Packages[0].LicenceConcluded.LicenceId() != "LicenseRef-1" {
+		t.Errorf("Wrong licence %v", doc.Packages[0].LicenceConcluded)
+	}
+	if doc.Files[0].LicenceConcluded != doc.ExtractedLicences[0] {
+		t.Error("References not resolved.")
+	}
+}
+
+func TestParseRecoverLexerErrors(t *testing.T) {
+	input := "SPDXVersion: SPDX-1.2\nDataLicense: CC0-1.0\nDataLicense: CC0-1.0\ninvalid\nDocumentComment: <text>a</text> b\nPackageName: p\n"
+	doc, errs, err := BuildRecover(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	lines := []int{3, 4, 5}
+	if len(errs) != len(lines) {
+		t.Fatalf("Expected %d errors but found %d: %v", len(lines), len(errs), errs)
+	}
+	for i, e := range errs {
+		if e.LineStart != lines[i] {
+			t.Errorf("Error %d (%s) at line %d, expected %d", i, e, e.LineStart, lines[i])
+		}
+	}
+	if doc.DataLicence.Val != "CC0-1.0" || doc.Comment.Val != "" || len(doc.Packages) != 1 {
+		t.Errorf("Wrong document %#v", doc)
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	Lenient(true)
+	defer Lenient(false)
+	input := []Pair{
+		{"SPDXVersion", "SPDX-1.2"},
+		{"VendorTool", "scanner 1.0"},
+		{"PackageVersion", "1.0"},
+		{"PackageName", "p"},
+		{"VendorPackageId", "42"},
+		{"FileName", "f"},
+		{"FileType", "SOURCE"},
+		{"VendorFileRisk", "low"},
+		{"LicenseID", "LicenseRef-1"},
+		{"FileNotice", "notice"},
+		{"Reviewer", "Person: Me"},
+		{"VendorReviewTool", "checker"},
+	}
+	doc, err := Parse(l(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+
+	exts := [][]spdx.Extension{
+		doc.Extensions,
+		doc.Packages[0].Extensions,
+		doc.Files[0].Extensions,
+		doc.ExtractedLicences[0].Extensions,
+		doc.Reviews[0].Extensions,
+	}
+	expected := [][]Pair{
+		{{"VendorTool", "scanner 1.0"}, {"PackageVersion", "1.0"}},
+		{{"VendorPackageId", "42"}},
+		{{"VendorFileRisk", "low"}},
+		{},
+		{{"VendorReviewTool", "checker"}},
+	}
+	for i := range exts {
+		if len(exts[i]) != len(expected[i]) {
+			t.Errorf("%d: Expected %v but found %v", i, expected[i], exts[i])
+			continue
+		}
+		for j, ext := range exts[i] {
+			if ext.Key != expected[i][j].Key || ext.Value != expected[i][j].Value {
+				t.Errorf("%d: Expected %v but found %v", i, expected[i], exts[i])
+			}
+		}
+	}
+	if doc.Files[0].Type.Val != "SOURCE" || doc.Files[0].Notice.Val != "notice" {
+		t.Errorf("Wrong file %#v", doc.Files[0])
+	}
+}
+
+func TestParseLenientErrors(t *testing.T) {
+	Lenient(true)
+	defer Lenient(false)
+	input := []Pair{
+		{"SPDXVersion", "SPDX-1.2"},
+		{"VendorTool", "scanner 1.0"},
+		{"SPDXVersion", "SPDX-1.2"},
+	}
+	_, err := Parse(l(input))
+	if err == nil || err.Error() != MsgAlreadyDefined {
+		t.Errorf("Expected error %q but found %v", MsgAlreadyDefined, err)
+	}
+}
+
+func TestParseRecoverLenient(t *testing.T) {
+	Lenient(true)
+	defer Lenient(false)
+	input := "SPDXVersion: SPDX-1.2\nVendorTool: scanner\nPackageName: p\nPackageName2: x\ninvalid\n"
+	doc, errs, err := BuildRecover(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if len(errs) != 1 || errs[0].LineStart != 5 {
+		t.Errorf("Expected one error at line 5 but found %v", errs)
+	}
+	if len(doc.Extensions) != 1 || doc.Extensions[0].LineStart != 2 {
+		t.Errorf("Wrong document extensions %v", doc.Extensions)
+	}
+	if len(doc.Packages) != 1 || len(doc.Packages[0].Extensions) != 1 || doc.Packages[0].Extensions[0].Key != "PackageName2" {
+		t.Errorf("Wrong package extensions %#v", doc.Packages)
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	input := "# header\nSPDXVersion: SPDX-1.2\nPackageName: p\n# legal\nPackageVersion: 1\n# creator\nCreator: Tool: x\n\n# file\nFileName: f\n# end\n"
+	doc, err := Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	comments := [][]spdx.TagComment{doc.TagComments, doc.Packages[0].TagComments, doc.Files[0].TagComments}
+	expected := [][]spdx.TagComment{
+		{{Text: " header", Before: "SPDXVersion", Meta: spdx.NewMetaL(1)}, {Text: " creator", Before: "Creator", Meta: spdx.NewMetaL(6)}, {Text: " end", Before: "", Meta: spdx.NewMetaL(11)}},
+		{{Text: " legal", Before: "PackageVersion", Meta: spdx.NewMetaL(4)}},
+		{{Text: " file", Before: "FileName", Meta: spdx.NewMetaL(9)}},
+	}
+	for i := range comments {
+		if len(comments[i]) != len(expected[i]) {
+			t.Errorf("%d: Expected %v but found %v", i, expected[i], comments[i])
+			continue
+		}
+		for j, c := range comments[i] {
+			e := expected[i][j]
+			if c.Text != e.Text || c.Before != e.Before || c.LineStart != e.LineStart {
+				t.Errorf("%d: Expected %v but found %v", i, e, c)
+			}
+		}
+	}
+}
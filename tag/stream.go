@@ -0,0 +1,274 @@
+package tag
+
+import "github.com/spdx/tools-go/spdx"
+
+import (
+	"io"
+	"sort"
+)
+
+// The properties which start a new section (element) of a Tag document.
+var sectionStart = map[string]bool{
+	"PackageName": true,
+	"FileName":    true,
+	"LicenseID":   true,
+	"Reviewer":    true,
+}
+
+// The callbacks of a Stream. Nil callbacks are not called. If a callback
+// returns an error, the parsing stops and the error is returned.
+type Handler struct {
+	// Called with the document properties (SPDXVersion, CreationInfo, ...)
+	// before the first package, file, licence or review. The document has no
+	// elements; properties found later in the input still update it.
+	Document func(*spdx.Document) error
+
+	Package          func(*spdx.Package) error
+	File             func(*spdx.File) error
+	ExtractedLicence func(*spdx.ExtractedLicence) error
+	Review           func(*spdx.Review) error
+}
+
+// An event-based Tag parser, which calls the Handler callbacks with every
+// element as soon as its section ends, instead of building the whole
+// spdx.Document in memory.
+//
+// The properties of an element must be in its section, before the next
+// package, file, licence or review; document properties may be anywhere.
+//
+// References are resolved as in Parse(), lazily for forward references: a
+// licence reference (LicenseRef-...) or a file dependency to an element not
+// parsed yet is a *spdx.ExtractedLicence or a *spdx.File which only has the
+// ID or name, and which is filled in place when the element is parsed. The
+// pointer passed to the ExtractedLicence or File callback is then the same.
+// References never defined in the document are listed by Unresolved().
+//
+// To resolve file dependencies to files parsed earlier, the Stream has to
+// keep the files; set IndexFiles to do so. Otherwise they are files which only
+// have the name.
+//
+// In lenient mode (see Lenient()), unknown properties and properties found
+// outside their section are kept in the Extensions of the current element.
+type Stream struct {
+	Handler
+	IndexFiles bool // Keep an index of all the files, by name
+
+	lex      lexer
+	doc      *spdx.Document
+	mapping  *updaterMapping
+	base     map[string]bool // properties of the document mapping
+	current  interface{}     // element of the current section, if any
+	docSent  bool
+	licences map[string]*spdx.ExtractedLicence
+	files    map[string]*spdx.File
+	defined  map[string]bool      // names of the files parsed
+	pending  map[interface{}]bool // forward references not defined yet
+}
+
+// Create a new Stream parsing the tokens given by `lex`.
+func NewStream(lex lexer, h Handler) *Stream {
+	s := &Stream{
+		Handler:  h,
+		lex:      lex,
+		doc:      new(spdx.Document),
+		base:     make(map[string]bool),
+		licences: make(map[string]*spdx.ExtractedLicence),
+		files:    make(map[string]*spdx.File),
+		defined:  make(map[string]bool),
+		pending:  make(map[interface{}]bool),
+	}
+	s.mapping = documentMap(s.doc)
+	for key := range *s.mapping {
+		s.base[key] = true
+	}
+	return s
+}
+
+// Lex a io.Reader with the lexer settings of Build() and parse it with a
+// Stream calling the callbacks of `h`. Returns the references never defined
+// in the document (see Stream.Unresolved()).
+func BuildStream(f io.Reader, h Handler) (unresolved []string, err error) {
+	lexer := NewLexer(f)
+	lexer.IgnoreComments = true
+	lexer.IgnoreMeta = noMeta
+	lexer.CaseSensitive = caseSensitive
+	s := NewStream(lexer, h)
+	if err := s.Parse(); err != nil {
+		return nil, err
+	}
+	return s.Unresolved(), nil
+}
+
+// Parse all the tokens of the lexer and call the callbacks. Errors returned
+// are the same as in Parse() or the errors returned by the callbacks.
+func (s *Stream) Parse() error {
+	loop := &tokenLoop{
+		doc:        s.doc,
+		mapping:    s.mapping,
+		extensions: s.extensions,
+		before: func(tok *Token) error {
+			if sectionStart[tok.Key] {
+				return s.endSection()
+			}
+			return nil
+		},
+		after:  func(*Token, bool) { s.takeElement() },
+		failed: func(_ *Token, err error) error { return err },
+	}
+	if err := loop.run(s.lex); err != nil {
+		return err
+	}
+	return s.endSection()
+}
+
+// Returns the IDs of the licences and the names of the files which are
+// referenced but were not defined in the parsed input.
+func (s *Stream) Unresolved() []string {
+	var res []string
+	for ref := range s.pending {
+		switch r := ref.(type) {
+		case *spdx.ExtractedLicence:
+			res = append(res, r.Id.Val)
+		case *spdx.File:
+			res = append(res, r.Name.Val)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Returns the extensions of the current element, or of the document if there
+// is none.
+func (s *Stream) extensions() *[]spdx.Extension {
+	if s.current == nil {
+		return &s.doc.Extensions
+	}
+	return extensions(s.current)
+}
+
+// Moves the element just created by the document mapping, if any, to
+// s.current.
+func (s *Stream) takeElement() {
+	switch {
+	case len(s.doc.Packages) > 0:
+		s.current = s.doc.Packages[0]
+		s.doc.Packages = nil
+	case len(s.doc.Files) > 0:
+		s.current = s.doc.Files[0]
+		s.doc.Files = nil
+	case len(s.doc.ExtractedLicences) > 0:
+		s.current = s.doc.ExtractedLicences[0]
+		s.doc.ExtractedLicences = nil
+	case len(s.doc.Reviews) > 0:
+		s.current = s.doc.Reviews[0]
+		s.doc.Reviews = nil
+	}
+}
+
+// Ends the current section: removes the properties of the section from the
+// mapping and calls the callback of the element.
+func (s *Stream) endSection() error {
+	for key := range *s.mapping {
+		if !s.base[key] {
+			delete(*s.mapping, key)
+		}
+	}
+
+	if !s.docSent {
+		s.docSent = true
+		if s.Document != nil {
+			if err := s.Document(s.doc); err != nil {
+				return err
+			}
+		}
+	}
+
+	current := s.current
+	s.current = nil
+	switch elem := current.(type) {
+	case *spdx.Package:
+		s.resolveLicence(&elem.LicenceConcluded)
+		s.resolveLicence(&elem.LicenceDeclared)
+		for i := range elem.LicenceInfoFromFiles {
+			s.resolveLicence(&elem.LicenceInfoFromFiles[i])
+		}
+		if s.Package != nil {
+			return s.Package(elem)
+		}
+	case *spdx.File:
+		s.resolveLicence(&elem.LicenceConcluded)
+		for i := range elem.LicenceInfoInFile {
+			s.resolveLicence(&elem.LicenceInfoInFile[i])
+		}
+		for i, dep := range elem.Dependency {
+			elem.Dependency[i] = s.file(dep)
+		}
+		s.defined[elem.Name.Val] = true
+		if ref, ok := s.files[elem.Name.Val]; ok && s.pending[ref] {
+			*ref = *elem
+			elem = ref
+			delete(s.pending, ref)
+		} else if s.IndexFiles {
+			s.files[elem.Name.Val] = elem
+		}
+		if s.File != nil {
+			return s.File(elem)
+		}
+	case *spdx.ExtractedLicence:
+		if ref, ok := s.licences[elem.LicenceId()]; ok && s.pending[ref] {
+			*ref = *elem
+			elem = ref
+			delete(s.pending, ref)
+		} else {
+			s.licences[elem.LicenceId()] = elem
+		}
+		if s.ExtractedLicence != nil {
+			return s.ExtractedLicence(elem)
+		}
+	case *spdx.Review:
+		if s.Review != nil {
+			return s.Review(elem)
+		}
+	}
+	return nil
+}
+
+// Returns the file the dependency `dep` (a file which only has the name)
+// refers to: the indexed file or `dep`, which is a forward reference if the
+// file was not parsed yet.
+func (s *Stream) file(dep *spdx.File) *spdx.File {
+	if f, ok := s.files[dep.Name.Val]; ok {
+		return f
+	}
+	if !s.defined[dep.Name.Val] {
+		s.files[dep.Name.Val] = dep
+		s.pending[dep] = true
+	}
+	return dep
+}
+
+// Replaces the licence references in `lic` by the *spdx.ExtractedLicence they
+// refer to, which is a forward reference if the licence was not parsed yet.
+func (s *Stream) resolveLicence(lic *spdx.AnyLicence) {
+	switch t := (*lic).(type) {
+	case spdx.Licence:
+		if !t.IsReference() {
+			return
+		}
+		ref, ok := s.licences[t.LicenceId()]
+		if !ok {
+			ref = &spdx.ExtractedLicence{Id: spdx.Str(t.LicenceId(), t.Meta)}
+			s.licences[t.LicenceId()] = ref
+			s.pending[ref] = true
+		}
+		*lic = ref
+	case spdx.DisjunctiveLicenceSet:
+		for i := range t.Members {
+			s.resolveLicence(&t.Members[i])
+		}
+	case spdx.ConjunctiveLicenceSet:
+		for i := range t.Members {
+			s.resolveLicence(&t.Members[i])
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package tag
+
+import (
+	"errors"
+	"github.com/vladvelici/spdx-go/spdx"
+	"strings"
+	"testing"
+)
+
+// Returns a Handler which adds the elements to `doc`.
+func collect(doc *spdx.Document) Handler {
+	return Handler{
+		Document: func(d *spdx.Document) error { *doc = *d; return nil },
+		Package:  func(pkg *spdx.Package) error { doc.Packages = append(doc.Packages, pkg); return nil },
+		File:     func(file *spdx.File) error { doc.Files = append(doc.Files, file); return nil },
+		ExtractedLicence: func(lic *spdx.ExtractedLicence) error {
+			doc.ExtractedLicences = append(doc.ExtractedLicences, lic)
+			return nil
+		},
+		Review: func(rev *spdx.Review) error { doc.Reviews = append(doc.Reviews, rev); return nil },
+	}
+}
+
+func TestStreamDocument(t *testing.T) {
+	input := getDocumentString()
+	for _, id := range []string{"1", "2", "3", "4"} {
+		input += "\n\nLicenseID: LicenseRef-" + id + "\nExtractedText: <text>Licence " + id + "</text>"
+	}
+	expected, err := Build(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	doc := new(spdx.Document)
+	unresolved, err := BuildStream(strings.NewReader(input), collect(doc))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !doc.Equal(expected) {
+		t.Error("Documents are not the same.")
+	}
+	if doc.Meta == nil || doc.Meta.LineStart != 1 {
+		t.Errorf("Wrong document meta %#v", doc.Meta)
+	}
+	if len(unresolved) != 0 {
+		t.Errorf("Wrong unresolved references %v", unresolved)
+	}
+}
+
+func TestStreamOrder(t *testing.T) {
+	input := []Pair{
+		{"SPDXVersion", "SPDX-1.2"},
+		{"FileName", "a"},
+		{"Reviewer", "Person: Joe"},
+		{"PackageName", "p"},
+		{"LicenseID", "LicenseRef-1"},
+		{"DocumentComment", "Comment"},
+	}
+	var events []string
+	var doc *spdx.Document
+	err := NewStream(l(input), Handler{
+		Document: func(d *spdx.Document) error {
+			doc = d
+			events = append(events, "document "+d.SpecVersion.Val)
+			return nil
+		},
+		Package: func(pkg *spdx.Package) error { events = append(events, "package "+pkg.Name.Val); return nil },
+		File:    func(file *spdx.File) error { events = append(events, "file "+file.Name.Val); return nil },
+		ExtractedLicence: func(lic *spdx.ExtractedLicence) error {
+			events = append(events, "licence "+lic.LicenceId())
+			return nil
+		},
+		Review: func(rev *spdx.Review) error { events = append(events, "review "+rev.Reviewer.V()); return nil },
+	}).Parse()
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := []string{"document SPDX-1.2", "file a", "review Person: Joe", "package p", "licence LicenseRef-1"}
+	if !sameStrSlice(events, expected) {
+		t.Errorf("Wrong events %v", events)
+	}
+	if doc.Comment.Val != "Comment" || doc.Files != nil || doc.Packages != nil || doc.Reviews != nil || doc.ExtractedLicences != nil {
+		t.Errorf("Wrong document %#v", doc)
+	}
+}
+
+func TestStreamForwardReferences(t *testing.T) {
+	input := []Pair{
+		{"FileName", "a"},
+		{"LicenseConcluded", "(LicenseRef-1 and MIT)"},
+		{"FileDependency", "b"},
+		{"FileName", "b"},
+		{"LicenseInfoInFile", "LicenseRef-1"},
+		{"FileDependency", "a"},
+		{"LicenseID", "LicenseRef-1"},
+		{"ExtractedText", "Text"},
+		{"PackageName", "p"},
+		{"PackageLicenseDeclared", "LicenseRef-1"},
+	}
+
+	for _, index := range []bool{false, true} {
+		doc := new(spdx.Document)
+		s := NewStream(l(input), collect(doc))
+		s.IndexFiles = index
+		var text string
+		s.File = func(file *spdx.File) error {
+			if file.Name.Val == "a" {
+				text = file.LicenceConcluded.(spdx.ConjunctiveLicenceSet).Members[0].(*spdx.ExtractedLicence).Text.Val
+			}
+			doc.Files = append(doc.Files, file)
+			return nil
+		}
+		if err := s.Parse(); err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+		if text != "" {
+			t.Errorf("Licence text known before the licence: %s", text)
+		}
+
+		a, b, lic := doc.Files[0], doc.Files[1], doc.ExtractedLicences[0]
+		if ref := a.LicenceConcluded.(spdx.ConjunctiveLicenceSet).Members[0]; ref != lic || lic.Text.Val != "Text" {
+			t.Errorf("Forward licence reference not resolved: %#v", ref)
+		}
+		if b.LicenceInfoInFile[0] != lic || doc.Packages[0].LicenceDeclared != lic {
+			t.Error("Licence reference not resolved.")
+		}
+		if a.Dependency[0] != b || b.LicenceInfoInFile == nil {
+			t.Errorf("Forward file reference not resolved: %#v", a.Dependency[0])
+		}
+		if dep := b.Dependency[0]; (dep == a) != index || dep.Name.Val != "a" {
+			t.Errorf("Wrong backward file reference (index %v): %#v", index, dep)
+		}
+		if unresolved := s.Unresolved(); len(unresolved) != 0 {
+			t.Errorf("Wrong unresolved references %v", unresolved)
+		}
+	}
+}
+
+func TestStreamUnresolved(t *testing.T) {
+	input := []Pair{
+		{"FileName", "a"},
+		{"LicenseConcluded", "LicenseRef-2"},
+		{"FileDependency", "missing"},
+		{"FileDependency", "a"},
+		{"LicenseID", "LicenseRef-1"},
+	}
+	doc := new(spdx.Document)
+	s := NewStream(l(input), collect(doc))
+	if err := s.Parse(); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if unresolved := s.Unresolved(); !sameStrSlice(unresolved, []string{"LicenseRef-2", "missing"}) {
+		t.Errorf("Wrong unresolved references %v", unresolved)
+	}
+	if a := doc.Files[0]; a.Dependency[1] != a {
+		t.Error("File dependency on itself not resolved.")
+	}
+}
+
+func TestStreamPropertyOutsideSection(t *testing.T) {
+	input := []Pair{
+		{"FileName", "a"},
+		{"LicenseID", "LicenseRef-1"},
+		{"FileType", "SOURCE"},
+	}
+	if err := NewStream(l(input), Handler{}).Parse(); err == nil {
+		t.Error("No error for a property outside its section.")
+	}
+}
+
+func TestStreamCallbackError(t *testing.T) {
+	input := []Pair{
+		{"FileName", "a"},
+		{"FileName", "b"},
+		{"FileName", "c"},
+	}
+	stop := errors.New("stop")
+	var files []string
+	err := NewStream(l(input), Handler{File: func(file *spdx.File) error {
+		files = append(files, file.Name.Val)
+		return stop
+	}}).Parse()
+	if err != stop || !sameStrSlice(files, []string{"a"}) {
+		t.Errorf("Wrong error %v or files %v", err, files)
+	}
+}
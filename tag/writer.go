@@ -5,11 +5,17 @@ import "github.com/spdx/tools-go/spdx"
 import (
 	"errors"
 	"io"
+	"regexp"
+	"sort"
+	"strings"
 	"unicode"
 )
 
 const commentLastWritten = "__comment"
 
+// Extension keys which can be written as Tag properties.
+var extensionKeyRegex = regexp.MustCompile(`^[^\s:#][^\s:]*$`)
+
 // spdx.Checksum representation as Tag string
 func cksumStr(cksum *spdx.Checksum) string {
 	if cksum == nil || (cksum.Algo.Val == "" && cksum.Value.Val == "") {
@@ -50,26 +56,45 @@ func fileInList(file *spdx.File, list []*spdx.File) bool {
 	return false
 }
 
+// Blank line policies of the Formatter.
+const (
+	BlankDefault  = iota // before sections and before comments following a property
+	BlankSections        // only before sections and the comments preceding them
+	BlankNone            // no blank lines
+)
+
 // Formatter is the pretty-printer for Tag format. It is aware of what has been
 // printed previously in order to leave nice newlines.
+//
+// The zero values of the options keep the properties in the order given and
+// unchanged.
 type Formatter struct {
+	Sort          bool // Write the files sorted by name and the extracted licences by ID
+	Align         bool // Align the values of all the properties in one column
+	Blank         int  // Blank line policy (BlankDefault, BlankSections or BlankNone)
+	TextOver      int  // Write values longer than TextOver in <text>; 0 never does
+	NormaliseKeys bool // Write the property names in the case of the specification
+
 	lastWritten string
 	out         io.Writer
+	comments    []spdx.TagComment // comments of the element being written
+	held        []string          // comments held back by BlankSections
+	width       int               // width of the property names with Align, once computed
 }
 
 // Create a new *Formatter that writes to f.
 func NewFormatter(f io.Writer) *Formatter {
-	return &Formatter{"", f}
+	return &Formatter{out: f}
 }
 
 // Print newlines where appropriate.
 //
 // Currently when:
-// - a property is followed by a comment
+// - a property is followed by a comment (BlankDefault only)
 // - printing one of these properties: FileName, LicenseID, PackageName,
 //   Reviewer, ArtifactOfProjectName
 func (f *Formatter) spaces(now string) {
-	if f.lastWritten == "" || f.lastWritten == commentLastWritten {
+	if f.lastWritten == "" || f.lastWritten == commentLastWritten || f.Blank == BlankNone {
 		return
 	}
 
@@ -82,20 +107,111 @@ func (f *Formatter) spaces(now string) {
 		}
 	}
 
-	if now == commentLastWritten {
+	if now == commentLastWritten && f.Blank == BlankDefault {
 		f.out.Write([]byte{'\n'})
 	}
 }
 
-// Read all tokens from a lexer and pretty-print them.
+// Read all tokens from a lexer and pretty-print them. With Sort, the file and
+// extracted licence sections are sorted, along with the comments preceding
+// them; the other sections keep their positions.
 func (f *Formatter) Lexer(lex lexer) error {
+	if f.Sort {
+		return f.sortedLexer(lex)
+	}
 	for lex.Lex() {
 		err := f.Token(lex.Token())
 		if err != nil {
 			return err
 		}
 	}
-	return lex.Err()
+	if lex.Err() != nil {
+		return lex.Err()
+	}
+	return f.Flush()
+}
+
+// Read all tokens from a lexer, split them in sections, sort the FileName and
+// LicenseID sections and pretty-print them. Comments belong to the section of
+// the following property.
+func (f *Formatter) sortedLexer(lex lexer) error {
+	var sections [][]*Token
+	var current, comments []*Token
+	for lex.Lex() {
+		tok := *lex.Token()
+		if tok.Type == TokenComment {
+			comments = append(comments, &tok)
+			continue
+		}
+		if tok.Type == TokenPair && sectionStart[tok.Key] {
+			sections = append(sections, current)
+			current = nil
+		}
+		current = append(append(current, comments...), &tok)
+		comments = nil
+	}
+	if lex.Err() != nil {
+		return lex.Err()
+	}
+	// the comments after the last property stay at the end, as the comments
+	// of the document
+	sections = append(sections, current, comments)
+
+	sortSections(sections, "FileName")
+	sortSections(sections, "LicenseID")
+
+	for _, section := range sections {
+		for _, tok := range section {
+			if err := f.Token(tok); err != nil {
+				return err
+			}
+		}
+	}
+	return f.Flush()
+}
+
+// Returns the first pair of a section, or nil if it has none.
+func sectionPair(section []*Token) *Pair {
+	for _, tok := range section {
+		if tok.Type == TokenPair {
+			return &tok.Pair
+		}
+	}
+	return nil
+}
+
+// Sorts by value, in place, the sections starting with the property `key`.
+// Other sections are not moved.
+func sortSections(sections [][]*Token, key string) {
+	var idx []int
+	var sorted [][]*Token
+	for i, section := range sections {
+		if p := sectionPair(section); p != nil && p.Key == key {
+			idx = append(idx, i)
+			sorted = append(sorted, section)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sectionPair(sorted[i]).Value < sectionPair(sorted[j]).Value
+	})
+	for i, section := range sorted {
+		sections[idx[i]] = section
+	}
+}
+
+// Write the comments held back by the BlankSections policy, which are
+// otherwise written before the next property. Lexer() and Document() flush
+// when done.
+func (f *Formatter) Flush() error {
+	held := f.held
+	f.held = nil
+	for _, comment := range held {
+		f.lastWritten = commentLastWritten
+		if _, err := io.WriteString(f.out, comment); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Write a Token.
@@ -113,36 +229,114 @@ func (f *Formatter) Token(tok *Token) error {
 	}
 }
 
-// Write a comment (# comment).
+// Write a comment (# comment). With BlankSections, the comment is held back
+// until the next property or Flush().
 func (f *Formatter) Comment(comment string) error {
-	f.spaces(commentLastWritten)
-
 	hashes := countLeft(comment, '#')
 	if hashes == len(comment) || (hashes < len(comment) && !unicode.IsSpace(rune(comment[hashes]))) {
 		comment = comment[:hashes] + " " + comment[hashes:]
 	}
 
+	if f.Blank == BlankSections {
+		f.held = append(f.held, "#"+comment+"\n")
+		return nil
+	}
+
+	f.spaces(commentLastWritten)
 	f.lastWritten = commentLastWritten
 	_, err := io.WriteString(f.out, "#"+comment+"\n")
 	return err
 }
 
-// Write a property (tag: value).
+// Write a property (tag: value). The comments of the element being written
+// which precede `tag` are written before it.
 func (f *Formatter) Property(tag, value string) error {
 	if value == "" {
 		return nil
 	}
 
+	if f.NormaliseKeys {
+		if ok, correct := IsValidPropertyInsensitive(tag); ok {
+			tag = correct
+		}
+	}
+
+	if err := f.commentsBefore(tag); err != nil {
+		return err
+	}
+
 	f.spaces(tag)
-	if value != spdx.NOASSERTION && value != spdx.NONE && (isMultiline(tag) || isMultilineValue(value)) {
+	if err := f.Flush(); err != nil {
+		return err
+	}
+
+	if value != spdx.NOASSERTION && value != spdx.NONE && (isMultiline(tag) || isMultilineValue(value) || (f.TextOver > 0 && len(value) > f.TextOver)) {
 		value = "<text>" + value + "</text>"
 	}
 
+	key := tag + ":"
+	if f.Align {
+		if pad := f.keyWidth() + 1 - len(key); pad > 0 {
+			key += strings.Repeat(" ", pad)
+		}
+	}
+
 	f.lastWritten = tag
-	_, err := io.WriteString(f.out, tag+": "+value+"\n")
+	_, err := io.WriteString(f.out, key+" "+value+"\n")
 	return err
 }
 
+// Returns the length of the longest property name of the specification. It
+// is computed on the first call.
+func (f *Formatter) keyWidth() int {
+	if f.width == 0 {
+		initProperties()
+		for prop := range properties {
+			if len(prop) > f.width {
+				f.width = len(prop)
+			}
+		}
+	}
+	return f.width
+}
+
+// Write the pending comments of the element being written which precede the
+// property `tag`.
+func (f *Formatter) commentsBefore(tag string) error {
+	rest := f.comments[:0]
+	for _, c := range f.comments {
+		if !strings.EqualFold(c.Before, tag) {
+			rest = append(rest, c)
+		} else if err := f.Comment(c.Text); err != nil {
+			return err
+		}
+	}
+	f.comments = rest
+	return nil
+}
+
+// Start writing an element with the given comments. The comments of the
+// previous element not written yet are written first.
+func (f *Formatter) startComments(comments []spdx.TagComment) error {
+	if err := f.endComments(); err != nil {
+		return err
+	}
+	f.comments = append([]spdx.TagComment(nil), comments...)
+	return nil
+}
+
+// Write the pending comments of the element being written, if any.
+func (f *Formatter) endComments() error {
+	comments := f.comments
+	f.comments = nil
+	for _, c := range comments {
+		if err := f.Comment(c.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Write a list of properties.
 func (f *Formatter) Properties(props []Pair) error {
 	for _, p := range props {
@@ -186,12 +380,44 @@ func (f *Formatter) PropertyLicenceSlice(tag string, values []spdx.AnyLicence) e
 	return nil
 }
 
-// Write `doc` incuding all its nested elements.
+// Write the extensions `exts` (properties not defined by the specification).
+// Extensions which are not Tag properties, such as RDF predicate URIs or SPDX
+// JSON members, are written as comments.
+func (f *Formatter) Extensions(exts []spdx.Extension) error {
+	for _, ext := range exts {
+		var err error
+		if !ext.Json && extensionKeyRegex.MatchString(ext.Key) {
+			err = f.Property(ext.Key, ext.Value)
+		} else if ext.Value != "" {
+			err = f.Comment(" " + ext.Key + ": " + strings.Replace(ext.Value, "\n", "\n# ", -1))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Write `doc` incuding all its nested elements. The Tag comments of the
+// elements are written before the properties they precede.
 func (f *Formatter) Document(doc *spdx.Document) error {
 	if doc == nil {
 		return nil
 	}
 
+	// the comments at the end of the document are written last
+	var start, end []spdx.TagComment
+	for _, c := range doc.TagComments {
+		if c.Before == "" {
+			end = append(end, c)
+		} else {
+			start = append(start, c)
+		}
+	}
+	if err := f.startComments(start); err != nil {
+		return err
+	}
+
 	err := f.Properties([]Pair{
 		{"SPDXVersion", doc.SpecVersion.Val},
 		{"DataLicense", doc.DataLicence.Val},
@@ -206,13 +432,21 @@ func (f *Formatter) Document(doc *spdx.Document) error {
 		return err
 	}
 
+	if err = f.Extensions(doc.Extensions); err != nil {
+		return err
+	}
+
+	if err = f.endComments(); err != nil {
+		return err
+	}
+
 	if err = f.Packages(doc.Packages); err != nil {
 		return err
 	}
 
-	files := doc.Files
+	// the document files and the package files not in the document
+	files := append([]*spdx.File(nil), doc.Files...)
 	for _, pkg := range doc.Packages {
-		// add all files that are not there yet
 		for _, file := range pkg.Files {
 			if !fileInList(file, files) {
 				files = append(files, file)
@@ -220,7 +454,18 @@ func (f *Formatter) Document(doc *spdx.Document) error {
 		}
 	}
 
-	if err = f.Files(doc.Files); err != nil {
+	licences := doc.ExtractedLicences
+	if f.Sort {
+		sort.SliceStable(files, func(i, j int) bool {
+			return files[i].Name.Val < files[j].Name.Val
+		})
+		licences = append([]*spdx.ExtractedLicence(nil), licences...)
+		sort.SliceStable(licences, func(i, j int) bool {
+			return licences[i].Id.Val < licences[j].Id.Val
+		})
+	}
+
+	if err = f.Files(files); err != nil {
 		return err
 	}
 
@@ -228,7 +473,17 @@ func (f *Formatter) Document(doc *spdx.Document) error {
 		return err
 	}
 
-	return f.ExtractedLicences(doc.ExtractedLicences)
+	if err = f.ExtractedLicences(licences); err != nil {
+		return err
+	}
+
+	if err = f.startComments(end); err != nil {
+		return err
+	}
+	if err = f.endComments(); err != nil {
+		return err
+	}
+	return f.Flush()
 }
 
 // Write `ci` the creation info part of a document.
@@ -241,11 +496,16 @@ func (f *Formatter) CreationInfo(ci *spdx.CreationInfo) error {
 		return err
 	}
 
-	return f.Properties([]Pair{
+	err := f.Properties([]Pair{
 		{"Created", ci.Created.V()},
 		{"CreatorComment", ci.Comment.Val},
 		{"LicenseListVersion", ci.LicenceListVersion.Val},
 	})
+	if err != nil {
+		return err
+	}
+
+	return f.Extensions(ci.Extensions)
 }
 
 // Write all the Packages in pkgs.
@@ -264,6 +524,10 @@ func (f *Formatter) Package(pkg *spdx.Package) error {
 		return nil
 	}
 
+	if err := f.startComments(pkg.TagComments); err != nil {
+		return err
+	}
+
 	err := f.Properties([]Pair{
 		{"PackageName", pkg.Name.Val},
 		{"PackageVersion", pkg.Version.Val},
@@ -294,12 +558,20 @@ func (f *Formatter) Package(pkg *spdx.Package) error {
 		return err
 	}
 
-	return f.Properties([]Pair{
+	err = f.Properties([]Pair{
 		{"PackageLicenseComments", pkg.LicenceComments.Val},
 		{"PackageCopyrightText", pkg.CopyrightText.Val},
 		{"PackageSummary", pkg.Summary.Val},
 		{"PackageDescription", pkg.Description.Val},
 	})
+	if err != nil {
+		return err
+	}
+
+	if err = f.Extensions(pkg.Extensions); err != nil {
+		return err
+	}
+	return f.endComments()
 }
 
 // Write all elements in `files`.
@@ -317,6 +589,9 @@ func (f *Formatter) File(file *spdx.File) error {
 	if file == nil {
 		return nil
 	}
+	if err := f.startComments(file.TagComments); err != nil {
+		return err
+	}
 	err := f.Properties([]Pair{
 		{"FileName", file.Name.Val},
 		{"FileType", file.Type.Val},
@@ -357,7 +632,10 @@ func (f *Formatter) File(file *spdx.File) error {
 		}
 	}
 
-	return nil
+	if err = f.Extensions(file.Extensions); err != nil {
+		return err
+	}
+	return f.endComments()
 }
 
 // Write all the reviews in `reviews`.
@@ -376,11 +654,23 @@ func (f *Formatter) Review(review *spdx.Review) error {
 		return nil
 	}
 
-	return f.Properties([]Pair{
+	if err := f.startComments(review.TagComments); err != nil {
+		return err
+	}
+
+	err := f.Properties([]Pair{
 		{"Reviewer", review.Reviewer.V()},
 		{"ReviewDate", review.Date.V()},
 		{"ReviewComment", review.Comment.V()},
 	})
+	if err != nil {
+		return err
+	}
+
+	if err = f.Extensions(review.Extensions); err != nil {
+		return err
+	}
+	return f.endComments()
 }
 
 // Write all licences in `lics`.
@@ -398,6 +688,9 @@ func (f *Formatter) ExtractedLicence(lic *spdx.ExtractedLicence) error {
 	if lic == nil {
 		return nil
 	}
+	if err := f.startComments(lic.TagComments); err != nil {
+		return err
+	}
 	err := f.Properties([]Pair{
 		{"LicenseID", lic.Id.Val},
 		{"ExtractedText", lic.Text.Val},
@@ -411,5 +704,11 @@ func (f *Formatter) ExtractedLicence(lic *spdx.ExtractedLicence) error {
 	if err = f.PropertySlice("LicenseCrossReference", lic.CrossReference); err != nil {
 		return err
 	}
-	return f.Property("LicenseComment", lic.Comment.Val)
+	if err = f.Property("LicenseComment", lic.Comment.Val); err != nil {
+		return err
+	}
+	if err = f.Extensions(lic.Extensions); err != nil {
+		return err
+	}
+	return f.endComments()
 }
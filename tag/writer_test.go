@@ -3,6 +3,7 @@ package tag
 import (
 	"bytes"
 	"github.com/vladvelici/spdx-go/spdx"
+	"strings"
 	"testing"
 )
 
@@ -157,3 +158,180 @@ func TestProperty(t *testing.T) {
 		buf.Reset()
 	}
 }
+
+func TestExtensions(t *testing.T) {
+	buf := new(bytes.Buffer)
+	f := NewFormatter(buf)
+
+	exts := []spdx.Extension{
+		{Key: "VendorTool", Value: "scanner"},
+		{Key: "VendorNote", Value: "a\nb"},
+		{Key: "http://example.org/vendor#risk", Value: "low\nmedium"},
+		{Key: "VendorEmpty"},
+	}
+	expected := "VendorTool: scanner\nVendorNote: <text>a\nb</text>\n\n# http://example.org/vendor#risk: low\n# medium\n"
+	if err := f.Extensions(exts); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if res := buf.String(); res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+}
+
+func TestWriteExtensionsRoundTrip(t *testing.T) {
+	Lenient(true)
+	defer Lenient(false)
+	input := "SPDXVersion: SPDX-1.2\nVendorTool: scanner\n\nPackageName: p\nVendorPackageId: 42\n\nFileName: f\nVendorFileRisk: low\n"
+	doc, err := Build(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := Write(buf, doc); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if res := buf.String(); res != input {
+		t.Errorf("Printed %#v but expected %#v", res, input)
+	}
+}
+
+func TestWriteCommentsRoundTrip(t *testing.T) {
+	input := "# header\nSPDXVersion: SPDX-1.2\n\n# who\nCreator: Tool: x\n\n# the package\nPackageName: p\n\n# legal\nPackageLicenseConcluded: MIT\n\n# first file\nFileName: f\n\n# end\n"
+	doc, err := Build(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := Write(buf, doc); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if res := buf.String(); res != input {
+		t.Errorf("Printed %#v but expected %#v", res, input)
+	}
+}
+
+func TestWriteCommentsEmptyProperty(t *testing.T) {
+	buf := new(bytes.Buffer)
+	pkg := &spdx.Package{
+		Name:        spdx.Str("p", nil),
+		TagComments: []spdx.TagComment{{Text: " no version", Before: "PackageVersion"}},
+	}
+	if err := NewFormatter(buf).Package(pkg); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := "PackageName: p\n\n# no version\n"
+	if res := buf.String(); res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+}
+
+const unsortedInput = "SPDXVersion: SPDX-1.2\n\nFileName: b.c\n\n# first\nFileName: a.c\n\nLicenseID: LicenseRef-2\n\nLicenseID: LicenseRef-1\n"
+
+func TestFormatterSortLexer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	f := NewFormatter(buf)
+	f.Sort = true
+	if err := f.Lexer(NewLexer(bytes.NewBufferString(unsortedInput))); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := "SPDXVersion: SPDX-1.2\n\n# first\nFileName: a.c\n\nFileName: b.c\n\nLicenseID: LicenseRef-1\n\nLicenseID: LicenseRef-2\n"
+	if res := buf.String(); res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+}
+
+func TestFormatterSortLexerComments(t *testing.T) {
+	input := "SPDXVersion: SPDX-1.2\n\n# about z\nFileName: z.c\n\nFileName: a.c\nFileType: SOURCE\n# trailing end\n"
+	buf := new(bytes.Buffer)
+	f := NewFormatter(buf)
+	f.Sort = true
+	if err := f.Lexer(NewLexer(bytes.NewBufferString(input))); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	doc, err := Build(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := new(bytes.Buffer)
+	f = NewFormatter(expected)
+	f.Sort = true
+	if err := f.Document(doc); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if res := buf.String(); res != expected.String() || !strings.HasSuffix(res, "# trailing end\n") {
+		t.Errorf("Printed %#v but expected %#v", res, expected.String())
+	}
+}
+
+func TestFormatterSortDocument(t *testing.T) {
+	doc := &spdx.Document{
+		SpecVersion: spdx.Str("SPDX-1.2", nil),
+		Files:       []*spdx.File{{Name: spdx.Str("b.c", nil)}, {Name: spdx.Str("a.c", nil)}},
+		ExtractedLicences: []*spdx.ExtractedLicence{
+			{Id: spdx.Str("LicenseRef-2", nil)},
+			{Id: spdx.Str("LicenseRef-1", nil)},
+		},
+	}
+	buf := new(bytes.Buffer)
+	f := NewFormatter(buf)
+	f.Sort = true
+	if err := f.Document(doc); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expected := "SPDXVersion: SPDX-1.2\n\nFileName: a.c\n\nFileName: b.c\n\nLicenseID: LicenseRef-1\n\nLicenseID: LicenseRef-2\n"
+	if res := buf.String(); res != expected {
+		t.Errorf("Printed %#v but expected %#v", res, expected)
+	}
+	if doc.Files[0].Name.Val != "b.c" || doc.ExtractedLicences[0].Id.Val != "LicenseRef-2" {
+		t.Error("The document was modified.")
+	}
+}
+
+func TestFormatterPackageFiles(t *testing.T) {
+	file := &spdx.File{Name: spdx.Str("a.c", nil)}
+	doc := &spdx.Document{
+		SpecVersion: spdx.Str("SPDX-1.2", nil),
+		Packages:    []*spdx.Package{{Name: spdx.Str("p", nil), Files: []*spdx.File{file}}},
+	}
+	buf := new(bytes.Buffer)
+	if err := NewFormatter(buf).Document(doc); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if !strings.Contains(buf.String(), "FileName: a.c\n") {
+		t.Errorf("Package file not written: %#v", buf.String())
+	}
+}
+
+func TestFormatterOptions(t *testing.T) {
+	input := "spdxversion: SPDX-1.2\n# creation\nCreator: Tool: x\n\n# the package\nPackageName: p\nPackageDownloadLocation: http://example.org/p.tar.gz\n# end\n"
+	tests := []struct {
+		f        Formatter
+		expected string
+	}{
+		{
+			f:        Formatter{NormaliseKeys: true, Blank: BlankSections},
+			expected: "SPDXVersion: SPDX-1.2\n# creation\nCreator: Tool: x\n\n# the package\nPackageName: p\nPackageDownloadLocation: http://example.org/p.tar.gz\n# end\n",
+		},
+		{
+			f:        Formatter{Blank: BlankNone, TextOver: 20},
+			expected: "spdxversion: SPDX-1.2\n# creation\nCreator: Tool: x\n# the package\nPackageName: p\nPackageDownloadLocation: <text>http://example.org/p.tar.gz</text>\n# end\n",
+		},
+		{
+			f:        Formatter{Align: true, Blank: BlankNone},
+			expected: "spdxversion:                 SPDX-1.2\n# creation\nCreator:                     Tool: x\n# the package\nPackageName:                 p\nPackageDownloadLocation:     http://example.org/p.tar.gz\n# end\n",
+		},
+	}
+	for i, test := range tests {
+		buf := new(bytes.Buffer)
+		f := test.f
+		f.out = buf
+		lex := NewLexer(bytes.NewBufferString(input))
+		lex.CaseSensitive = true
+		if err := f.Lexer(lex); err != nil {
+			t.Fatalf("%d: Unexpected error %s", i, err)
+		}
+		if res := buf.String(); res != test.expected {
+			t.Errorf("%d: Printed %#v but expected %#v", i, res, test.expected)
+		}
+	}
+}
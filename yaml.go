@@ -0,0 +1,26 @@
+//go:build yaml
+// +build yaml
+
+package main
+
+import (
+	"github.com/spdx/tools-go/spdx"
+	"github.com/spdx/tools-go/yaml"
+)
+
+import (
+	"io"
+)
+
+// The SPDX YAML format is supported by the builds with the "yaml" tag.
+var yamlFormats = []string{formatYaml}
+
+// Parse SPDX YAML to a *spdx.Document.
+func readYaml(r io.Reader) (*spdx.Document, error) {
+	return yaml.Build(r)
+}
+
+// Write `doc` as SPDX YAML.
+func writeYaml(w io.Writer, doc *spdx.Document) error {
+	return yaml.Write(w, doc)
+}
@@ -0,0 +1,40 @@
+//go:build yaml
+// +build yaml
+
+// Package yaml reads and writes SPDX documents in the SPDX YAML format.
+//
+// SPDX YAML has the same structure and property names as SPDX JSON, so the
+// documents are converted to and from the json.Node tree of package json and
+// the mapping of that package is used. See package json for the details.
+//
+// The package needs gopkg.in/yaml.v3 and is only built with the "yaml" build
+// tag.
+package yaml
+
+import (
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/spdx"
+)
+
+import (
+	"io"
+)
+
+// Decode a io.Reader and Parse it to a *spdx.Document. If there is an error
+// in the input, it is of type *spdx.ParseError. All the elements and values
+// have *spdx.Meta with the lines they span in the input.
+func Build(r io.Reader) (*spdx.Document, error) {
+	root, err := Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return json.Parse(root)
+}
+
+// Write a *spdx.Document to the given io.Writer
+func Write(w io.Writer, doc *spdx.Document) error {
+	if doc == nil {
+		return nil
+	}
+	return Encode(w, json.DocumentNode(doc))
+}
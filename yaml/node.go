@@ -0,0 +1,161 @@
+//go:build yaml
+// +build yaml
+
+package yaml
+
+import (
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/spdx"
+	"gopkg.in/yaml.v3"
+)
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Error messages used by the decoder
+var (
+	MsgEmptyDocument = "Empty YAML document."
+	MsgInvalidKey    = "Mapping keys must be scalars."
+)
+
+// The line reported in the yaml.v3 error messages.
+var errLine = regexp.MustCompile(`^yaml: line (\d+):`)
+
+// Decode the first YAML document in `r` to a *json.Node. Aliases are
+// expanded. Errors are of type *spdx.ParseError when the line is known.
+func Decode(r io.Reader) (*json.Node, error) {
+	var root yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&root); err != nil {
+		if err == io.EOF {
+			return nil, spdx.NewParseError(MsgEmptyDocument, nil)
+		}
+		return nil, decodeError(err)
+	}
+	return node(&root)
+}
+
+// Convert a yaml.v3 error to a *spdx.ParseError with the line it reports, if
+// there is one.
+func decodeError(err error) error {
+	m := errLine.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	line, _ := strconv.Atoi(m[1])
+	msg := strings.TrimSpace(strings.TrimPrefix(err.Error(), m[0]))
+	return spdx.NewParseError(msg, spdx.NewMetaL(line))
+}
+
+// Convert a *yaml.Node to a *json.Node.
+func node(n *yaml.Node) (*json.Node, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, spdx.NewParseError(MsgEmptyDocument, spdx.NewMetaL(n.Line))
+		}
+		return node(n.Content[0])
+	case yaml.AliasNode:
+		return node(n.Alias)
+	case yaml.MappingNode:
+		res := json.Object(nil)
+		end := n.Line
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Kind != yaml.ScalarNode {
+				return nil, spdx.NewParseError(MsgInvalidKey, spdx.NewMetaL(key.Line))
+			}
+			val, err := node(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			res.Add(key.Value, val)
+			if val.LineEnd > end {
+				end = val.LineEnd
+			}
+		}
+		res.Meta = spdx.NewMeta(n.Line, end)
+		return res, nil
+	case yaml.SequenceNode:
+		res := json.Array(nil)
+		end := n.Line
+		for _, item := range n.Content {
+			val, err := node(item)
+			if err != nil {
+				return nil, err
+			}
+			res.Items = append(res.Items, val)
+			if val.LineEnd > end {
+				end = val.LineEnd
+			}
+		}
+		res.Meta = spdx.NewMeta(n.Line, end)
+		return res, nil
+	}
+
+	res := &json.Node{Kind: scalarKind(n), Value: n.Value, Meta: spdx.NewMetaL(n.Line)}
+	if n.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+		// block scalars start on the line after the indicator
+		res.LineStart++
+		res.LineEnd += strings.Count(strings.TrimRight(n.Value, "\n"), "\n") + 1
+	}
+	return res, nil
+}
+
+// Returns the json.Node kind of a YAML scalar. Timestamps and other tags are
+// strings.
+func scalarKind(n *yaml.Node) int {
+	switch n.ShortTag() {
+	case "!!int", "!!float":
+		return json.KindNumber
+	case "!!bool":
+		return json.KindBool
+	case "!!null":
+		return json.KindNull
+	}
+	return json.KindString
+}
+
+// Encode the *json.Node as YAML to `w`.
+func Encode(w io.Writer, n *json.Node) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(n)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// Convert a *json.Node to a *yaml.Node.
+func yamlNode(n *json.Node) *yaml.Node {
+	switch n.Kind {
+	case json.KindObject:
+		res := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, f := range n.Fields {
+			key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: f.Key}
+			res.Content = append(res.Content, key, yamlNode(f.Value))
+		}
+		return res
+	case json.KindArray:
+		res := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range n.Items {
+			res.Content = append(res.Content, yamlNode(item))
+		}
+		return res
+	case json.KindNumber:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: n.Value}
+	case json.KindBool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: n.Value}
+	case json.KindNull:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+
+	res := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: n.Value}
+	if strings.Contains(n.Value, "\n") {
+		res.Style = yaml.LiteralStyle
+	}
+	return res
+}
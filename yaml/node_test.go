@@ -0,0 +1,128 @@
+//go:build yaml
+// +build yaml
+
+package yaml
+
+import (
+	"github.com/spdx/tools-go/json"
+	"github.com/spdx/tools-go/spdx"
+)
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const testDocument = `spdxVersion: SPDX-1.2
+dataLicense: CC0-1.0
+creationInfo:
+  creators:
+    - "Tool: spdx-go"
+  created: 2014-08-01T00:00:00Z
+packages:
+  - name: pkg
+    versionInfo: 1.0
+    licenseDeclared: (MIT or LicenseRef-1)
+    description: |
+      First line.
+      Second line.
+    hasFiles:
+      - ./a.c
+files:
+  - &file
+    fileName: ./a.c
+    licenseInfoInFiles:
+      - LicenseRef-1
+hasExtractedLicensingInfos:
+  - licenseId: LicenseRef-1
+    extractedText: Some licence
+    name: Some
+`
+
+func TestBuild(t *testing.T) {
+	doc, err := Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.CreationInfo == nil || doc.CreationInfo.Created.V() != "2014-08-01T00:00:00Z" || doc.CreationInfo.Created.Time() == nil {
+		t.Fatalf("Wrong creation info %+v", doc.CreationInfo)
+	}
+	if doc.CreationInfo.LineStart != 4 || doc.CreationInfo.LineEnd != 6 {
+		t.Errorf("Wrong creation info lines %+v", doc.CreationInfo.Meta)
+	}
+	if len(doc.Packages) != 1 || len(doc.Files) != 1 || len(doc.ExtractedLicences) != 1 {
+		t.Fatalf("Wrong number of elements: %d packages, %d files, %d licences", len(doc.Packages), len(doc.Files), len(doc.ExtractedLicences))
+	}
+
+	pkg := doc.Packages[0]
+	if pkg.LineStart != 8 || pkg.LineEnd != 15 {
+		t.Errorf("Wrong package lines %+v", pkg.Meta)
+	}
+	if pkg.Version.Val != "1.0" {
+		t.Errorf("Wrong package version %#v", pkg.Version.Val)
+	}
+	if pkg.Description.Val != "First line.\nSecond line.\n" || pkg.Description.Meta.LineStart != 12 || pkg.Description.Meta.LineEnd != 13 {
+		t.Errorf("Wrong package description %#v %+v", pkg.Description.Val, pkg.Description.Meta)
+	}
+	if _, ok := pkg.LicenceDeclared.(spdx.DisjunctiveLicenceSet); !ok {
+		t.Errorf("Wrong declared licence %#v", pkg.LicenceDeclared)
+	}
+	if len(pkg.Files) != 1 || pkg.Files[0] != doc.Files[0] {
+		t.Errorf("Package files not resolved %+v", pkg.Files)
+	}
+	if doc.Files[0].LicenceInfoInFile[0] != doc.ExtractedLicences[0] {
+		t.Errorf("Licence reference not resolved %#v", doc.Files[0].LicenceInfoInFile[0])
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	inputs := map[string]int{
+		"spdxVersion: SPDX-1.2\ndataLicense: [a]\n": 2,
+		"packages:\n  name: a\n":                    2,
+		"a: b\n  c: d\n":                            2,
+	}
+	for input, line := range inputs {
+		_, err := Build(strings.NewReader(input))
+		perr, ok := err.(*spdx.ParseError)
+		if !ok {
+			t.Errorf("No parse error for %#v: %v", input, err)
+			continue
+		}
+		if perr.LineStart != line {
+			t.Errorf("Wrong line for %#v: %d, expected %d (%s)", input, perr.LineStart, line, perr.Error())
+		}
+	}
+	if _, err := Build(strings.NewReader("")); err == nil {
+		t.Error("No error for empty input.")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	doc, err := Build(strings.NewReader(testDocument))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = Write(&b, doc); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, s := range []string{"versionInfo: \"1.0\"\n", "description: |\n", "    name: pkg\n", "  - SPDXID: SPDXRef-Package-1\n"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("%#v not found in output:\n%s", s, out)
+		}
+	}
+
+	// same output as the JSON of the document, after reading it again
+	doc2, err := Build(&b)
+	if err != nil {
+		t.Fatalf("%s\n%s", err, out)
+	}
+	var j1, j2 bytes.Buffer
+	json.Write(&j1, doc)
+	json.Write(&j2, doc2)
+	if j1.String() != j2.String() {
+		t.Errorf("Different documents after writing and building.\n%s\n%s", j1.String(), j2.String())
+	}
+}